@@ -0,0 +1,357 @@
+package compress
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+)
+
+const (
+
+	// changeCreate
+	//
+	// # English:
+	//
+	// Node created by the osmChange file
+	//
+	// # Português:
+	//
+	// Node criado pelo arquivo osmChange
+	changeCreate = "create"
+
+	// changeModify
+	//
+	// # English:
+	//
+	// Node modified by the osmChange file
+	//
+	// # Português:
+	//
+	// Node modificado pelo arquivo osmChange
+	changeModify = "modify"
+
+	// changeDelete
+	//
+	// # English:
+	//
+	// Node deleted by the osmChange file
+	//
+	// # Português:
+	//
+	// Node apagado pelo arquivo osmChange
+	changeDelete = "delete"
+)
+
+// nodeChange
+//
+// # English:
+//
+// Last action of the osmChange file over a node.
+//
+// # Português:
+//
+// Última ação do arquivo osmChange sobre um node.
+type nodeChange struct {
+	Node
+	action string
+}
+
+// ApplyChanges
+//
+// # English:
+//
+// Applies the node actions, create, modify and delete, of an osmChange file to a node file, version 1 or 2, without
+// rebuilding it from the pbf file.
+//
+// The base file is read in order of ID and merged with the sorted changes into a new file, with the same version and
+// block size and a rebuilt index, which then replaces the base file.
+//
+//	Input:
+//	  base: path of the node file, complete, after WriteFileHeaders() and MountIndexIntoFile();
+//	  osc: path of the osmChange XML file, optionally compressed with gzip.
+//
+//	Note:
+//	  * Ways and relations of the osmChange file are ignored.
+//	  * When a node appears more than once, the last action is kept, as in the osmChange specification.
+//	  * Modify of a node not found in the base file creates the node, delete of a node not found is ignored.
+//	  * The new file is written to base + ".new" and renamed to base only at the end, the base file is not changed in
+//	    case of error.
+//	  * The spatial side index, base + ".hilbert", is rebuilt when it exists.
+//
+// # Português:
+//
+// Aplica as ações sobre nodes, create, modify e delete, de um arquivo osmChange em um arquivo de nodes, versão 1 ou 2,
+// sem reconstruí-lo a partir do arquivo pbf.
+//
+// O arquivo base é lido em ordem de ID e mesclado com as alterações ordenadas em um novo arquivo, com a mesma versão e
+// o mesmo tamanho de bloco e com o índice reconstruído, que então substitui o arquivo base.
+//
+//	Entrada:
+//	  base: caminho do arquivo de nodes, completo, depois de WriteFileHeaders() e MountIndexIntoFile();
+//	  osc: caminho do arquivo XML osmChange, opcionalmente compactado com gzip.
+//
+//	Nota:
+//	  * Ways e relações do arquivo osmChange são ignorados.
+//	  * Quando um node aparece mais de uma vez, a última ação é mantida, como na especificação do osmChange.
+//	  * Modify de um node não encontrado no arquivo base cria o node, delete de um node não encontrado é ignorado.
+//	  * O novo arquivo é escrito em base + ".new" e renomeado para base apenas no final, o arquivo base não é alterado
+//	    em caso de erro.
+//	  * O índice espacial lateral, base + ".hilbert", é reconstruído quando existe.
+func ApplyChanges(base, osc string) (err error) {
+	var changes []nodeChange
+	changes, err = readOsmChange(osc)
+	if err != nil {
+		err = fmt.Errorf("ApplyChanges().readOsmChange().Error: %v", err)
+		return
+	}
+
+	var source = Compress{}
+	source.Init(0)
+	err = source.OpenForSearch(base)
+	if err != nil {
+		err = fmt.Errorf("ApplyChanges().OpenForSearch().Error: %v", err)
+		return
+	}
+
+	var path = base + ".new"
+	var destination = Compress{}
+	destination.Init(source.blockSize)
+	destination.version = source.version
+
+	// # English: the spatial side index is rebuilt when the base file has one
+	// # Português: o índice espacial lateral é reconstruído quando o arquivo base tem um
+	var spatial = source.openSpatialIndex() == nil
+	if spatial {
+		_ = destination.SetSpatialIndex(int(source.spatialOrder))
+	}
+
+	err = destination.Create(path)
+	if err != nil {
+		source.Close()
+		err = fmt.Errorf("ApplyChanges().Create().Error: %v", err)
+		return
+	}
+
+	err = mergeChanges(&source, &destination, changes)
+	if err == nil {
+		err = destination.WriteFileHeaders()
+	}
+	if err == nil {
+		err = destination.MountIndexIntoFile()
+	}
+	destination.Close()
+	source.Close()
+
+	if err != nil {
+		_ = os.Remove(path)
+		_ = os.Remove(path + spatialFileExtension)
+		err = fmt.Errorf("ApplyChanges().Error: %v", err)
+		return
+	}
+
+	err = os.Rename(path, base)
+	if err != nil {
+		err = fmt.Errorf("ApplyChanges().Rename().Error: %v", err)
+		return
+	}
+
+	if spatial {
+		err = os.Rename(path+spatialFileExtension, base+spatialFileExtension)
+		if err != nil {
+			err = fmt.Errorf("ApplyChanges().Rename().Error: %v", err)
+		}
+	}
+	return
+}
+
+// mergeChanges
+//
+// # English:
+//
+// Writes into destination the nodes of source merged with the sorted changes.
+//
+// # Português:
+//
+// Escreve em destination os nodes de source mesclados com as alterações ordenadas.
+func mergeChanges(source, destination *Compress, changes []nodeChange) (err error) {
+	var next = 0
+
+	// # English: writes the created or modified nodes whose ID is less than id
+	// # Português: escreve os nodes criados ou modificados cujo ID é menor do que id
+	var writeChangesUntil = func(id int64) (err error) {
+		for ; next != len(changes) && changes[next].ID < id; next++ {
+			if changes[next].action == changeDelete {
+				continue
+			}
+
+			err = destination.WriteNode(changes[next].ID, changes[next].Lon, changes[next].Lat)
+			if err != nil {
+				return
+			}
+		}
+		return
+	}
+
+	var errScan = source.ForEachNode(func(id int64, longitude, latitude float64) bool {
+		err = writeChangesUntil(id)
+		if err != nil {
+			return false
+		}
+
+		if next != len(changes) && changes[next].ID == id {
+			change := changes[next]
+			next++
+
+			if change.action == changeDelete {
+				return true
+			}
+			longitude, latitude = change.Lon, change.Lat
+		}
+
+		err = destination.WriteNode(id, longitude, latitude)
+		return err == nil
+	})
+	if errScan != nil {
+		return errScan
+	}
+	if err != nil {
+		return
+	}
+
+	return writeChangesUntil(1<<63 - 1)
+}
+
+// readOsmChange
+//
+// # English:
+//
+// Reads the node actions of the osmChange file, sorted by ID, keeping the last action of each node.
+//
+// # Português:
+//
+// Lê as ações sobre nodes do arquivo osmChange, ordenadas por ID, mantendo a última ação de cada node.
+func readOsmChange(path string) (changes []nodeChange, err error) {
+	var file *os.File
+	file, err = os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	var reader io.Reader
+	var buffered = bufio.NewReader(file)
+	var magic []byte
+	magic, err = buffered.Peek(2)
+	if err != nil && err != io.EOF {
+		return
+	}
+	err = nil
+
+	reader = buffered
+	if len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		var gz *gzip.Reader
+		gz, err = gzip.NewReader(buffered)
+		if err != nil {
+			return
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	var last = make(map[int64]int)
+	var action string
+	var decoder = xml.NewDecoder(reader)
+	for {
+		var token xml.Token
+		token, err = decoder.Token()
+		if err == io.EOF {
+			err = nil
+			break
+		}
+		if err != nil {
+			return
+		}
+
+		element, ok := token.(xml.StartElement)
+		if !ok {
+			if end, ok := token.(xml.EndElement); ok && end.Name.Local == action {
+				action = ""
+			}
+			continue
+		}
+
+		switch element.Name.Local {
+		case changeCreate, changeModify, changeDelete:
+			action = element.Name.Local
+		case "node":
+			if action == "" {
+				continue
+			}
+
+			var change nodeChange
+			change, err = parseNodeChange(element, action)
+			if err != nil {
+				return
+			}
+
+			if key, found := last[change.ID]; found {
+				changes[key] = change
+				continue
+			}
+
+			last[change.ID] = len(changes)
+			changes = append(changes, change)
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].ID < changes[j].ID })
+	return
+}
+
+// parseNodeChange
+//
+// # English:
+//
+// Reads the id, lon and lat attributes of a node element, lon and lat are not required by delete.
+//
+// # Português:
+//
+// Lê os atributos id, lon e lat de um elemento node, lon e lat não são exigidos por delete.
+func parseNodeChange(element xml.StartElement, action string) (change nodeChange, err error) {
+	change.action = action
+
+	var hasLon, hasLat bool
+	for _, attr := range element.Attr {
+		switch attr.Name.Local {
+		case "id":
+			change.ID, err = strconv.ParseInt(attr.Value, 10, 64)
+		case "lon":
+			change.Lon, err = strconv.ParseFloat(attr.Value, 64)
+			hasLon = true
+		case "lat":
+			change.Lat, err = strconv.ParseFloat(attr.Value, 64)
+			hasLat = true
+		}
+
+		if err != nil {
+			err = fmt.Errorf("node attribute %v: %v", attr.Name.Local, err)
+			return
+		}
+	}
+
+	if change.ID == 0 {
+		err = errors.New("node without id")
+		return
+	}
+
+	if action != changeDelete && (!hasLon || !hasLat) {
+		err = fmt.Errorf("node %v without lon or lat", change.ID)
+	}
+	return
+}
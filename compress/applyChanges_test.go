@@ -0,0 +1,168 @@
+package compress
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestApplyChanges
+//
+// English:
+//
+// # Applies an osmChange file, plain and compressed with gzip, to node files of version 1 and 2 and tests the result
+//
+// Português:
+//
+// Aplica um arquivo osmChange, simples e compactado com gzip, em arquivos de nodes das versões 1 e 2 e testa o
+// resultado
+func TestApplyChanges(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.Remove("./test.node.osc.tmp")
+		_ = os.Remove("./test.osc.tmp")
+	})
+
+	const osc = `<?xml version="1.0" encoding="UTF-8"?>
+<osmChange version="0.6" generator="test">
+  <modify>
+    <node id="10" version="2" lat="-23.5505199" lon="-46.6333094"/>
+  </modify>
+  <delete>
+    <node id="20" version="3"/>
+  </delete>
+  <create>
+    <node id="150" version="1" lat="1.5" lon="2.5"/>
+    <node id="200" version="1" lat="1" lon="1"/>
+    <node id="-1" version="1" lat="1" lon="1"/>
+  </create>
+  <delete>
+    <node id="200" version="2"/>
+  </delete>
+  <modify>
+    <node id="0" version="2" lat="1" lon="1"/>
+    <way id="10" version="2"><nd ref="10"/></way>
+  </modify>
+</osmChange>`
+
+	var err error
+	for _, version := range []int{1, 2} {
+		for _, gzipped := range []bool{false, true} {
+			var file *os.File
+			file, err = os.Create("./test.osc.tmp")
+			if err != nil {
+				t.Logf("create osc error: %v", err)
+				t.FailNow()
+			}
+
+			var writer io.Writer = file
+			var gz *gzip.Writer
+			if gzipped {
+				gz = gzip.NewWriter(file)
+				writer = gz
+			}
+
+			// English: node -1 and node 0 are invalid and must be removed
+			// Português: node -1 e node 0 são inválidos e devem ser removidos
+			valid := osc
+			valid = strings.Replace(valid, `    <node id="-1" version="1" lat="1" lon="1"/>`+"\n", "", 1)
+			valid = strings.Replace(valid, `    <node id="0" version="2" lat="1" lon="1"/>`+"\n", "", 1)
+			_, _ = writer.Write([]byte(valid))
+			if gz != nil {
+				_ = gz.Close()
+			}
+			_ = file.Close()
+
+			compress := Compress{}
+			compress.Init(7)
+			_ = compress.SetFormatVersion(version)
+			err = compress.Create("./test.node.osc.tmp")
+			if err != nil {
+				t.Logf("create error: %v", err)
+				t.FailNow()
+			}
+
+			for id := int64(1); id <= 100; id++ {
+				err = compress.WriteNode(id, float64(id)/100, -float64(id)/100)
+				if err != nil {
+					t.Logf("write node error: %v", err)
+					t.FailNow()
+				}
+			}
+
+			err = compress.WriteFileHeaders()
+			if err != nil {
+				t.Logf("write header error: %v", err)
+				t.FailNow()
+			}
+
+			err = compress.MountIndexIntoFile()
+			if err != nil {
+				t.Logf("mount index error: %v", err)
+				t.FailNow()
+			}
+			compress.Close()
+
+			err = ApplyChanges("./test.node.osc.tmp", "./test.osc.tmp")
+			if err != nil {
+				t.Logf("apply changes error: %v", err)
+				t.FailNow()
+			}
+
+			compress = Compress{}
+			compress.Init(0)
+			err = compress.OpenForSearch("./test.node.osc.tmp")
+			if err != nil {
+				t.Logf("open for search error: %v", err)
+				t.FailNow()
+			}
+
+			if compress.version != []string{headerVersion, headerVersion2}[version-1] || compress.blockSize != 7 {
+				t.Logf("version %v: format not kept: %v, %v", version, compress.version, compress.blockSize)
+				t.FailNow()
+			}
+
+			for id := int64(1); id <= 200; id++ {
+				var lon, lat float64
+				lon, lat, err = compress.FindNodeByID(id)
+
+				switch {
+				case id == 20 || (id > 100 && id != 150):
+					if err != io.EOF {
+						t.Logf("version %v: node %v should not be found: %v", version, id, err)
+						t.FailNow()
+					}
+				case id == 10:
+					if err != nil || lon != -46.6333094 || lat != -23.5505199 {
+						t.Logf("version %v: node 10 not modified: %v, %v, %v", version, lon, lat, err)
+						t.FailNow()
+					}
+				case id == 150:
+					if err != nil || lon != 2.5 || lat != 1.5 {
+						t.Logf("version %v: node 150 not created: %v, %v, %v", version, lon, lat, err)
+						t.FailNow()
+					}
+				default:
+					if err != nil || lon != float64(id)/100 || lat != -float64(id)/100 {
+						t.Logf("version %v: node %v changed: %v, %v, %v", version, id, lon, lat, err)
+						t.FailNow()
+					}
+				}
+			}
+			compress.Close()
+		}
+	}
+
+	err = os.WriteFile("./test.osc.tmp", []byte(osc), 0644)
+	if err != nil {
+		t.Logf("write osc error: %v", err)
+		t.FailNow()
+	}
+
+	err = ApplyChanges("./test.node.osc.tmp", "./test.osc.tmp")
+	if err == nil {
+		t.Logf("invalid node IDs should return an error")
+		t.FailNow()
+	}
+}
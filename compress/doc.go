@@ -19,7 +19,7 @@
 //	   +/-90˚ in latitude, with 7 decimal places.
 //
 //		  For data compression, the floating point number of the coordinate is multiplied by 10,000,000 and then converted
-//		  to an integer, losing the decimal part, so the largest number saved is the integer +/-1,800,000,000.
+//		  to the nearest integer, so the largest number saved is the integer +/-1,800,000,000.
 //		  With this, it can be represented by the group of four bytes X110 1011 0100 1001 1101 0010 0000 0000, where the
 //		  most significant bit, `X` is never used, and can be used to indicate positive or negative sign, that is, X= 1
 //		  represents a negative number and X=0 a positive number (the rule of two was not used).
@@ -52,6 +52,18 @@
 //		  Therefore, the ID sought will be between addresses 1160 and 1320 of the binary file on disk.
 //		  On disk, each address is 8 bytes for ID + 4 bytes for longitude + 4 bytes for latitude.
 //
+//		Version 2, "00000002":
+//		  Enabled by Compress.SetFormatVersion(2). Nodes are saved in blocks of blockSize nodes, as delta+zigzag varint,
+//		  and the in-memory index points to the start of each block. See typeCompressBlock.go.
+//
+//		Integrity, "GOSMN001" and "GOSMN002":
+//		  Files written by this version have magic bytes in the version field and a footer with CRC32C checksums and a
+//		  complete flag after the index. Compress.Verify() checks the whole file. See typeCompressVerify.go.
+//
+//		Spatial side index, "GOSMH001":
+//		  Optional, enabled by Compress.SetSpatialIndex(). Node IDs grouped by Hilbert cell in path + ".hilbert", used by
+//		  Compress.NodesInBox(). See typeCompressSpatial.go.
+//
 // # Português:
 //
 //	Este pacote arquiva coordenadas geográficas usadas na construção do OpenStreetMap em um arquivo binário feito para
@@ -72,7 +84,7 @@
 //	   e +/-90˚ na latitude, com 7 casas decimais.
 //
 //		  Para a compactação de dados, o número de ponto flutuante da coordenada é multiplicado por 10.000.000 e em seguida
-//		  é convertido em inteiro, perdendo a parte decimal, logo, o maior número salvo é o inteiro +/-1.800.000.000.
+//		  é arredondado para o inteiro mais próximo, logo, o maior número salvo é o inteiro +/-1.800.000.000.
 //		  Com isto, pode ser representado pelo grupo de quatro bytes X110 1011 0100 1001 1101 0010 0000 0000, onde o bit
 //		  mais significativo, `X` nunca é usado, e pode ser usado para indicar sinal de positivo ou negativo, ou seja, X=1
 //		  representa um número negativo e X=0 um número positivo (não foi usada a regra de dois).
@@ -105,6 +117,18 @@
 //		  Logo, o ID procurado estará entre os endereços 1.160 e 1.320 do arquivo binário em disco.
 //		  No disco, cada endereço tem 8 bytes para ID + 4 bytes para a longitude + 4 bytes para a latitude.
 //
+//		Versão 2, "00000002":
+//		  Habilitada por Compress.SetFormatVersion(2). Os nodes são salvos em blocos de blockSize nodes, como delta+zigzag
+//		  varint, e o índice em memória aponta para o início de cada bloco. Veja typeCompressBlock.go.
+//
+//		Integridade, "GOSMN001" e "GOSMN002":
+//		  Arquivos escritos por esta versão têm bytes mágicos no campo versão e um rodapé com somas de verificação CRC32C e
+//		  um flag de completo depois do índice. Compress.Verify() verifica o arquivo inteiro. Veja typeCompressVerify.go.
+//
+//		Índice espacial lateral, "GOSMH001":
+//		  Opcional, habilitado por Compress.SetSpatialIndex(). IDs de nodes agrupados por célula de Hilbert em
+//		  path + ".hilbert", usado por Compress.NodesInBox(). Veja typeCompressSpatial.go.
+//
 // # Drawing:
 //
 //	Drawing the binary file for better understanding:
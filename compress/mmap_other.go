@@ -0,0 +1,35 @@
+//go:build !unix
+
+package compress
+
+import (
+	"errors"
+	"os"
+)
+
+// mmapFile
+//
+// # English:
+//
+// Memory mapping is not supported on this system, Compress falls back to ReadAt().
+//
+// # Português:
+//
+// O mapeamento de memória não é suportado neste sistema, Compress volta a usar ReadAt().
+func mmapFile(_ *os.File) (data []byte, err error) {
+	err = errors.New("mmap is not supported on this system")
+	return
+}
+
+// munmapFile
+//
+// # English:
+//
+// Memory mapping is not supported on this system.
+//
+// # Português:
+//
+// O mapeamento de memória não é suportado neste sistema.
+func munmapFile(_ []byte) (err error) {
+	return
+}
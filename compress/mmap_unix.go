@@ -0,0 +1,45 @@
+//go:build unix
+
+package compress
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmapFile
+//
+// # English:
+//
+// Maps the whole file, read-only, into memory.
+//
+// # Português:
+//
+// Mapeia o arquivo inteiro, apenas para leitura, na memória.
+func mmapFile(file *os.File) (data []byte, err error) {
+	var info os.FileInfo
+	info, err = file.Stat()
+	if err != nil {
+		return
+	}
+
+	if info.Size() == 0 {
+		return
+	}
+
+	data, err = syscall.Mmap(int(file.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	return
+}
+
+// munmapFile
+//
+// # English:
+//
+// Releases the memory mapped by mmapFile().
+//
+// # Português:
+//
+// Libera a memória mapeada por mmapFile().
+func munmapFile(data []byte) (err error) {
+	return syscall.Munmap(data)
+}
@@ -93,6 +93,40 @@ const (
 	// Texto de versão escrito no cabeçalho do arquivo binário
 	headerVersion = "00000001"
 
+	// headerVersion2
+	//
+	// # English:
+	//
+	// Version text of the block-compressed file, where nodes are saved in blocks of delta+zigzag varint
+	//
+	// # Português:
+	//
+	// Texto de versão do arquivo compactado em blocos, onde os nodes são salvos em blocos de delta+zigzag varint
+	headerVersion2 = "00000002"
+
+	// headerMagicVersion
+	//
+	// # English:
+	//
+	// Version text of version 1 files with magic bytes, "GOSMN" from goosm nodes, and checksums. See typeCompressVerify.go
+	//
+	// # Português:
+	//
+	// Texto de versão de arquivos da versão 1 com bytes mágicos, "GOSMN" de goosm nodes, e somas de verificação. Veja
+	// typeCompressVerify.go
+	headerMagicVersion = "GOSMN001"
+
+	// headerMagicVersion2
+	//
+	// # English:
+	//
+	// Version text of version 2 files with magic bytes and checksums
+	//
+	// # Português:
+	//
+	// Texto de versão de arquivos da versão 2 com bytes mágicos e somas de verificação
+	headerMagicVersion2 = "GOSMN002"
+
 	// headerVersionByteSize
 	//
 	// # English:
@@ -523,6 +557,128 @@ type Compress struct {
 	//    Logo, o ID procurado estará entre os endereços 1.160 e 1320; E cada endereço tem 8 bytes para ID + 4 bytes para
 	//    a longitude + 4 bytes para a latitude.
 	memory [][2]int64
+
+	// # English:
+	//
+	// Version of the file format, headerVersion or headerVersion2.
+	//
+	// # Português:
+	//
+	// Versão do formato do arquivo, headerVersion ou headerVersion2.
+	version string
+
+	// # English:
+	//
+	// Address of the end of the data and start of the indexes, used by version 2 to find the end of the last block.
+	//
+	// # Português:
+	//
+	// Endereço do fim dos dados e início dos índices, usado pela versão 2 para achar o fim do último bloco.
+	indexesAddress int64
+
+	// # English:
+	//
+	// Version 2 only. Block being mounted, not yet written to file, and the amount of nodes it contains.
+	//
+	// # Português:
+	//
+	// Apenas versão 2. Bloco sendo montado, ainda não escrito no arquivo, e a quantidade de nodes contida nele.
+	block      []byte
+	blockNodes int64
+
+	// # English:
+	//
+	// Version 2 only. ID, longitude and latitude of the previous node in the block, used to calculate the delta.
+	//
+	// # Português:
+	//
+	// Apenas versão 2. ID, longitude e latitude do node anterior no bloco, usados para calcular o delta.
+	blockLast [3]int64
+
+	// # English:
+	//
+	// Version 2 only. First ID and address of each block written, saved by MountIndexIntoFile().
+	//
+	// # Português:
+	//
+	// Apenas versão 2. Primeiro ID e endereço de cada bloco escrito, salvos por MountIndexIntoFile().
+	blockIndex [][2]int64
+
+	// # English:
+	//
+	// Version 2 only. Last decoded block, since the nodes of a way are usually close to each other.
+	//
+	// # Português:
+	//
+	// Apenas versão 2. Último bloco decodificado, pois os nodes de um way costumam estar próximos uns dos outros.
+	cachedBlock int
+	cachedNodes [][3]int64
+
+	// # English:
+	//
+	// File mapped into memory by OpenForSearchMmap(), nil when the search uses ReadAt().
+	//
+	// # Português:
+	//
+	// Arquivo mapeado na memória por OpenForSearchMmap(), nil quando a busca usa ReadAt().
+	mapped []byte
+
+	// # English:
+	//
+	// True when the file has magic bytes and checksums, false for files written before them, which are read without
+	// integrity checks.
+	//
+	// # Português:
+	//
+	// True quando o arquivo tem bytes mágicos e somas de verificação, false para arquivos escritos antes deles, que são
+	// lidos sem verificações de integridade.
+	checksum bool
+
+	// # English:
+	//
+	// CRC32C of each data block written and of the block being written.
+	//
+	// # Português:
+	//
+	// CRC32C de cada bloco de dados escrito e do bloco sendo escrito.
+	blockCRC []uint32
+	crc      uint32
+
+	// # English:
+	//
+	// Path of the node file, used by the spatial side index, path + ".hilbert".
+	//
+	// # Português:
+	//
+	// Caminho do arquivo de nodes, usado pelo índice espacial lateral, path + ".hilbert".
+	path string
+
+	// # English:
+	//
+	// Order of the grid of the spatial side index, 0 when disabled, and the cell and ID of each node written, in memory
+	// and in the sorted runs spilled to disk, collected only after SetSpatialIndex().
+	//
+	// # Português:
+	//
+	// Ordem da grade do índice espacial lateral, 0 quando desabilitado, e a célula e o ID de cada node escrito, na
+	// memória e nas execuções ordenadas despejadas no disco, coletados apenas depois de SetSpatialIndex().
+	spatialOrder       int64
+	spatialCells       [][2]int64
+	spatialRuns        []string
+	spatialCollect     bool
+	spatialMemoryLimit int64
+
+	// # English:
+	//
+	// Spatial side index opened by NodesInBox(), cell and address of each non-empty cell and the end of the last cell.
+	//
+	// # Português:
+	//
+	// Índice espacial lateral aberto por NodesInBox(), célula e endereço de cada célula não vazia e o fim da última
+	// célula.
+	spatialFile             *os.File
+	spatialDirectory        [][2]int64
+	spatialDirectoryAddress int64
 }
 
 // Init
@@ -555,6 +711,54 @@ func (e *Compress) Init(blockSize int64) {
 	e.nodeReadDataPosition = nodeDataPositionStartAtAddress
 	e.blockSize = blockSize
 	e.memory = make([][2]int64, 0)
+	e.version = headerVersion
+	e.block = make([]byte, 0)
+	e.blockNodes = 0
+	e.blockLast = [3]int64{}
+	e.blockIndex = make([][2]int64, 0)
+	e.cachedBlock = -1
+	e.lastID = 0
+	e.checksum = true
+	e.blockCRC = make([]uint32, 0)
+	e.crc = 0
+	e.removeSpatialRuns()
+	e.spatialOrder = 0
+	e.spatialCells = nil
+	e.spatialCollect = false
+}
+
+// SetFormatVersion
+//
+// # English:
+//
+// Defines the format of the file to be written, must be called after Init() and before the first node is written.
+//
+//	Input:
+//	  version: 1, fixed 16 bytes per node, or 2, blocks of blockSize nodes with delta+zigzag varint ID and coordinates.
+//
+//	Note:
+//	  * OpenForSearch() and ReadFileHeaders() detect the version of the file, both versions can be read.
+//
+// # Português:
+//
+// Define o formato do arquivo a ser escrito, deve ser chamada depois de Init() e antes do primeiro node ser escrito.
+//
+//	Entrada:
+//	  version: 1, 16 bytes fixos por node, ou 2, blocos de blockSize nodes com ID e coordenadas em delta+zigzag varint.
+//
+//	Nota:
+//	  * OpenForSearch() e ReadFileHeaders() detectam a versão do arquivo, as duas versões podem ser lidas.
+func (e *Compress) SetFormatVersion(version int) (err error) {
+	switch version {
+	case 1:
+		e.version = headerVersion
+	case 2:
+		e.version = headerVersion2
+	default:
+		err = fmt.Errorf("Compress.SetFormatVersion().error: unknown version %v", version)
+	}
+
+	return
 }
 
 // OpenForSearch
@@ -577,6 +781,8 @@ func (e *Compress) OpenForSearch(path string) (err error) {
 		_ = e.file.Close()
 	}
 
+	e.closeSpatialIndex()
+	e.path = path
 	e.file, err = os.OpenFile(path, os.O_RDONLY, fs.ModePerm)
 	if err != nil {
 		err = fmt.Errorf("Compress.OpenForSearch().OpenFile().Error: %v", err)
@@ -589,6 +795,12 @@ func (e *Compress) OpenForSearch(path string) (err error) {
 		return
 	}
 
+	err = e.checkIntegrity()
+	if err != nil {
+		err = fmt.Errorf("Compress.OpenForSearch().checkIntegrity().Error: %v", err)
+		return
+	}
+
 	err = e.IndexToMemory()
 	if err != nil {
 		err = fmt.Errorf("Compress.OpenForSearch().IndexToMemory().Error: %v", err)
@@ -614,6 +826,11 @@ func (e *Compress) ResizeBlock(blockSize int64) (err error) {
 		return
 	}
 
+	if e.version == headerVersion2 {
+		err = errors.New("ResizeBlock().error: the block size of a version 2 file is fixed when the file is written")
+		return
+	}
+
 	e.blockSize = blockSize
 	err = e.writeHeaderBlockSize()
 	if err != nil {
@@ -684,7 +901,13 @@ func (e *Compress) Create(path string) (err error) {
 		_ = e.file.Close()
 	}
 
-	e.file, err = os.OpenFile(path, os.O_CREATE|os.O_RDWR, fs.ModePerm)
+	e.closeSpatialIndex()
+	e.path = path
+	// English: truncated, so the header, the index and the complete flag of a previous file are never read back after a
+	// crash
+	// Português: truncado, assim o cabeçalho, o índice e o flag de completo de um arquivo anterior nunca são lidos de
+	// volta depois de uma falha
+	e.file, err = os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, fs.ModePerm)
 	if err != nil {
 		err = fmt.Errorf("compress.Create().error: the function OpenFile() returned an error: %v", err)
 		return
@@ -702,6 +925,10 @@ func (e *Compress) Create(path string) (err error) {
 //
 // Fecha o arquivo temporário
 func (e *Compress) Close() {
+	e.unmap()
+	e.closeSpatialIndex()
+	e.removeSpatialRuns()
+
 	var err = e.file.Close()
 	if err != nil {
 		log.Printf("Compress.Close().error: %v", err)
@@ -779,25 +1006,54 @@ func (e *Compress) WriteNode(id int64, longitude, latitude float64) (err error)
 	// | 13.387366542s | 2436762280 Allocs | map[id][2]float64{longitude, latitude}       |
 	// |---------------|-------------------|----------------------------------------------|
 
+	e.lastID = id
+
+	if e.version == headerVersion2 {
+		err = e.writeNodeIntoBlock(id, longitude, latitude)
+		if err != nil {
+			err = fmt.Errorf("writeNode().error: the writeNodeIntoBlock() function returned an error: %v", err)
+			return
+		}
+
+		e.totalOfNodesInTmpFile++
+		err = e.addNodeToSpatialIndex(id, longitude, latitude)
+		if err != nil {
+			err = fmt.Errorf("writeNode().error: the addNodeToSpatialIndex() function returned an error: %v", err)
+		}
+		return
+	}
+
 	err = e.writeID(id)
 	if err != nil {
 		err = fmt.Errorf("writeNode().error: the writeID() function returned an error: %v", err)
 		return
 	}
+	e.updateChecksum(e.dataFile[:nodeIdByteSize])
 
 	err = e.writeCoordinate(longitude)
 	if err != nil {
 		err = fmt.Errorf("writeNode().error: the writeCoordinate(longitude) function returned an error: %v", err)
 		return
 	}
+	e.updateChecksum(e.dataFile[:nodeCoordinateByteSize])
 
 	err = e.writeCoordinate(latitude)
 	if err != nil {
 		err = fmt.Errorf("writeNode().error: the writeCoordinate(latitude) function returned an error: %v", err)
 		return
 	}
+	e.updateChecksum(e.dataFile[:nodeCoordinateByteSize])
 
 	e.totalOfNodesInTmpFile++
+	err = e.addNodeToSpatialIndex(id, longitude, latitude)
+	if err != nil {
+		err = fmt.Errorf("writeNode().error: the addNodeToSpatialIndex() function returned an error: %v", err)
+		return
+	}
+
+	if e.blockSize > 0 && e.totalOfNodesInTmpFile%e.blockSize == 0 {
+		e.closeChecksumBlock()
+	}
 
 	return
 }
@@ -828,6 +1084,16 @@ func (e *Compress) WriteNode(id int64, longitude, latitude float64) (err error)
 //	  latitude: valor entre ±90 com 7 casas decimais;
 //	  err: objeto de padrão, com erro io.EOF quando o valor não é encontrado no arquivo
 func (e *Compress) FindNodeByID(id int64) (longitude, latitude float64, err error) {
+	if e.version == headerVersion2 {
+		longitude, latitude, err = e.findNodeByIDIntoBlock(id)
+		return
+	}
+
+	if e.mapped != nil {
+		longitude, latitude, err = e.findNodeByIDMmap(id)
+		return
+	}
+
 	i := sort.Search(len(e.memory), func(i int) bool { return e.memory[i][memorySliceAddrID] >= id })
 	if i < len(e.memory) && e.memory[i][memorySliceAddrID] == id {
 
@@ -931,6 +1197,11 @@ func (e *Compress) binarySearchCoordinate(leftBoundFileAddr, rightBoundFileAddr,
 //
 // Devolve o endereço de onde o ID será inserido
 func (e *Compress) FindNextAddressByID(id int64) (address int64, err error) {
+	if e.version == headerVersion2 {
+		err = errors.New("FindNextAddressByID().error: function not supported by version 2 files")
+		return
+	}
+
 	i := sort.Search(len(e.memory), func(i int) bool { return e.memory[i][memorySliceAddrID] >= id })
 	if i < len(e.memory) && e.memory[i][memorySliceAddrID] == id {
 
@@ -1017,6 +1288,21 @@ func (e *Compress) binarySearchAddress(leftBoundFileAddr, rightBoundFileAddr, no
 //	  * Índices são blocos com intervalos de IDs para ajudar a calcular o endereço do ID dentro do arquivo temporário.
 //	  * Índices são carregados em memória para maior desempenho.
 func (e *Compress) MountIndexIntoFile() (err error) {
+	var indexesAddress = e.nodeWriteDataPosition
+	defer func() {
+		if err == nil {
+			err = e.writeFooter(indexesAddress)
+		}
+		if err == nil {
+			err = e.mountSpatialIndex()
+		}
+	}()
+
+	if e.version == headerVersion2 {
+		err = e.mountBlockIndexIntoFile()
+		return
+	}
+
 	e.nodeReadDataPosition = nodeDataPositionStartAtAddress
 
 	//(place * (id space + lon space + lat space)) + (version + total nodes + block size + total indexes + index addr) = addr
@@ -1184,7 +1470,7 @@ func (e *Compress) writeCoordinate(coordinate float64) (err error) {
 		coordinate *= -1.0
 	}
 
-	binary.LittleEndian.PutUint64(e.dataFile, uint64(coordinate*decimalPlaces))
+	binary.LittleEndian.PutUint64(e.dataFile, uint64(math.Round(coordinate*decimalPlaces)))
 	if negativeNumber {
 		e.dataFile[mostSignificantByte] = e.dataFile[mostSignificantByte] | mostSignificantBit
 	}
@@ -1239,6 +1525,14 @@ func (e *Compress) readCoordinate(nodeReadDataPosition int64) (coordinate float6
 //
 // Escreve os dados de configuração no início do arquivo.
 func (e *Compress) WriteFileHeaders() (err error) {
+	if e.version == headerVersion2 {
+		err = e.flushBlock()
+		if err != nil {
+			err = fmt.Errorf("WriteFileHeaders().error: the flushBlock() function returned an error: %v", err)
+			return
+		}
+	}
+
 	err = e.writeHeaderVersion()
 	if err != nil {
 		err = fmt.Errorf("WriteFileHeaders().error: the writeHeaderVersion() function returned an error: %v", err)
@@ -1282,6 +1576,12 @@ func (e *Compress) WriteFileHeaders() (err error) {
 //
 // Lê os dados de configuração no início do arquivo.
 func (e *Compress) ReadFileHeaders() (err error) {
+	// # English: the checksums of the data blocks are only known while the file is written, MountIndexIntoFile()
+	// recalculates them from the data
+	// # Português: as somas de verificação dos blocos de dados só são conhecidas enquanto o arquivo é escrito,
+	// MountIndexIntoFile() as recalcula a partir dos dados
+	e.blockCRC = nil
+
 	err = e.readHeaderVersion()
 	if err != nil {
 		err = fmt.Errorf("ReadFileHeaders().error: the readHeaderVersion() function returned an error: %v", err)
@@ -1348,7 +1648,12 @@ func (e *Compress) writeHeaderVersion() (err error) {
 		return
 	}
 
-	_, err = e.file.WriteAt([]byte(headerVersion), headerVersionAddress)
+	var version = e.version
+	if e.checksum {
+		version = map[string]string{headerVersion: headerMagicVersion, headerVersion2: headerMagicVersion2}[e.version]
+	}
+
+	_, err = e.file.WriteAt([]byte(version), headerVersionAddress)
 	return
 }
 
@@ -1367,8 +1672,21 @@ func (e *Compress) readHeaderVersion() (err error) {
 		return
 	}
 
-	if string(e.dataFile) != headerVersion {
-		err = fmt.Errorf("file version header does not match code version: %v != %v", string(e.dataFile), headerVersion)
+	switch string(e.dataFile) {
+	case headerVersion, headerVersion2:
+		e.version = string(e.dataFile)
+		e.checksum = false
+		e.cachedBlock = -1
+	case headerMagicVersion:
+		e.version = headerVersion
+		e.checksum = true
+		e.cachedBlock = -1
+	case headerMagicVersion2:
+		e.version = headerVersion2
+		e.checksum = true
+		e.cachedBlock = -1
+	default:
+		err = fmt.Errorf("file version header does not match code version: %v != %v or %v", string(e.dataFile), headerMagicVersion, headerMagicVersion2)
 		return
 	}
 
@@ -1512,5 +1830,6 @@ func (e *Compress) readHeaderIndexesAddress() (err error) {
 	}
 
 	e.nodeWriteDataPosition = int64(binary.LittleEndian.Uint64(e.dataFile))
+	e.indexesAddress = e.nodeWriteDataPosition
 	return
 }
@@ -0,0 +1,199 @@
+package compress
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// Version 2 file format
+//
+// # English:
+//
+//	The header is the same as version 1, with version "00000002", and blockSize becomes the number of nodes per block.
+//
+//	Data block:
+//	  Sequence of blocks with blockSize nodes, the last one may be smaller. Each node is written as three zigzag varint,
+//	  ID, longitude * 10,000,000 and latitude * 10,000,000, each as the difference to the previous node of the same
+//	  block. The first node of the block is the difference to zero, so each block can be decoded on its own.
+//
+//	Index block:
+//	  One entry for each block, first node ID: 8 bytes and block address: 8 bytes, loaded into memory as in version 1.
+//	  The end of a block is the start of the next one, or the start of the indexes for the last block.
+//
+//	Since IDs are almost consecutive and neighboring coordinates are close, a node takes about 5 to 8 bytes instead of
+//	16, and FindNodeByID() reads and decodes a single block instead of doing a binary search on disk.
+//
+// # Português:
+//
+//	O cabeçalho é o mesmo da versão 1, com a versão "00000002", e blockSize passa a ser a quantidade de nodes por bloco.
+//
+//	Bloco de dados:
+//	  Sequência de blocos com blockSize nodes, o último pode ser menor. Cada node é escrito como três zigzag varint, ID,
+//	  longitude * 10.000.000 e latitude * 10.000.000, cada um como a diferença para o node anterior do mesmo bloco. O
+//	  primeiro node do bloco é a diferença para zero, assim cada bloco pode ser decodificado sozinho.
+//
+//	Bloco de índices:
+//	  Uma entrada para cada bloco, ID do primeiro node: 8 bytes e endereço do bloco: 8 bytes, carregados em memória como
+//	  na versão 1. O fim de um bloco é o início do próximo, ou o início dos índices para o último bloco.
+//
+//	Como os IDs são quase consecutivos e coordenadas vizinhas são próximas, um node ocupa entre 5 e 8 bytes em vez de
+//	16, e FindNodeByID() lê e decodifica um único bloco em vez de fazer uma busca binária no disco.
+
+// writeNodeIntoBlock
+//
+// # English:
+//
+// Adds the node to the block being mounted and writes the block to the file when it is full.
+//
+// # Português:
+//
+// Adiciona o node ao bloco sendo montado e escreve o bloco no arquivo quando o mesmo está cheio.
+func (e *Compress) writeNodeIntoBlock(id int64, longitude, latitude float64) (err error) {
+	if e.blockNodes == 0 {
+		e.blockIndex = append(e.blockIndex, [2]int64{memorySliceAddrID: id, memorySliceAddrOfAddrIntoFile: e.nodeWriteDataPosition})
+		e.blockLast = [3]int64{}
+	}
+
+	var node = [3]int64{id, int64(math.Round(longitude * decimalPlaces)), int64(math.Round(latitude * decimalPlaces))}
+	for key := range node {
+		e.block = binary.AppendVarint(e.block, node[key]-e.blockLast[key])
+	}
+	e.blockLast = node
+	e.blockNodes++
+
+	if e.blockNodes == e.blockSize {
+		err = e.flushBlock()
+	}
+
+	return
+}
+
+// flushBlock
+//
+// # English:
+//
+// Writes the block being mounted to the file.
+//
+// # Português:
+//
+// Escreve o bloco sendo montado no arquivo.
+func (e *Compress) flushBlock() (err error) {
+	if e.blockNodes == 0 {
+		return
+	}
+
+	_, err = e.file.WriteAt(e.block, e.nodeWriteDataPosition)
+	if err != nil {
+		return
+	}
+
+	e.nodeWriteDataPosition += int64(len(e.block))
+	e.updateChecksum(e.block)
+	e.closeChecksumBlock()
+	e.block = e.block[:0]
+	e.blockNodes = 0
+	return
+}
+
+// mountBlockIndexIntoFile
+//
+// # English:
+//
+// Saves the first ID and the address of each block after the data.
+//
+// # Português:
+//
+// Salva o primeiro ID e o endereço de cada bloco depois dos dados.
+func (e *Compress) mountBlockIndexIntoFile() (err error) {
+	var buffer = make([]byte, 2*int64ByteSize*len(e.blockIndex))
+	for key, value := range e.blockIndex {
+		binary.LittleEndian.PutUint64(buffer[2*int64ByteSize*key:], uint64(value[memorySliceAddrID]))
+		binary.LittleEndian.PutUint64(buffer[2*int64ByteSize*key+int64ByteSize:], uint64(value[memorySliceAddrOfAddrIntoFile]))
+	}
+
+	_, err = e.file.WriteAt(buffer, e.nodeWriteDataPosition)
+	if err != nil {
+		return
+	}
+
+	e.nodeWriteDataPosition += int64(len(buffer))
+	return
+}
+
+// findNodeByIDIntoBlock
+//
+// # English:
+//
+// Finds the block of the ID in the in-memory index, then reads and decodes the block.
+//
+// # Português:
+//
+// Encontra o bloco do ID no índice em memória, em seguida, lê e decodifica o bloco.
+func (e *Compress) findNodeByIDIntoBlock(id int64) (longitude, latitude float64, err error) {
+
+	// # English: first block whose first ID is greater than the ID sought, the ID is in the previous block
+	// # Português: primeiro bloco cujo primeiro ID é maior do que o ID procurado, o ID está no bloco anterior
+	i := sort.Search(len(e.memory), func(i int) bool { return e.memory[i][memorySliceAddrID] > id }) - 1
+	if i < 0 {
+		err = io.EOF
+		return
+	}
+
+	if i != e.cachedBlock {
+		err = e.decodeBlock(i)
+		if err != nil {
+			err = fmt.Errorf("findNodeByIDIntoBlock().error: decodeBlock(%v) function returned an error: %v", i, err)
+			return
+		}
+	}
+
+	k := sort.Search(len(e.cachedNodes), func(k int) bool { return e.cachedNodes[k][0] >= id })
+	if k == len(e.cachedNodes) || e.cachedNodes[k][0] != id {
+		err = io.EOF
+		return
+	}
+
+	longitude = float64(e.cachedNodes[k][1]) / decimalPlaces
+	latitude = float64(e.cachedNodes[k][2]) / decimalPlaces
+	return
+}
+
+// decodeBlock
+//
+// # English:
+//
+// Reads the block from the file and decodes its nodes into e.cachedNodes.
+//
+// # Português:
+//
+// Lê o bloco do arquivo e decodifica seus nodes em e.cachedNodes.
+func (e *Compress) decodeBlock(i int) (err error) {
+	leftBound := e.memory[i][memorySliceAddrOfAddrIntoFile]
+	rightBound := e.indexesAddress
+	if i+1 < len(e.memory) {
+		rightBound = e.memory[i+1][memorySliceAddrOfAddrIntoFile]
+	}
+
+	var block []byte
+	if e.mapped != nil && rightBound <= int64(len(e.mapped)) {
+		block = e.mapped[leftBound:rightBound]
+	} else {
+		block = make([]byte, rightBound-leftBound)
+		_, err = e.file.ReadAt(block, leftBound)
+		if err != nil {
+			return
+		}
+	}
+
+	e.cachedBlock = -1
+	e.cachedNodes, err = decodeNodes(block)
+	if err != nil {
+		return
+	}
+
+	e.cachedBlock = i
+	return
+}
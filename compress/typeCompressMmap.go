@@ -0,0 +1,146 @@
+package compress
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+)
+
+// OpenForSearchMmap
+//
+// # English:
+//
+// Same as OpenForSearch(), but maps the binary file read-only into memory and performs the search on the mapped bytes,
+// without one ReadAt() syscall per probe of the binary search.
+//
+//	Input:
+//	  path: Binary file path
+//
+//	Note:
+//	  * When mapping fails, the error is logged and the search falls back to the ReadAt() path.
+//	  * The mapped memory is released by Close().
+//
+// # Português:
+//
+// Igual a OpenForSearch(), mas mapeia o arquivo binário apenas para leitura na memória e faz a busca nos bytes
+// mapeados, sem uma syscall ReadAt() por passo da busca binária.
+//
+//	Entrada:
+//	  path: Caminho do arquivo binário
+//
+//	Nota:
+//	  * Quando o mapeamento falha, o erro é registrado no log e a busca volta a usar ReadAt().
+//	  * A memória mapeada é liberada por Close().
+func (e *Compress) OpenForSearchMmap(path string) (err error) {
+	e.unmap()
+
+	err = e.OpenForSearch(path)
+	if err != nil {
+		err = fmt.Errorf("Compress.OpenForSearchMmap().OpenForSearch().Error: %v", err)
+		return
+	}
+
+	var mapped []byte
+	mapped, err = mmapFile(e.file)
+	if err != nil {
+		log.Printf("Compress.OpenForSearchMmap().event: mmap failed, using ReadAt(): %v", err)
+		err = nil
+		return
+	}
+
+	e.mapped = mapped
+	return
+}
+
+// unmap
+//
+// # English:
+//
+// Releases the mapped memory, if any.
+//
+// # Português:
+//
+// Libera a memória mapeada, caso exista.
+func (e *Compress) unmap() {
+	if e.mapped == nil {
+		return
+	}
+
+	var err = munmapFile(e.mapped)
+	if err != nil {
+		log.Printf("Compress.unmap().error: %v", err)
+	}
+	e.mapped = nil
+}
+
+// findNodeByIDMmap
+//
+// # English:
+//
+// Version 1 search on the mapped bytes, same bounds as FindNodeByID(), but without disk access.
+//
+// # Português:
+//
+// Busca da versão 1 nos bytes mapeados, mesmos limites de FindNodeByID(), mas sem acesso ao disco.
+func (e *Compress) findNodeByIDMmap(id int64) (longitude, latitude float64, err error) {
+	var leftBound, rightBound int64
+
+	i := sort.Search(len(e.memory), func(i int) bool { return e.memory[i][memorySliceAddrID] >= id })
+	if i < len(e.memory) && e.memory[i][memorySliceAddrID] == id {
+		leftBound = e.memory[i][memorySliceAddrOfAddrIntoFile]
+		rightBound = leftBound
+	} else {
+		if i > 0 {
+			i--
+		}
+
+		if len(e.memory)-1 < i+1 {
+			leftBound = nodeDataPositionStartAtAddress
+			rightBound = (e.totalOfNodesInTmpFile-1)*nodeDataByteSize + nodeDataPositionStartAtAddress
+		} else {
+			leftBound = e.memory[i][memorySliceAddrOfAddrIntoFile]
+			rightBound = e.memory[i+1][memorySliceAddrOfAddrIntoFile]
+		}
+	}
+
+	if leftBound < nodeDataPositionStartAtAddress || rightBound+nodeDataByteSize > int64(len(e.mapped)) {
+		err = io.EOF
+		return
+	}
+
+	total := int((rightBound-leftBound)/nodeDataByteSize) + 1
+	k := sort.Search(total, func(k int) bool {
+		return int64(binary.LittleEndian.Uint64(e.mapped[leftBound+int64(k)*nodeDataByteSize:])) >= id
+	})
+
+	address := leftBound + int64(k)*nodeDataByteSize
+	if k == total || int64(binary.LittleEndian.Uint64(e.mapped[address:])) != id {
+		err = io.EOF
+		return
+	}
+
+	longitude = coordinateFromBytes(e.mapped[address+nodeIdByteSize:])
+	latitude = coordinateFromBytes(e.mapped[address+nodeIdByteSize+nodeCoordinateByteSize:])
+	return
+}
+
+// coordinateFromBytes
+//
+// # English:
+//
+// Same conversion as readCoordinate(), from 4 bytes with the sign in the most significant bit.
+//
+// # Português:
+//
+// Mesma conversão de readCoordinate(), a partir de 4 bytes com o sinal no bit mais significativo.
+func coordinateFromBytes(data []byte) (coordinate float64) {
+	value := binary.LittleEndian.Uint32(data)
+	coordinate = float64(value&0x7FFFFFFF) / decimalPlaces
+	if value&0x80000000 != 0 {
+		coordinate *= -1
+	}
+
+	return
+}
@@ -0,0 +1,85 @@
+package compress
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// compressScanBufferNodes
+//
+// # English:
+//
+// # Number of version 1 nodes read by each ReadAt() during the sequential scan
+//
+// # Português:
+//
+// Quantidade de nodes da versão 1 lidos por cada ReadAt() durante a leitura sequencial
+const compressScanBufferNodes = 4096
+
+// ForEachNode
+//
+// # English:
+//
+// Walks all the nodes of the file opened by OpenForSearch(), in ascending order of ID.
+//
+//	Input:
+//	  fn: function called for each node, returns false to stop the scan.
+//
+// # Português:
+//
+// Percorre todos os nodes do arquivo aberto por OpenForSearch(), em ordem crescente de ID.
+//
+//	Entrada:
+//	  fn: função chamada para cada node, devolve false para interromper a leitura.
+func (e *Compress) ForEachNode(fn func(id int64, longitude, latitude float64) bool) (err error) {
+	if e.version == headerVersion2 {
+		for i := range e.memory {
+			err = e.decodeBlock(i)
+			if err != nil {
+				err = fmt.Errorf("Compress.ForEachNode().decodeBlock(%v).Error: %v", i, err)
+				return
+			}
+
+			for _, node := range e.cachedNodes {
+				if !fn(node[0], float64(node[1])/decimalPlaces, float64(node[2])/decimalPlaces) {
+					return
+				}
+			}
+		}
+		return
+	}
+
+	var buffer = make([]byte, compressScanBufferNodes*nodeDataByteSize)
+	for read := int64(0); read < e.totalOfNodesInTmpFile; {
+		total := e.totalOfNodesInTmpFile - read
+		if total > compressScanBufferNodes {
+			total = compressScanBufferNodes
+		}
+
+		var data = buffer[:total*nodeDataByteSize]
+		address := nodeDataPositionStartAtAddress + read*nodeDataByteSize
+		if e.mapped != nil && address+int64(len(data)) <= int64(len(e.mapped)) {
+			data = e.mapped[address : address+int64(len(data))]
+		} else {
+			_, err = e.file.ReadAt(data, address)
+			if err != nil {
+				err = fmt.Errorf("Compress.ForEachNode().ReadAt(%v).Error: %v", address, err)
+				return
+			}
+		}
+
+		for k := int64(0); k != total; k++ {
+			record := data[k*nodeDataByteSize:]
+			id := int64(binary.LittleEndian.Uint64(record))
+			longitude := coordinateFromBytes(record[nodeIdByteSize:])
+			latitude := coordinateFromBytes(record[nodeIdByteSize+nodeCoordinateByteSize:])
+			if !fn(id, longitude, latitude) {
+				return
+			}
+		}
+
+		read += total
+	}
+
+	return
+}
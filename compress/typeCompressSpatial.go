@@ -0,0 +1,867 @@
+package compress
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"goosm/goosm"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Spatial side index file format
+//
+// # English:
+//
+//	Enabled by Compress.SetSpatialIndex(), written by MountIndexIntoFile() next to the node file, path + ".hilbert".
+//
+//	The world is divided into a grid of 2^order x 2^order cells, longitude by latitude, numbered along a Hilbert curve,
+//	so that neighboring cells have close numbers and a box becomes a few ranges of cell numbers.
+//
+//	Header: 32 bytes
+//	  magic and version: "GOSMH001", 8 bytes
+//	  order: 8 bytes
+//	  total of non-empty cells: 8 bytes
+//	  directory address: 8 bytes
+//
+//	Data block:
+//	  For each non-empty cell, in ascending order of cell, the IDs of its nodes in ascending order, as zigzag varint of
+//	  the difference to the previous ID of the same cell.
+//
+//	Directory block:
+//	  For each non-empty cell, cell number: 8 bytes and address of its IDs: 8 bytes. The end of a cell is the start of
+//	  the next one, or the directory address for the last cell.
+//
+//	The header is written last, so a side file without header is ignored.
+//
+// # Português:
+//
+//	Habilitado por Compress.SetSpatialIndex(), escrito por MountIndexIntoFile() ao lado do arquivo de nodes,
+//	path + ".hilbert".
+//
+//	O mundo é dividido em uma grade de 2^order x 2^order células, longitude por latitude, numeradas ao longo de uma
+//	curva de Hilbert, de modo que células vizinhas têm números próximos e uma caixa se torna poucos intervalos de
+//	números de células.
+//
+//	Cabeçalho: 32 bytes
+//	  bytes mágicos e versão: "GOSMH001", 8 bytes
+//	  order: 8 bytes
+//	  total de células não vazias: 8 bytes
+//	  endereço do diretório: 8 bytes
+//
+//	Bloco de dados:
+//	  Para cada célula não vazia, em ordem crescente de célula, os IDs de seus nodes em ordem crescente, como zigzag
+//	  varint da diferença para o ID anterior da mesma célula.
+//
+//	Bloco de diretório:
+//	  Para cada célula não vazia, número da célula: 8 bytes e endereço de seus IDs: 8 bytes. O fim de uma célula é o
+//	  início da próxima, ou o endereço do diretório para a última célula.
+//
+//	O cabeçalho é escrito por último, assim um arquivo lateral sem cabeçalho é ignorado.
+
+const (
+
+	// spatialFileExtension
+	//
+	// # English:
+	//
+	// Extension added to the path of the node file for the spatial side index
+	//
+	// # Português:
+	//
+	// Extensão adicionada ao caminho do arquivo de nodes para o índice espacial lateral
+	spatialFileExtension = ".hilbert"
+
+	// spatialHeaderVersion
+	//
+	// # English:
+	//
+	// Magic bytes and version of the spatial side index
+	//
+	// # Português:
+	//
+	// Bytes mágicos e versão do índice espacial lateral
+	spatialHeaderVersion = "GOSMH001"
+
+	// spatialHeaderByteSize
+	//
+	// # English:
+	//
+	// Number of bytes of the header of the spatial side index
+	//
+	// # Português:
+	//
+	// Quantidade de bytes do cabeçalho do índice espacial lateral
+	spatialHeaderByteSize = 4 * int64ByteSize
+
+	// SpatialIndexDefaultOrder
+	//
+	// # English:
+	//
+	// Grid of 65536 x 65536 cells, about 600m x 300m at the equator
+	//
+	// # Português:
+	//
+	// Grade de 65536 x 65536 células, cerca de 600m x 300m no equador
+	SpatialIndexDefaultOrder = 16
+
+	// spatialIndexMaxOrder
+	//
+	// # English:
+	//
+	// Greatest order, the cell number must fit in 64 bits
+	//
+	// # Português:
+	//
+	// Maior ordem, o número da célula deve caber em 64 bits
+	spatialIndexMaxOrder = 31
+
+	// spatialCellByteSize
+	//
+	// # English:
+	//
+	// Number of bytes of a cell and ID pair, in memory and in the run files
+	//
+	// # Português:
+	//
+	// Quantidade de bytes de um par célula e ID, na memória e nos arquivos de execução
+	spatialCellByteSize = 2 * int64ByteSize
+
+	// spatialDefaultMemoryLimit
+	//
+	// # English:
+	//
+	// Default memory used by the cell and ID pairs before a sorted run is spilled to disk, 256MB, 16M nodes
+	//
+	// # Português:
+	//
+	// Memória padrão usada pelos pares célula e ID antes de uma execução ordenada ser despejada no disco, 256MB, 16M
+	// nodes
+	spatialDefaultMemoryLimit = 256 * 1024 * 1024
+)
+
+// SetSpatialIndex
+//
+// # English:
+//
+// Enables the spatial side index, written by MountIndexIntoFile() and used by NodesInBox(). Must be called after Init()
+// and before the first node is written.
+//
+//	Input:
+//	  order: grid of 2^order x 2^order cells, from 1 to 31, SpatialIndexDefaultOrder is a good start, 0 disables.
+//
+//	Note:
+//	  * The cell and the ID of each node, 16 bytes per node, are kept in memory up to SetSpatialMemoryLimit(), then
+//	    sorted and spilled to a temporary run file next to the node file. MountIndexIntoFile() merges the runs into the
+//	    side index and removes them.
+//	  * Only the nodes written after SetSpatialIndex() are indexed, MountIndexIntoFile() without them, as in
+//	    ResizeBlock(), keeps the side index already written.
+//
+// # Português:
+//
+// Habilita o índice espacial lateral, escrito por MountIndexIntoFile() e usado por NodesInBox(). Deve ser chamada
+// depois de Init() e antes do primeiro node ser escrito.
+//
+//	Entrada:
+//	  order: grade de 2^order x 2^order células, de 1 a 31, SpatialIndexDefaultOrder é um bom começo, 0 desabilita.
+//
+//	Nota:
+//	  * A célula e o ID de cada node, 16 bytes por node, são mantidos em memória até SetSpatialMemoryLimit(), depois
+//	    ordenados e despejados em um arquivo de execução temporário ao lado do arquivo de nodes. MountIndexIntoFile()
+//	    mescla as execuções no índice lateral e as remove.
+//	  * Apenas os nodes escritos depois de SetSpatialIndex() são indexados, MountIndexIntoFile() sem eles, como em
+//	    ResizeBlock(), mantém o índice lateral já escrito.
+func (e *Compress) SetSpatialIndex(order int) (err error) {
+	if order < 0 || order > spatialIndexMaxOrder {
+		err = fmt.Errorf("Compress.SetSpatialIndex().error: order must be between 0 and %v", spatialIndexMaxOrder)
+		return
+	}
+
+	e.removeSpatialRuns()
+	e.spatialOrder = int64(order)
+	e.spatialCells = make([][2]int64, 0)
+	e.spatialCollect = order != 0
+	return
+}
+
+// SetSpatialMemoryLimit
+//
+// # English:
+//
+// Defines the maximum memory, in bytes, used by the cell and ID pairs of the spatial side index before a sorted run is
+// spilled to disk. Each node takes 16 bytes. Default 256MB.
+//
+// # Português:
+//
+// Define a memória máxima, em bytes, usada pelos pares célula e ID do índice espacial lateral antes de uma execução
+// ordenada ser despejada no disco. Cada node ocupa 16 bytes. Padrão 256MB.
+func (e *Compress) SetSpatialMemoryLimit(memoryLimit int64) {
+	e.spatialMemoryLimit = memoryLimit
+}
+
+// addNodeToSpatialIndex
+//
+// # English:
+//
+// Keeps the cell and the ID of the node written, when the spatial side index is enabled, and spills a sorted run when
+// the memory limit is reached.
+//
+// # Português:
+//
+// Guarda a célula e o ID do node escrito, quando o índice espacial lateral está habilitado, e despeja uma execução
+// ordenada quando o limite de memória é atingido.
+func (e *Compress) addNodeToSpatialIndex(id int64, longitude, latitude float64) (err error) {
+	if !e.spatialCollect {
+		return
+	}
+
+	x, y := spatialCellXY(e.spatialOrder, longitude, latitude)
+	e.spatialCells = append(e.spatialCells, [2]int64{int64(hilbertXYToD(e.spatialOrder, x, y)), id})
+
+	var memoryLimit = e.spatialMemoryLimit
+	if memoryLimit <= 0 {
+		memoryLimit = spatialDefaultMemoryLimit
+	}
+
+	if int64(len(e.spatialCells))*spatialCellByteSize >= memoryLimit {
+		err = e.spillSpatialCells()
+		if err != nil {
+			err = fmt.Errorf("Compress.addNodeToSpatialIndex().spillSpatialCells().Error: %v", err)
+		}
+	}
+	return
+}
+
+// sortSpatialCells
+//
+// # English:
+//
+// Sorts the cell and ID pairs in memory by cell and then by ID.
+//
+// # Português:
+//
+// Ordena os pares célula e ID na memória por célula e depois por ID.
+func (e *Compress) sortSpatialCells() {
+	sort.Slice(e.spatialCells, func(i, j int) bool {
+		if e.spatialCells[i][0] != e.spatialCells[j][0] {
+			return e.spatialCells[i][0] < e.spatialCells[j][0]
+		}
+		return e.spatialCells[i][1] < e.spatialCells[j][1]
+	})
+}
+
+// spillSpatialCells
+//
+// # English:
+//
+// Sorts the cell and ID pairs in memory and writes them to a new temporary run file, next to the node file.
+//
+// # Português:
+//
+// Ordena os pares célula e ID na memória e os escreve em um novo arquivo de execução temporário, ao lado do arquivo de
+// nodes.
+func (e *Compress) spillSpatialCells() (err error) {
+	e.sortSpatialCells()
+
+	var file *os.File
+	file, err = os.CreateTemp(filepath.Dir(e.path), filepath.Base(e.path)+spatialFileExtension+".*.tmp")
+	if err != nil {
+		return
+	}
+	e.spatialRuns = append(e.spatialRuns, file.Name())
+
+	var writer = bufio.NewWriter(file)
+	var data = make([]byte, spatialCellByteSize)
+	for _, pair := range e.spatialCells {
+		binary.LittleEndian.PutUint64(data[0:], uint64(pair[0]))
+		binary.LittleEndian.PutUint64(data[int64ByteSize:], uint64(pair[1]))
+		_, err = writer.Write(data)
+		if err != nil {
+			_ = file.Close()
+			return
+		}
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		_ = file.Close()
+		return
+	}
+
+	err = file.Close()
+	e.spatialCells = e.spatialCells[:0]
+	return
+}
+
+// removeSpatialRuns
+//
+// # English:
+//
+// Removes the temporary run files of the spatial side index.
+//
+// # Português:
+//
+// Remove os arquivos de execução temporários do índice espacial lateral.
+func (e *Compress) removeSpatialRuns() {
+	for _, path := range e.spatialRuns {
+		var err = os.Remove(path)
+		if err != nil && !os.IsNotExist(err) {
+			log.Printf("Compress.removeSpatialRuns().error: %v", err)
+		}
+	}
+	e.spatialRuns = nil
+}
+
+// mountSpatialIndex
+//
+// # English:
+//
+// Merges the sorted runs and the cells in memory into the spatial side index, path + ".hilbert", only when cells were
+// collected since SetSpatialIndex().
+//
+// # Português:
+//
+// Mescla as execuções ordenadas e as células na memória no índice espacial lateral, path + ".hilbert", apenas quando
+// células foram coletadas desde SetSpatialIndex().
+func (e *Compress) mountSpatialIndex() (err error) {
+	if !e.spatialCollect {
+		return
+	}
+
+	if e.path == "" {
+		err = errors.New("Compress.mountSpatialIndex().error: the path of the node file is unknown, use Create()")
+		return
+	}
+
+	e.sortSpatialCells()
+
+	var sources = make([]spatialRunSource, 0, len(e.spatialRuns)+1)
+	defer func() {
+		for _, source := range sources {
+			if source.file != nil {
+				_ = source.file.Close()
+			}
+		}
+		e.removeSpatialRuns()
+		e.spatialCells = nil
+		e.spatialCollect = false
+	}()
+
+	for _, path := range e.spatialRuns {
+		var run *os.File
+		run, err = os.Open(path)
+		if err != nil {
+			err = fmt.Errorf("Compress.mountSpatialIndex().Open().Error: %v", err)
+			return
+		}
+		sources = append(sources, spatialRunSource{file: run, reader: bufio.NewReaderSize(run, sortWriterReadBufferSize)})
+	}
+	sources = append(sources, spatialRunSource{buffer: e.spatialCells})
+
+	var queue = make(spatialQueue, 0, len(sources))
+	for key := range sources {
+		var pair [2]int64
+		var found bool
+		pair, found, err = sources[key].next()
+		if err != nil {
+			err = fmt.Errorf("Compress.mountSpatialIndex().next().Error: %v", err)
+			return
+		}
+
+		if found {
+			queue = append(queue, spatialQueueItem{pair: pair, run: key})
+		}
+	}
+	heap.Init(&queue)
+
+	var file *os.File
+	file, err = os.Create(e.path + spatialFileExtension)
+	if err != nil {
+		err = fmt.Errorf("Compress.mountSpatialIndex().Create().Error: %v", err)
+		return
+	}
+	defer file.Close()
+
+	var writer = bufio.NewWriter(file)
+	var address int64 = spatialHeaderByteSize
+	var directory = make([]byte, 0)
+	var data = make([]byte, 0, binary.MaxVarintLen64)
+	var lastCell, lastID int64 = -1, 0
+
+	_, err = writer.Write(make([]byte, spatialHeaderByteSize))
+	if err != nil {
+		return
+	}
+
+	var totalOfCells int64
+	for queue.Len() != 0 {
+		item := heap.Pop(&queue).(spatialQueueItem)
+		pair := item.pair
+
+		var next [2]int64
+		var found bool
+		next, found, err = sources[item.run].next()
+		if err != nil {
+			err = fmt.Errorf("Compress.mountSpatialIndex().next().Error: %v", err)
+			return
+		}
+
+		if found {
+			heap.Push(&queue, spatialQueueItem{pair: next, run: item.run})
+		}
+
+		if pair[0] != lastCell {
+			directory = binary.LittleEndian.AppendUint64(directory, uint64(pair[0]))
+			directory = binary.LittleEndian.AppendUint64(directory, uint64(address))
+			lastCell = pair[0]
+			lastID = 0
+			totalOfCells++
+		}
+
+		data = binary.AppendVarint(data[:0], pair[1]-lastID)
+		lastID = pair[1]
+
+		_, err = writer.Write(data)
+		if err != nil {
+			return
+		}
+		address += int64(len(data))
+	}
+
+	_, err = writer.Write(directory)
+	if err != nil {
+		return
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		return
+	}
+
+	var header = make([]byte, 0, spatialHeaderByteSize)
+	header = append(header, spatialHeaderVersion...)
+	header = binary.LittleEndian.AppendUint64(header, uint64(e.spatialOrder))
+	header = binary.LittleEndian.AppendUint64(header, uint64(totalOfCells))
+	header = binary.LittleEndian.AppendUint64(header, uint64(address))
+	_, err = file.WriteAt(header, 0)
+	return
+}
+
+// spatialRunSource
+//
+// # English:
+//
+// Sorted run of cell and ID pairs, in a temporary file or in memory.
+//
+// # Português:
+//
+// Execução ordenada de pares célula e ID, em um arquivo temporário ou na memória.
+type spatialRunSource struct {
+	file   *os.File
+	reader *bufio.Reader
+	buffer [][2]int64
+	data   []byte
+}
+
+// next
+//
+// # English:
+//
+// Returns the next pair of the run, found is false at the end of the run.
+//
+// # Português:
+//
+// Devolve o próximo par da execução, found é false no fim da execução.
+func (e *spatialRunSource) next() (pair [2]int64, found bool, err error) {
+	if e.reader == nil {
+		if len(e.buffer) == 0 {
+			return
+		}
+
+		pair = e.buffer[0]
+		e.buffer = e.buffer[1:]
+		found = true
+		return
+	}
+
+	if e.data == nil {
+		e.data = make([]byte, spatialCellByteSize)
+	}
+
+	_, err = io.ReadFull(e.reader, e.data)
+	if err == io.EOF {
+		err = nil
+		return
+	}
+
+	if err != nil {
+		return
+	}
+
+	pair[0] = int64(binary.LittleEndian.Uint64(e.data[0:]))
+	pair[1] = int64(binary.LittleEndian.Uint64(e.data[int64ByteSize:]))
+	found = true
+	return
+}
+
+// spatialQueueItem
+//
+// # English:
+//
+// Pair in the merge queue and the run it came from.
+//
+// # Português:
+//
+// Par na fila de junção e a execução de onde veio.
+type spatialQueueItem struct {
+	pair [2]int64
+	run  int
+}
+
+// spatialQueue
+//
+// # English:
+//
+// Min-heap of pairs, ordered by cell and then by ID.
+//
+// # Português:
+//
+// Min-heap de pares, ordenado por célula e depois por ID.
+type spatialQueue []spatialQueueItem
+
+func (e spatialQueue) Len() int { return len(e) }
+
+func (e spatialQueue) Less(i, j int) bool {
+	if e[i].pair[0] != e[j].pair[0] {
+		return e[i].pair[0] < e[j].pair[0]
+	}
+	return e[i].pair[1] < e[j].pair[1]
+}
+
+func (e spatialQueue) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+
+func (e *spatialQueue) Push(x any) { *e = append(*e, x.(spatialQueueItem)) }
+
+func (e *spatialQueue) Pop() any {
+	old := *e
+	item := old[len(old)-1]
+	*e = old[:len(old)-1]
+	return item
+}
+
+// openSpatialIndex
+//
+// # English:
+//
+// Opens the spatial side index and loads its directory into memory, only once.
+//
+// # Português:
+//
+// Abre o índice espacial lateral e carrega o seu diretório na memória, apenas uma vez.
+func (e *Compress) openSpatialIndex() (err error) {
+	if e.spatialFile != nil {
+		return
+	}
+
+	if e.path == "" {
+		err = errors.New("the path of the node file is unknown")
+		return
+	}
+
+	var file *os.File
+	file, err = os.Open(e.path + spatialFileExtension)
+	if err != nil {
+		return
+	}
+
+	var header = make([]byte, spatialHeaderByteSize)
+	_, err = file.ReadAt(header, 0)
+	if err == nil && string(header[:headerVersionByteSize]) != spatialHeaderVersion {
+		err = errors.New("the spatial side index is incomplete or has an unknown version")
+	}
+	if err != nil {
+		_ = file.Close()
+		return
+	}
+
+	order := int64(binary.LittleEndian.Uint64(header[8:]))
+	totalOfCells := int64(binary.LittleEndian.Uint64(header[16:]))
+	directoryAddress := int64(binary.LittleEndian.Uint64(header[24:]))
+
+	var data = make([]byte, 2*int64ByteSize*totalOfCells)
+	_, err = file.ReadAt(data, directoryAddress)
+	if err != nil {
+		_ = file.Close()
+		return
+	}
+
+	e.spatialDirectory = make([][2]int64, totalOfCells)
+	for key := range e.spatialDirectory {
+		e.spatialDirectory[key][0] = int64(binary.LittleEndian.Uint64(data[2*int64ByteSize*key:]))
+		e.spatialDirectory[key][1] = int64(binary.LittleEndian.Uint64(data[2*int64ByteSize*key+int64ByteSize:]))
+	}
+
+	e.spatialFile = file
+	e.spatialOrder = order
+	e.spatialDirectoryAddress = directoryAddress
+	return
+}
+
+// closeSpatialIndex
+//
+// # English:
+//
+// Closes the spatial side index, if open.
+//
+// # Português:
+//
+// Fecha o índice espacial lateral, caso esteja aberto.
+func (e *Compress) closeSpatialIndex() {
+	if e.spatialFile == nil {
+		return
+	}
+
+	_ = e.spatialFile.Close()
+	e.spatialFile = nil
+	e.spatialDirectory = nil
+}
+
+// NodesInBox
+//
+// # English:
+//
+// Streams all the nodes inside the box, without touching the database, using the spatial side index.
+//
+//	Input:
+//	  box: bottom left and upper right corners, in degrees;
+//	  fn: function called for each node inside the box, edges included, returns false to stop.
+//
+//	Note:
+//	  * The node file must be open for search, after OpenForSearch() or after MountIndexIntoFile(), ReadFileHeaders()
+//	    and IndexToMemory().
+//	  * The nodes are delivered in order of Hilbert cell, and in order of ID inside each cell.
+//
+// # Português:
+//
+// Entrega todos os nodes dentro da caixa, sem tocar no banco de dados, usando o índice espacial lateral.
+//
+//	Entrada:
+//	  box: cantos inferior esquerdo e superior direito, em graus;
+//	  fn: função chamada para cada node dentro da caixa, bordas incluídas, devolve false para interromper.
+//
+//	Nota:
+//	  * O arquivo de nodes deve estar aberto para busca, depois de OpenForSearch() ou depois de MountIndexIntoFile(),
+//	    ReadFileHeaders() e IndexToMemory().
+//	  * Os nodes são entregues em ordem de célula de Hilbert, e em ordem de ID dentro de cada célula.
+func (e *Compress) NodesInBox(box goosm.Box, fn func(id int64, longitude, latitude float64) bool) (err error) {
+	err = e.openSpatialIndex()
+	if err != nil {
+		err = fmt.Errorf("Compress.NodesInBox().openSpatialIndex().Error: %v", err)
+		return
+	}
+
+	west, south := box.BottomLeft.Loc[goosm.Longitude], box.BottomLeft.Loc[goosm.Latitude]
+	east, north := box.UpperRight.Loc[goosm.Longitude], box.UpperRight.Loc[goosm.Latitude]
+	if west > east || south > north {
+		err = errors.New("Compress.NodesInBox().error: the bottom left corner must be below and to the left of the upper right corner")
+		return
+	}
+
+	minX, minY := spatialCellXY(e.spatialOrder, west, south)
+	maxX, maxY := spatialCellXY(e.spatialOrder, east, north)
+
+	for _, cellRange := range hilbertRanges(e.spatialOrder, minX, minY, maxX, maxY) {
+		k := sort.Search(len(e.spatialDirectory), func(k int) bool { return e.spatialDirectory[k][0] >= int64(cellRange[0]) })
+		for ; k < len(e.spatialDirectory) && e.spatialDirectory[k][0] < int64(cellRange[1]); k++ {
+			var ids []int64
+			ids, err = e.readSpatialCell(k)
+			if err != nil {
+				err = fmt.Errorf("Compress.NodesInBox().readSpatialCell(%v).Error: %v", k, err)
+				return
+			}
+
+			for _, id := range ids {
+				var longitude, latitude float64
+				longitude, latitude, err = e.FindNodeByID(id)
+				if err != nil {
+					err = fmt.Errorf("Compress.NodesInBox().FindNodeByID(%v).Error: %v", id, err)
+					return
+				}
+
+				// # English: the cells of the edge of the box are only partially inside it
+				// # Português: as células da borda da caixa estão apenas parcialmente dentro dela
+				if longitude < west || longitude > east || latitude < south || latitude > north {
+					continue
+				}
+
+				if !fn(id, longitude, latitude) {
+					return
+				}
+			}
+		}
+	}
+
+	return
+}
+
+// readSpatialCell
+//
+// # English:
+//
+// Reads and decodes the IDs of the cell k of the directory.
+//
+// # Português:
+//
+// Lê e decodifica os IDs da célula k do diretório.
+func (e *Compress) readSpatialCell(k int) (ids []int64, err error) {
+	leftBound := e.spatialDirectory[k][1]
+	rightBound := e.spatialDirectoryAddress
+	if k+1 < len(e.spatialDirectory) {
+		rightBound = e.spatialDirectory[k+1][1]
+	}
+
+	var data = make([]byte, rightBound-leftBound)
+	_, err = e.spatialFile.ReadAt(data, leftBound)
+	if err != nil && err != io.EOF {
+		return
+	}
+	err = nil
+
+	var id int64
+	for len(data) != 0 {
+		delta, n := binary.Varint(data)
+		if n <= 0 {
+			err = errors.New("invalid varint")
+			return
+		}
+
+		id += delta
+		ids = append(ids, id)
+		data = data[n:]
+	}
+
+	return
+}
+
+// spatialCellXY
+//
+// # English:
+//
+// Returns the column and the row of the grid cell of the coordinate.
+//
+// # Português:
+//
+// Devolve a coluna e a linha da célula da grade da coordenada.
+func spatialCellXY(order int64, longitude, latitude float64) (x, y uint64) {
+	var side = uint64(1) << order
+	var cell = func(value, minimum, size float64) uint64 {
+		position := (value - minimum) / size * float64(side)
+		if position < 0 {
+			return 0
+		}
+		if position >= float64(side) {
+			return side - 1
+		}
+		return uint64(position)
+	}
+
+	return cell(longitude, -180.0, 360.0), cell(latitude, -90.0, 180.0)
+}
+
+// hilbertXYToD
+//
+// # English:
+//
+// Converts the column and the row of the cell into the distance along the Hilbert curve of a grid 2^order x 2^order.
+//
+// # Português:
+//
+// Converte a coluna e a linha da célula na distância ao longo da curva de Hilbert de uma grade 2^order x 2^order.
+func hilbertXYToD(order int64, x, y uint64) (d uint64) {
+	for s := uint64(1) << (order - 1); s > 0; s /= 2 {
+		var rx, ry uint64
+		if x&s > 0 {
+			rx = 1
+		}
+		if y&s > 0 {
+			ry = 1
+		}
+		d += s * s * ((3 * rx) ^ ry)
+
+		// # English: rotates the quadrant
+		// # Português: gira o quadrante
+		if ry == 0 {
+			if rx == 1 {
+				x = s - 1 - x&(s-1)
+				y = s - 1 - y&(s-1)
+			}
+			x, y = y, x
+		}
+	}
+
+	return
+}
+
+// hilbertRanges
+//
+// # English:
+//
+// Returns the ranges of cell numbers, [start, end), that cover the cells from minX, minY to maxX, maxY, inclusive.
+//
+// Each aligned quadrant of size s of the grid is a contiguous range of s*s cell numbers, so the grid is split as a
+// quadtree, quadrants fully inside the box become one range and quadrants on the edge are split again.
+//
+// # Português:
+//
+// Devolve os intervalos de números de células, [início, fim), que cobrem as células de minX, minY até maxX, maxY,
+// inclusive.
+//
+// Cada quadrante alinhado de tamanho s da grade é um intervalo contíguo de s*s números de células, assim a grade é
+// dividida como uma quadtree, quadrantes totalmente dentro da caixa viram um intervalo e quadrantes na borda são
+// divididos novamente.
+func hilbertRanges(order int64, minX, minY, maxX, maxY uint64) (ranges [][2]uint64) {
+	var split func(x, y, size uint64)
+	split = func(x, y, size uint64) {
+		if x > maxX || y > maxY || x+size-1 < minX || y+size-1 < minY {
+			return
+		}
+
+		if x >= minX && y >= minY && x+size-1 <= maxX && y+size-1 <= maxY {
+			area := size * size
+			start := hilbertXYToD(order, x, y) / area * area
+			if len(ranges) != 0 && ranges[len(ranges)-1][1] == start {
+				ranges[len(ranges)-1][1] = start + area
+				return
+			}
+			ranges = append(ranges, [2]uint64{start, start + area})
+			return
+		}
+
+		half := size / 2
+		split(x, y, half)
+		split(x+half, y, half)
+		split(x, y+half, half)
+		split(x+half, y+half, half)
+	}
+	split(0, 0, uint64(1)<<order)
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+
+	var merged = ranges[:0]
+	for _, value := range ranges {
+		if len(merged) != 0 && merged[len(merged)-1][1] >= value[0] {
+			if value[1] > merged[len(merged)-1][1] {
+				merged[len(merged)-1][1] = value[1]
+			}
+			continue
+		}
+		merged = append(merged, value)
+	}
+
+	return merged
+}
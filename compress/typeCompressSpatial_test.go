@@ -0,0 +1,314 @@
+package compress
+
+import (
+	"goosm/goosm"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestCompress_NodesInBox
+//
+// English:
+//
+// # Writes random nodes with the spatial side index and compares NodesInBox() with a linear search over the same nodes
+//
+// Português:
+//
+// Escreve nodes aleatórios com o índice espacial lateral e compara NodesInBox() com uma busca linear sobre os mesmos
+// nodes
+func TestCompress_NodesInBox(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.Remove("./test.node.spatial.tmp")
+		_ = os.Remove("./test.node.spatial.tmp" + spatialFileExtension)
+	})
+
+	var err error
+	var random = rand.New(rand.NewSource(1))
+	var nodeList = make([]Node, 0)
+	for id := int64(1); id <= 5000; id++ {
+		nodeList = append(nodeList, Node{
+			ID:  id,
+			Lon: float64(random.Int63n(3600000000)-1800000000) / 10000000,
+			Lat: float64(random.Int63n(1800000000)-900000000) / 10000000,
+		})
+	}
+
+	for _, version := range []int{1, 2} {
+		compress := Compress{}
+		compress.Init(100)
+		_ = compress.SetFormatVersion(version)
+		err = compress.SetSpatialIndex(8)
+		if err != nil {
+			t.Logf("set spatial index error: %v", err)
+			t.FailNow()
+		}
+
+		err = compress.Create("./test.node.spatial.tmp")
+		if err != nil {
+			t.Logf("open file error: %v", err)
+			t.FailNow()
+		}
+
+		for _, node := range nodeList {
+			err = compress.WriteNode(node.ID, node.Lon, node.Lat)
+			if err != nil {
+				t.Logf("write node error: %v", err)
+				t.FailNow()
+			}
+		}
+
+		err = compress.WriteFileHeaders()
+		if err != nil {
+			t.Logf("write header error: %v", err)
+			t.FailNow()
+		}
+
+		err = compress.MountIndexIntoFile()
+		if err != nil {
+			t.Logf("mount index error: %v", err)
+			t.FailNow()
+		}
+		compress.Close()
+
+		compress = Compress{}
+		compress.Init(0)
+		err = compress.OpenForSearch("./test.node.spatial.tmp")
+		if err != nil {
+			t.Logf("open for search error: %v", err)
+			t.FailNow()
+		}
+
+		var boxList = [][4]float64{
+			{-46.8, -23.7, -46.3, -23.3},
+			{-10, -10, 10, 10},
+			{100, 40, 179.9, 89},
+			{-180, -90, 180, 90},
+		}
+
+		for _, corners := range boxList {
+			var box goosm.Box
+			box.BottomLeft.Loc = [2]float64{corners[0], corners[1]}
+			box.UpperRight.Loc = [2]float64{corners[2], corners[3]}
+
+			var want = make(map[int64]bool)
+			for _, node := range nodeList {
+				if node.Lon >= corners[0] && node.Lon <= corners[2] && node.Lat >= corners[1] && node.Lat <= corners[3] {
+					want[node.ID] = true
+				}
+			}
+
+			var found = make(map[int64]bool)
+			err = compress.NodesInBox(box, func(id int64, lon, lat float64) bool {
+				if lon != nodeList[id-1].Lon || lat != nodeList[id-1].Lat {
+					t.Logf("version %v: node %v: wrong coordinate: %v, %v", version, id, lon, lat)
+					t.FailNow()
+				}
+				found[id] = true
+				return true
+			})
+			if err != nil {
+				t.Logf("nodes in box error: %v", err)
+				t.FailNow()
+			}
+
+			if len(found) != len(want) {
+				t.Logf("version %v: box %v: %v nodes found, %v expected", version, corners, len(found), len(want))
+				t.FailNow()
+			}
+
+			for id := range want {
+				if !found[id] {
+					t.Logf("version %v: box %v: node %v not found", version, corners, id)
+					t.FailNow()
+				}
+			}
+		}
+
+		var total = 0
+		var box goosm.Box
+		box.BottomLeft.Loc = [2]float64{-180, -90}
+		box.UpperRight.Loc = [2]float64{180, 90}
+		err = compress.NodesInBox(box, func(id int64, lon, lat float64) bool {
+			total++
+			return total != 10
+		})
+		if err != nil || total != 10 {
+			t.Logf("version %v: the iterator did not stop: %v, %v", version, total, err)
+			t.FailNow()
+		}
+		compress.Close()
+	}
+}
+
+// TestHilbertRanges
+//
+// English:
+//
+// # Compares the cells covered by hilbertRanges() with all the cells of the box, cell by cell
+//
+// Português:
+//
+// Compara as células cobertas por hilbertRanges() com todas as células da caixa, célula a célula
+func TestHilbertRanges(t *testing.T) {
+	const order = 5
+	var seen = make(map[uint64]bool)
+	for x := uint64(0); x != 1<<order; x++ {
+		for y := uint64(0); y != 1<<order; y++ {
+			d := hilbertXYToD(order, x, y)
+			if seen[d] || d >= 1<<(2*order) {
+				t.Logf("invalid cell number %v", d)
+				t.FailNow()
+			}
+			seen[d] = true
+		}
+	}
+
+	var boxList = [][4]uint64{{0, 0, 31, 31}, {3, 5, 17, 9}, {10, 10, 10, 10}, {0, 30, 31, 31}}
+	for _, box := range boxList {
+		var want = make(map[uint64]bool)
+		for x := box[0]; x <= box[2]; x++ {
+			for y := box[1]; y <= box[3]; y++ {
+				want[hilbertXYToD(order, x, y)] = true
+			}
+		}
+
+		var total = 0
+		for _, cellRange := range hilbertRanges(order, box[0], box[1], box[2], box[3]) {
+			for d := cellRange[0]; d != cellRange[1]; d++ {
+				if !want[d] {
+					t.Logf("box %v: cell %v outside the box", box, d)
+					t.FailNow()
+				}
+				total++
+			}
+		}
+
+		if total != len(want) {
+			t.Logf("box %v: %v cells covered, %v expected", box, total, len(want))
+			t.FailNow()
+		}
+	}
+}
+
+// TestCompress_spatialRuns
+//
+// English:
+//
+// Writes nodes with a memory limit of 100 pairs, so the spatial side index is merged from sorted runs on disk, and
+// calls ResizeBlock() after NodesInBox(), which must keep the side index.
+//
+// Português:
+//
+// Escreve nodes com um limite de memória de 100 pares, assim o índice espacial lateral é mesclado a partir de
+// execuções ordenadas no disco, e chama ResizeBlock() depois de NodesInBox(), que deve manter o índice lateral.
+func TestCompress_spatialRuns(t *testing.T) {
+	var dir = t.TempDir()
+	var path = filepath.Join(dir, "test.node.spatial.tmp")
+
+	var err error
+	var random = rand.New(rand.NewSource(2))
+	var nodeList = make([]Node, 0)
+	for id := int64(1); id <= 1000; id++ {
+		nodeList = append(nodeList, Node{
+			ID:  id,
+			Lon: float64(random.Int63n(3600000000)-1800000000) / 10000000,
+			Lat: float64(random.Int63n(1800000000)-900000000) / 10000000,
+		})
+	}
+
+	compress := Compress{}
+	compress.Init(100)
+	compress.SetSpatialMemoryLimit(100 * spatialCellByteSize)
+	err = compress.SetSpatialIndex(8)
+	if err != nil {
+		t.Logf("set spatial index error: %v", err)
+		t.FailNow()
+	}
+
+	err = compress.Create(path)
+	if err != nil {
+		t.Logf("open file error: %v", err)
+		t.FailNow()
+	}
+
+	for _, node := range nodeList {
+		err = compress.WriteNode(node.ID, node.Lon, node.Lat)
+		if err != nil {
+			t.Logf("write node error: %v", err)
+			t.FailNow()
+		}
+	}
+
+	if len(compress.spatialRuns) != 10 || len(compress.spatialCells) != 0 {
+		t.Logf("runs error: %v runs, %v pairs in memory", len(compress.spatialRuns), len(compress.spatialCells))
+		t.FailNow()
+	}
+
+	err = compress.WriteFileHeaders()
+	if err == nil {
+		err = compress.MountIndexIntoFile()
+	}
+	if err == nil {
+		err = compress.ReadFileHeaders()
+	}
+	if err == nil {
+		err = compress.IndexToMemory()
+	}
+	if err != nil {
+		t.Logf("mount index error: %v", err)
+		t.FailNow()
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.tmp"))
+	if len(files) != 1 {
+		t.Logf("the run files were not removed: %v", files)
+		t.FailNow()
+	}
+
+	var box goosm.Box
+	box.BottomLeft.Loc = [2]float64{-180, -90}
+	box.UpperRight.Loc = [2]float64{180, 90}
+	var countNodes = func() (total int) {
+		err = compress.NodesInBox(box, func(id int64, lon, lat float64) bool {
+			if lon != nodeList[id-1].Lon || lat != nodeList[id-1].Lat {
+				t.Logf("node %v: wrong coordinate: %v, %v", id, lon, lat)
+				t.FailNow()
+			}
+			total++
+			return true
+		})
+		if err != nil {
+			t.Logf("nodes in box error: %v", err)
+			t.FailNow()
+		}
+		return
+	}
+
+	if total := countNodes(); total != len(nodeList) {
+		t.Logf("%v nodes found, %v expected", total, len(nodeList))
+		t.FailNow()
+	}
+
+	err = compress.ResizeBlock(50)
+	if err != nil {
+		t.Logf("resize block error: %v", err)
+		t.FailNow()
+	}
+	compress.Close()
+
+	compress = Compress{}
+	compress.Init(0)
+	err = compress.OpenForSearch(path)
+	if err != nil {
+		t.Logf("open for search error: %v", err)
+		t.FailNow()
+	}
+	defer compress.Close()
+
+	if total := countNodes(); total != len(nodeList) {
+		t.Logf("after ResizeBlock(): %v nodes found, %v expected", total, len(nodeList))
+		t.FailNow()
+	}
+}
@@ -0,0 +1,660 @@
+package compress
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+)
+
+// Integrity footer
+//
+// # English:
+//
+//	Files written with headerMagicVersion or headerMagicVersion2 start with the magic bytes "GOSMN" in the version
+//	field of the header and have a footer after the index block, written by MountIndexIntoFile():
+//
+//	  CRC32C of each data block: 4 bytes per block, one block for each entry of the index;
+//	  CRC32C of the header: 4 bytes;
+//	  CRC32C of the index block: 4 bytes;
+//	  CRC32C of the list of block checksums: 4 bytes;
+//	  complete flag: 4 bytes, written last, only after everything else was written to disk.
+//
+//	The data block of version 1 is a group of blockSize nodes, the last one may be smaller, and the data block of
+//	version 2 is the block itself.
+//
+//	OpenForSearch() refuses files without the complete flag or whose header or index does not match its checksum, so a
+//	file left by an import that crashed does not open. Verify() reads the whole file.
+//
+//	Files written before the magic bytes, "00000001" and "00000002", are still read, without integrity checks.
+//
+// # Português:
+//
+//	Arquivos escritos com headerMagicVersion ou headerMagicVersion2 começam com os bytes mágicos "GOSMN" no campo
+//	versão do cabeçalho e têm um rodapé depois do bloco de índices, escrito por MountIndexIntoFile():
+//
+//	  CRC32C de cada bloco de dados: 4 bytes por bloco, um bloco para cada entrada do índice;
+//	  CRC32C do cabeçalho: 4 bytes;
+//	  CRC32C do bloco de índices: 4 bytes;
+//	  CRC32C da lista de somas de verificação dos blocos: 4 bytes;
+//	  flag de completo: 4 bytes, escrito por último, apenas depois de todo o resto ter sido escrito no disco.
+//
+//	O bloco de dados da versão 1 é um grupo de blockSize nodes, o último pode ser menor, e o bloco de dados da versão 2
+//	é o próprio bloco.
+//
+//	OpenForSearch() recusa arquivos sem o flag de completo ou cujo cabeçalho ou índice não confere com a sua soma de
+//	verificação, assim um arquivo deixado por uma importação que travou não abre. Verify() lê o arquivo inteiro.
+//
+//	Arquivos escritos antes dos bytes mágicos, "00000001" e "00000002", continuam sendo lidos, sem verificações de
+//	integridade.
+
+const (
+
+	// footerFixedByteSize
+	//
+	// # English:
+	//
+	// Number of bytes of the footer after the list of block checksums
+	//
+	// # Português:
+	//
+	// Quantidade de bytes do rodapé depois da lista de somas de verificação dos blocos
+	footerFixedByteSize = 16
+
+	// footerCompleteFlag
+	//
+	// # English:
+	//
+	// Value of the complete flag, "DONE" in little endian
+	//
+	// # Português:
+	//
+	// Valor do flag de completo, "DONE" em little endian
+	footerCompleteFlag = 0x454E4F44
+
+	// verifyMaxProblems
+	//
+	// # English:
+	//
+	// Maximum number of problems described by VerifyReport.Problems, the counters keep counting
+	//
+	// # Português:
+	//
+	// Quantidade máxima de problemas descritos por VerifyReport.Problems, os contadores continuam contando
+	verifyMaxProblems = 100
+)
+
+// crc32cTable
+//
+// # English:
+//
+// # Castagnoli table, CRC32C, accelerated by hardware on amd64 and arm64
+//
+// # Português:
+//
+// Tabela Castagnoli, CRC32C, acelerada por hardware em amd64 e arm64
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// VerifyReport
+//
+// # English:
+//
+// Result of Compress.Verify().
+//
+// # Português:
+//
+// Resultado de Compress.Verify().
+type VerifyReport struct {
+
+	// # English:
+	//
+	// True when the file has magic bytes and checksums.
+	//
+	// # Português:
+	//
+	// True quando o arquivo tem bytes mágicos e somas de verificação.
+	Checksum bool
+
+	// # English:
+	//
+	// True when the complete flag was found, always false for files without checksums.
+	//
+	// # Português:
+	//
+	// True quando o flag de completo foi encontrado, sempre false para arquivos sem somas de verificação.
+	Complete bool
+
+	// # English:
+	//
+	// Number of nodes read.
+	//
+	// # Português:
+	//
+	// Quantidade de nodes lidos.
+	Nodes int64
+
+	// # English:
+	//
+	// Number of IDs less than or equal to the previous ID.
+	//
+	// # Português:
+	//
+	// Quantidade de IDs menores ou iguais ao ID anterior.
+	OrderViolations int64
+
+	// # English:
+	//
+	// Number of nodes with longitude outside ±180 or latitude outside ±90.
+	//
+	// # Português:
+	//
+	// Quantidade de nodes com longitude fora de ±180 ou latitude fora de ±90.
+	OutOfRange int64
+
+	// # English:
+	//
+	// Number of checksums of the header, index and data blocks that do not match.
+	//
+	// # Português:
+	//
+	// Quantidade de somas de verificação do cabeçalho, índice e blocos de dados que não conferem.
+	ChecksumMismatches int64
+
+	// # English:
+	//
+	// Description of the first problems found, truncation, decoding errors and the problems counted above.
+	//
+	// # Português:
+	//
+	// Descrição dos primeiros problemas encontrados, truncamento, erros de decodificação e os problemas contados acima.
+	Problems []string
+}
+
+// Ok
+//
+// # English:
+//
+// Returns true when no problem was found.
+//
+// # Português:
+//
+// Devolve true quando nenhum problema foi encontrado.
+func (e *VerifyReport) Ok() bool {
+	return len(e.Problems) == 0
+}
+
+// problem
+//
+// # English:
+//
+// Counts the problem and keeps its description while there is room.
+//
+// # Português:
+//
+// Conta o problema e guarda a sua descrição enquanto houver espaço.
+func (e *VerifyReport) problem(counter *int64, format string, a ...any) {
+	if counter != nil {
+		*counter++
+	}
+
+	if len(e.Problems) < verifyMaxProblems {
+		e.Problems = append(e.Problems, fmt.Sprintf(format, a...))
+	}
+}
+
+// updateChecksum
+//
+// # English:
+//
+// Adds the bytes written to the checksum of the data block being written.
+//
+// # Português:
+//
+// Adiciona os bytes escritos à soma de verificação do bloco de dados sendo escrito.
+func (e *Compress) updateChecksum(data []byte) {
+	if !e.checksum {
+		return
+	}
+
+	e.crc = crc32.Update(e.crc, crc32cTable, data)
+}
+
+// closeChecksumBlock
+//
+// # English:
+//
+// Saves the checksum of the data block being written and starts the next one.
+//
+// # Português:
+//
+// Guarda a soma de verificação do bloco de dados sendo escrito e começa o próximo.
+func (e *Compress) closeChecksumBlock() {
+	if !e.checksum || e.blockCRC == nil {
+		return
+	}
+
+	e.blockCRC = append(e.blockCRC, e.crc)
+	e.crc = 0
+}
+
+// writeFooter
+//
+// # English:
+//
+// Writes the checksums after the index block and, after they are on disk, the complete flag.
+//
+// # Português:
+//
+// Escreve as somas de verificação depois do bloco de índices e, depois delas estarem no disco, o flag de completo.
+func (e *Compress) writeFooter(indexesAddress int64) (err error) {
+	if !e.checksum {
+		return
+	}
+
+	// # English: last block of version 1, smaller than blockSize
+	// # Português: último bloco da versão 1, menor do que blockSize
+	if e.version == headerVersion && e.blockCRC != nil && int64(len(e.blockCRC))+1 == e.totalIndexIntoFile {
+		e.closeChecksumBlock()
+	}
+
+	if e.blockCRC == nil || int64(len(e.blockCRC)) != e.totalIndexIntoFile {
+		if e.version == headerVersion2 {
+			err = errors.New("Compress.writeFooter().error: the checksums of the version 2 blocks are only known while the file is written")
+			return
+		}
+
+		e.blockCRC, err = e.dataChecksums()
+		if err != nil {
+			err = fmt.Errorf("Compress.writeFooter().dataChecksums().Error: %v", err)
+			return
+		}
+	}
+
+	var header, index []byte
+	header, index, err = e.readHeaderAndIndex(indexesAddress)
+	if err != nil {
+		err = fmt.Errorf("Compress.writeFooter().readHeaderAndIndex().Error: %v", err)
+		return
+	}
+
+	var footer = make([]byte, 4*len(e.blockCRC)+footerFixedByteSize)
+	for key, value := range e.blockCRC {
+		binary.LittleEndian.PutUint32(footer[4*key:], value)
+	}
+
+	var fixed = footer[4*len(e.blockCRC):]
+	binary.LittleEndian.PutUint32(fixed[0:], crc32.Checksum(header, crc32cTable))
+	binary.LittleEndian.PutUint32(fixed[4:], crc32.Checksum(index, crc32cTable))
+	binary.LittleEndian.PutUint32(fixed[8:], crc32.Checksum(footer[:4*len(e.blockCRC)], crc32cTable))
+
+	var footerAddress = indexesAddress + int64(len(index))
+	_, err = e.file.WriteAt(footer, footerAddress)
+	if err != nil {
+		err = fmt.Errorf("Compress.writeFooter().WriteAt().Error: %v", err)
+		return
+	}
+
+	err = e.file.Sync()
+	if err != nil {
+		err = fmt.Errorf("Compress.writeFooter().Sync().Error: %v", err)
+		return
+	}
+
+	binary.LittleEndian.PutUint32(fixed[12:], footerCompleteFlag)
+	_, err = e.file.WriteAt(fixed[12:], footerAddress+int64(len(footer))-4)
+	if err != nil {
+		err = fmt.Errorf("Compress.writeFooter().WriteAt().Error: %v", err)
+		return
+	}
+
+	return
+}
+
+// readHeaderAndIndex
+//
+// # English:
+//
+// Reads the bytes of the header and of the index block.
+//
+// # Português:
+//
+// Lê os bytes do cabeçalho e do bloco de índices.
+func (e *Compress) readHeaderAndIndex(indexesAddress int64) (header, index []byte, err error) {
+	header = make([]byte, nodeDataPositionStartAtAddress)
+	_, err = e.file.ReadAt(header, headerVersionAddress)
+	if err != nil {
+		return
+	}
+
+	index = make([]byte, 2*int64ByteSize*e.totalIndexIntoFile)
+	_, err = e.file.ReadAt(index, indexesAddress)
+	return
+}
+
+// readFooter
+//
+// # English:
+//
+// Reads the list of block checksums and the fixed part of the footer, after ReadFileHeaders().
+//
+// # Português:
+//
+// Lê a lista de somas de verificação dos blocos e a parte fixa do rodapé, depois de ReadFileHeaders().
+func (e *Compress) readFooter() (blockCRC []byte, fixed []byte, err error) {
+	var footer = make([]byte, 4*e.totalIndexIntoFile+footerFixedByteSize)
+	_, err = e.file.ReadAt(footer, e.indexesAddress+2*int64ByteSize*e.totalIndexIntoFile)
+	if err != nil {
+		err = fmt.Errorf("footer not found, the file is incomplete: %v", err)
+		return
+	}
+
+	blockCRC = footer[:4*e.totalIndexIntoFile]
+	fixed = footer[4*e.totalIndexIntoFile:]
+	return
+}
+
+// checkFooter
+//
+// # English:
+//
+// Compares the checksums of the header, the index and the list of block checksums with the footer.
+//
+//	Output:
+//	  complete: the complete flag was found;
+//	  mismatches: description of each checksum that does not match.
+//
+// # Português:
+//
+// Compara as somas de verificação do cabeçalho, do índice e da lista de somas de verificação dos blocos com o rodapé.
+//
+//	Saída:
+//	  complete: o flag de completo foi encontrado;
+//	  mismatches: descrição de cada soma de verificação que não confere.
+func (e *Compress) checkFooter(blockCRC, fixed []byte) (complete bool, mismatches []string, err error) {
+	complete = binary.LittleEndian.Uint32(fixed[12:]) == footerCompleteFlag
+
+	var header, index []byte
+	header, index, err = e.readHeaderAndIndex(e.indexesAddress)
+	if err != nil {
+		return
+	}
+
+	if crc32.Checksum(header, crc32cTable) != binary.LittleEndian.Uint32(fixed[0:]) {
+		mismatches = append(mismatches, "header checksum mismatch")
+	}
+
+	if crc32.Checksum(index, crc32cTable) != binary.LittleEndian.Uint32(fixed[4:]) {
+		mismatches = append(mismatches, "index checksum mismatch")
+	}
+
+	if crc32.Checksum(blockCRC, crc32cTable) != binary.LittleEndian.Uint32(fixed[8:]) {
+		mismatches = append(mismatches, "block checksum list mismatch")
+	}
+
+	return
+}
+
+// checkIntegrity
+//
+// # English:
+//
+// Refuses incomplete files and files whose header or index does not match its checksum, after ReadFileHeaders().
+// The data blocks are only checked by Verify().
+//
+// # Português:
+//
+// Recusa arquivos incompletos e arquivos cujo cabeçalho ou índice não confere com a sua soma de verificação, depois de
+// ReadFileHeaders(). Os blocos de dados só são verificados por Verify().
+func (e *Compress) checkIntegrity() (err error) {
+	if !e.checksum {
+		return
+	}
+
+	var blockCRC, fixed []byte
+	blockCRC, fixed, err = e.readFooter()
+	if err != nil {
+		return
+	}
+
+	var complete bool
+	var mismatches []string
+	complete, mismatches, err = e.checkFooter(blockCRC, fixed)
+	if err != nil {
+		return
+	}
+
+	if !complete {
+		err = errors.New("the file is incomplete, MountIndexIntoFile() did not finish")
+		return
+	}
+
+	if len(mismatches) != 0 {
+		err = fmt.Errorf("the file is corrupted: %v", mismatches[0])
+		return
+	}
+
+	return
+}
+
+// dataChecksums
+//
+// # English:
+//
+// Reads the version 1 data and calculates the checksum of each group of blockSize nodes.
+//
+// # Português:
+//
+// Lê os dados da versão 1 e calcula a soma de verificação de cada grupo de blockSize nodes.
+func (e *Compress) dataChecksums() (blockCRC []uint32, err error) {
+	blockCRC = make([]uint32, 0, e.totalIndexIntoFile)
+	for k := int64(0); k != e.totalIndexIntoFile; k++ {
+		var data []byte
+		data, err = e.readVersion1Block(k)
+		if err != nil {
+			return
+		}
+
+		blockCRC = append(blockCRC, crc32.Checksum(data, crc32cTable))
+	}
+
+	return
+}
+
+// readVersion1Block
+//
+// # English:
+//
+// Reads the nodes of the data block k of version 1, blockSize nodes, the last block may be smaller.
+//
+// # Português:
+//
+// Lê os nodes do bloco de dados k da versão 1, blockSize nodes, o último bloco pode ser menor.
+func (e *Compress) readVersion1Block(k int64) (data []byte, err error) {
+	first := k * e.blockSize
+	last := first + e.blockSize
+	if last > e.totalOfNodesInTmpFile {
+		last = e.totalOfNodesInTmpFile
+	}
+
+	data = make([]byte, (last-first)*nodeDataByteSize)
+	_, err = e.file.ReadAt(data, nodeDataPositionStartAtAddress+first*nodeDataByteSize)
+	return
+}
+
+// Verify
+//
+// # English:
+//
+// Reads the whole file and reports ID ordering violations, out-of-range coordinates, checksum mismatches, truncation
+// and incomplete files.
+//
+//	Output:
+//	  report: problems found;
+//	  err: only when the header cannot be read.
+//
+//	Note:
+//	  * Can be called after OpenForSearch(), even when it returned an integrity error, or after Create() of an existing
+//	    file.
+//	  * Files without magic bytes are verified without checksums.
+//
+// # Português:
+//
+// Lê o arquivo inteiro e relata violações de ordem de ID, coordenadas fora do intervalo, somas de verificação que não
+// conferem, truncamento e arquivos incompletos.
+//
+//	Saída:
+//	  report: problemas encontrados;
+//	  err: apenas quando o cabeçalho não pode ser lido.
+//
+//	Nota:
+//	  * Pode ser chamada depois de OpenForSearch(), mesmo quando ela devolveu um erro de integridade, ou depois de
+//	    Create() de um arquivo existente.
+//	  * Arquivos sem bytes mágicos são verificados sem somas de verificação.
+func (e *Compress) Verify() (report VerifyReport, err error) {
+	// # English: keeps the checksums of the blocks of a file being written
+	// # Português: mantém as somas de verificação dos blocos de um arquivo sendo escrito
+	var blockCRC = e.blockCRC
+	defer func() {
+		e.blockCRC = blockCRC
+	}()
+
+	err = e.ReadFileHeaders()
+	if err != nil {
+		err = fmt.Errorf("Compress.Verify().ReadFileHeaders().Error: %v", err)
+		return
+	}
+
+	report.Checksum = e.checksum
+
+	var footerCRC []byte
+	if e.checksum {
+		var fixed []byte
+		footerCRC, fixed, err = e.readFooter()
+		if err != nil {
+			report.problem(nil, "%v", err)
+			footerCRC = nil
+			err = nil
+		} else {
+			var mismatches []string
+			report.Complete, mismatches, err = e.checkFooter(footerCRC, fixed)
+			if err != nil {
+				report.problem(nil, "index not found, the file is truncated: %v", err)
+				err = nil
+			}
+
+			for _, mismatch := range mismatches {
+				report.problem(&report.ChecksumMismatches, "%v", mismatch)
+			}
+
+			if !report.Complete {
+				report.problem(nil, "the complete flag was not found, MountIndexIntoFile() did not finish")
+			}
+		}
+	}
+
+	var lastID int64
+	var check = func(id int64, longitude, latitude float64) {
+		report.Nodes++
+		if id <= lastID {
+			report.problem(&report.OrderViolations, "node %v after node %v", id, lastID)
+		}
+		lastID = id
+
+		if longitude < -180.0 || longitude > 180.0 || latitude < -90.0 || latitude > 90.0 {
+			report.problem(&report.OutOfRange, "node %v out of range: %v, %v", id, longitude, latitude)
+		}
+	}
+
+	var blocks [][2]int64
+	if e.version == headerVersion2 {
+		blocks, err = e.readBlockBounds()
+		if err != nil {
+			report.problem(nil, "index not found, the file is truncated: %v", err)
+			err = nil
+			return
+		}
+	}
+
+	for k := int64(0); k != e.totalIndexIntoFile; k++ {
+		var data []byte
+		if e.version == headerVersion2 {
+			data = make([]byte, blocks[k][1]-blocks[k][0])
+			_, err = e.file.ReadAt(data, blocks[k][0])
+		} else {
+			data, err = e.readVersion1Block(k)
+		}
+
+		if err != nil {
+			report.problem(nil, "block %v not found, the file is truncated: %v", k, err)
+			err = nil
+			return
+		}
+
+		if footerCRC != nil && crc32.Checksum(data, crc32cTable) != binary.LittleEndian.Uint32(footerCRC[4*k:]) {
+			report.problem(&report.ChecksumMismatches, "block %v checksum mismatch", k)
+		}
+
+		if e.version == headerVersion2 {
+			var nodes [][3]int64
+			nodes, err = decodeNodes(data)
+			if err != nil {
+				report.problem(nil, "block %v: %v", k, err)
+				err = nil
+			}
+
+			for _, node := range nodes {
+				check(node[0], float64(node[1])/decimalPlaces, float64(node[2])/decimalPlaces)
+			}
+			continue
+		}
+
+		for address := 0; address != len(data); address += nodeDataByteSize {
+			check(
+				int64(binary.LittleEndian.Uint64(data[address:])),
+				coordinateFromBytes(data[address+nodeIdByteSize:]),
+				coordinateFromBytes(data[address+nodeIdByteSize+nodeCoordinateByteSize:]),
+			)
+		}
+	}
+
+	if report.Nodes != e.totalOfNodesInTmpFile {
+		report.problem(nil, "%v nodes found, the header says %v", report.Nodes, e.totalOfNodesInTmpFile)
+	}
+
+	return
+}
+
+// readBlockBounds
+//
+// # English:
+//
+// Reads the index of version 2 and returns the start and end address of each block.
+//
+// # Português:
+//
+// Lê o índice da versão 2 e devolve o endereço de início e de fim de cada bloco.
+func (e *Compress) readBlockBounds() (blocks [][2]int64, err error) {
+	var index = make([]byte, 2*int64ByteSize*e.totalIndexIntoFile)
+	_, err = e.file.ReadAt(index, e.indexesAddress)
+	if err != nil {
+		return
+	}
+
+	blocks = make([][2]int64, e.totalIndexIntoFile)
+	for k := range blocks {
+		blocks[k][0] = int64(binary.LittleEndian.Uint64(index[2*int64ByteSize*k+int64ByteSize:]))
+		blocks[k][1] = e.indexesAddress
+		if k > 0 {
+			blocks[k-1][1] = blocks[k][0]
+		}
+	}
+
+	for k := range blocks {
+		if blocks[k][0] < nodeDataPositionStartAtAddress || blocks[k][1] < blocks[k][0] {
+			err = fmt.Errorf("block %v: corrupted index", k)
+			return
+		}
+	}
+
+	return
+}
@@ -1,6 +1,7 @@
 package compress
 
 import (
+	"io"
 	"os"
 	"testing"
 )
@@ -120,8 +121,10 @@ func TestCompress(t *testing.T) {
 	// fim da escrita do arquivo
 	// -------------------------------------------------------------------------------------------------------------------
 
+	// English: Create() truncates the file, the complete file is reopened read-only
+	// Português: Create() trunca o arquivo, o arquivo completo é reaberto apenas para leitura
 	compress.Init(7)
-	err = compress.Create("./test.node.tmp")
+	err = compress.OpenForSearch("./test.node.tmp")
 	if err != nil {
 		t.Logf("open file error: %v", err)
 		t.FailNow()
@@ -160,3 +163,368 @@ func TestCompress(t *testing.T) {
 		}
 	}
 }
+
+// TestCompress_version2
+//
+// English:
+//
+// # Writes a block-compressed file, version 2, reopens it with OpenForSearch() and tests the values found by the search
+//
+// Português:
+//
+// Escreve um arquivo compactado em blocos, versão 2, reabre o mesmo com OpenForSearch() e testa os valores encontrados
+// pela busca
+func TestCompress_version2(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.Remove("./test.node.v2.tmp")
+	})
+
+	var err error
+	var testLimit = 1000
+
+	compress := Compress{}
+	compress.Init(64)
+	err = compress.SetFormatVersion(2)
+	if err != nil {
+		t.Logf("set format version error: %v", err)
+		t.FailNow()
+	}
+
+	err = compress.Create("./test.node.v2.tmp")
+	if err != nil {
+		t.Logf("open file error: %v", err)
+		t.FailNow()
+	}
+
+	// Gera a informação de controle, com IDs pulados a cada 3 nodes
+	nodeList := make([]Node, 0)
+	for i := int64(0); i != int64(testLimit); i++ {
+		nodeList = append(nodeList, Node{ID: i*3/2 + 1, Lon: compress.Round((float64(i)*0.00001+2.123456)*-1, 6.0), Lat: compress.Round(float64(i)*0.00001+1.98765, 6.0)})
+	}
+
+	for i := 0; i != testLimit; i++ {
+		err = compress.WriteNode(nodeList[i].ID, nodeList[i].Lon, nodeList[i].Lat)
+		if err != nil {
+			t.Logf("write node error: %v", err)
+			t.FailNow()
+		}
+	}
+
+	err = compress.WriteFileHeaders()
+	if err != nil {
+		t.Logf("write header error: %v", err)
+		t.FailNow()
+	}
+
+	err = compress.MountIndexIntoFile()
+	if err != nil {
+		t.Logf("write index error: %v", err)
+		t.FailNow()
+	}
+
+	if compress.nodeWriteDataPosition >= int64(testLimit)*nodeDataByteSize {
+		t.Logf("file size error: version 2 must be smaller than version 1")
+		t.FailNow()
+	}
+	compress.Close()
+
+	// -------------------------------------------------------------------------------------------------------------------
+	// fim da escrita do arquivo
+	// -------------------------------------------------------------------------------------------------------------------
+
+	compress = Compress{}
+	compress.Init(0)
+	err = compress.OpenForSearch("./test.node.v2.tmp")
+	if err != nil {
+		t.Logf("open for search error: %v", err)
+		t.FailNow()
+	}
+	defer compress.Close()
+
+	var longitude, latitude float64
+	for i := testLimit - 1; i != -1; i-- {
+		longitude, latitude, err = compress.FindNodeByID(nodeList[i].ID)
+		if err != nil {
+			t.Logf("FindNodeByID(%v) error: %v", nodeList[i].ID, err)
+			t.FailNow()
+		}
+
+		if compress.Round(longitude, 6.0) != nodeList[i].Lon || compress.Round(latitude, 6.0) != nodeList[i].Lat {
+			t.Logf("FindNodeByID(%v) error: %v, %v != %v, %v", nodeList[i].ID, longitude, latitude, nodeList[i].Lon, nodeList[i].Lat)
+			t.FailNow()
+		}
+	}
+
+	for _, id := range []int64{0, 3, nodeList[testLimit-1].ID + 1} {
+		_, _, err = compress.FindNodeByID(id)
+		if err != io.EOF {
+			t.Logf("FindNodeByID(%v) error: io.EOF expected, found %v", id, err)
+			t.FailNow()
+		}
+	}
+}
+
+// TestCompress_mmap
+//
+// English:
+//
+// # Writes version 1 and version 2 files and compares the search on the mapped bytes with the ReadAt() search
+//
+// Português:
+//
+// Escreve arquivos versão 1 e versão 2 e compara a busca nos bytes mapeados com a busca feita por ReadAt()
+func TestCompress_mmap(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.Remove("./test.node.mmap.tmp")
+	})
+
+	var err error
+	var testLimit = 500
+
+	for _, version := range []int{1, 2} {
+		compress := Compress{}
+		compress.Init(16)
+		_ = os.Remove("./test.node.mmap.tmp")
+		err = compress.SetFormatVersion(version)
+		if err != nil {
+			t.Logf("set format version error: %v", err)
+			t.FailNow()
+		}
+
+		err = compress.Create("./test.node.mmap.tmp")
+		if err != nil {
+			t.Logf("open file error: %v", err)
+			t.FailNow()
+		}
+
+		for i := int64(0); i != int64(testLimit); i++ {
+			err = compress.WriteNode(i*2+1, float64(i)*0.001-120.0, 45.0-float64(i)*0.001)
+			if err != nil {
+				t.Logf("write node error: %v", err)
+				t.FailNow()
+			}
+		}
+
+		err = compress.WriteFileHeaders()
+		if err != nil {
+			t.Logf("write header error: %v", err)
+			t.FailNow()
+		}
+
+		err = compress.MountIndexIntoFile()
+		if err != nil {
+			t.Logf("write index error: %v", err)
+			t.FailNow()
+		}
+		compress.Close()
+
+		readAt := Compress{}
+		readAt.Init(0)
+		err = readAt.OpenForSearch("./test.node.mmap.tmp")
+		if err != nil {
+			t.Logf("open for search error: %v", err)
+			t.FailNow()
+		}
+
+		mmap := Compress{}
+		mmap.Init(0)
+		err = mmap.OpenForSearchMmap("./test.node.mmap.tmp")
+		if err != nil {
+			t.Logf("open for search mmap error: %v", err)
+			t.FailNow()
+		}
+
+		if mmap.mapped == nil {
+			t.Logf("version %v: the file was not mapped", version)
+		}
+
+		for id := int64(0); id != int64(testLimit*2+2); id++ {
+			lonReadAt, latReadAt, errReadAt := readAt.FindNodeByID(id)
+			lonMmap, latMmap, errMmap := mmap.FindNodeByID(id)
+			if errReadAt != errMmap || lonReadAt != lonMmap || latReadAt != latMmap {
+				t.Logf("version %v: FindNodeByID(%v) error: ReadAt() = %v, %v, %v; mmap = %v, %v, %v", version, id, lonReadAt, latReadAt, errReadAt, lonMmap, latMmap, errMmap)
+				t.FailNow()
+			}
+
+			if id%2 == 1 && id < int64(testLimit*2) && errMmap != nil {
+				t.Logf("version %v: FindNodeByID(%v) error: %v", version, id, errMmap)
+				t.FailNow()
+			}
+		}
+
+		readAt.Close()
+		mmap.Close()
+	}
+}
+
+// TestCompress_verify
+//
+// English:
+//
+// # Writes version 1 and version 2 files, tests Verify() on the complete file, on a corrupted block and on a truncated file
+//
+// Português:
+//
+// Escreve arquivos das versões 1 e 2, testa Verify() no arquivo completo, em um bloco corrompido e em um arquivo truncado
+func TestCompress_verify(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.Remove("./test.node.verify.tmp")
+	})
+
+	var err error
+	for _, version := range []int{1, 2} {
+		_ = os.Remove("./test.node.verify.tmp")
+
+		compress := Compress{}
+		compress.Init(10)
+		_ = compress.SetFormatVersion(version)
+		err = compress.Create("./test.node.verify.tmp")
+		if err != nil {
+			t.Logf("open file error: %v", err)
+			t.FailNow()
+		}
+
+		for id := int64(1); id <= 95; id++ {
+			err = compress.WriteNode(id, float64(id)/10, -float64(id)/10)
+			if err != nil {
+				t.Logf("write node error: %v", err)
+				t.FailNow()
+			}
+		}
+
+		err = compress.WriteFileHeaders()
+		if err != nil {
+			t.Logf("write header error: %v", err)
+			t.FailNow()
+		}
+
+		var report VerifyReport
+		report, err = compress.Verify()
+		if err != nil || report.Complete {
+			t.Logf("version %v: the file must be incomplete before MountIndexIntoFile(): %+v, %v", version, report, err)
+			t.FailNow()
+		}
+
+		err = compress.MountIndexIntoFile()
+		if err != nil {
+			t.Logf("mount index error: %v", err)
+			t.FailNow()
+		}
+		compress.Close()
+
+		compress = Compress{}
+		compress.Init(0)
+		err = compress.OpenForSearch("./test.node.verify.tmp")
+		if err != nil {
+			t.Logf("version %v: open for search error: %v", version, err)
+			t.FailNow()
+		}
+
+		report, err = compress.Verify()
+		if err != nil || !report.Ok() || !report.Complete || !report.Checksum || report.Nodes != 95 {
+			t.Logf("version %v: verify error: %+v, %v", version, report, err)
+			t.FailNow()
+		}
+		compress.Close()
+
+		// English: changes a byte of the first block
+		// Português: altera um byte do primeiro bloco
+		var file *os.File
+		file, err = os.OpenFile("./test.node.verify.tmp", os.O_RDWR, 0644)
+		if err != nil {
+			t.Logf("open file error: %v", err)
+			t.FailNow()
+		}
+
+		var info os.FileInfo
+		info, _ = file.Stat()
+		_, _ = file.WriteAt([]byte{0xFF}, nodeDataPositionStartAtAddress+nodeIdByteSize+1)
+
+		compress = Compress{}
+		compress.Init(0)
+		compress.file = file
+		report, err = compress.Verify()
+		if err != nil || report.ChecksumMismatches != 1 {
+			t.Logf("version %v: the corrupted block was not found: %+v, %v", version, report, err)
+			t.FailNow()
+		}
+
+		// English: removes the complete flag, as in an import that crashed
+		// Português: remove o flag de completo, como em uma importação que travou
+		_ = file.Truncate(info.Size() - 4)
+		_ = file.Close()
+
+		compress = Compress{}
+		compress.Init(0)
+		err = compress.OpenForSearch("./test.node.verify.tmp")
+		if err == nil {
+			t.Logf("version %v: OpenForSearch() must refuse an incomplete file", version)
+			t.FailNow()
+		}
+
+		report, err = compress.Verify()
+		if err != nil || report.Ok() || report.Complete {
+			t.Logf("version %v: the incomplete file was not reported: %+v, %v", version, report, err)
+			t.FailNow()
+		}
+		compress.Close()
+	}
+}
+
+// TestCompress_createOverComplete
+//
+// English:
+//
+// Writes a complete file of 100 nodes and, on the same path, 50 nodes of a new file that stops before the headers, as
+// in a crash. The half-written file must be refused.
+//
+// Português:
+//
+// Escreve um arquivo completo de 100 nodes e, no mesmo caminho, 50 nodes de um novo arquivo que para antes dos
+// cabeçalhos, como em uma falha. O arquivo escrito pela metade deve ser recusado.
+func TestCompress_createOverComplete(t *testing.T) {
+	var path = t.TempDir() + "/test.node.tmp"
+
+	var err error
+	for _, version := range []int{1, 2} {
+		for _, total := range []int64{100, 50} {
+			compress := Compress{}
+			compress.Init(10)
+			_ = compress.SetFormatVersion(version)
+			err = compress.Create(path)
+			if err != nil {
+				t.Logf("version %v: open file error: %v", version, err)
+				t.FailNow()
+			}
+
+			for id := int64(1); id <= total; id++ {
+				err = compress.WriteNode(id, float64(id)/10, -float64(id)/10)
+				if err != nil {
+					t.Logf("version %v: write node error: %v", version, err)
+					t.FailNow()
+				}
+			}
+
+			if total == 100 {
+				err = compress.WriteFileHeaders()
+				if err == nil {
+					err = compress.MountIndexIntoFile()
+				}
+				if err != nil {
+					t.Logf("version %v: write index error: %v", version, err)
+					t.FailNow()
+				}
+			}
+			compress.Close()
+		}
+
+		compress := Compress{}
+		compress.Init(0)
+		err = compress.OpenForSearch(path)
+		if err == nil {
+			_, _, err = compress.FindNodeByID(60)
+			t.Logf("version %v: OpenForSearch() must refuse the half-written file, FindNodeByID(60): %v", version, err)
+			t.FailNow()
+		}
+	}
+}
@@ -0,0 +1,481 @@
+package compress
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"math"
+	"os"
+)
+
+const (
+
+	// denseHeaderVersion
+	//
+	// # English:
+	//
+	// Version text written in the header of the dense binary file
+	//
+	// # Português:
+	//
+	// Texto de versão escrito no cabeçalho do arquivo binário denso
+	denseHeaderVersion = "D0000001"
+
+	// denseHeaderMaxIdAddress
+	//
+	// # English:
+	//
+	// Header address of the greatest node ID contained in the dense file
+	//
+	// # Português:
+	//
+	// Endereço do cabeçalho do maior ID de node contido no arquivo denso
+	denseHeaderMaxIdAddress = headerTotalNodesAddress + 8
+
+	// denseDataPositionStartAtAddress
+	//
+	// # English:
+	//
+	// Total amount of bytes to be skipped before node data, version + total nodes + max ID
+	//
+	// # Português:
+	//
+	// Quantidade total de bytes a ser pulada antes dos dados dos nodes, versão + total de nodes + maior ID
+	denseDataPositionStartAtAddress = headerVersionByteSize + totalNodesByteSize + int64ByteSize
+
+	// denseNodeDataByteSize
+	//
+	// # English:
+	//
+	// Number of bytes occupied by a node, int32 longitude and int32 latitude
+	//
+	// # Português:
+	//
+	// Quantidade de bytes ocupada por um node, int32 longitude e int32 latitude
+	denseNodeDataByteSize = 2 * nodeCoordinateByteSize
+
+	// denseLatitudeOffset
+	//
+	// # English:
+	//
+	// Added to latitude * 10,000,000 before writing, so a written node never has all bytes zero, which is an empty
+	// position of the sparse file. The latitude is within ±900,000,000, so the value written is always positive.
+	//
+	// # Português:
+	//
+	// Somado à latitude * 10.000.000 antes da escrita, assim um node escrito nunca tem todos os bytes zero, o que é uma
+	// posição vazia do arquivo esparso. A latitude está dentro de ±900.000.000, logo, o valor escrito é sempre positivo.
+	denseLatitudeOffset = 1000000000
+)
+
+// Dense
+//
+// # English:
+//
+//	CompressInterface implementation for planet-sized imports, where node IDs are dense enough for an array indexed by
+//	ID to be better than any binary search.
+//
+//	Each node is written as int32 longitude and int32 latitude, multiplied by 10,000,000, at address
+//	header + ID * 8 of a sparse file, and FindNodeByID() is O(1), a single read, with no index in memory.
+//
+//	The file size is the greatest ID * 8 bytes, so for extracts, with few nodes spread over a large ID range, Compress
+//	is better. See NewNodeStore().
+//
+// File format:
+//
+//	Header: 24 bytes
+//	  version: 8 bytes
+//	  total of nodes in a file: 8 bytes
+//	  greatest node ID: 8 bytes
+//
+//	Data block:
+//	  node ID * 8 + 24: longitude: 4 bytes, latitude + 1,000,000,000: 4 bytes
+//	  All bytes zero: node not found.
+//
+// # Português:
+//
+//	Implementação de CompressInterface para importações do tamanho do planeta, onde os IDs dos nodes são densos o
+//	bastante para um array indexado pelo ID ser melhor do que qualquer busca binária.
+//
+//	Cada node é escrito como int32 longitude e int32 latitude, multiplicados por 10.000.000, no endereço
+//	cabeçalho + ID * 8 de um arquivo esparso, e FindNodeByID() é O(1), uma única leitura, sem índice em memória.
+//
+//	O tamanho do arquivo é o maior ID * 8 bytes, logo, para recortes, com poucos nodes espalhados em um grande
+//	intervalo de IDs, Compress é melhor. Veja NewNodeStore().
+//
+// Formato do arquivo:
+//
+//	Header: 24 bytes
+//	  version: 8 bytes
+//	  total of nodes in a file: 8 bytes
+//	  greatest node ID: 8 bytes
+//
+//	Data block:
+//	  node ID * 8 + 24: longitude: 4 bytes, latitude + 1.000.000.000: 4 bytes
+//	  Todos os bytes zero: node não encontrado.
+type Dense struct {
+
+	// # English:
+	//
+	// Pointer to the temporary file.
+	//
+	// # Português:
+	//
+	// Ponteiro para o arquivo temporário.
+	file *os.File
+
+	// # English:
+	//
+	// 8 bytes buffer. Dense shares this buffer between calls, as Compress, and is not safe for concurrent use.
+	//
+	// # Português:
+	//
+	// Buffer de 8 bytes. Dense compartilha este buffer entre as chamadas, como Compress, e não é seguro para uso
+	// concorrente.
+	dataFile []byte
+
+	// # English:
+	//
+	// Total nodes saved in the file.
+	//
+	// # Português:
+	//
+	// Total de nodes salvos no arquivo.
+	totalOfNodesInTmpFile int64
+
+	// # English:
+	//
+	// Greatest node ID saved in the file.
+	//
+	// # Português:
+	//
+	// Maior ID de node salvo no arquivo.
+	maxID int64
+}
+
+// Init
+//
+// # English:
+//
+// Initializes the object.
+//
+//	Input:
+//	  blockSize: ignored, the dense file has no index. Kept for CompressInterface compatibility.
+//
+// # Português:
+//
+// Inicializa o objeto.
+//
+//	Entrada:
+//	  blockSize: ignorado, o arquivo denso não tem índice. Mantido para compatibilidade com CompressInterface.
+func (e *Dense) Init(_ int64) {
+	e.dataFile = make([]byte, 8)
+	e.totalOfNodesInTmpFile = 0
+	e.maxID = 0
+}
+
+// Round
+//
+// # English:
+//
+// Rounds a floating point to N decimal places
+//
+//	Input:
+//	  value: value to be rounded off;
+//	  places: number of decimal places. Eg. 7.0
+//
+// # Português:
+//
+// Arredonda um ponto flutuante para N casas decimais
+//
+//	Entrada:
+//	  value: valor a ser arredondado;
+//	  places: quantidade de casas decimais. Ex: 7.0
+func (e *Dense) Round(value, places float64) float64 {
+	return (&Compress{}).Round(value, places)
+}
+
+// Create
+//
+// # English:
+//
+// Open the temporary file.
+//
+// # Português:
+//
+// Abre o arquivo temporário.
+func (e *Dense) Create(path string) (err error) {
+	if e.file != nil {
+		_ = e.file.Close()
+	}
+
+	// English: truncated, the nodes of a previous file must not be found in the empty positions of the new one
+	// Português: truncado, os nodes de um arquivo anterior não podem ser encontrados nas posições vazias do novo
+	e.file, err = os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, fs.ModePerm)
+	if err != nil {
+		err = fmt.Errorf("Dense.Create().OpenFile().Error: %v", err)
+		return
+	}
+	return
+}
+
+// OpenForSearch
+//
+// # English:
+//
+// Opens the binary file read-only and is used when only the search function is intended.
+//
+//	Input:
+//	  path: Binary file path
+//
+// # Português:
+//
+// Abre o arquivo binário apenas para leitura e é usado quando se pretende usar apenas a função de busca
+//
+//	Entrada:
+//	  path: Caminho do arquivo binário
+func (e *Dense) OpenForSearch(path string) (err error) {
+	if e.file != nil {
+		_ = e.file.Close()
+	}
+
+	e.file, err = os.OpenFile(path, os.O_RDONLY, fs.ModePerm)
+	if err != nil {
+		err = fmt.Errorf("Dense.OpenForSearch().OpenFile().Error: %v", err)
+		return
+	}
+
+	err = e.ReadFileHeaders()
+	if err != nil {
+		err = fmt.Errorf("Dense.OpenForSearch().ReadFileHeaders().Error: %v", err)
+		return
+	}
+
+	return
+}
+
+// Close
+//
+// # English:
+//
+// # Close the temporary file
+//
+// # Português:
+//
+// Fecha o arquivo temporário
+func (e *Dense) Close() {
+	var err = e.file.Close()
+	if err != nil {
+		log.Printf("Dense.Close().error: %v", err)
+	}
+}
+
+// WriteNode
+//
+// # English:
+//
+// Write node to temporary file, at address header + ID * 8.
+//
+//	Input:
+//	  id: positive number greater than zero;
+//	  longitude: value between ±180 to 7 decimal places;
+//	  latitude: value between ±90 with 7 decimal places;
+//
+//	Note:
+//	  * Unlike Compress, the IDs do not need to be in ascending order, and a repeated ID overwrites the previous one.
+//
+// # Português:
+//
+// Escreve o node no arquivo temporário, no endereço cabeçalho + ID * 8.
+//
+//	Entrada:
+//	  id: número positivo maior do que zero;
+//	  longitude: valor entre ±180 com 7 casas decimais;
+//	  latitude: valor entre ±90 com 7 casas decimais;
+//
+//	Nota:
+//	  * Diferente de Compress, os IDs não precisam estar em ordem crescente, e um ID repetido sobrescreve o anterior.
+func (e *Dense) WriteNode(id int64, longitude, latitude float64) (err error) {
+	if id < 1 {
+		err = errors.New("id must be greater than zero")
+		return
+	}
+
+	if longitude < -180.0 || longitude > 180.0 {
+		err = errors.New("longitude must be within ±180˚")
+		return
+	}
+
+	if latitude < -90.0 || latitude > 90.0 {
+		err = errors.New("latitude must be within ±90˚")
+		return
+	}
+
+	binary.LittleEndian.PutUint32(e.dataFile[0:], uint32(int32(math.Round(longitude*decimalPlaces))))
+	binary.LittleEndian.PutUint32(e.dataFile[nodeCoordinateByteSize:], uint32(int32(math.Round(latitude*decimalPlaces))+denseLatitudeOffset))
+	_, err = e.file.WriteAt(e.dataFile, denseDataPositionStartAtAddress+id*denseNodeDataByteSize)
+	if err != nil {
+		err = fmt.Errorf("Dense.WriteNode().WriteAt().Error: %v", err)
+		return
+	}
+
+	if id > e.maxID {
+		e.maxID = id
+	}
+	e.totalOfNodesInTmpFile++
+	return
+}
+
+// FindNodeByID
+//
+// # English:
+//
+// Search for longitude and latitude in the temporary file.
+//
+//	Input:
+//	  id: ID of the node sought.
+//
+//	Output:
+//	  longitude: value between ±180 width 7 decimal places;
+//	  latitude: value between ±90 width 7 decimal places;
+//	  err: pattern object, with io.EOF error when value not found in file
+//
+// # Português:
+//
+// Procura por longitude e latitude no arquivo temporário.
+//
+//	Entrada:
+//	  id: ID do node procurado.
+//
+//	Saída:
+//	  longitude: valor entre ±180 com 7 casas decimais;
+//	  latitude: valor entre ±90 com 7 casas decimais;
+//	  err: objeto de padrão, com erro io.EOF quando o valor não é encontrado no arquivo
+func (e *Dense) FindNodeByID(id int64) (longitude, latitude float64, err error) {
+	if id < 1 || id > e.maxID {
+		err = io.EOF
+		return
+	}
+
+	_, err = e.file.ReadAt(e.dataFile, denseDataPositionStartAtAddress+id*denseNodeDataByteSize)
+	if err != nil {
+		err = fmt.Errorf("Dense.FindNodeByID().ReadAt().Error: %v", err)
+		return
+	}
+
+	lon := int32(binary.LittleEndian.Uint32(e.dataFile[0:]))
+	lat := int32(binary.LittleEndian.Uint32(e.dataFile[nodeCoordinateByteSize:]))
+
+	// # English: empty position of the sparse file
+	// # Português: posição vazia do arquivo esparso
+	if lon == 0 && lat == 0 {
+		err = io.EOF
+		return
+	}
+
+	longitude = float64(lon) / decimalPlaces
+	latitude = float64(lat-denseLatitudeOffset) / decimalPlaces
+	return
+}
+
+// WriteFileHeaders
+//
+// # English:
+//
+// Write configuration data at the beginning of the file.
+//
+// # Português:
+//
+// Escreve os dados de configuração no início do arquivo.
+func (e *Dense) WriteFileHeaders() (err error) {
+	_, err = e.file.WriteAt([]byte(denseHeaderVersion), headerVersionAddress)
+	if err != nil {
+		err = fmt.Errorf("Dense.WriteFileHeaders().version.Error: %v", err)
+		return
+	}
+
+	binary.LittleEndian.PutUint64(e.dataFile, uint64(e.totalOfNodesInTmpFile))
+	_, err = e.file.WriteAt(e.dataFile, headerTotalNodesAddress)
+	if err != nil {
+		err = fmt.Errorf("Dense.WriteFileHeaders().totalOfNodes.Error: %v", err)
+		return
+	}
+
+	binary.LittleEndian.PutUint64(e.dataFile, uint64(e.maxID))
+	_, err = e.file.WriteAt(e.dataFile, denseHeaderMaxIdAddress)
+	if err != nil {
+		err = fmt.Errorf("Dense.WriteFileHeaders().maxID.Error: %v", err)
+		return
+	}
+
+	return
+}
+
+// ReadFileHeaders
+//
+// # English:
+//
+// Read the configuration data at the beginning of the file.
+//
+// # Português:
+//
+// Lê os dados de configuração no início do arquivo.
+func (e *Dense) ReadFileHeaders() (err error) {
+	_, err = e.file.ReadAt(e.dataFile, headerVersionAddress)
+	if err != nil {
+		err = fmt.Errorf("Dense.ReadFileHeaders().version.Error: %v", err)
+		return
+	}
+
+	if string(e.dataFile) != denseHeaderVersion {
+		err = fmt.Errorf("file version header does not match code version: %v != %v", string(e.dataFile), denseHeaderVersion)
+		return
+	}
+
+	_, err = e.file.ReadAt(e.dataFile, headerTotalNodesAddress)
+	if err != nil {
+		err = fmt.Errorf("Dense.ReadFileHeaders().totalOfNodes.Error: %v", err)
+		return
+	}
+	e.totalOfNodesInTmpFile = int64(binary.LittleEndian.Uint64(e.dataFile))
+
+	_, err = e.file.ReadAt(e.dataFile, denseHeaderMaxIdAddress)
+	if err != nil {
+		err = fmt.Errorf("Dense.ReadFileHeaders().maxID.Error: %v", err)
+		return
+	}
+	e.maxID = int64(binary.LittleEndian.Uint64(e.dataFile))
+
+	return
+}
+
+// MountIndexIntoFile
+//
+// # English:
+//
+// The dense file has no index, the address is calculated from the ID. Kept for CompressInterface compatibility.
+//
+// # Português:
+//
+// O arquivo denso não tem índice, o endereço é calculado a partir do ID. Mantido para compatibilidade com
+// CompressInterface.
+func (e *Dense) MountIndexIntoFile() (err error) {
+	return
+}
+
+// IndexToMemory
+//
+// # English:
+//
+// The dense file has no index, the address is calculated from the ID. Kept for CompressInterface compatibility.
+//
+// # Português:
+//
+// O arquivo denso não tem índice, o endereço é calculado a partir do ID. Mantido para compatibilidade com
+// CompressInterface.
+func (e *Dense) IndexToMemory() (err error) {
+	return
+}
@@ -0,0 +1,174 @@
+package compress
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestDense
+//
+// English:
+//
+// # Writes nodes out of order into the dense file and tests the values read, including nodes at coordinate zero
+//
+// Português:
+//
+// Escreve nodes fora de ordem no arquivo denso e testa os valores lidos, inclusive nodes na coordenada zero
+func TestDense(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.Remove("./test.dense.tmp")
+	})
+
+	var err error
+	var nodeList = []Node{
+		{ID: 10, Lon: -179.9999999, Lat: -89.9999999},
+		{ID: 3, Lon: 0.0, Lat: 0.0},
+		{ID: 7, Lon: 180.0, Lat: 90.0},
+		{ID: 1000, Lon: -46.6333094, Lat: -23.5505199},
+	}
+
+	dense := Dense{}
+	dense.Init(0)
+	err = dense.Create("./test.dense.tmp")
+	if err != nil {
+		t.Logf("open file error: %v", err)
+		t.FailNow()
+	}
+
+	for _, node := range nodeList {
+		err = dense.WriteNode(node.ID, node.Lon, node.Lat)
+		if err != nil {
+			t.Logf("write node error: %v", err)
+			t.FailNow()
+		}
+	}
+
+	err = dense.WriteFileHeaders()
+	if err != nil {
+		t.Logf("write header error: %v", err)
+		t.FailNow()
+	}
+	dense.Close()
+
+	dense = Dense{}
+	dense.Init(0)
+	err = dense.OpenForSearch("./test.dense.tmp")
+	if err != nil {
+		t.Logf("open for search error: %v", err)
+		t.FailNow()
+	}
+	defer dense.Close()
+
+	var longitude, latitude float64
+	for _, node := range nodeList {
+		longitude, latitude, err = dense.FindNodeByID(node.ID)
+		if err != nil {
+			t.Logf("FindNodeByID(%v) error: %v", node.ID, err)
+			t.FailNow()
+		}
+
+		if longitude != node.Lon || latitude != node.Lat {
+			t.Logf("FindNodeByID(%v) error: %v, %v != %v, %v", node.ID, longitude, latitude, node.Lon, node.Lat)
+			t.FailNow()
+		}
+	}
+
+	for _, id := range []int64{0, 1, 4, 999, 1001} {
+		_, _, err = dense.FindNodeByID(id)
+		if err != io.EOF {
+			t.Logf("FindNodeByID(%v) error: io.EOF expected, found %v", id, err)
+			t.FailNow()
+		}
+	}
+}
+
+// TestDense_createOverFile
+//
+// English:
+//
+// # Writes two files on the same path, the nodes of the first file must not be found in the second one
+//
+// Português:
+//
+// Escreve dois arquivos no mesmo caminho, os nodes do primeiro arquivo não podem ser encontrados no segundo
+func TestDense_createOverFile(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "test.dense.tmp")
+
+	var err error
+	for _, nodeList := range [][]Node{
+		{{ID: 3, Lon: -48.5, Lat: -27.5}, {ID: 1000, Lon: -46.6333094, Lat: -23.5505199}},
+		{{ID: 1000, Lon: 10.5, Lat: 20.5}},
+	} {
+		dense := Dense{}
+		dense.Init(0)
+		err = dense.Create(path)
+		if err != nil {
+			t.Logf("open file error: %v", err)
+			t.FailNow()
+		}
+
+		for _, node := range nodeList {
+			err = dense.WriteNode(node.ID, node.Lon, node.Lat)
+			if err != nil {
+				t.Logf("write node error: %v", err)
+				t.FailNow()
+			}
+		}
+
+		err = dense.WriteFileHeaders()
+		if err != nil {
+			t.Logf("write header error: %v", err)
+			t.FailNow()
+		}
+		dense.Close()
+	}
+
+	dense := Dense{}
+	dense.Init(0)
+	err = dense.OpenForSearch(path)
+	if err != nil {
+		t.Logf("open for search error: %v", err)
+		t.FailNow()
+	}
+	defer dense.Close()
+
+	longitude, latitude, err := dense.FindNodeByID(3)
+	if err != io.EOF {
+		t.Logf("FindNodeByID(3) error: io.EOF expected, found %v, %v, %v", longitude, latitude, err)
+		t.FailNow()
+	}
+
+	longitude, latitude, err = dense.FindNodeByID(1000)
+	if err != nil || longitude != 10.5 || latitude != 20.5 {
+		t.Logf("FindNodeByID(1000) error: %v, %v, %v", longitude, latitude, err)
+		t.FailNow()
+	}
+}
+
+// TestNewNodeStore
+//
+// English:
+//
+// # Tests the choice between the dense file and the binary search
+//
+// Português:
+//
+// Testa a escolha entre o arquivo denso e a busca binária
+func TestNewNodeStore(t *testing.T) {
+	var dense bool
+	var store NodeStore
+
+	store, dense = NewNodeStore(9000000000, 12000000000, 1000)
+	if _, ok := store.(*Dense); !ok || !dense {
+		t.Logf("planet: *Dense expected, found %T", store)
+		t.FailNow()
+	}
+
+	store, dense = NewNodeStore(50000000, 12000000000, 1000)
+	if _, ok := store.(*Compress); !ok || dense {
+		t.Logf("extract: *Compress expected, found %T", store)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,418 @@
+package compress
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+)
+
+// memorySpillDefaultBlockSize
+//
+// # English:
+//
+// # Block size of the spill file when Init() received zero
+//
+// # Português:
+//
+// Tamanho do bloco do arquivo de despejo quando Init() recebeu zero
+const memorySpillDefaultBlockSize = 1000
+
+// Memory
+//
+// # English:
+//
+// Node store kept in memory, in a slice sorted by ID, for unit tests and small extracts, without temporary files.
+//
+// Implements the same methods as Compress, goosm.CompressInterface, and accepts nodes in any order, on duplicate IDs the
+// last version written is kept. When a spill threshold is defined and the number of nodes passes it, the nodes are moved
+// to a SortWriter over a Compress file, at the path given to Create(), and from then on all the calls are passed to it.
+//
+// # Português:
+//
+// Armazenamento de nodes mantido na memória, em um slice ordenado por ID, para testes unitários e recortes pequenos, sem
+// arquivos temporários.
+//
+// Implementa os mesmos métodos de Compress, goosm.CompressInterface, e aceita nodes em qualquer ordem, em IDs
+// duplicados a última versão escrita é mantida. Quando um limite de despejo é definido e a quantidade de nodes passa
+// dele, os nodes são movidos para um SortWriter sobre um arquivo Compress, no caminho passado para Create(), e a partir
+// daí todas as chamadas são repassadas a ele.
+type Memory struct {
+
+	// # English:
+	//
+	// Nodes written, sorted by ID when sorted is true.
+	//
+	// # Português:
+	//
+	// Nodes escritos, ordenados por ID quando sorted é true.
+	nodes  []Node
+	sorted bool
+
+	// # English:
+	//
+	// Spacing between ID captures for the in-memory index of the spill file.
+	//
+	// # Português:
+	//
+	// Espaçamento entre as capturas de IDs para o índice em memória do arquivo de despejo.
+	blockSize int64
+
+	// # English:
+	//
+	// Path given to Create(), used only by the spill file.
+	//
+	// # Português:
+	//
+	// Caminho passado para Create(), usado apenas pelo arquivo de despejo.
+	path string
+
+	// # English:
+	//
+	// Number of nodes that triggers the spill to disk, 0 never spills.
+	//
+	// # Português:
+	//
+	// Quantidade de nodes que dispara o despejo para o disco, 0 nunca despeja.
+	spillThreshold int64
+
+	// # English:
+	//
+	// Store on disk after the spill, nil while the nodes are in memory.
+	//
+	// # Português:
+	//
+	// Armazenamento no disco depois do despejo, nil enquanto os nodes estão na memória.
+	spill NodeStore
+}
+
+// SetSpillThreshold
+//
+// # English:
+//
+// Defines the number of nodes from which the nodes are moved to disk, at the path given to Create(). Each node takes 24
+// bytes in memory. Default 0, the nodes never leave memory.
+//
+// # Português:
+//
+// Define a quantidade de nodes a partir da qual os nodes são movidos para o disco, no caminho passado para Create().
+// Cada node ocupa 24 bytes na memória. Padrão 0, os nodes nunca saem da memória.
+func (e *Memory) SetSpillThreshold(totalOfNodes int64) {
+	e.spillThreshold = totalOfNodes
+}
+
+// Spilled
+//
+// # English:
+//
+// Returns true when the nodes were moved to disk.
+//
+// # Português:
+//
+// Devolve true quando os nodes foram movidos para o disco.
+func (e *Memory) Spilled() bool {
+	return e.spill != nil
+}
+
+// Init
+//
+// # English:
+//
+// Initializes the object.
+//
+//	Input:
+//	  blockSize: Spacing between ID captures for the in-memory index of the spill file, not used in memory.
+//
+// # Português:
+//
+// Inicializa o objeto.
+//
+//	Entrada:
+//	  blockSize: Espaçamento entre as capturas de IDs para o índice em memória do arquivo de despejo, não usado na
+//	  memória.
+func (e *Memory) Init(blockSize int64) {
+	if e.spill != nil {
+		e.spill.Close()
+	}
+
+	e.nodes = make([]Node, 0)
+	e.sorted = true
+	e.blockSize = blockSize
+	e.spill = nil
+}
+
+// Round
+//
+// # English:
+//
+// # Rounds a floating point to N decimal places
+//
+// # Português:
+//
+// Arredonda um ponto flutuante para N casas decimais
+func (e *Memory) Round(value, places float64) float64 {
+	return (&Compress{}).Round(value, places)
+}
+
+// Create
+//
+// # English:
+//
+// Keeps the path for the spill file, no file is created while the nodes are in memory.
+//
+// # Português:
+//
+// Guarda o caminho para o arquivo de despejo, nenhum arquivo é criado enquanto os nodes estão na memória.
+func (e *Memory) Create(path string) (err error) {
+	e.path = path
+	return
+}
+
+// Close
+//
+// # English:
+//
+// Releases the nodes and closes the spill file.
+//
+// # Português:
+//
+// Libera os nodes e fecha o arquivo de despejo.
+func (e *Memory) Close() {
+	if e.spill != nil {
+		e.spill.Close()
+		e.spill = nil
+	}
+
+	e.nodes = nil
+}
+
+// WriteNode
+//
+// # English:
+//
+// Adds the node, in any order.
+//
+//	Input:
+//	  id: positive number greater than zero;
+//	  longitude: value between ±180 to 7 decimal places;
+//	  latitude: value between ±90 with 7 decimal places;
+//
+// # Português:
+//
+// Adiciona o node, em qualquer ordem.
+//
+//	Entrada:
+//	  id: número positivo maior do que zero;
+//	  longitude: valor entre ±180 com 7 casas decimais;
+//	  latitude: valor entre ±90 com 7 casas decimais;
+func (e *Memory) WriteNode(id int64, longitude, latitude float64) (err error) {
+	if e.spill != nil {
+		return e.spill.WriteNode(id, longitude, latitude)
+	}
+
+	if id < 1 {
+		err = errors.New("id must be greater than zero")
+		return
+	}
+
+	if longitude < -180.0 || longitude > 180.0 {
+		err = errors.New("longitude must be within ±180˚")
+		return
+	}
+
+	if latitude < -90.0 || latitude > 90.0 {
+		err = errors.New("latitude must be within ±90˚")
+		return
+	}
+
+	if len(e.nodes) != 0 && e.nodes[len(e.nodes)-1].ID >= id {
+		e.sorted = false
+	}
+	e.nodes = append(e.nodes, Node{ID: id, Lon: longitude, Lat: latitude})
+
+	if e.spillThreshold > 0 && int64(len(e.nodes)) > e.spillThreshold {
+		err = e.spillToDisk()
+		if err != nil {
+			err = fmt.Errorf("Memory.WriteNode().spillToDisk().Error: %v", err)
+			return
+		}
+	}
+
+	return
+}
+
+// FindNodeByID
+//
+// # English:
+//
+// Search for longitude and latitude.
+//
+//	Input:
+//	  id: ID of the node sought.
+//
+//	Output:
+//	  longitude: value between ±180 width 7 decimal places;
+//	  latitude: value between ±90 width 7 decimal places;
+//	  err: pattern object, with io.EOF error when value not found
+//
+// # Português:
+//
+// Procura por longitude e latitude.
+//
+//	Entrada:
+//	  id: ID do node procurado.
+//
+//	Saída:
+//	  longitude: valor entre ±180 com 7 casas decimais;
+//	  latitude: valor entre ±90 com 7 casas decimais;
+//	  err: objeto de padrão, com erro io.EOF quando o valor não é encontrado
+func (e *Memory) FindNodeByID(id int64) (longitude, latitude float64, err error) {
+	if e.spill != nil {
+		return e.spill.FindNodeByID(id)
+	}
+
+	e.sort()
+
+	i := sort.Search(len(e.nodes), func(i int) bool { return e.nodes[i].ID >= id })
+	if i == len(e.nodes) || e.nodes[i].ID != id {
+		err = io.EOF
+		return
+	}
+
+	longitude = e.nodes[i].Lon
+	latitude = e.nodes[i].Lat
+	return
+}
+
+// WriteFileHeaders
+//
+// # English:
+//
+// Sorts the nodes, or writes the headers of the spill file.
+//
+// # Português:
+//
+// Ordena os nodes, ou escreve os cabeçalhos do arquivo de despejo.
+func (e *Memory) WriteFileHeaders() (err error) {
+	if e.spill != nil {
+		return e.spill.WriteFileHeaders()
+	}
+
+	e.sort()
+	return
+}
+
+// ReadFileHeaders
+//
+// # English:
+//
+// Does nothing in memory, or reads the headers of the spill file.
+//
+// # Português:
+//
+// Não faz nada na memória, ou lê os cabeçalhos do arquivo de despejo.
+func (e *Memory) ReadFileHeaders() (err error) {
+	if e.spill != nil {
+		return e.spill.ReadFileHeaders()
+	}
+
+	return
+}
+
+// MountIndexIntoFile
+//
+// # English:
+//
+// Sorts the nodes, or saves the indexes of the spill file.
+//
+// # Português:
+//
+// Ordena os nodes, ou salva os índices do arquivo de despejo.
+func (e *Memory) MountIndexIntoFile() (err error) {
+	if e.spill != nil {
+		return e.spill.MountIndexIntoFile()
+	}
+
+	e.sort()
+	return
+}
+
+// IndexToMemory
+//
+// # English:
+//
+// Does nothing in memory, or loads the indexes of the spill file.
+//
+// # Português:
+//
+// Não faz nada na memória, ou carrega os índices do arquivo de despejo.
+func (e *Memory) IndexToMemory() (err error) {
+	if e.spill != nil {
+		return e.spill.IndexToMemory()
+	}
+
+	return
+}
+
+// sort
+//
+// # English:
+//
+// Sorts the nodes by ID, keeping the last version of duplicate IDs, only when a node was written out of order.
+//
+// # Português:
+//
+// Ordena os nodes por ID, mantendo a última versão de IDs duplicados, apenas quando um node foi escrito fora de ordem.
+func (e *Memory) sort() {
+	if e.sorted {
+		return
+	}
+
+	e.nodes = sortNodes(e.nodes)
+	e.sorted = true
+}
+
+// spillToDisk
+//
+// # English:
+//
+// Moves the nodes to a SortWriter over a Compress file at the path given to Create().
+//
+// # Português:
+//
+// Move os nodes para um SortWriter sobre um arquivo Compress no caminho passado para Create().
+func (e *Memory) spillToDisk() (err error) {
+	if e.path == "" {
+		err = errors.New("the spill threshold was reached, but Create() was not called with a path")
+		return
+	}
+
+	// # English: Compress divides the nodes by the block size
+	// # Português: Compress divide os nodes pelo tamanho do bloco
+	var blockSize = e.blockSize
+	if blockSize <= 0 {
+		blockSize = memorySpillDefaultBlockSize
+	}
+
+	var spill = &SortWriter{}
+	spill.SetTarget(&Compress{})
+	spill.SetTempDir(filepath.Dir(e.path))
+	spill.Init(blockSize)
+	err = spill.Create(e.path)
+	if err != nil {
+		return
+	}
+
+	for _, node := range e.nodes {
+		err = spill.WriteNode(node.ID, node.Lon, node.Lat)
+		if err != nil {
+			spill.Close()
+			return
+		}
+	}
+
+	e.spill = spill
+	e.nodes = nil
+	return
+}
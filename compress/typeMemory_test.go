@@ -0,0 +1,98 @@
+package compress
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+)
+
+// TestMemory
+//
+// English:
+//
+// # Writes nodes out of order and with duplicate IDs in memory, with and without spill to disk, and tests the values read
+//
+// Português:
+//
+// Escreve nodes fora de ordem e com IDs duplicados na memória, com e sem despejo para o disco, e testa os valores lidos
+func TestMemory(t *testing.T) {
+	var err error
+	for _, spillThreshold := range []int64{0, 100} {
+		memory := Memory{}
+		memory.Init(10)
+		memory.SetSpillThreshold(spillThreshold)
+		err = memory.Create(filepath.Join(t.TempDir(), "test.node.memory.tmp"))
+		if err != nil {
+			t.Logf("create error: %v", err)
+			t.FailNow()
+		}
+
+		for id := int64(999); id > 0; id -= 2 {
+			err = memory.WriteNode(id, 1.0, 1.0)
+			if err != nil {
+				t.Logf("write node error: %v", err)
+				t.FailNow()
+			}
+		}
+
+		for id := int64(3); id < 1000; id += 6 {
+			err = memory.WriteNode(id, float64(id)/10000000, -float64(id)/10000000)
+			if err != nil {
+				t.Logf("write node error: %v", err)
+				t.FailNow()
+			}
+		}
+
+		if memory.Spilled() != (spillThreshold != 0) {
+			t.Logf("spill threshold %v: spilled: %v", spillThreshold, memory.Spilled())
+			t.FailNow()
+		}
+
+		err = memory.WriteFileHeaders()
+		if err != nil {
+			t.Logf("write header error: %v", err)
+			t.FailNow()
+		}
+
+		err = memory.MountIndexIntoFile()
+		if err != nil {
+			t.Logf("mount index error: %v", err)
+			t.FailNow()
+		}
+
+		err = memory.ReadFileHeaders()
+		if err != nil {
+			t.Logf("read header error: %v", err)
+			t.FailNow()
+		}
+
+		err = memory.IndexToMemory()
+		if err != nil {
+			t.Logf("index to memory error: %v", err)
+			t.FailNow()
+		}
+
+		for id := int64(1); id <= 1000; id++ {
+			var lon, lat float64
+			lon, lat, err = memory.FindNodeByID(id)
+			if id%2 == 0 {
+				if err != io.EOF {
+					t.Logf("spill threshold %v: id %v should not be found: %v", spillThreshold, id, err)
+					t.FailNow()
+				}
+				continue
+			}
+
+			wantLon, wantLat := 1.0, 1.0
+			if id%3 == 0 {
+				wantLon, wantLat = float64(id)/10000000, -float64(id)/10000000
+			}
+
+			if err != nil || lon != wantLon || lat != wantLat {
+				t.Logf("spill threshold %v: node %v: %v, %v, %v", spillThreshold, id, lon, lat, err)
+				t.FailNow()
+			}
+		}
+		memory.Close()
+	}
+}
@@ -0,0 +1,85 @@
+package compress
+
+// NodeStore
+//
+// # English:
+//
+// Methods in common between Compress and Dense, the same as goosm.CompressInterface.
+//
+// # Português:
+//
+// Métodos em comum entre Compress e Dense, os mesmos de goosm.CompressInterface.
+type NodeStore interface {
+	Init(blockSize int64)
+	Round(value, places float64) float64
+	Create(path string) (err error)
+	Close()
+	WriteNode(id int64, longitude, latitude float64) (err error)
+	FindNodeByID(id int64) (longitude, latitude float64, err error)
+	IndexToMemory() (err error)
+	MountIndexIntoFile() (err error)
+	WriteFileHeaders() (err error)
+	ReadFileHeaders() (err error)
+}
+
+// denseMinimumDensity
+//
+// # English:
+//
+// Minimum ratio between the number of nodes and the greatest node ID for the dense file to be chosen.
+// With 25%, the dense file, greatest ID * 8 bytes, is at most twice the size of the Compress file, nodes * 16 bytes.
+//
+// # Português:
+//
+// Razão mínima entre a quantidade de nodes e o maior ID de node para o arquivo denso ser escolhido.
+// Com 25%, o arquivo denso, maior ID * 8 bytes, tem no máximo o dobro do tamanho do arquivo de Compress, nodes * 16
+// bytes.
+const denseMinimumDensity = 0.25
+
+// NewNodeStore
+//
+// # English:
+//
+// Chooses between Dense and Compress from the number of nodes and the greatest node ID of the pbf file, and returns the
+// object already initialized.
+//
+//	Input:
+//	  totalOfNodes: number of nodes in the pbf file;
+//	  maxNodeID: greatest node ID in the pbf file;
+//	  blockSize: spacing between ID captures for the in-memory index of Compress.
+//
+//	Output:
+//	  store: *Dense for planet-sized files, where node IDs are dense, or *Compress for extracts;
+//	  dense: true when *Dense was chosen.
+//
+//	Note:
+//	  * The pbf header does not contain the number of nodes, see goosm.PbfProcess.NodeStatistics().
+//
+// # Português:
+//
+// Escolhe entre Dense e Compress a partir da quantidade de nodes e do maior ID de node do arquivo pbf, e devolve o
+// objeto já inicializado.
+//
+//	Entrada:
+//	  totalOfNodes: quantidade de nodes no arquivo pbf;
+//	  maxNodeID: maior ID de node no arquivo pbf;
+//	  blockSize: espaçamento entre as capturas de IDs para o índice em memória de Compress.
+//
+//	Saída:
+//	  store: *Dense para arquivos do tamanho do planeta, onde os IDs de nodes são densos, ou *Compress para recortes;
+//	  dense: true quando *Dense foi escolhido.
+//
+//	Nota:
+//	  * O cabeçalho pbf não contém a quantidade de nodes, veja goosm.PbfProcess.NodeStatistics().
+func NewNodeStore(totalOfNodes, maxNodeID, blockSize int64) (store NodeStore, dense bool) {
+	if maxNodeID > 0 && float64(totalOfNodes)/float64(maxNodeID) >= denseMinimumDensity {
+		store = &Dense{}
+		store.Init(blockSize)
+		dense = true
+		return
+	}
+
+	store = &Compress{}
+	store.Init(blockSize)
+	return
+}
@@ -0,0 +1,405 @@
+package compress
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+)
+
+// Reader
+//
+// # English:
+//
+// Read-only access to the node file, version 1 or 2, safe for concurrent use by multiple goroutines.
+//
+// Compress shares its buffers, dataFile and dataCoordinate, between calls and can only be used by one goroutine at a
+// time. Reader does not keep state between searches, it only uses ReadAt(), which is safe for concurrent use, or the
+// file mapped into memory, so the ways can be resolved by a pool of workers.
+//
+//	Note:
+//	  * Reader only reads complete files, after WriteFileHeaders() and MountIndexIntoFile().
+//
+// # Português:
+//
+// Acesso apenas de leitura ao arquivo de nodes, versão 1 ou 2, seguro para uso concorrente por várias goroutines.
+//
+// Compress compartilha seus buffers, dataFile e dataCoordinate, entre as chamadas e só pode ser usado por uma goroutine
+// por vez. Reader não guarda estado entre as buscas, apenas usa ReadAt(), que é seguro para uso concorrente, ou o
+// arquivo mapeado na memória, assim os ways podem ser resolvidos por um grupo de workers.
+//
+//	Nota:
+//	  * Reader só lê arquivos completos, depois de WriteFileHeaders() e MountIndexIntoFile().
+type Reader struct {
+
+	// # English:
+	//
+	// Pointer to the node file.
+	//
+	// # Português:
+	//
+	// Ponteiro para o arquivo de nodes.
+	file *os.File
+
+	// # English:
+	//
+	// File mapped into memory, nil when the search uses ReadAt().
+	//
+	// # Português:
+	//
+	// Arquivo mapeado na memória, nil quando a busca usa ReadAt().
+	mapped []byte
+
+	// # English:
+	//
+	// Version of the file format, headerVersion or headerVersion2.
+	//
+	// # Português:
+	//
+	// Versão do formato do arquivo, headerVersion ou headerVersion2.
+	version string
+
+	// # English:
+	//
+	// Address of the end of the data and start of the indexes.
+	//
+	// # Português:
+	//
+	// Endereço do fim dos dados e início dos índices.
+	indexesAddress int64
+
+	// # English:
+	//
+	// In-memory index, the same as Compress.memory. Only read after Open().
+	//
+	// # Português:
+	//
+	// Índice em memória, o mesmo de Compress.memory. Apenas lido depois de Open().
+	memory [][2]int64
+}
+
+// Open
+//
+// # English:
+//
+// Opens the node file read-only, loads the indexes into memory and maps the file into memory when possible.
+//
+//	Input:
+//	  path: Binary file path
+//
+//	Note:
+//	  * When mapping fails, the error is logged and the search falls back to ReadAt().
+//
+// # Português:
+//
+// Abre o arquivo de nodes apenas para leitura, carrega os índices na memória e mapeia o arquivo na memória quando
+// possível.
+//
+//	Entrada:
+//	  path: Caminho do arquivo binário
+//
+//	Nota:
+//	  * Quando o mapeamento falha, o erro é registrado no log e a busca volta a usar ReadAt().
+func (e *Reader) Open(path string) (err error) {
+	e.Close()
+
+	var compress = Compress{}
+	compress.Init(0)
+	err = compress.OpenForSearch(path)
+	if err != nil {
+		err = fmt.Errorf("Reader.Open().OpenForSearch().Error: %v", err)
+		return
+	}
+
+	e.file = compress.file
+	e.version = compress.version
+	e.indexesAddress = compress.indexesAddress
+	e.memory = compress.memory
+
+	var mapped []byte
+	mapped, err = mmapFile(e.file)
+	if err != nil {
+		log.Printf("Reader.Open().event: mmap failed, using ReadAt(): %v", err)
+		err = nil
+		return
+	}
+
+	e.mapped = mapped
+	return
+}
+
+// Close
+//
+// # English:
+//
+// Releases the mapped memory and closes the file.
+//
+// # Português:
+//
+// Libera a memória mapeada e fecha o arquivo.
+func (e *Reader) Close() {
+	var err error
+	if e.mapped != nil {
+		err = munmapFile(e.mapped)
+		if err != nil {
+			log.Printf("Reader.Close().munmap().error: %v", err)
+		}
+		e.mapped = nil
+	}
+
+	if e.file != nil {
+		err = e.file.Close()
+		if err != nil {
+			log.Printf("Reader.Close().error: %v", err)
+		}
+		e.file = nil
+	}
+}
+
+// FindNodeByID
+//
+// # English:
+//
+// Search for longitude and latitude in the node file.
+//
+//	Input:
+//	  id: ID of the node sought.
+//
+//	Output:
+//	  longitude: value between ±180 width 7 decimal places;
+//	  latitude: value between ±90 width 7 decimal places;
+//	  err: pattern object, with io.EOF error when value not found in file
+//
+// # Português:
+//
+// Procura por longitude e latitude no arquivo de nodes.
+//
+//	Entrada:
+//	  id: ID do node procurado.
+//
+//	Saída:
+//	  longitude: valor entre ±180 com 7 casas decimais;
+//	  latitude: valor entre ±90 com 7 casas decimais;
+//	  err: objeto de padrão, com erro io.EOF quando o valor não é encontrado no arquivo
+func (e *Reader) FindNodeByID(id int64) (longitude, latitude float64, err error) {
+	var loc [][2]float64
+	var errList []error
+	loc, errList = e.FindNodesByIDs([]int64{id})
+	return loc[0][0], loc[0][1], errList[0]
+}
+
+// FindNodesByIDs
+//
+// # English:
+//
+// Search for the coordinates of a list of nodes, usually the nodes of a way.
+//
+// The IDs are sorted internally and the file is walked only once, each block of the in-memory index is read and
+// searched only once for all the IDs it contains.
+//
+//	Input:
+//	  ids: list of node IDs, in any order and with repetition.
+//
+//	Output:
+//	  loc: coordinates, [longitude, latitude], in the same order as ids;
+//	  errList: error of each ID, in the same order as ids, nil when found and io.EOF when not found in file.
+//
+// # Português:
+//
+// Procura pelas coordenadas de uma lista de nodes, normalmente os nodes de um way.
+//
+// Os IDs são ordenados internamente e o arquivo é percorrido apenas uma vez, cada bloco do índice em memória é lido e
+// pesquisado apenas uma vez para todos os IDs que ele contém.
+//
+//	Entrada:
+//	  ids: lista de IDs de nodes, em qualquer ordem e com repetição.
+//
+//	Saída:
+//	  loc: coordenadas, [longitude, latitude], na mesma ordem de ids;
+//	  errList: erro de cada ID, na mesma ordem de ids, nil quando encontrado e io.EOF quando não encontrado no arquivo.
+func (e *Reader) FindNodesByIDs(ids []int64) (loc [][2]float64, errList []error) {
+	loc = make([][2]float64, len(ids))
+	errList = make([]error, len(ids))
+
+	// # English: positions of ids sorted by ID, so that the result keeps the order of the way
+	// # Português: posições de ids ordenadas por ID, para que o resultado mantenha a ordem do way
+	var order = make([]int, len(ids))
+	for key := range order {
+		order[key] = key
+	}
+	sort.Slice(order, func(a, b int) bool { return ids[order[a]] < ids[order[b]] })
+
+	for start := 0; start != len(order); {
+		block := e.blockOf(ids[order[start]])
+
+		// # English: all IDs of the same block of the in-memory index
+		// # Português: todos os IDs do mesmo bloco do índice em memória
+		end := start + 1
+		for end != len(order) && e.blockOf(ids[order[end]]) == block {
+			end++
+		}
+
+		e.findIntoBlock(block, ids, order[start:end], loc, errList)
+		start = end
+	}
+
+	return
+}
+
+// blockOf
+//
+// # English:
+//
+// Returns the key of the in-memory index whose ID is the greatest ID less than or equal to id, or -1.
+//
+// # Português:
+//
+// Devolve a chave do índice em memória cujo ID é o maior ID menor ou igual a id, ou -1.
+func (e *Reader) blockOf(id int64) (block int) {
+	return sort.Search(len(e.memory), func(i int) bool { return e.memory[i][memorySliceAddrID] > id }) - 1
+}
+
+// findIntoBlock
+//
+// # English:
+//
+// Reads the block once and looks for all the IDs of positions.
+//
+// # Português:
+//
+// Lê o bloco uma vez e procura todos os IDs de positions.
+func (e *Reader) findIntoBlock(block int, ids []int64, positions []int, loc [][2]float64, errList []error) {
+	var err error
+	var data []byte
+
+	if block < 0 {
+		err = io.EOF
+	} else {
+		data, err = e.readBlock(block)
+	}
+
+	if err != nil {
+		for _, position := range positions {
+			errList[position] = err
+		}
+		return
+	}
+
+	if e.version == headerVersion2 {
+		var nodes [][3]int64
+		nodes, err = decodeNodes(data)
+		for _, position := range positions {
+			if err != nil {
+				errList[position] = fmt.Errorf("Reader.FindNodesByIDs().decodeNodes().Error: %v", err)
+				continue
+			}
+
+			k := sort.Search(len(nodes), func(k int) bool { return nodes[k][0] >= ids[position] })
+			if k == len(nodes) || nodes[k][0] != ids[position] {
+				errList[position] = io.EOF
+				continue
+			}
+
+			loc[position] = [2]float64{float64(nodes[k][1]) / decimalPlaces, float64(nodes[k][2]) / decimalPlaces}
+		}
+		return
+	}
+
+	total := len(data) / nodeDataByteSize
+	for _, position := range positions {
+		k := sort.Search(total, func(k int) bool { return int64(binary.LittleEndian.Uint64(data[k*nodeDataByteSize:])) >= ids[position] })
+		if k == total || int64(binary.LittleEndian.Uint64(data[k*nodeDataByteSize:])) != ids[position] {
+			errList[position] = io.EOF
+			continue
+		}
+
+		loc[position] = [2]float64{
+			coordinateFromBytes(data[k*nodeDataByteSize+nodeIdByteSize:]),
+			coordinateFromBytes(data[k*nodeDataByteSize+nodeIdByteSize+nodeCoordinateByteSize:]),
+		}
+	}
+}
+
+// readBlock
+//
+// # English:
+//
+// Returns the bytes of the block, from the mapped memory or read by ReadAt().
+//
+//	Note:
+//	  * Version 1: from the node of the index to the node of the next index, inclusive, or the last node.
+//	  * Version 2: from the start of the block to the start of the next block, or the start of the indexes.
+//
+// # Português:
+//
+// Devolve os bytes do bloco, da memória mapeada ou lidos por ReadAt().
+//
+//	Nota:
+//	  * Versão 1: do node do índice até o node do próximo índice, inclusive, ou o último node.
+//	  * Versão 2: do início do bloco até o início do próximo bloco, ou o início dos índices.
+func (e *Reader) readBlock(block int) (data []byte, err error) {
+	leftBound := e.memory[block][memorySliceAddrOfAddrIntoFile]
+	var rightBound int64
+
+	switch {
+	case e.version == headerVersion2 && block+1 < len(e.memory):
+		rightBound = e.memory[block+1][memorySliceAddrOfAddrIntoFile]
+	case e.version == headerVersion2:
+		rightBound = e.indexesAddress
+	case block+1 < len(e.memory):
+		rightBound = e.memory[block+1][memorySliceAddrOfAddrIntoFile] + nodeDataByteSize
+	default:
+		rightBound = leftBound + nodeDataByteSize
+	}
+
+	if rightBound < leftBound {
+		err = errors.New("Reader.readBlock().error: corrupted index")
+		return
+	}
+
+	if e.mapped != nil && rightBound <= int64(len(e.mapped)) {
+		data = e.mapped[leftBound:rightBound]
+		return
+	}
+
+	data = make([]byte, rightBound-leftBound)
+	_, err = e.file.ReadAt(data, leftBound)
+	if err != nil {
+		err = fmt.Errorf("Reader.readBlock().ReadAt(%v).Error: %v", leftBound, err)
+	}
+	return
+}
+
+// decodeNodes
+//
+// # English:
+//
+// Decodes a version 2 block into a list of ID, longitude and latitude, the last two multiplied by 10,000,000.
+//
+// # Português:
+//
+// Decodifica um bloco da versão 2 em uma lista de ID, longitude e latitude, as duas últimas multiplicadas por
+// 10.000.000.
+func decodeNodes(block []byte) (nodes [][3]int64, err error) {
+	nodes = make([][3]int64, 0)
+
+	var last [3]int64
+	for len(block) != 0 {
+		for key := range last {
+			delta, n := binary.Varint(block)
+			if n <= 0 {
+				err = errors.New("invalid varint")
+				return
+			}
+
+			last[key] += delta
+			block = block[n:]
+		}
+		nodes = append(nodes, last)
+	}
+
+	return
+}
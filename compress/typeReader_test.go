@@ -0,0 +1,120 @@
+package compress
+
+import (
+	"io"
+	"os"
+	"sync"
+	"testing"
+)
+
+// TestReader
+//
+// English:
+//
+// # Writes version 1 and version 2 files and searches the nodes with several goroutines at the same time
+//
+// Português:
+//
+// Escreve arquivos versão 1 e versão 2 e procura os nodes com várias goroutines ao mesmo tempo
+func TestReader(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.Remove("./test.node.reader.tmp")
+	})
+
+	var err error
+	var testLimit = 1000
+
+	for _, version := range []int{1, 2} {
+		compress := Compress{}
+		compress.Init(32)
+		_ = os.Remove("./test.node.reader.tmp")
+		err = compress.SetFormatVersion(version)
+		if err != nil {
+			t.Logf("set format version error: %v", err)
+			t.FailNow()
+		}
+
+		err = compress.Create("./test.node.reader.tmp")
+		if err != nil {
+			t.Logf("open file error: %v", err)
+			t.FailNow()
+		}
+
+		for i := int64(0); i != int64(testLimit); i++ {
+			err = compress.WriteNode(i*2+1, float64(i)*0.001-120.0, 45.0-float64(i)*0.001)
+			if err != nil {
+				t.Logf("write node error: %v", err)
+				t.FailNow()
+			}
+		}
+
+		err = compress.WriteFileHeaders()
+		if err != nil {
+			t.Logf("write header error: %v", err)
+			t.FailNow()
+		}
+
+		err = compress.MountIndexIntoFile()
+		if err != nil {
+			t.Logf("write index error: %v", err)
+			t.FailNow()
+		}
+		compress.Close()
+
+		reader := Reader{}
+		err = reader.Open("./test.node.reader.tmp")
+		if err != nil {
+			t.Logf("open reader error: %v", err)
+			t.FailNow()
+		}
+
+		var wg sync.WaitGroup
+		var errList = make([]error, 8)
+		for worker := 0; worker != len(errList); worker++ {
+			wg.Add(1)
+			go func(worker int) {
+				defer wg.Done()
+
+				// way with unsorted, repeated and missing IDs
+				ids := []int64{int64(worker*100 + 41), 1, int64(worker*100 + 1), 2, int64(testLimit*2 - 1), int64(worker*100 + 41), int64(testLimit * 2)}
+				loc, findErrList := reader.FindNodesByIDs(ids)
+				for key, id := range ids {
+					if id%2 == 0 || id > int64(testLimit*2) {
+						if findErrList[key] != io.EOF {
+							errList[worker] = findErrList[key]
+							return
+						}
+						continue
+					}
+
+					if findErrList[key] != nil {
+						errList[worker] = findErrList[key]
+						return
+					}
+
+					i := (id - 1) / 2
+					if compress.Round(loc[key][0], 6) != compress.Round(float64(i)*0.001-120.0, 6) || compress.Round(loc[key][1], 6) != compress.Round(45.0-float64(i)*0.001, 6) {
+						errList[worker] = io.ErrUnexpectedEOF
+						return
+					}
+				}
+			}(worker)
+		}
+		wg.Wait()
+
+		for worker, workerErr := range errList {
+			if workerErr != nil {
+				t.Logf("version %v: worker %v error: %v", version, worker, workerErr)
+				t.FailNow()
+			}
+		}
+
+		longitude, latitude, err := reader.FindNodeByID(3)
+		if err != nil || compress.Round(longitude, 6) != -119.999 || compress.Round(latitude, 6) != 44.999 {
+			t.Logf("version %v: FindNodeByID(3) error: %v, %v, %v", version, longitude, latitude, err)
+			t.FailNow()
+		}
+
+		reader.Close()
+	}
+}
@@ -0,0 +1,636 @@
+package compress
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"sort"
+)
+
+const (
+
+	// sortWriterDefaultMemoryLimit
+	//
+	// # English:
+	//
+	// Default memory used by the run buffer, 1GB, enough for a build machine with 8GB
+	//
+	// # Português:
+	//
+	// Memória padrão usada pelo buffer de execução, 1GB, suficiente para uma máquina de build com 8GB
+	sortWriterDefaultMemoryLimit = 1024 * 1024 * 1024
+
+	// sortWriterNodeByteSize
+	//
+	// # English:
+	//
+	// Number of bytes occupied by a node in memory and in the run files, ID, longitude and latitude as float64, without
+	// loss of precision before the final file
+	//
+	// # Português:
+	//
+	// Quantidade de bytes ocupada por um node na memória e nos arquivos de execução, ID, longitude e latitude como
+	// float64, sem perda de precisão antes do arquivo final
+	sortWriterNodeByteSize = 3 * int64ByteSize
+
+	// sortWriterReadBufferSize
+	//
+	// # English:
+	//
+	// Read buffer of each run file during the merge
+	//
+	// # Português:
+	//
+	// Buffer de leitura de cada arquivo de execução durante a junção
+	sortWriterReadBufferSize = 64 * 1024
+)
+
+// SortWriter
+//
+// # English:
+//
+//	Compress.WriteNode() only accepts IDs in ascending order, which rules out merged extracts, OSM XML produced by
+//	editors and history files. SortWriter accepts nodes in any order and writes them sorted into the final node file.
+//
+//	Nodes are buffered in memory up to the memory limit, then the buffer is sorted and spilled to a temporary run file.
+//	When WriteFileHeaders() is called, the runs are merged, k-way, into the target, Compress or Dense, and from then on
+//	all the calls are passed to the target, so SortWriter can be used wherever CompressInterface is expected.
+//
+//	On duplicate IDs, the last version written is kept.
+//
+// # Português:
+//
+//	Compress.WriteNode() só aceita IDs em ordem crescente, o que exclui recortes mesclados, OSM XML produzido por
+//	editores e arquivos de histórico. SortWriter aceita nodes em qualquer ordem e os escreve ordenados no arquivo de
+//	nodes final.
+//
+//	Os nodes são guardados em memória até o limite de memória, em seguida o buffer é ordenado e despejado em um arquivo
+//	de execução temporário. Quando WriteFileHeaders() é chamada, as execuções são mescladas, k-way, no destino, Compress
+//	ou Dense, e a partir daí todas as chamadas são repassadas ao destino, assim SortWriter pode ser usado onde
+//	CompressInterface é esperada.
+//
+//	Em IDs duplicados, a última versão escrita é mantida.
+type SortWriter struct {
+
+	// # English:
+	//
+	// Final node file, Compress or Dense.
+	//
+	// # Português:
+	//
+	// Arquivo de nodes final, Compress ou Dense.
+	target NodeStore
+
+	// # English:
+	//
+	// Maximum memory, in bytes, used by the run buffer.
+	//
+	// # Português:
+	//
+	// Memória máxima, em bytes, usada pelo buffer de execução.
+	memoryLimit int64
+
+	// # English:
+	//
+	// Folder of the temporary run files, os.TempDir() when empty.
+	//
+	// # Português:
+	//
+	// Pasta dos arquivos de execução temporários, os.TempDir() quando vazia.
+	tempDir string
+
+	// # English:
+	//
+	// Run being mounted in memory.
+	//
+	// # Português:
+	//
+	// Execução sendo montada na memória.
+	buffer []Node
+
+	// # English:
+	//
+	// Paths of the run files already spilled, in order of writing.
+	//
+	// # Português:
+	//
+	// Caminhos dos arquivos de execução já despejados, em ordem de escrita.
+	runs []string
+
+	// # English:
+	//
+	// True after the runs have been merged into the target.
+	//
+	// # Português:
+	//
+	// True depois das execuções terem sido mescladas no destino.
+	merged bool
+}
+
+// SetTarget
+//
+// # English:
+//
+// Defines the final node file, Compress or Dense, already initialized. Must be called before Init().
+//
+// # Português:
+//
+// Define o arquivo de nodes final, Compress ou Dense, já inicializado. Deve ser chamada antes de Init().
+func (e *SortWriter) SetTarget(target NodeStore) {
+	e.target = target
+}
+
+// SetMemoryLimit
+//
+// # English:
+//
+// Defines the maximum memory, in bytes, used by the run buffer. Each node takes 24 bytes. Default 1GB.
+//
+// # Português:
+//
+// Define a memória máxima, em bytes, usada pelo buffer de execução. Cada node ocupa 24 bytes. Padrão 1GB.
+func (e *SortWriter) SetMemoryLimit(memoryLimit int64) {
+	e.memoryLimit = memoryLimit
+}
+
+// SetTempDir
+//
+// # English:
+//
+// Defines the folder of the temporary run files. Default os.TempDir().
+//
+// # Português:
+//
+// Define a pasta dos arquivos de execução temporários. Padrão os.TempDir().
+func (e *SortWriter) SetTempDir(tempDir string) {
+	e.tempDir = tempDir
+}
+
+// Init
+//
+// # English:
+//
+// Initializes the object and the target.
+//
+//	Input:
+//	  blockSize: Spacing between ID captures for the in-memory index of the target.
+//
+// # Português:
+//
+// Inicializa o objeto e o destino.
+//
+//	Entrada:
+//	  blockSize: Espaçamento entre as capturas de IDs para o índice em memória do destino.
+func (e *SortWriter) Init(blockSize int64) {
+	if e.target == nil {
+		e.target = &Compress{}
+	}
+	e.target.Init(blockSize)
+
+	if e.memoryLimit <= 0 {
+		e.memoryLimit = sortWriterDefaultMemoryLimit
+	}
+
+	e.removeRuns()
+	e.buffer = make([]Node, 0)
+	e.merged = false
+}
+
+// Round
+//
+// # English:
+//
+// # Rounds a floating point to N decimal places
+//
+// # Português:
+//
+// Arredonda um ponto flutuante para N casas decimais
+func (e *SortWriter) Round(value, places float64) float64 {
+	return e.target.Round(value, places)
+}
+
+// Create
+//
+// # English:
+//
+// Open the target file.
+//
+// # Português:
+//
+// Abre o arquivo de destino.
+func (e *SortWriter) Create(path string) (err error) {
+	return e.target.Create(path)
+}
+
+// Close
+//
+// # English:
+//
+// Removes the temporary run files and closes the target file.
+//
+// # Português:
+//
+// Remove os arquivos de execução temporários e fecha o arquivo de destino.
+func (e *SortWriter) Close() {
+	e.removeRuns()
+	e.target.Close()
+}
+
+// WriteNode
+//
+// # English:
+//
+// Buffers the node, in any order, and spills the buffer to a run file when the memory limit is reached.
+//
+//	Input:
+//	  id: positive number greater than zero;
+//	  longitude: value between ±180 to 7 decimal places;
+//	  latitude: value between ±90 with 7 decimal places;
+//
+// # Português:
+//
+// Guarda o node, em qualquer ordem, e despeja o buffer em um arquivo de execução quando o limite de memória é atingido.
+//
+//	Entrada:
+//	  id: número positivo maior do que zero;
+//	  longitude: valor entre ±180 com 7 casas decimais;
+//	  latitude: valor entre ±90 com 7 casas decimais;
+func (e *SortWriter) WriteNode(id int64, longitude, latitude float64) (err error) {
+	if e.merged {
+		err = errors.New("SortWriter.WriteNode().error: nodes cannot be written after WriteFileHeaders()")
+		return
+	}
+
+	if id < 1 {
+		err = errors.New("id must be greater than zero")
+		return
+	}
+
+	if longitude < -180.0 || longitude > 180.0 {
+		err = errors.New("longitude must be within ±180˚")
+		return
+	}
+
+	if latitude < -90.0 || latitude > 90.0 {
+		err = errors.New("latitude must be within ±90˚")
+		return
+	}
+
+	e.buffer = append(e.buffer, Node{ID: id, Lon: longitude, Lat: latitude})
+	if int64(len(e.buffer))*sortWriterNodeByteSize >= e.memoryLimit {
+		err = e.spill()
+		if err != nil {
+			err = fmt.Errorf("SortWriter.WriteNode().spill().Error: %v", err)
+			return
+		}
+	}
+
+	return
+}
+
+// FindNodeByID
+//
+// # English:
+//
+// Search for longitude and latitude in the target file, after WriteFileHeaders().
+//
+// # Português:
+//
+// Procura por longitude e latitude no arquivo de destino, depois de WriteFileHeaders().
+func (e *SortWriter) FindNodeByID(id int64) (longitude, latitude float64, err error) {
+	return e.target.FindNodeByID(id)
+}
+
+// WriteFileHeaders
+//
+// # English:
+//
+// Merges the runs into the target, only once, and writes the headers of the target.
+//
+// # Português:
+//
+// Mescla as execuções no destino, apenas uma vez, e escreve os cabeçalhos do destino.
+func (e *SortWriter) WriteFileHeaders() (err error) {
+	if !e.merged {
+		err = e.merge()
+		if err != nil {
+			err = fmt.Errorf("SortWriter.WriteFileHeaders().merge().Error: %v", err)
+			return
+		}
+		e.merged = true
+	}
+
+	return e.target.WriteFileHeaders()
+}
+
+// ReadFileHeaders
+//
+// # English:
+//
+// Read the configuration data of the target.
+//
+// # Português:
+//
+// Lê os dados de configuração do destino.
+func (e *SortWriter) ReadFileHeaders() (err error) {
+	return e.target.ReadFileHeaders()
+}
+
+// MountIndexIntoFile
+//
+// # English:
+//
+// Saves the indexes of the target.
+//
+// # Português:
+//
+// Salva os índices do destino.
+func (e *SortWriter) MountIndexIntoFile() (err error) {
+	return e.target.MountIndexIntoFile()
+}
+
+// IndexToMemory
+//
+// # English:
+//
+// Loads the indexes of the target into memory.
+//
+// # Português:
+//
+// Carrega os índices do destino na memória.
+func (e *SortWriter) IndexToMemory() (err error) {
+	return e.target.IndexToMemory()
+}
+
+// sortNodes
+//
+// # English:
+//
+// Sorts the nodes by ID and removes duplicate IDs, keeping the last version written.
+//
+// # Português:
+//
+// Ordena os nodes por ID e remove IDs duplicados, mantendo a última versão escrita.
+func sortNodes(nodes []Node) (unique []Node) {
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+
+	unique = nodes[:0]
+	for key, node := range nodes {
+		if key+1 < len(nodes) && nodes[key+1].ID == node.ID {
+			continue
+		}
+		unique = append(unique, node)
+	}
+	return
+}
+
+// spill
+//
+// # English:
+//
+// Sorts the buffer and writes it to a new temporary run file.
+//
+// # Português:
+//
+// Ordena o buffer e o escreve em um novo arquivo de execução temporário.
+func (e *SortWriter) spill() (err error) {
+	e.buffer = sortNodes(e.buffer)
+
+	var file *os.File
+	file, err = os.CreateTemp(e.tempDir, "goosm.sort.*.tmp")
+	if err != nil {
+		return
+	}
+	e.runs = append(e.runs, file.Name())
+
+	var writer = bufio.NewWriter(file)
+	var data = make([]byte, sortWriterNodeByteSize)
+	for _, node := range e.buffer {
+		binary.LittleEndian.PutUint64(data[0:], uint64(node.ID))
+		binary.LittleEndian.PutUint64(data[int64ByteSize:], math.Float64bits(node.Lon))
+		binary.LittleEndian.PutUint64(data[2*int64ByteSize:], math.Float64bits(node.Lat))
+		_, err = writer.Write(data)
+		if err != nil {
+			_ = file.Close()
+			return
+		}
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		_ = file.Close()
+		return
+	}
+
+	err = file.Close()
+	e.buffer = e.buffer[:0]
+	return
+}
+
+// merge
+//
+// # English:
+//
+// K-way merge of the run files and the buffer into the target, keeping the last version of duplicate IDs.
+//
+// # Português:
+//
+// Junção k-way dos arquivos de execução e do buffer no destino, mantendo a última versão de IDs duplicados.
+func (e *SortWriter) merge() (err error) {
+	e.buffer = sortNodes(e.buffer)
+
+	var sources = make([]sortWriterSource, 0, len(e.runs)+1)
+	defer func() {
+		for _, source := range sources {
+			if source.file != nil {
+				_ = source.file.Close()
+			}
+		}
+		e.removeRuns()
+	}()
+
+	for _, path := range e.runs {
+		var file *os.File
+		file, err = os.Open(path)
+		if err != nil {
+			return
+		}
+		sources = append(sources, sortWriterSource{file: file, reader: bufio.NewReaderSize(file, sortWriterReadBufferSize)})
+	}
+
+	// # English: the buffer is the last run, the most recent one
+	// # Português: o buffer é a última execução, a mais recente
+	sources = append(sources, sortWriterSource{buffer: e.buffer})
+
+	var queue = make(sortWriterQueue, 0, len(sources))
+	for key := range sources {
+		var node Node
+		var found bool
+		node, found, err = sources[key].next()
+		if err != nil {
+			return
+		}
+
+		if found {
+			queue = append(queue, sortWriterItem{node: node, run: key})
+		}
+	}
+	heap.Init(&queue)
+
+	for queue.Len() != 0 {
+		item := heap.Pop(&queue).(sortWriterItem)
+
+		// # English: queue order is ID and then run, so the last item with the same ID is the most recent version
+		// # Português: a ordem da fila é ID e depois execução, então o último item com o mesmo ID é a versão mais recente
+		last := queue.Len() == 0 || queue[0].node.ID != item.node.ID
+		if last {
+			err = e.target.WriteNode(item.node.ID, item.node.Lon, item.node.Lat)
+			if err != nil {
+				return
+			}
+		}
+
+		var node Node
+		var found bool
+		node, found, err = sources[item.run].next()
+		if err != nil {
+			return
+		}
+
+		if found {
+			heap.Push(&queue, sortWriterItem{node: node, run: item.run})
+		}
+	}
+
+	e.buffer = nil
+	return
+}
+
+// removeRuns
+//
+// # English:
+//
+// Removes the temporary run files.
+//
+// # Português:
+//
+// Remove os arquivos de execução temporários.
+func (e *SortWriter) removeRuns() {
+	for _, path := range e.runs {
+		var err = os.Remove(path)
+		if err != nil && !os.IsNotExist(err) {
+			log.Printf("SortWriter.removeRuns().error: %v", err)
+		}
+	}
+	e.runs = nil
+}
+
+// sortWriterSource
+//
+// # English:
+//
+// Sorted run, in a temporary file or in memory.
+//
+// # Português:
+//
+// Execução ordenada, em um arquivo temporário ou na memória.
+type sortWriterSource struct {
+	file   *os.File
+	reader *bufio.Reader
+	buffer []Node
+	data   []byte
+}
+
+// next
+//
+// # English:
+//
+// Returns the next node of the run, found is false at the end of the run.
+//
+// # Português:
+//
+// Devolve o próximo node da execução, found é false no fim da execução.
+func (e *sortWriterSource) next() (node Node, found bool, err error) {
+	if e.reader == nil {
+		if len(e.buffer) == 0 {
+			return
+		}
+
+		node = e.buffer[0]
+		e.buffer = e.buffer[1:]
+		found = true
+		return
+	}
+
+	if e.data == nil {
+		e.data = make([]byte, sortWriterNodeByteSize)
+	}
+
+	var data = e.data
+	_, err = io.ReadFull(e.reader, data)
+	if err == io.EOF {
+		err = nil
+		return
+	}
+
+	if err != nil {
+		return
+	}
+
+	node.ID = int64(binary.LittleEndian.Uint64(data[0:]))
+	node.Lon = math.Float64frombits(binary.LittleEndian.Uint64(data[int64ByteSize:]))
+	node.Lat = math.Float64frombits(binary.LittleEndian.Uint64(data[2*int64ByteSize:]))
+	found = true
+	return
+}
+
+// sortWriterItem
+//
+// # English:
+//
+// Node in the merge queue and the run it came from.
+//
+// # Português:
+//
+// Node na fila de junção e a execução de onde ele veio.
+type sortWriterItem struct {
+	node Node
+	run  int
+}
+
+// sortWriterQueue
+//
+// # English:
+//
+// container/heap ordered by ID and then by run.
+//
+// # Português:
+//
+// container/heap ordenado por ID e depois por execução.
+type sortWriterQueue []sortWriterItem
+
+func (e sortWriterQueue) Len() int { return len(e) }
+
+func (e sortWriterQueue) Less(i, j int) bool {
+	if e[i].node.ID != e[j].node.ID {
+		return e[i].node.ID < e[j].node.ID
+	}
+	return e[i].run < e[j].run
+}
+
+func (e sortWriterQueue) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+
+func (e *sortWriterQueue) Push(x any) { *e = append(*e, x.(sortWriterItem)) }
+
+func (e *sortWriterQueue) Pop() any {
+	old := *e
+	item := old[len(old)-1]
+	*e = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,116 @@
+package compress
+
+import (
+	"io"
+	"os"
+	"testing"
+)
+
+// TestSortWriter
+//
+// English:
+//
+// # Writes unsorted nodes with duplicate IDs through SortWriter, forcing several runs, and tests the file written
+//
+// Português:
+//
+// Escreve nodes fora de ordem e com IDs duplicados através de SortWriter, forçando várias execuções, e testa o arquivo
+// escrito
+func TestSortWriter(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.Remove("./test.node.sort.tmp")
+	})
+
+	var err error
+	var testLimit int64 = 1000
+
+	sortWriter := SortWriter{}
+	sortWriter.SetTarget(&Compress{})
+	sortWriter.SetTempDir(t.TempDir())
+	// English: 100 nodes for each run
+	// Português: 100 nodes por execução
+	sortWriter.SetMemoryLimit(100 * sortWriterNodeByteSize)
+	sortWriter.Init(10)
+	err = sortWriter.Create("./test.node.sort.tmp")
+	if err != nil {
+		t.Logf("open file error: %v", err)
+		t.FailNow()
+	}
+
+	// English: odd IDs in descending order, then every multiple of three again, which must replace the first version
+	// Português: IDs ímpares em ordem decrescente, depois cada múltiplo de três novamente, que deve substituir a primeira
+	// versão
+	for id := testLimit*2 - 1; id > 0; id -= 2 {
+		err = sortWriter.WriteNode(id, 1.0, 1.0)
+		if err != nil {
+			t.Logf("write node error: %v", err)
+			t.FailNow()
+		}
+	}
+	for id := int64(3); id < testLimit*2; id += 6 {
+		err = sortWriter.WriteNode(id, float64(id)/10000000, -float64(id)/10000000)
+		if err != nil {
+			t.Logf("write node error: %v", err)
+			t.FailNow()
+		}
+	}
+
+	if len(sortWriter.runs) < 2 {
+		t.Logf("expected more than one run, found %v", len(sortWriter.runs))
+		t.FailNow()
+	}
+
+	err = sortWriter.WriteFileHeaders()
+	if err != nil {
+		t.Logf("write header error: %v", err)
+		t.FailNow()
+	}
+
+	err = sortWriter.MountIndexIntoFile()
+	if err != nil {
+		t.Logf("mount index error: %v", err)
+		t.FailNow()
+	}
+
+	if len(sortWriter.runs) != 0 {
+		t.Logf("temporary runs were not removed")
+		t.FailNow()
+	}
+	sortWriter.Close()
+
+	compress := Compress{}
+	compress.Init(10)
+	err = compress.OpenForSearch("./test.node.sort.tmp")
+	if err != nil {
+		t.Logf("open for search error: %v", err)
+		t.FailNow()
+	}
+	defer compress.Close()
+
+	for id := int64(1); id < testLimit*2+1; id++ {
+		var lon, lat float64
+		lon, lat, err = compress.FindNodeByID(id)
+		if id%2 == 0 {
+			if err != io.EOF {
+				t.Logf("id %v should not be found: %v", id, err)
+				t.FailNow()
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Logf("find node %v error: %v", id, err)
+			t.FailNow()
+		}
+
+		wantLon, wantLat := 1.0, 1.0
+		if id%3 == 0 {
+			wantLon, wantLat = float64(id)/10000000, -float64(id)/10000000
+		}
+
+		if lon != wantLon || lat != wantLat {
+			t.Logf("node %v: expected %v, %v, found %v, %v", id, wantLon, wantLat, lon, lat)
+			t.FailNow()
+		}
+	}
+}
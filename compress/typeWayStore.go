@@ -0,0 +1,802 @@
+package compress
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"math"
+	"os"
+	"sort"
+)
+
+const (
+
+	// wayStoreHeaderVersion
+	//
+	// # English:
+	//
+	// Version text written in the header of the way binary file
+	//
+	// # Português:
+	//
+	// Texto de versão escrito no cabeçalho do arquivo binário de ways
+	wayStoreHeaderVersion = "W0000001"
+
+	// wayStoreKindNodeIDs
+	//
+	// # English:
+	//
+	// The way record contains the delta-encoded list of node IDs
+	//
+	// # Português:
+	//
+	// O registro do way contém a lista de IDs de nodes codificada em delta
+	wayStoreKindNodeIDs = 0
+
+	// wayStoreKindCoordinates
+	//
+	// # English:
+	//
+	// The way record contains the delta-encoded list of resolved coordinates
+	//
+	// # Português:
+	//
+	// O registro do way contém a lista de coordenadas resolvidas codificada em delta
+	wayStoreKindCoordinates = 1
+
+	// wayStoreKindByteSize
+	//
+	// # English:
+	//
+	// Number of bytes occupied by the record kind
+	//
+	// # Português:
+	//
+	// Quantidade de bytes ocupada pelo tipo do registro
+	wayStoreKindByteSize = 1
+
+	// wayStorePayloadSizeByteSize
+	//
+	// # English:
+	//
+	// Number of bytes occupied by the size of the record payload
+	//
+	// # Português:
+	//
+	// Quantidade de bytes ocupada pelo tamanho do conteúdo do registro
+	wayStorePayloadSizeByteSize = 4
+
+	// wayStoreRecordHeaderByteSize
+	//
+	// # English:
+	//
+	// Number of bytes before the payload of each way record
+	//
+	// # Português:
+	//
+	// Quantidade de bytes antes do conteúdo de cada registro de way
+	wayStoreRecordHeaderByteSize = nodeIdByteSize + wayStoreKindByteSize + wayStorePayloadSizeByteSize
+)
+
+// WayStore
+//
+// # English:
+//
+//	Sibling of Compress, stores way ID -> node ID list, or way ID -> resolved coordinates, in a binary file.
+//
+// The problem:
+//
+//	Relations only contain the ID of the member ways, and by the time the relation section of the pbf file is read, the
+//	node list of the ways has already gone. Without this file, every relation member must be read back from the
+//	database.
+//
+// File format:
+//
+//	The header and the in-memory index are the same as the node file, only the data block changes, because the size of
+//	a way is variable.
+//
+//	Header: 40 bytes
+//	  version: 8 bytes
+//	  total of ways in a file: 8 bytes
+//	  total block size: 8 bytes
+//	  total index into file 8 bytes
+//	  start index address: 8 bytes
+//
+//	Data block:
+//	  way.ID: 8 bytes
+//	  kind: 1 byte, 0 for node IDs and 1 for coordinates
+//	  payload size: 4 bytes
+//	  payload: zig-zag varint of the difference to the previous value. Node IDs are written as is, coordinates are
+//	  multiplied by 10,000,000, as in the node file, and longitude and latitude are interleaved.
+//
+//	Index block:
+//	  way.ID: 8 bytes and address of the way: 8 bytes, for each block of blockSize ways, plus the last way.
+//	  FindWayByID() does the binary search in memory and then reads the block between two indexes with a single disk
+//	  access.
+//
+// # Português:
+//
+//	Irmão de Compress, arquiva way ID -> lista de IDs de nodes, ou way ID -> coordenadas resolvidas, em um arquivo
+//	binário.
+//
+// O problema:
+//
+//	Relations contém apenas o ID dos ways membros, e quando a seção de relations do arquivo pbf é lida, a lista de nodes
+//	dos ways já se foi. Sem este arquivo, cada membro da relation deve ser lido de volta do banco de dados.
+//
+// Formato do arquivo:
+//
+//	O cabeçalho e o índice em memória são os mesmos do arquivo de nodes, apenas o bloco de dados muda, porque o tamanho
+//	de um way é variável.
+//
+//	Header: 40 bytes
+//	  version: 8 bytes
+//	  total of ways in a file: 8 bytes
+//	  total block size: 8 bytes
+//	  total index into file 8 bytes
+//	  start index address: 8 bytes
+//
+//	Data block:
+//	  way.ID: 8 bytes
+//	  kind: 1 byte, 0 para IDs de nodes e 1 para coordenadas
+//	  payload size: 4 bytes
+//	  payload: zig-zag varint da diferença para o valor anterior. IDs de nodes são escritos como estão, coordenadas são
+//	  multiplicadas por 10.000.000, como no arquivo de nodes, e longitude e latitude são intercaladas.
+//
+//	Index block:
+//	  way.ID: 8 bytes e endereço do way: 8 bytes, para cada bloco de blockSize ways, mais o último way.
+//	  FindWayByID() faz a busca binária em memória e em seguida lê o bloco entre dois índices com um único acesso ao
+//	  disco.
+type WayStore struct {
+
+	// # English:
+	//
+	// Pointer to the temporary file.
+	//
+	// # Português:
+	//
+	// Ponteiro para o arquivo temporário.
+	file *os.File
+
+	// # English:
+	//
+	// Checks if the entered ID is in ascending order
+	//
+	// # Português:
+	//
+	// Verifica se o ID inserido esta em ordem crescente
+	lastID int64
+
+	// # English:
+	//
+	// 8 bytes to file uint64.
+	//
+	// # Português:
+	//
+	// 8 bytes para arquivar uint64.
+	dataFile []byte
+
+	// # English:
+	//
+	// Reusable buffer of the record being written.
+	//
+	// # Português:
+	//
+	// Buffer reutilizável do registro sendo escrito.
+	record []byte
+
+	// # English:
+	//
+	// Write pointer to temporary file.
+	//
+	// # Português:
+	//
+	// Ponteiro de escrita no arquivo temporário.
+	wayWriteDataPosition int64
+
+	// # English:
+	//
+	// Address of the end of the data block and start of the index block.
+	//
+	// # Português:
+	//
+	// Endereço do fim do bloco de dados e início do bloco de índices.
+	indexesPosition int64
+
+	// # English:
+	//
+	// Total ways saved in the file.
+	//
+	// # Português:
+	//
+	// Total de ways salvos no arquivo.
+	totalOfWaysInTmpFile int64
+
+	// # English:
+	//
+	// Total indexes to be used in memory in the file. Affected by blockSize.
+	//
+	// # Português:
+	//
+	// Total de índices para serem usados em memória no arquivo. Afetado por blockSize.
+	totalIndexIntoFile int64
+
+	// # English:
+	//
+	// Spacing between IDs for the in-memory key, i.e. 10 represents one data capture every 10 IDs.
+	//
+	// # Português:
+	//
+	// Espaçamento entre IDs para a chave em memória, ou seja, 10 representa uma captura de dados a cada 10 IDs.
+	blockSize int64
+
+	// # English:
+	//
+	// In-memory index, way ID and address of the way in the file.
+	//
+	// # Português:
+	//
+	// Índice em memória, ID do way e endereço do way no arquivo.
+	memory [][2]int64
+
+	// # English:
+	//
+	// Address and ID of the last way written, used to close the index.
+	//
+	// # Português:
+	//
+	// Endereço e ID do último way escrito, usado para fechar o índice.
+	lastWay [2]int64
+}
+
+// Init
+//
+// # English:
+//
+// Initializes the object.
+//
+//	Input:
+//	  blockSize: Spacing between ID captures for the in-memory index.
+//
+// # Português:
+//
+// Inicializa o objeto.
+//
+//	Entrada:
+//	  blockSize: Espaçamento entre as capturas de IDs para o índice em memória.
+func (e *WayStore) Init(blockSize int64) {
+	e.dataFile = make([]byte, 8)
+	e.record = make([]byte, 0, 1024)
+	e.wayWriteDataPosition = nodeDataPositionStartAtAddress
+	e.indexesPosition = nodeDataPositionStartAtAddress
+	e.blockSize = blockSize
+	e.memory = make([][2]int64, 0)
+	e.lastID = 0
+	e.totalOfWaysInTmpFile = 0
+	e.totalIndexIntoFile = 0
+}
+
+// Create
+//
+// # English:
+//
+// Open the temporary file.
+//
+// # Português:
+//
+// Abre o arquivo temporário.
+func (e *WayStore) Create(path string) (err error) {
+	if e.file != nil {
+		_ = e.file.Close()
+	}
+
+	// English: truncated, the header and the index of a previous file must not be read back after a crash
+	// Português: truncado, o cabeçalho e o índice de um arquivo anterior não podem ser lidos de volta depois de uma falha
+	e.file, err = os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, fs.ModePerm)
+	if err != nil {
+		err = fmt.Errorf("WayStore.Create().OpenFile().Error: %v", err)
+		return
+	}
+	return
+}
+
+// OpenForSearch
+//
+// # English:
+//
+// Opens the binary file read-only and is used when only the search function is intended.
+//
+//	Input:
+//	  path: Binary file path
+//
+// # Português:
+//
+// Abre o arquivo binário apenas para leitura e é usado quando se pretende usar apenas a função de busca
+//
+//	Entrada:
+//	  path: Caminho do arquivo binário
+func (e *WayStore) OpenForSearch(path string) (err error) {
+	if e.file != nil {
+		_ = e.file.Close()
+	}
+
+	e.file, err = os.OpenFile(path, os.O_RDONLY, fs.ModePerm)
+	if err != nil {
+		err = fmt.Errorf("WayStore.OpenForSearch().OpenFile().Error: %v", err)
+		return
+	}
+
+	err = e.ReadFileHeaders()
+	if err != nil {
+		err = fmt.Errorf("WayStore.OpenForSearch().ReadFileHeaders().Error: %v", err)
+		return
+	}
+
+	err = e.IndexToMemory()
+	if err != nil {
+		err = fmt.Errorf("WayStore.OpenForSearch().IndexToMemory().Error: %v", err)
+		return
+	}
+
+	return
+}
+
+// Close
+//
+// # English:
+//
+// # Close the temporary file
+//
+// # Português:
+//
+// Fecha o arquivo temporário
+func (e *WayStore) Close() {
+	var err = e.file.Close()
+	if err != nil {
+		log.Printf("WayStore.Close().error: %v", err)
+	}
+}
+
+// WriteWayNodeIDs
+//
+// # English:
+//
+// Write the way and its list of node IDs to the temporary file.
+//
+//	Input:
+//	  id: positive number greater than zero, in ascending order;
+//	  nodeIDs: list of node IDs of the way.
+//
+// # Português:
+//
+// Escreve o way e sua lista de IDs de nodes no arquivo temporário.
+//
+//	Entrada:
+//	  id: número positivo maior do que zero, em ordem crescente;
+//	  nodeIDs: lista de IDs de nodes do way.
+func (e *WayStore) WriteWayNodeIDs(id int64, nodeIDs []int64) (err error) {
+	e.record = e.record[:0]
+
+	var last int64
+	for _, nodeID := range nodeIDs {
+		e.record = binary.AppendVarint(e.record, nodeID-last)
+		last = nodeID
+	}
+
+	err = e.writeRecord(id, wayStoreKindNodeIDs)
+	if err != nil {
+		err = fmt.Errorf("WayStore.WriteWayNodeIDs().writeRecord().Error: %v", err)
+	}
+	return
+}
+
+// WriteWayCoordinates
+//
+// # English:
+//
+// Write the way and its resolved coordinates to the temporary file.
+//
+//	Input:
+//	  id: positive number greater than zero, in ascending order;
+//	  loc: list of coordinates, [longitude, latitude], with 7 decimal places.
+//
+// # Português:
+//
+// Escreve o way e suas coordenadas resolvidas no arquivo temporário.
+//
+//	Entrada:
+//	  id: número positivo maior do que zero, em ordem crescente;
+//	  loc: lista de coordenadas, [longitude, latitude], com 7 casas decimais.
+func (e *WayStore) WriteWayCoordinates(id int64, loc [][2]float64) (err error) {
+	e.record = e.record[:0]
+
+	var lastLon, lastLat int64
+	for _, coordinate := range loc {
+		if coordinate[0] < -180.0 || coordinate[0] > 180.0 {
+			err = errors.New("longitude must be within ±180˚")
+			return
+		}
+
+		if coordinate[1] < -90.0 || coordinate[1] > 90.0 {
+			err = errors.New("latitude must be within ±90˚")
+			return
+		}
+
+		lon := coordinateToInt(coordinate[0])
+		lat := coordinateToInt(coordinate[1])
+		e.record = binary.AppendVarint(e.record, lon-lastLon)
+		e.record = binary.AppendVarint(e.record, lat-lastLat)
+		lastLon = lon
+		lastLat = lat
+	}
+
+	err = e.writeRecord(id, wayStoreKindCoordinates)
+	if err != nil {
+		err = fmt.Errorf("WayStore.WriteWayCoordinates().writeRecord().Error: %v", err)
+	}
+	return
+}
+
+// writeRecord
+//
+// # English:
+//
+// Writes the record header followed by the payload already mounted in e.record.
+//
+// # Português:
+//
+// Escreve o cabeçalho do registro seguido do conteúdo já montado em e.record.
+func (e *WayStore) writeRecord(id int64, kind byte) (err error) {
+	if id < 1 {
+		err = errors.New("id must be greater than zero")
+		return
+	}
+
+	if id <= e.lastID {
+		err = errors.New("id must be entered in ascending order and must not be repeated")
+		return
+	}
+
+	var header = make([]byte, wayStoreRecordHeaderByteSize)
+	binary.LittleEndian.PutUint64(header, uint64(id))
+	header[nodeIdByteSize] = kind
+	binary.LittleEndian.PutUint32(header[nodeIdByteSize+wayStoreKindByteSize:], uint32(len(e.record)))
+
+	_, err = e.file.WriteAt(header, e.wayWriteDataPosition)
+	if err != nil {
+		return
+	}
+
+	_, err = e.file.WriteAt(e.record, e.wayWriteDataPosition+wayStoreRecordHeaderByteSize)
+	if err != nil {
+		return
+	}
+
+	// # English: every blockSize ways, the address is captured for the in-memory index.
+	// # Português: a cada blockSize ways, o endereço é capturado para o índice em memória.
+	if e.totalOfWaysInTmpFile%e.blockSize == 0 {
+		e.memory = append(e.memory, [2]int64{memorySliceAddrID: id, memorySliceAddrOfAddrIntoFile: e.wayWriteDataPosition})
+	}
+
+	e.lastWay = [2]int64{memorySliceAddrID: id, memorySliceAddrOfAddrIntoFile: e.wayWriteDataPosition}
+	e.lastID = id
+	e.wayWriteDataPosition += wayStoreRecordHeaderByteSize + int64(len(e.record))
+	e.totalOfWaysInTmpFile++
+	return
+}
+
+// WriteFileHeaders
+//
+// # English:
+//
+// Write configuration data at the beginning of the file.
+//
+//	Note:
+//	  * Must be called after the last way has been written.
+//
+// # Português:
+//
+// Escreve os dados de configuração no início do arquivo.
+//
+//	Nota:
+//	  * Deve ser chamada depois do último way ter sido escrito.
+func (e *WayStore) WriteFileHeaders() (err error) {
+	_, err = e.file.WriteAt([]byte(wayStoreHeaderVersion), headerVersionAddress)
+	if err != nil {
+		err = fmt.Errorf("WayStore.WriteFileHeaders().version.Error: %v", err)
+		return
+	}
+
+	e.totalIndexIntoFile = int64(len(e.memory))
+	if e.totalOfWaysInTmpFile != 0 && e.memory[len(e.memory)-1] != e.lastWay {
+		e.totalIndexIntoFile++
+	}
+	e.indexesPosition = e.wayWriteDataPosition
+
+	for address, value := range map[int64]int64{
+		headerTotalNodesAddress:      e.totalOfWaysInTmpFile,
+		headerBlockSizeAddress:       e.blockSize,
+		headerTotalIndexAddress:      e.totalIndexIntoFile,
+		headerIndexesPositionAddress: e.indexesPosition,
+	} {
+		binary.LittleEndian.PutUint64(e.dataFile, uint64(value))
+		_, err = e.file.WriteAt(e.dataFile, address)
+		if err != nil {
+			err = fmt.Errorf("WayStore.WriteFileHeaders().WriteAt(%v).Error: %v", address, err)
+			return
+		}
+	}
+
+	return
+}
+
+// ReadFileHeaders
+//
+// # English:
+//
+// Read the configuration data at the beginning of the file.
+//
+// # Português:
+//
+// Lê os dados de configuração no início do arquivo.
+func (e *WayStore) ReadFileHeaders() (err error) {
+	_, err = e.file.ReadAt(e.dataFile, headerVersionAddress)
+	if err != nil {
+		err = fmt.Errorf("WayStore.ReadFileHeaders().version.Error: %v", err)
+		return
+	}
+
+	if string(e.dataFile) != wayStoreHeaderVersion {
+		err = fmt.Errorf("file version header does not match code version: %v != %v", string(e.dataFile), wayStoreHeaderVersion)
+		return
+	}
+
+	for address, value := range map[int64]*int64{
+		headerTotalNodesAddress:      &e.totalOfWaysInTmpFile,
+		headerBlockSizeAddress:       &e.blockSize,
+		headerTotalIndexAddress:      &e.totalIndexIntoFile,
+		headerIndexesPositionAddress: &e.indexesPosition,
+	} {
+		_, err = e.file.ReadAt(e.dataFile, address)
+		if err != nil {
+			err = fmt.Errorf("WayStore.ReadFileHeaders().ReadAt(%v).Error: %v", address, err)
+			return
+		}
+		*value = int64(binary.LittleEndian.Uint64(e.dataFile))
+	}
+
+	e.wayWriteDataPosition = e.indexesPosition
+	return
+}
+
+// MountIndexIntoFile
+//
+// # English:
+//
+// Saves the indexes captured during writing at the end of the temporary file.
+//
+// # Português:
+//
+// Salva os índices capturados durante a escrita no fim do arquivo temporário.
+func (e *WayStore) MountIndexIntoFile() (err error) {
+	// English: the last way closes the last block and must also be in memory, for FindWayByID() on the same object
+	// Português: o último way fecha o último bloco e também deve estar na memória, para FindWayByID() no mesmo objeto
+	if e.totalIndexIntoFile > int64(len(e.memory)) {
+		e.memory = append(e.memory, e.lastWay)
+	}
+	var index = e.memory
+
+	var buffer = make([]byte, 2*int64ByteSize*len(index))
+	for key, value := range index {
+		binary.LittleEndian.PutUint64(buffer[2*int64ByteSize*key:], uint64(value[memorySliceAddrID]))
+		binary.LittleEndian.PutUint64(buffer[2*int64ByteSize*key+int64ByteSize:], uint64(value[memorySliceAddrOfAddrIntoFile]))
+	}
+
+	_, err = e.file.WriteAt(buffer, e.indexesPosition)
+	if err != nil {
+		err = fmt.Errorf("WayStore.MountIndexIntoFile().WriteAt().Error: %v", err)
+		return
+	}
+
+	return
+}
+
+// IndexToMemory
+//
+// # English:
+//
+// Loads the indexes contained in the temporary file into memory.
+//
+// # Português:
+//
+// Carrega os índices contidos no arquivo temporário na memória.
+func (e *WayStore) IndexToMemory() (err error) {
+	var buffer = make([]byte, 2*int64ByteSize*e.totalIndexIntoFile)
+	_, err = e.file.ReadAt(buffer, e.indexesPosition)
+	if err != nil {
+		err = fmt.Errorf("WayStore.IndexToMemory().ReadAt().Error: %v", err)
+		return
+	}
+
+	e.memory = make([][2]int64, e.totalIndexIntoFile)
+	for key := range e.memory {
+		e.memory[key] = [2]int64{
+			memorySliceAddrID:             int64(binary.LittleEndian.Uint64(buffer[2*int64ByteSize*key:])),
+			memorySliceAddrOfAddrIntoFile: int64(binary.LittleEndian.Uint64(buffer[2*int64ByteSize*key+int64ByteSize:])),
+		}
+	}
+
+	return
+}
+
+// FindWayByID
+//
+// # English:
+//
+// Search for the way in the temporary file.
+//
+//	Input:
+//	  id: ID of the way sought.
+//
+//	Output:
+//	  nodeIDs: list of node IDs, when the way was written by WriteWayNodeIDs();
+//	  loc: list of coordinates, when the way was written by WriteWayCoordinates();
+//	  err: pattern object, with io.EOF error when value not found in file
+//
+// # Português:
+//
+// Procura pelo way no arquivo temporário.
+//
+//	Entrada:
+//	  id: ID do way procurado.
+//
+//	Saída:
+//	  nodeIDs: lista de IDs de nodes, quando o way foi escrito por WriteWayNodeIDs();
+//	  loc: lista de coordenadas, quando o way foi escrito por WriteWayCoordinates();
+//	  err: objeto de padrão, com erro io.EOF quando o valor não é encontrado no arquivo
+func (e *WayStore) FindWayByID(id int64) (nodeIDs []int64, loc [][2]float64, err error) {
+	i := sort.Search(len(e.memory), func(i int) bool { return e.memory[i][memorySliceAddrID] >= id })
+
+	// # English: the ID is greater than the last way or smaller than the first
+	// # Português: o ID é maior do que o último way ou menor do que o primeiro
+	if i == len(e.memory) || (i == 0 && e.memory[i][memorySliceAddrID] != id) {
+		err = io.EOF
+		return
+	}
+
+	var leftBound, rightBound int64
+	if e.memory[i][memorySliceAddrID] == id {
+
+		// # English: The searched ID was found in memory, only the record header is read to know its size.
+		// # Português: O ID procurado foi encontrado na memória, apenas o cabeçalho do registro é lido para saber seu tamanho.
+		leftBound = e.memory[i][memorySliceAddrOfAddrIntoFile]
+		var header = make([]byte, wayStoreRecordHeaderByteSize)
+		_, err = e.file.ReadAt(header, leftBound)
+		if err != nil {
+			err = fmt.Errorf("WayStore.FindWayByID().ReadAt(%v).Error: %v", leftBound, err)
+			return
+		}
+		rightBound = leftBound + wayStoreRecordHeaderByteSize + int64(binary.LittleEndian.Uint32(header[nodeIdByteSize+wayStoreKindByteSize:]))
+	} else {
+		leftBound = e.memory[i-1][memorySliceAddrOfAddrIntoFile]
+		rightBound = e.memory[i][memorySliceAddrOfAddrIntoFile]
+	}
+
+	var block = make([]byte, rightBound-leftBound)
+	_, err = e.file.ReadAt(block, leftBound)
+	if err != nil {
+		err = fmt.Errorf("WayStore.FindWayByID().ReadAt(%v).Error: %v", leftBound, err)
+		return
+	}
+
+	for len(block) >= wayStoreRecordHeaderByteSize {
+		idFound := int64(binary.LittleEndian.Uint64(block))
+		kind := block[nodeIdByteSize]
+		size := int(binary.LittleEndian.Uint32(block[nodeIdByteSize+wayStoreKindByteSize:]))
+		block = block[wayStoreRecordHeaderByteSize:]
+
+		if len(block) < size {
+			err = fmt.Errorf("WayStore.FindWayByID().error: way %v is truncated", idFound)
+			return
+		}
+
+		if idFound > id {
+			break
+		}
+
+		if idFound == id {
+			nodeIDs, loc, err = e.decodePayload(kind, block[:size])
+			return
+		}
+
+		block = block[size:]
+	}
+
+	err = io.EOF
+	return
+}
+
+// decodePayload
+//
+// # English:
+//
+// Decodes the delta-encoded content of a way record.
+//
+// # Português:
+//
+// Decodifica o conteúdo codificado em delta de um registro de way.
+func (e *WayStore) decodePayload(kind byte, payload []byte) (nodeIDs []int64, loc [][2]float64, err error) {
+	var deltas = make([]int64, 0, len(payload))
+	for len(payload) != 0 {
+		delta, n := binary.Varint(payload)
+		if n <= 0 {
+			err = errors.New("WayStore.decodePayload().error: invalid varint")
+			return
+		}
+
+		deltas = append(deltas, delta)
+		payload = payload[n:]
+	}
+
+	switch kind {
+	case wayStoreKindNodeIDs:
+		nodeIDs = make([]int64, len(deltas))
+		var last int64
+		for key, delta := range deltas {
+			last += delta
+			nodeIDs[key] = last
+		}
+
+	case wayStoreKindCoordinates:
+		if len(deltas)%2 != 0 {
+			err = errors.New("WayStore.decodePayload().error: odd number of coordinates")
+			return
+		}
+
+		// # English: longitude and latitude are delta-encoded separately and interleaved in the payload
+		// # Português: longitude e latitude são codificadas em delta separadamente e intercaladas no conteúdo
+		loc = make([][2]float64, len(deltas)/2)
+		var lon, lat int64
+		for key := range loc {
+			lon += deltas[2*key]
+			lat += deltas[2*key+1]
+			loc[key] = [2]float64{float64(lon) / decimalPlaces, float64(lat) / decimalPlaces}
+		}
+
+	default:
+		err = fmt.Errorf("WayStore.decodePayload().error: unknown record kind %v", kind)
+	}
+
+	return
+}
+
+// GetTotalOfWays
+//
+// # English:
+//
+// Returns the total number of ways in the file.
+//
+// # Português:
+//
+// Retorna a quantidade total de ways no arquivo.
+func (e *WayStore) GetTotalOfWays() (total int64) {
+	return e.totalOfWaysInTmpFile
+}
+
+// coordinateToInt
+//
+// # English:
+//
+// Converts the coordinate to an integer with 7 decimal places, the same precision as the node file, rounding to avoid
+// floating point error, e.g. 2.6e-06 * 1e7 = 25.999999.
+//
+// # Português:
+//
+// Converte a coordenada em um inteiro com 7 casas decimais, a mesma precisão do arquivo de nodes, arredondando para
+// evitar o erro de ponto flutuante, ex.: 2.6e-06 * 1e7 = 25.999999.
+func coordinateToInt(coordinate float64) (value int64) {
+	return int64(math.Round(coordinate * decimalPlaces))
+}
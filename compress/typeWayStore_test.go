@@ -0,0 +1,234 @@
+package compress
+
+import (
+	"io"
+	"os"
+	"testing"
+)
+
+// TestWayStore
+//
+// English:
+//
+// # Writes ways with node IDs and with coordinates and tests the values found by the search
+//
+// Português:
+//
+// Escreve ways com IDs de nodes e com coordenadas e testa os valores encontrados pela busca
+func TestWayStore(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.Remove("./test.way.tmp")
+	})
+
+	var err error
+	var testLimit = 100
+
+	store := WayStore{}
+	store.Init(7)
+	err = store.Create("./test.way.tmp")
+	if err != nil {
+		t.Logf("open file error: %v", err)
+		t.FailNow()
+	}
+
+	// Gera a informação de controle, ways pares com coordenadas e ímpares com IDs de nodes
+	for i := 0; i != testLimit; i++ {
+		id := int64(i*2 + 1)
+		if i%2 == 0 {
+			err = store.WriteWayCoordinates(id, [][2]float64{{-45.1234567, -23.7654321}, {float64(i) / 10000000, 12.0}, {180.0, -90.0}})
+		} else {
+			err = store.WriteWayNodeIDs(id, []int64{int64(i) * 1000, 5, int64(i)*1000 + 1})
+		}
+		if err != nil {
+			t.Logf("write way error: %v", err)
+			t.FailNow()
+		}
+	}
+
+	err = store.WriteWayNodeIDs(1, []int64{1})
+	if err == nil {
+		t.Logf("write way error: repeated ID must return an error")
+		t.FailNow()
+	}
+
+	err = store.WriteFileHeaders()
+	if err != nil {
+		t.Logf("write header error: %v", err)
+		t.FailNow()
+	}
+
+	err = store.MountIndexIntoFile()
+	if err != nil {
+		t.Logf("write index error: %v", err)
+		t.FailNow()
+	}
+	store.Close()
+
+	// -------------------------------------------------------------------------------------------------------------------
+	// fim da escrita do arquivo
+	// -------------------------------------------------------------------------------------------------------------------
+
+	store = WayStore{}
+	store.Init(0)
+	err = store.OpenForSearch("./test.way.tmp")
+	if err != nil {
+		t.Logf("open for search error: %v", err)
+		t.FailNow()
+	}
+	defer store.Close()
+
+	if store.GetTotalOfWays() != int64(testLimit) {
+		t.Logf("total of ways error: %v != %v", store.GetTotalOfWays(), testLimit)
+		t.FailNow()
+	}
+
+	var nodeIDs []int64
+	var loc [][2]float64
+	for i := 0; i != testLimit; i++ {
+		nodeIDs, loc, err = store.FindWayByID(int64(i*2 + 1))
+		if err != nil {
+			t.Logf("FindWayByID(%v) error: %v", i*2+1, err)
+			t.FailNow()
+		}
+
+		if i%2 == 0 {
+			if len(nodeIDs) != 0 || len(loc) != 3 {
+				t.Logf("FindWayByID(%v) error: wrong kind of record", i*2+1)
+				t.FailNow()
+			}
+
+			if loc[0] != [2]float64{-45.1234567, -23.7654321} || loc[1] != [2]float64{float64(i) / 10000000, 12.0} || loc[2] != [2]float64{180.0, -90.0} {
+				t.Logf("FindWayByID(%v) error: wrong coordinates %v", i*2+1, loc)
+				t.FailNow()
+			}
+			continue
+		}
+
+		if len(loc) != 0 || len(nodeIDs) != 3 {
+			t.Logf("FindWayByID(%v) error: wrong kind of record", i*2+1)
+			t.FailNow()
+		}
+
+		if nodeIDs[0] != int64(i)*1000 || nodeIDs[1] != 5 || nodeIDs[2] != int64(i)*1000+1 {
+			t.Logf("FindWayByID(%v) error: wrong node IDs %v", i*2+1, nodeIDs)
+			t.FailNow()
+		}
+	}
+
+	for _, id := range []int64{0, 2, 100, int64(testLimit*2 + 1)} {
+		_, _, err = store.FindWayByID(id)
+		if err != io.EOF {
+			t.Logf("FindWayByID(%v) error: io.EOF expected, found %v", id, err)
+			t.FailNow()
+		}
+	}
+}
+
+// TestWayStore_sameObject
+//
+// English:
+//
+// # Writes 25 ways in blocks of 10 and searches them on the same object, without reopening the file
+//
+// Português:
+//
+// Escreve 25 ways em blocos de 10 e os procura no mesmo objeto, sem reabrir o arquivo
+func TestWayStore_sameObject(t *testing.T) {
+	var path = t.TempDir() + "/test.way.tmp"
+
+	store := WayStore{}
+	store.Init(10)
+	err := store.Create(path)
+	if err != nil {
+		t.Logf("open file error: %v", err)
+		t.FailNow()
+	}
+	defer store.Close()
+
+	for id := int64(1); id <= 25; id++ {
+		err = store.WriteWayNodeIDs(id, []int64{id * 10, id*10 + 1})
+		if err != nil {
+			t.Logf("write way error: %v", err)
+			t.FailNow()
+		}
+	}
+
+	err = store.WriteFileHeaders()
+	if err == nil {
+		err = store.MountIndexIntoFile()
+	}
+	if err != nil {
+		t.Logf("write index error: %v", err)
+		t.FailNow()
+	}
+
+	for id := int64(1); id <= 25; id++ {
+		nodeIDs, _, err := store.FindWayByID(id)
+		if err != nil || len(nodeIDs) != 2 || nodeIDs[0] != id*10 {
+			t.Logf("FindWayByID(%v) error: %v, %v", id, err, nodeIDs)
+			t.FailNow()
+		}
+	}
+
+	_, _, err = store.FindWayByID(26)
+	if err != io.EOF {
+		t.Logf("FindWayByID(26) error: io.EOF expected, found %v", err)
+		t.FailNow()
+	}
+}
+
+// TestWayStore_createOverComplete
+//
+// English:
+//
+// Writes a complete way store of 100 ways and, on the same path, 10 ways of a new store that stops before the headers,
+// as in a crash. The half-written file must be refused.
+//
+// Português:
+//
+// Escreve um arquivo de ways completo de 100 ways e, no mesmo caminho, 10 ways de um novo arquivo que para antes dos
+// cabeçalhos, como em uma falha. O arquivo escrito pela metade deve ser recusado.
+func TestWayStore_createOverComplete(t *testing.T) {
+	var path = t.TempDir() + "/test.way.tmp"
+
+	var err error
+	for _, total := range []int64{100, 10} {
+		store := WayStore{}
+		store.Init(10)
+		err = store.Create(path)
+		if err != nil {
+			t.Logf("open file error: %v", err)
+			t.FailNow()
+		}
+
+		for id := int64(1); id <= total; id++ {
+			err = store.WriteWayNodeIDs(id, []int64{id * 10, id*10 + 1})
+			if err != nil {
+				t.Logf("write way error: %v", err)
+				t.FailNow()
+			}
+		}
+
+		if total == 100 {
+			err = store.WriteFileHeaders()
+			if err == nil {
+				err = store.MountIndexIntoFile()
+			}
+			if err != nil {
+				t.Logf("write index error: %v", err)
+				t.FailNow()
+			}
+		}
+		store.Close()
+	}
+
+	store := WayStore{}
+	store.Init(0)
+	err = store.OpenForSearch(path)
+	if err == nil {
+		nodeIDs, _, err := store.FindWayByID(60)
+		store.Close()
+		t.Logf("OpenForSearch() must refuse the half-written file, FindWayByID(60): %v, %v", nodeIDs, err)
+		t.FailNow()
+	}
+}
@@ -24,6 +24,36 @@ import (
 //
 // Na minha máquina local, este exemplo leva em torno de oito minutos.
 func BenchmarkFindNodeById(b *testing.B) {
+	benchmarkFindNodeById(b, func(compressData *compress.Compress, path string) (err error) {
+		return compressData.OpenForSearch(path)
+	})
+}
+
+// BenchmarkFindNodeByIdMmap
+//
+// # English:
+//
+// Same as BenchmarkFindNodeById, but the node file is mapped into memory by OpenForSearchMmap().
+//
+// # Português:
+//
+// Igual a BenchmarkFindNodeById, mas o arquivo de nodes é mapeado na memória por OpenForSearchMmap().
+func BenchmarkFindNodeByIdMmap(b *testing.B) {
+	benchmarkFindNodeById(b, func(compressData *compress.Compress, path string) (err error) {
+		return compressData.OpenForSearchMmap(path)
+	})
+}
+
+// benchmarkFindNodeById
+//
+// # English:
+//
+// Opens the planet node file with the open function and searches random IDs.
+//
+// # Português:
+//
+// Abre o arquivo de nodes do planeta com a função open e procura IDs aleatórios.
+func benchmarkFindNodeById(b *testing.B, open func(compressData *compress.Compress, path string) (err error)) {
 	var err error
 
 	// change main dir to open 'commonFiles' folder
@@ -43,228 +73,22 @@ func BenchmarkFindNodeById(b *testing.B) {
 	start := time.Now()
 	compressData := &compress.Compress{}
 	compressData.Init(1000)
-	err = compressData.OpenForSearch("./commonFiles/node.planet.tmp")
+	err = open(compressData, "./commonFiles/node.planet.tmp")
 	if err != nil {
 		b.Errorf("%v", err)
 	}
+	defer compressData.Close()
 	b.Logf("prepare time: %v\n", time.Since(start))
 
 	var id int64
 	var lon, lat float64
 
-	// randoms IDs (2048) selected into database
-	list := []int64{245623033, 36561745, 296623329, 198514416, 71608976, 27555416, 335740598, 333791574, 31519244,
-		296535265, 296460548, 151219265, 17912927, 343988875, 158624493, 280107949, 282440101, 341002882, 358600578,
-		57403323, 331923753, 343012114, 260788029, 247628446, 342074957, 296664003, 240062547, 344584164, 186792715,
-		337885273, 15176404, 296732372, 247014818, 356551185, 331165696, 242716433, 296441790, 135648849, 341715398,
-		244524641, 344930688, 315664608, 151511502, 341805969, 358268971, 257840407, 334513405, 221602785, 241115186,
-		296651040, 348703554, 282402058, 296837272, 30929387, 243018406, 274422066, 281217156, 149133192, 340699531,
-		303084226, 198693614, 245692688, 38656782, 307616001, 358620978, 154112347, 340518669, 38894092, 295727717,
-		31194345, 342481745, 149159953, 340190122, 291672732, 264130894, 296447970, 52257639, 288469881, 296493969,
-		227771798, 292174186, 27273399, 238278, 341178574, 356548740, 358663776, 352732593, 282041640, 342554649,
-		154303232, 21641895, 19648464, 293197538, 301232589, 130758720, 149991710, 31394901, 348585537, 356544936,
-		340827044, 341237250, 277873975, 288260403, 253238072, 51300357, 287864549, 337525159, 264368525, 258754258,
-		268555969, 304808493, 256798463, 26000629, 33271441, 330667145, 340520335, 337826910, 340682553, 291205538,
-		300136808, 24678113, 342733855, 246900300, 356818821, 358213310, 304248101, 33970066, 357973541, 26929255,
-		343891586, 296649690, 249788298, 24479856, 26942379, 122849492, 348489075, 123184298, 166017203, 121944019,
-		122687608, 98773935, 340581781, 35757330, 357997154, 340714461, 333683438, 43077948, 288456630, 203382484,
-		227030208, 356561337, 330647578, 304117652, 321385729, 342649188, 348714430, 281768600, 240078960, 240081257,
-		246919969, 245618727, 36113542, 152870176, 278889765, 310843644, 304256080, 356541897, 288203818, 59769968,
-		149997301, 269385243, 32805901, 346706462, 315877395, 296434150, 346420194, 180907795, 265223606, 318564662,
-		151512149, 253004462, 17976643, 61341689, 353420714, 24206115, 259534255, 28476235, 130234353, 347469612,
-		285682623, 175857386, 245381804, 296741714, 292672940, 100001077, 342708198, 296448005, 246447736, 335143775,
-		184878718, 61872233, 143278178, 340736006, 32621006, 291298547, 189682440, 301812407, 253285069, 356594235,
-		31573554, 293183048, 249429875, 242071866, 340672702, 296556292, 311354754, 244293744, 354101473, 340768675,
-		37649086, 296790759, 265125032, 99716474, 33571545, 326109626, 309992766, 250486249, 241823516, 341724249,
-		24257258, 318237021, 296445164, 258417498, 287781101, 334902374, 343032216, 59845148, 296645974, 306640037,
-		100118947, 25843494, 24152719, 343898198, 288890992, 257788542, 337603699, 126685097, 24310995, 244161457,
-		271135056, 31633427, 325231439, 27833560, 296739647, 24114358, 262722193, 248332546, 253975552, 277587871,
-		357522580, 344367079, 244304294, 337373479, 206121399, 60216256, 149577359, 331576777, 33378223, 256107456,
-		289100717, 256615911, 305678572, 339156348, 340828655, 189682463, 253380141, 253155322, 246437317, 302003522,
-		358230847, 342312674, 356553730, 357776397, 61137002, 303755046, 292301425, 356528761, 245292318, 298686484,
-		320235443, 315680996, 24320566, 257799385, 309124194, 299019573, 311320427, 227405243, 203351078, 61524988,
-		342726850, 240292543, 263873970, 348848489, 18357025, 82576440, 357434572, 154291540, 154058770, 327862410,
-		153533421, 25785280, 296538166, 243859307, 256818615, 155529013, 262470794, 357314902, 291653741, 82455301,
-		337563779, 56130394, 16811626, 304000206, 297153256, 358388207, 254881569, 277023709, 243723803, 337534731,
-		341012729, 341632654, 80002753, 357930315, 269670764, 26159857, 244953686, 89678204, 31118180, 113688356,
-		356812173, 341014843, 25880461, 240419226, 226071375, 237444372, 241819138, 32338003, 35487669, 99822807,
-		341657774, 153979988, 301443572, 141043505, 341141787, 340126903, 308415823, 300464095, 149843703, 271635291,
-		340634437, 90844723, 348507808, 209287575, 176239760, 301422387, 260700458, 98986794, 257580644, 38813881,
-		244201728, 172635875, 54302802, 296523314, 337664265, 31690883, 28704900, 260262107, 335636908, 292232466,
-		83699565, 153399634, 16543804, 342720264, 340499031, 312505027, 255223102, 264720167, 179406079, 353593226,
-		341018728, 344782183, 340645513, 304156467, 282402213, 264012042, 271376937, 251262683, 296795330, 42807413,
-		240029630, 30345658, 242082298, 304579910, 256504130, 297736931, 291104678, 340702726, 267466370, 19643021,
-		52984833, 255701762, 33407933, 246474459, 151523880, 297233892, 321606655, 321491773, 18360206, 132040425,
-		340732819, 25210202, 249643795, 357332300, 313749348, 344112063, 28497266, 340793678, 90590719, 341929870,
-		274214655, 243362598, 296873788, 244464333, 329581143, 71300535, 296694208, 296624279, 245542820, 133301343,
-		153066996, 304781500, 291487999, 154164970, 154104683, 348822894, 296693625, 353069023, 289041557, 342674053,
-		270788621, 56515952, 249236250, 205540949, 334543289, 357484759, 249104023, 33575096, 333215532, 269653084,
-		119789498, 281712871, 317633267, 282741006, 296769000, 145249089, 341013759, 340404124, 240569825, 257809281,
-		308026941, 262208876, 32520685, 356818278, 331245305, 240124991, 357551396, 16092682, 262306357, 296555198,
-		342983486, 331836257, 277774213, 270331883, 254970570, 249831381, 150914015, 317790378, 60607077, 282365966,
-		66905244, 249037038, 296683721, 250498808, 300599693, 107362973, 246278184, 296520344, 291002709, 356551916,
-		246982818, 311528820, 27682447, 353317227, 40772907, 24466261, 90204507, 288570901, 99207057, 12524619,
-		16528428, 240775258, 253275350, 342332112, 31754943, 296462440, 122679181, 113400102, 230110147, 262478492,
-		33307187, 134934978, 162905767, 46989851, 321605716, 283331099, 100224694, 274090052, 27871087, 29859239,
-		318585840, 248315330, 19526213, 32542308, 153590929, 329538181, 270944141, 358177337, 42412136, 305752089,
-		296686948, 357942443, 252674255, 262698210, 354419640, 337605789, 308127758, 246314508, 208429640, 78477401,
-		150624271, 337675613, 296737585, 260832430, 356566405, 19577204, 342332188, 296686469, 247617066, 262187823,
-		352803075, 151399781, 150049361, 19522694, 60886499, 313688033, 295318141, 27234828, 338424100, 358212042,
-		296765409, 346464785, 245144813, 273474923, 296760679, 348487874, 260861043, 49161693, 348700105, 356673494,
-		33206789, 296498900, 22469704, 265064820, 254819346, 245284291, 33125638, 296775465, 240929934, 242982391,
-		30785605, 158809498, 51487374, 153125984, 353241249, 296618826, 67203583, 210172669, 312868871, 249599260,
-		314920749, 341632273, 224584975, 354531211, 344330654, 282600052, 71872385, 30230177, 280841264, 282402030,
-		296629090, 296502472, 31894906, 242784658, 357427465, 253252379, 49259598, 144667150, 277362988, 95874325,
-		204175321, 29563833, 47235974, 9329201, 154111200, 275512428, 340756446, 22658274, 204047332, 281944759,
-		148983598, 297740145, 303976946, 296447666, 296499476, 241159501, 262237350, 225646996, 340668154, 351950927,
-		240062710, 320602250, 357088528, 33310066, 341648636, 243480789, 30352680, 293171959, 348863207, 277709959,
-		73514577, 348441777, 210331520, 341930164, 299855887, 82799861, 151916073, 357565480, 50873072, 263769756,
-		291027045, 60564493, 273533531, 144026281, 334723250, 358085737, 155116491, 177135363, 296761504, 348537149,
-		29325904, 28532348, 313938065, 246728935, 337680434, 130078766, 296625330, 276691325, 296513644, 263586584,
-		28014353, 26858922, 314454508, 299186024, 353917044, 340530645, 341028900, 266171512, 36077264, 356836705,
-		17167783, 304721321, 241008408, 242995657, 269015192, 256834678, 354528797, 26618161, 296447670, 249350934,
-		274625496, 244091058, 341722032, 346102968, 308819337, 307712124, 330474763, 140510257, 246069466, 330188743,
-		38686763, 358695074, 123054971, 337571499, 304131850, 305116562, 354635432, 351944054, 243028003, 305234940,
-		273880482, 279980042, 27976877, 340655692, 281718611, 342018185, 282514978, 253623891, 341009206, 16418382,
-		260974564, 358601137, 248352335, 270561738, 281905133, 304865814, 357586936, 123828174, 153618898, 313301267,
-		33582881, 291527797, 30249288, 347457909, 346818205, 306597725, 49790488, 347974367, 357760748, 329095544,
-		296552853, 202761567, 26944098, 16807452, 246456797, 337521740, 262743862, 33683501, 356587470, 342482335,
-		348792488, 340798732, 297310619, 330777514, 342800000, 154673960, 350560680, 340690650, 110680349, 340760853,
-		112099443, 15950387, 358069930, 296439107, 60395447, 312484466, 54019265, 245866705, 249074233, 403091971,
-		338427003, 158408695, 251684551, 312956111, 148867085, 34966321, 320719501, 158595474, 277386220, 343892301,
-		247563562, 110070381, 356565474, 230122193, 29253003, 246121284, 296716987, 343939875, 134214816, 296505505,
-		242984265, 172403970, 54365444, 30873635, 342424838, 347802507, 31627059, 172492800, 84029076, 200039937,
-		31353821, 45394312, 297527472, 258693690, 296598082, 296724085, 150946437, 205519304, 81197287, 45316566,
-		188523476, 21475781, 263790481, 286739365, 259545977, 340485827, 293427322, 31873315, 340710542, 246197766,
-		288472913, 261581839, 330684077, 158815120, 357604416, 357194465, 153995815, 123671578, 27862323, 308914192,
-		325168291, 20984347, 297589172, 339377746, 255082738, 249306369, 330756257, 340996509, 291857893, 154042019,
-		81761126, 306257283, 314916619, 296839603, 270743482, 341023618, 246885074, 340832821, 341006901, 112225140,
-		244210653, 296693535, 26430629, 24777831, 358151689, 148146480, 337579258, 245408045, 54358131, 357447327,
-		337558815, 339444744, 316031821, 25873948, 37171573, 358668543, 296592048, 395655896, 118032126, 42695158,
-		154360531, 145497623, 27897477, 352855418, 348140886, 266171905, 296723488, 348520155, 357552847, 154090665,
-		33360318, 354159788, 158577053, 252759908, 307686566, 198683949, 27599452, 340628105, 280177164, 341801947,
-		32144114, 137180310, 98744116, 247589906, 341027104, 369819111, 171120996, 99311449, 56342330, 340681737,
-		66604582, 263546945, 248335632, 358682590, 72028728, 249637779, 291607425, 263651438, 34043776, 343073162,
-		340575177, 259549203, 292356466, 276672824, 149745885, 89545332, 255064840, 18157101, 348568096, 340857845,
-		356832124, 26804370, 342430363, 358400740, 28547785, 240511854, 279729164, 330674601, 246157681, 254240955,
-		296636531, 337550062, 262735814, 18238277, 296655428, 338983030, 341645832, 243013774, 340793379, 348643928,
-		132218901, 249628464, 296739213, 149554972, 343934489, 243345022, 346071720, 277318540, 307645903, 99131257,
-		249350477, 77741551, 296742731, 356562217, 337542397, 309964701, 151413779, 248302804, 201777974, 314494500,
-		330634838, 268545146, 296658693, 376450427, 154102440, 134252636, 339081781, 346699107, 253784411, 353577876,
-		130163301, 296699055, 348473589, 32257916, 243814468, 296861098, 290518105, 151681902, 340596624, 353427633,
-		303706057, 249305816, 151374538, 311552467, 282413227, 358245305, 342057158, 286138851, 338746264, 277780036,
-		277510289, 342421634, 302032581, 252579464, 296837902, 149106804, 318256882, 168487169, 151928102, 246442696,
-		246963317, 269139075, 61777624, 246156294, 24586320, 2113576, 150607305, 180410529, 246416894, 253027229,
-		158496862, 289286519, 306433495, 242987067, 356852104, 158582662, 13700891, 357802388, 307876586, 31875667,
-		245423406, 356587388, 305933226, 317473578, 342034353, 295028543, 262399462, 352740347, 306588422, 158806580,
-		310021856, 348936426, 342486989, 340683596, 227304290, 171317721, 304114818, 131973341, 26864028, 296632179,
-		256267082, 254833722, 290192224, 24190465, 354253915, 286005878, 113755394, 357883947, 154305878, 17165547,
-		314491757, 340678367, 357788452, 243187363, 168506669, 312821285, 151630940, 292425701, 256745964, 31254490,
-		340896370, 130962680, 122043075, 291327913, 296802223, 15431433, 357839580, 107549733, 297964457, 303475635,
-		52131804, 170800062, 178431860, 257807950, 337562770, 110370778, 308966126, 276542906, 358631719, 376809434,
-		263555223, 59982573, 52241907, 345001160, 296805070, 270211610, 135027080, 348830387, 304163303, 33338996,
-		30114808, 235129275, 112524684, 357767684, 135059632, 44025454, 242895339, 304702969, 334388079, 340872747,
-		27609746, 26019636, 265417664, 243206369, 207570530, 342418793, 358689888, 243728004, 88924762, 358040280,
-		246924204, 252915301, 138931040, 317893515, 296710872, 248988409, 346446992, 241804962, 342016724, 58485550,
-		50007448, 114018290, 260559537, 341714755, 158602438, 257086662, 296743326, 16658127, 243026220, 342031782,
-		203501596, 296730757, 33532438, 255059446, 358635171, 248193789, 315673143, 329120099, 341931687, 32942821,
-		340504889, 249020052, 241817640, 269784875, 255646939, 241616523, 346882094, 82306899, 311919614, 343965149,
-		337534737, 336172029, 186114391, 255188767, 331399791, 250511807, 270272656, 357569035, 296694895, 293474132,
-		296492817, 340696609, 349415024, 26703166, 154012868, 229916975, 234968021, 258918502, 36269170, 341680730,
-		260773942, 342008167, 78163761, 13930057, 339011700, 304492483, 296743738, 354714590, 340550630, 342565016,
-		296762118, 153413805, 289089846, 337520444, 38745567, 296710009, 178531723, 334276947, 352699859, 288481271,
-		62014207, 324625220, 331387258, 356557992, 226069622, 57374971, 246259067, 279824915, 270977740, 240061119,
-		246029331, 296547309, 326875976, 231325037, 296623783, 248368265, 28735716, 354342899, 255576110, 336168195,
-		255503967, 339252905, 296734056, 240116161, 348783392, 260971213, 33379726, 267059819, 29497752, 296680409,
-		249408014, 333780904, 248252956, 33438850, 264253010, 203996141, 74087331, 252905435, 158649818, 296486316,
-		38441411, 353879610, 52220115, 177898358, 65220878, 104421223, 451908, 357501465, 151323464, 267706655,
-		36408872, 304849144, 276820988, 282427629, 42057452, 268369358, 281498191, 296726296, 336334999, 342794461,
-		340641844, 273232052, 342573042, 340594656, 348243494, 282484471, 291807054, 309651872, 154187534, 321725866,
-		220253663, 308275539, 301907970, 23375589, 341653215, 318146169, 347632424, 309977321, 358270416, 327794291,
-		96518297, 298335535, 331633099, 340701726, 389344478, 155118135, 340606350, 245265422, 33177981, 153076768,
-		339474581, 81869292, 27976460, 267754345, 340552321, 154142579, 21496640, 241141360, 296784558, 46085340,
-		304730079, 342526890, 296645649, 99980931, 240115581, 154478948, 358056265, 343696464, 348563894, 291364019,
-		296652307, 340692028, 296448646, 33207158, 273880835, 252713830, 245667916, 30171778, 291415280, 304958503,
-		130254623, 135057518, 160195611, 346342849, 41632651, 41494397, 28216133, 340215457, 282442956, 24312231,
-		346744473, 269161893, 240074670, 297286445, 253807362, 224587252, 250482754, 344867560, 253872814, 245706628,
-		310293341, 292355454, 30887623, 243043458, 249031288, 31575313, 25213313, 279357587, 243046994, 240055188,
-		248143249, 341638188, 158811925, 150267452, 49473495, 26275016, 358061452, 281870749, 309734405, 342695200,
-		337526512, 201220988, 256648381, 96633929, 342069018, 318344080, 248767585, 241154188, 90523921, 25638217,
-		296617055, 240101207, 345084990, 337548909, 26864934, 30025899, 246205900, 357615276, 73467481, 281731186,
-		340831998, 317898208, 78020727, 123007765, 32969454, 27258200, 340600927, 304376317, 248384565, 257775847,
-		296483834, 54915828, 52259922, 258895569, 357622196, 339809812, 356778845, 358074506, 60688080, 334646804,
-		122989902, 353130071, 57438373, 292063035, 112368609, 169456695, 167284276, 357290015, 245650965, 158851564,
-		246334795, 151664365, 130004931, 303721627, 43772527, 28565171, 309444275, 154373633, 154146868, 350232772,
-		161088544, 341807703, 241591055, 245657468, 338087273, 346262154, 244299679, 341626453, 296732559, 141046434,
-		345129056, 296492806, 25966472, 96991674, 74127292, 26205826, 291693843, 263782020, 24724601, 34566623,
-		337571117, 312716712, 340768876, 184746695, 316662529, 246102257, 68171844, 354647581, 30796061, 277727873,
-		20971391, 296581752, 281976382, 81504737, 356555799, 253830336, 342073973, 241915946, 357459768, 348577462,
-		254761598, 358227097, 358044596, 243015834, 341006341, 300814432, 256848254, 30169737, 213102990, 341671642,
-		208411072, 51720726, 285708835, 331894738, 56283178, 254709057, 16067603, 241813298, 158478227, 242984897,
-		255151967, 23904221, 256827671, 30485763, 356556997, 299262526, 265841781, 339836142, 318406016, 304378912,
-		337505172, 346122555, 262640593, 245814318, 296796178, 343907571, 61104184, 26489018, 369133002, 342788214,
-		354360038, 259558647, 343023621, 357293843, 81390424, 314775299, 242981884, 154301412, 27850028, 241909760,
-		356606207, 334136088, 26612068, 159120214, 60053420, 281553134, 340518740, 106987340, 243031291, 84848431,
-		54288389, 26936410, 153810825, 340822860, 281818036, 325617851, 149586503, 260042853, 256515945, 337584967,
-		113206534, 292453946, 245135938, 348495343, 248344706, 295485824, 303717650, 82489681, 54469435, 338323722,
-		352818412, 173094408, 252375118, 148821397, 296840806, 358089954, 348929369, 186398492, 151512651, 257817082,
-		296631578, 305012724, 290517706, 271737838, 309545848, 340862306, 27906681, 54594196, 305495390, 337589097,
-		249825088, 303648995, 241145150, 21137983, 338104648, 154125308, 24181305, 257584695, 277915696, 33322079,
-		281707655, 198499335, 69159317, 27829936, 112834975, 170121408, 60736976, 253136300, 286009161, 311879579,
-		256772135, 266820547, 27274751, 54851666, 270420653, 266710837, 53552784, 258997482, 152579027, 24108212,
-		149395291, 331879159, 205279044, 41852092, 283947689, 276890729, 341871208, 12718547, 24128991, 137797633,
-		302105639, 357534409, 283735787, 279338754, 343939647, 307211480, 309758990, 16852340, 282085853, 30109777,
-		292003381, 342029080, 296637322, 41562224, 269444038, 100244190, 145345960, 13902196, 356560752, 299564300,
-		45488744, 20628424, 73900980, 190976735, 296826527, 282405962, 46720466, 340681109, 347320920, 263436536,
-		348796518, 265117054, 29393990, 64778890, 344445998, 357361258, 318620105, 286487653, 340829248, 27790630,
-		343076490, 270090818, 340861966, 23386851, 123717185, 257757379, 61334693, 245241827, 358229525, 253145577,
-		269343810, 199243674, 26296206, 291497031, 293259748, 307815525, 340803230, 304485034, 356611181, 21088956,
-		296758959, 340538840, 223900961, 257768833, 228109164, 340850068, 189146430, 357642673, 357957890, 343045501,
-		310744887, 342419243, 47283612, 154149863, 211711297, 153521494, 102934372, 247009112, 317934031, 28513004,
-		204093236, 100281244, 342650805, 314939401, 286181633, 337663055, 353606779, 296735827, 342473621, 100274209,
-		242979616, 353356545, 341018473, 296377397, 263367955, 346119333, 182329230, 295228015, 29664764, 181010549,
-		342987813, 16267142, 172854771, 99873091, 33349150, 283346899, 28012132, 341804167, 166823265, 62688937,
-		10395767, 110134106, 296744565, 264866370, 153982228, 27375053, 132501159, 33206023, 153659445, 289244970,
-		336814041, 99387394, 297254887, 211874692, 130062011, 28402636, 342365750, 241089437, 333788236, 337518646,
-		340676695, 25164367, 248318383, 343906779, 125125435, 348584358, 342448484, 247230047, 309949766, 248436378,
-		186513515, 246716279, 240087296, 266447172, 315791428, 293160936, 42613827, 296509169, 96576038, 141412392,
-		248354364, 348508691, 342645600, 104430130, 246877420, 154360841, 340848898, 154184500, 341637063, 256650810,
-		154193027, 122471542, 54688773, 348000177, 296711373, 234579205, 357768702, 23520031, 340701004, 32042929,
-		336166500, 333775014, 240072310, 185515158, 256642304, 122988611, 184094683, 226895948, 331146938, 333797180,
-		288379436, 143015392, 344895000, 28492755, 348511526, 296643182, 28046997, 274778991, 314716701, 35351954,
-		344473749, 296719768, 240730741, 262543951, 296570128, 246273209, 249337445, 153932458, 145496568, 319615914,
-		358599496, 33331552, 357190220, 249118554, 34674463, 30948905, 123529255, 124611643, 347266679, 357423404,
-		340853903, 248996491, 46889180, 36747410, 296480840, 342672014, 276517055, 249269699, 348854919, 296739394,
-		244200070, 200712512, 224092782, 256686896, 240066908, 250310787, 346483861, 296488581, 136186884, 73456648,
-		340889998, 296483365, 262739435, 244316787, 357268251, 282402316, 252822785, 354332814, 270743489, 16090982,
-		82697224, 317604839, 292281018, 262599175, 312823040, 351510961, 327322553, 358269471, 208812962, 348917251,
-		64997871, 357278103, 342491845, 64004036, 56705558, 28354160, 357766970, 296935935, 32550849, 257905419,
-		282080288, 296462830, 81441380, 25556442, 353468341, 341004780, 248388721, 251424664, 340759879, 304128232,
-		341645824, 122812654, 253849947, 146368498, 26902813, 192922759, 292011884, 267613230, 296480602, 341669199,
-		291682787, 44883058, 248799275, 282402322, 26694289, 246440729, 353028299, 341624427, 59653078, 154672205,
-		340768561, 30076512, 346111228, 31454046, 258023316, 27878633, 341680275, 277978772, 240486311, 28706827,
-		356724421, 356847934, 301492120, 261295575, 286268818, 97146065, 277200610, 337564298, 30130584, 296744791,
-		12165533, 382994815, 291008749, 134121846, 343501510, 262283244, 18589457, 261095456, 270298568, 341017327,
-		307179500, 295691141, 296115230, 75089112, 291219451, 311806708, 279630669, 340806017, 341365610, 246273980,
-		340669472, 303844588, 307348482, 296703548, 282854777, 158586057, 303636567, 340805398, 296524785, 296823397,
-		296754312, 267474064, 265213162, 87714911, 33971627, 151482359, 208544054, 357792108, 312165155, 256089610,
-		240111920, 255137037, 358650450, 357472832, 358245283, 285686536, 28011542, 340771602, 241279713, 312771884,
-		87690219, 148613702, 17169980, 36738254, 322918119, 345165782, 357804181, 358613951, 208210165, 296496211,
-		79358707, 342647339, 21176323, 67952466, 30954363, 258923219, 320100370, 343963932, 357573745, 353362369,
-		253876134, 330785123, 300441349, 253808526, 100753664, 23483848, 122775503, 253784900, 348437900, 358282584,
-		348712890, 248990708, 339883645, 17715651, 337602414, 28774287, 297542121, 348187475, 23383987, 317917094,
-		344398265, 241587497, 245267960, 341034666, 340552265, 300801547, 341054367, 309911792, 149021952, 245872010,
-		341867223, 74239546, 318199013, 80123370, 135402988, 342324401, 296742226, 348794936, 154097549, 23675559,
-		28438803, 340679974, 296426078, 320192960, 204025540, 340821633, 336673960, 284432116, 315125999, 61271297,
-		301947838, 24597855, 296657632, 269945543, 282361216, 335647707, 210716093, 340750756, 31916416, 74152938,
-		125346674, 153830992, 357305305, 270497231, 341676849, 337511852, 248993514, 209898576, 24494525}
-
 	b.ResetTimer()
 	b.ReportAllocs()
 
 	for i := 0; i < b.N; i++ {
-		k := rand.Intn(len(list))
-		id = list[k]
+		k := rand.Intn(len(randomIdList))
+		id = randomIdList[k]
 		lon, lat, err = compressData.FindNodeByID(id)
 		if err != nil {
 			b.Errorf("%v", err)
@@ -275,6 +99,221 @@ func BenchmarkFindNodeById(b *testing.B) {
 	_ = lat
 }
 
+// randomIdList
+//
+// # English:
+//
+// # Randoms IDs (2048) selected into database
+//
+// # Português:
+//
+// IDs aleatórios (2048) selecionados no banco de dados
+var randomIdList = []int64{245623033, 36561745, 296623329, 198514416, 71608976, 27555416, 335740598, 333791574, 31519244,
+	296535265, 296460548, 151219265, 17912927, 343988875, 158624493, 280107949, 282440101, 341002882, 358600578,
+	57403323, 331923753, 343012114, 260788029, 247628446, 342074957, 296664003, 240062547, 344584164, 186792715,
+	337885273, 15176404, 296732372, 247014818, 356551185, 331165696, 242716433, 296441790, 135648849, 341715398,
+	244524641, 344930688, 315664608, 151511502, 341805969, 358268971, 257840407, 334513405, 221602785, 241115186,
+	296651040, 348703554, 282402058, 296837272, 30929387, 243018406, 274422066, 281217156, 149133192, 340699531,
+	303084226, 198693614, 245692688, 38656782, 307616001, 358620978, 154112347, 340518669, 38894092, 295727717,
+	31194345, 342481745, 149159953, 340190122, 291672732, 264130894, 296447970, 52257639, 288469881, 296493969,
+	227771798, 292174186, 27273399, 238278, 341178574, 356548740, 358663776, 352732593, 282041640, 342554649,
+	154303232, 21641895, 19648464, 293197538, 301232589, 130758720, 149991710, 31394901, 348585537, 356544936,
+	340827044, 341237250, 277873975, 288260403, 253238072, 51300357, 287864549, 337525159, 264368525, 258754258,
+	268555969, 304808493, 256798463, 26000629, 33271441, 330667145, 340520335, 337826910, 340682553, 291205538,
+	300136808, 24678113, 342733855, 246900300, 356818821, 358213310, 304248101, 33970066, 357973541, 26929255,
+	343891586, 296649690, 249788298, 24479856, 26942379, 122849492, 348489075, 123184298, 166017203, 121944019,
+	122687608, 98773935, 340581781, 35757330, 357997154, 340714461, 333683438, 43077948, 288456630, 203382484,
+	227030208, 356561337, 330647578, 304117652, 321385729, 342649188, 348714430, 281768600, 240078960, 240081257,
+	246919969, 245618727, 36113542, 152870176, 278889765, 310843644, 304256080, 356541897, 288203818, 59769968,
+	149997301, 269385243, 32805901, 346706462, 315877395, 296434150, 346420194, 180907795, 265223606, 318564662,
+	151512149, 253004462, 17976643, 61341689, 353420714, 24206115, 259534255, 28476235, 130234353, 347469612,
+	285682623, 175857386, 245381804, 296741714, 292672940, 100001077, 342708198, 296448005, 246447736, 335143775,
+	184878718, 61872233, 143278178, 340736006, 32621006, 291298547, 189682440, 301812407, 253285069, 356594235,
+	31573554, 293183048, 249429875, 242071866, 340672702, 296556292, 311354754, 244293744, 354101473, 340768675,
+	37649086, 296790759, 265125032, 99716474, 33571545, 326109626, 309992766, 250486249, 241823516, 341724249,
+	24257258, 318237021, 296445164, 258417498, 287781101, 334902374, 343032216, 59845148, 296645974, 306640037,
+	100118947, 25843494, 24152719, 343898198, 288890992, 257788542, 337603699, 126685097, 24310995, 244161457,
+	271135056, 31633427, 325231439, 27833560, 296739647, 24114358, 262722193, 248332546, 253975552, 277587871,
+	357522580, 344367079, 244304294, 337373479, 206121399, 60216256, 149577359, 331576777, 33378223, 256107456,
+	289100717, 256615911, 305678572, 339156348, 340828655, 189682463, 253380141, 253155322, 246437317, 302003522,
+	358230847, 342312674, 356553730, 357776397, 61137002, 303755046, 292301425, 356528761, 245292318, 298686484,
+	320235443, 315680996, 24320566, 257799385, 309124194, 299019573, 311320427, 227405243, 203351078, 61524988,
+	342726850, 240292543, 263873970, 348848489, 18357025, 82576440, 357434572, 154291540, 154058770, 327862410,
+	153533421, 25785280, 296538166, 243859307, 256818615, 155529013, 262470794, 357314902, 291653741, 82455301,
+	337563779, 56130394, 16811626, 304000206, 297153256, 358388207, 254881569, 277023709, 243723803, 337534731,
+	341012729, 341632654, 80002753, 357930315, 269670764, 26159857, 244953686, 89678204, 31118180, 113688356,
+	356812173, 341014843, 25880461, 240419226, 226071375, 237444372, 241819138, 32338003, 35487669, 99822807,
+	341657774, 153979988, 301443572, 141043505, 341141787, 340126903, 308415823, 300464095, 149843703, 271635291,
+	340634437, 90844723, 348507808, 209287575, 176239760, 301422387, 260700458, 98986794, 257580644, 38813881,
+	244201728, 172635875, 54302802, 296523314, 337664265, 31690883, 28704900, 260262107, 335636908, 292232466,
+	83699565, 153399634, 16543804, 342720264, 340499031, 312505027, 255223102, 264720167, 179406079, 353593226,
+	341018728, 344782183, 340645513, 304156467, 282402213, 264012042, 271376937, 251262683, 296795330, 42807413,
+	240029630, 30345658, 242082298, 304579910, 256504130, 297736931, 291104678, 340702726, 267466370, 19643021,
+	52984833, 255701762, 33407933, 246474459, 151523880, 297233892, 321606655, 321491773, 18360206, 132040425,
+	340732819, 25210202, 249643795, 357332300, 313749348, 344112063, 28497266, 340793678, 90590719, 341929870,
+	274214655, 243362598, 296873788, 244464333, 329581143, 71300535, 296694208, 296624279, 245542820, 133301343,
+	153066996, 304781500, 291487999, 154164970, 154104683, 348822894, 296693625, 353069023, 289041557, 342674053,
+	270788621, 56515952, 249236250, 205540949, 334543289, 357484759, 249104023, 33575096, 333215532, 269653084,
+	119789498, 281712871, 317633267, 282741006, 296769000, 145249089, 341013759, 340404124, 240569825, 257809281,
+	308026941, 262208876, 32520685, 356818278, 331245305, 240124991, 357551396, 16092682, 262306357, 296555198,
+	342983486, 331836257, 277774213, 270331883, 254970570, 249831381, 150914015, 317790378, 60607077, 282365966,
+	66905244, 249037038, 296683721, 250498808, 300599693, 107362973, 246278184, 296520344, 291002709, 356551916,
+	246982818, 311528820, 27682447, 353317227, 40772907, 24466261, 90204507, 288570901, 99207057, 12524619,
+	16528428, 240775258, 253275350, 342332112, 31754943, 296462440, 122679181, 113400102, 230110147, 262478492,
+	33307187, 134934978, 162905767, 46989851, 321605716, 283331099, 100224694, 274090052, 27871087, 29859239,
+	318585840, 248315330, 19526213, 32542308, 153590929, 329538181, 270944141, 358177337, 42412136, 305752089,
+	296686948, 357942443, 252674255, 262698210, 354419640, 337605789, 308127758, 246314508, 208429640, 78477401,
+	150624271, 337675613, 296737585, 260832430, 356566405, 19577204, 342332188, 296686469, 247617066, 262187823,
+	352803075, 151399781, 150049361, 19522694, 60886499, 313688033, 295318141, 27234828, 338424100, 358212042,
+	296765409, 346464785, 245144813, 273474923, 296760679, 348487874, 260861043, 49161693, 348700105, 356673494,
+	33206789, 296498900, 22469704, 265064820, 254819346, 245284291, 33125638, 296775465, 240929934, 242982391,
+	30785605, 158809498, 51487374, 153125984, 353241249, 296618826, 67203583, 210172669, 312868871, 249599260,
+	314920749, 341632273, 224584975, 354531211, 344330654, 282600052, 71872385, 30230177, 280841264, 282402030,
+	296629090, 296502472, 31894906, 242784658, 357427465, 253252379, 49259598, 144667150, 277362988, 95874325,
+	204175321, 29563833, 47235974, 9329201, 154111200, 275512428, 340756446, 22658274, 204047332, 281944759,
+	148983598, 297740145, 303976946, 296447666, 296499476, 241159501, 262237350, 225646996, 340668154, 351950927,
+	240062710, 320602250, 357088528, 33310066, 341648636, 243480789, 30352680, 293171959, 348863207, 277709959,
+	73514577, 348441777, 210331520, 341930164, 299855887, 82799861, 151916073, 357565480, 50873072, 263769756,
+	291027045, 60564493, 273533531, 144026281, 334723250, 358085737, 155116491, 177135363, 296761504, 348537149,
+	29325904, 28532348, 313938065, 246728935, 337680434, 130078766, 296625330, 276691325, 296513644, 263586584,
+	28014353, 26858922, 314454508, 299186024, 353917044, 340530645, 341028900, 266171512, 36077264, 356836705,
+	17167783, 304721321, 241008408, 242995657, 269015192, 256834678, 354528797, 26618161, 296447670, 249350934,
+	274625496, 244091058, 341722032, 346102968, 308819337, 307712124, 330474763, 140510257, 246069466, 330188743,
+	38686763, 358695074, 123054971, 337571499, 304131850, 305116562, 354635432, 351944054, 243028003, 305234940,
+	273880482, 279980042, 27976877, 340655692, 281718611, 342018185, 282514978, 253623891, 341009206, 16418382,
+	260974564, 358601137, 248352335, 270561738, 281905133, 304865814, 357586936, 123828174, 153618898, 313301267,
+	33582881, 291527797, 30249288, 347457909, 346818205, 306597725, 49790488, 347974367, 357760748, 329095544,
+	296552853, 202761567, 26944098, 16807452, 246456797, 337521740, 262743862, 33683501, 356587470, 342482335,
+	348792488, 340798732, 297310619, 330777514, 342800000, 154673960, 350560680, 340690650, 110680349, 340760853,
+	112099443, 15950387, 358069930, 296439107, 60395447, 312484466, 54019265, 245866705, 249074233, 403091971,
+	338427003, 158408695, 251684551, 312956111, 148867085, 34966321, 320719501, 158595474, 277386220, 343892301,
+	247563562, 110070381, 356565474, 230122193, 29253003, 246121284, 296716987, 343939875, 134214816, 296505505,
+	242984265, 172403970, 54365444, 30873635, 342424838, 347802507, 31627059, 172492800, 84029076, 200039937,
+	31353821, 45394312, 297527472, 258693690, 296598082, 296724085, 150946437, 205519304, 81197287, 45316566,
+	188523476, 21475781, 263790481, 286739365, 259545977, 340485827, 293427322, 31873315, 340710542, 246197766,
+	288472913, 261581839, 330684077, 158815120, 357604416, 357194465, 153995815, 123671578, 27862323, 308914192,
+	325168291, 20984347, 297589172, 339377746, 255082738, 249306369, 330756257, 340996509, 291857893, 154042019,
+	81761126, 306257283, 314916619, 296839603, 270743482, 341023618, 246885074, 340832821, 341006901, 112225140,
+	244210653, 296693535, 26430629, 24777831, 358151689, 148146480, 337579258, 245408045, 54358131, 357447327,
+	337558815, 339444744, 316031821, 25873948, 37171573, 358668543, 296592048, 395655896, 118032126, 42695158,
+	154360531, 145497623, 27897477, 352855418, 348140886, 266171905, 296723488, 348520155, 357552847, 154090665,
+	33360318, 354159788, 158577053, 252759908, 307686566, 198683949, 27599452, 340628105, 280177164, 341801947,
+	32144114, 137180310, 98744116, 247589906, 341027104, 369819111, 171120996, 99311449, 56342330, 340681737,
+	66604582, 263546945, 248335632, 358682590, 72028728, 249637779, 291607425, 263651438, 34043776, 343073162,
+	340575177, 259549203, 292356466, 276672824, 149745885, 89545332, 255064840, 18157101, 348568096, 340857845,
+	356832124, 26804370, 342430363, 358400740, 28547785, 240511854, 279729164, 330674601, 246157681, 254240955,
+	296636531, 337550062, 262735814, 18238277, 296655428, 338983030, 341645832, 243013774, 340793379, 348643928,
+	132218901, 249628464, 296739213, 149554972, 343934489, 243345022, 346071720, 277318540, 307645903, 99131257,
+	249350477, 77741551, 296742731, 356562217, 337542397, 309964701, 151413779, 248302804, 201777974, 314494500,
+	330634838, 268545146, 296658693, 376450427, 154102440, 134252636, 339081781, 346699107, 253784411, 353577876,
+	130163301, 296699055, 348473589, 32257916, 243814468, 296861098, 290518105, 151681902, 340596624, 353427633,
+	303706057, 249305816, 151374538, 311552467, 282413227, 358245305, 342057158, 286138851, 338746264, 277780036,
+	277510289, 342421634, 302032581, 252579464, 296837902, 149106804, 318256882, 168487169, 151928102, 246442696,
+	246963317, 269139075, 61777624, 246156294, 24586320, 2113576, 150607305, 180410529, 246416894, 253027229,
+	158496862, 289286519, 306433495, 242987067, 356852104, 158582662, 13700891, 357802388, 307876586, 31875667,
+	245423406, 356587388, 305933226, 317473578, 342034353, 295028543, 262399462, 352740347, 306588422, 158806580,
+	310021856, 348936426, 342486989, 340683596, 227304290, 171317721, 304114818, 131973341, 26864028, 296632179,
+	256267082, 254833722, 290192224, 24190465, 354253915, 286005878, 113755394, 357883947, 154305878, 17165547,
+	314491757, 340678367, 357788452, 243187363, 168506669, 312821285, 151630940, 292425701, 256745964, 31254490,
+	340896370, 130962680, 122043075, 291327913, 296802223, 15431433, 357839580, 107549733, 297964457, 303475635,
+	52131804, 170800062, 178431860, 257807950, 337562770, 110370778, 308966126, 276542906, 358631719, 376809434,
+	263555223, 59982573, 52241907, 345001160, 296805070, 270211610, 135027080, 348830387, 304163303, 33338996,
+	30114808, 235129275, 112524684, 357767684, 135059632, 44025454, 242895339, 304702969, 334388079, 340872747,
+	27609746, 26019636, 265417664, 243206369, 207570530, 342418793, 358689888, 243728004, 88924762, 358040280,
+	246924204, 252915301, 138931040, 317893515, 296710872, 248988409, 346446992, 241804962, 342016724, 58485550,
+	50007448, 114018290, 260559537, 341714755, 158602438, 257086662, 296743326, 16658127, 243026220, 342031782,
+	203501596, 296730757, 33532438, 255059446, 358635171, 248193789, 315673143, 329120099, 341931687, 32942821,
+	340504889, 249020052, 241817640, 269784875, 255646939, 241616523, 346882094, 82306899, 311919614, 343965149,
+	337534737, 336172029, 186114391, 255188767, 331399791, 250511807, 270272656, 357569035, 296694895, 293474132,
+	296492817, 340696609, 349415024, 26703166, 154012868, 229916975, 234968021, 258918502, 36269170, 341680730,
+	260773942, 342008167, 78163761, 13930057, 339011700, 304492483, 296743738, 354714590, 340550630, 342565016,
+	296762118, 153413805, 289089846, 337520444, 38745567, 296710009, 178531723, 334276947, 352699859, 288481271,
+	62014207, 324625220, 331387258, 356557992, 226069622, 57374971, 246259067, 279824915, 270977740, 240061119,
+	246029331, 296547309, 326875976, 231325037, 296623783, 248368265, 28735716, 354342899, 255576110, 336168195,
+	255503967, 339252905, 296734056, 240116161, 348783392, 260971213, 33379726, 267059819, 29497752, 296680409,
+	249408014, 333780904, 248252956, 33438850, 264253010, 203996141, 74087331, 252905435, 158649818, 296486316,
+	38441411, 353879610, 52220115, 177898358, 65220878, 104421223, 451908, 357501465, 151323464, 267706655,
+	36408872, 304849144, 276820988, 282427629, 42057452, 268369358, 281498191, 296726296, 336334999, 342794461,
+	340641844, 273232052, 342573042, 340594656, 348243494, 282484471, 291807054, 309651872, 154187534, 321725866,
+	220253663, 308275539, 301907970, 23375589, 341653215, 318146169, 347632424, 309977321, 358270416, 327794291,
+	96518297, 298335535, 331633099, 340701726, 389344478, 155118135, 340606350, 245265422, 33177981, 153076768,
+	339474581, 81869292, 27976460, 267754345, 340552321, 154142579, 21496640, 241141360, 296784558, 46085340,
+	304730079, 342526890, 296645649, 99980931, 240115581, 154478948, 358056265, 343696464, 348563894, 291364019,
+	296652307, 340692028, 296448646, 33207158, 273880835, 252713830, 245667916, 30171778, 291415280, 304958503,
+	130254623, 135057518, 160195611, 346342849, 41632651, 41494397, 28216133, 340215457, 282442956, 24312231,
+	346744473, 269161893, 240074670, 297286445, 253807362, 224587252, 250482754, 344867560, 253872814, 245706628,
+	310293341, 292355454, 30887623, 243043458, 249031288, 31575313, 25213313, 279357587, 243046994, 240055188,
+	248143249, 341638188, 158811925, 150267452, 49473495, 26275016, 358061452, 281870749, 309734405, 342695200,
+	337526512, 201220988, 256648381, 96633929, 342069018, 318344080, 248767585, 241154188, 90523921, 25638217,
+	296617055, 240101207, 345084990, 337548909, 26864934, 30025899, 246205900, 357615276, 73467481, 281731186,
+	340831998, 317898208, 78020727, 123007765, 32969454, 27258200, 340600927, 304376317, 248384565, 257775847,
+	296483834, 54915828, 52259922, 258895569, 357622196, 339809812, 356778845, 358074506, 60688080, 334646804,
+	122989902, 353130071, 57438373, 292063035, 112368609, 169456695, 167284276, 357290015, 245650965, 158851564,
+	246334795, 151664365, 130004931, 303721627, 43772527, 28565171, 309444275, 154373633, 154146868, 350232772,
+	161088544, 341807703, 241591055, 245657468, 338087273, 346262154, 244299679, 341626453, 296732559, 141046434,
+	345129056, 296492806, 25966472, 96991674, 74127292, 26205826, 291693843, 263782020, 24724601, 34566623,
+	337571117, 312716712, 340768876, 184746695, 316662529, 246102257, 68171844, 354647581, 30796061, 277727873,
+	20971391, 296581752, 281976382, 81504737, 356555799, 253830336, 342073973, 241915946, 357459768, 348577462,
+	254761598, 358227097, 358044596, 243015834, 341006341, 300814432, 256848254, 30169737, 213102990, 341671642,
+	208411072, 51720726, 285708835, 331894738, 56283178, 254709057, 16067603, 241813298, 158478227, 242984897,
+	255151967, 23904221, 256827671, 30485763, 356556997, 299262526, 265841781, 339836142, 318406016, 304378912,
+	337505172, 346122555, 262640593, 245814318, 296796178, 343907571, 61104184, 26489018, 369133002, 342788214,
+	354360038, 259558647, 343023621, 357293843, 81390424, 314775299, 242981884, 154301412, 27850028, 241909760,
+	356606207, 334136088, 26612068, 159120214, 60053420, 281553134, 340518740, 106987340, 243031291, 84848431,
+	54288389, 26936410, 153810825, 340822860, 281818036, 325617851, 149586503, 260042853, 256515945, 337584967,
+	113206534, 292453946, 245135938, 348495343, 248344706, 295485824, 303717650, 82489681, 54469435, 338323722,
+	352818412, 173094408, 252375118, 148821397, 296840806, 358089954, 348929369, 186398492, 151512651, 257817082,
+	296631578, 305012724, 290517706, 271737838, 309545848, 340862306, 27906681, 54594196, 305495390, 337589097,
+	249825088, 303648995, 241145150, 21137983, 338104648, 154125308, 24181305, 257584695, 277915696, 33322079,
+	281707655, 198499335, 69159317, 27829936, 112834975, 170121408, 60736976, 253136300, 286009161, 311879579,
+	256772135, 266820547, 27274751, 54851666, 270420653, 266710837, 53552784, 258997482, 152579027, 24108212,
+	149395291, 331879159, 205279044, 41852092, 283947689, 276890729, 341871208, 12718547, 24128991, 137797633,
+	302105639, 357534409, 283735787, 279338754, 343939647, 307211480, 309758990, 16852340, 282085853, 30109777,
+	292003381, 342029080, 296637322, 41562224, 269444038, 100244190, 145345960, 13902196, 356560752, 299564300,
+	45488744, 20628424, 73900980, 190976735, 296826527, 282405962, 46720466, 340681109, 347320920, 263436536,
+	348796518, 265117054, 29393990, 64778890, 344445998, 357361258, 318620105, 286487653, 340829248, 27790630,
+	343076490, 270090818, 340861966, 23386851, 123717185, 257757379, 61334693, 245241827, 358229525, 253145577,
+	269343810, 199243674, 26296206, 291497031, 293259748, 307815525, 340803230, 304485034, 356611181, 21088956,
+	296758959, 340538840, 223900961, 257768833, 228109164, 340850068, 189146430, 357642673, 357957890, 343045501,
+	310744887, 342419243, 47283612, 154149863, 211711297, 153521494, 102934372, 247009112, 317934031, 28513004,
+	204093236, 100281244, 342650805, 314939401, 286181633, 337663055, 353606779, 296735827, 342473621, 100274209,
+	242979616, 353356545, 341018473, 296377397, 263367955, 346119333, 182329230, 295228015, 29664764, 181010549,
+	342987813, 16267142, 172854771, 99873091, 33349150, 283346899, 28012132, 341804167, 166823265, 62688937,
+	10395767, 110134106, 296744565, 264866370, 153982228, 27375053, 132501159, 33206023, 153659445, 289244970,
+	336814041, 99387394, 297254887, 211874692, 130062011, 28402636, 342365750, 241089437, 333788236, 337518646,
+	340676695, 25164367, 248318383, 343906779, 125125435, 348584358, 342448484, 247230047, 309949766, 248436378,
+	186513515, 246716279, 240087296, 266447172, 315791428, 293160936, 42613827, 296509169, 96576038, 141412392,
+	248354364, 348508691, 342645600, 104430130, 246877420, 154360841, 340848898, 154184500, 341637063, 256650810,
+	154193027, 122471542, 54688773, 348000177, 296711373, 234579205, 357768702, 23520031, 340701004, 32042929,
+	336166500, 333775014, 240072310, 185515158, 256642304, 122988611, 184094683, 226895948, 331146938, 333797180,
+	288379436, 143015392, 344895000, 28492755, 348511526, 296643182, 28046997, 274778991, 314716701, 35351954,
+	344473749, 296719768, 240730741, 262543951, 296570128, 246273209, 249337445, 153932458, 145496568, 319615914,
+	358599496, 33331552, 357190220, 249118554, 34674463, 30948905, 123529255, 124611643, 347266679, 357423404,
+	340853903, 248996491, 46889180, 36747410, 296480840, 342672014, 276517055, 249269699, 348854919, 296739394,
+	244200070, 200712512, 224092782, 256686896, 240066908, 250310787, 346483861, 296488581, 136186884, 73456648,
+	340889998, 296483365, 262739435, 244316787, 357268251, 282402316, 252822785, 354332814, 270743489, 16090982,
+	82697224, 317604839, 292281018, 262599175, 312823040, 351510961, 327322553, 358269471, 208812962, 348917251,
+	64997871, 357278103, 342491845, 64004036, 56705558, 28354160, 357766970, 296935935, 32550849, 257905419,
+	282080288, 296462830, 81441380, 25556442, 353468341, 341004780, 248388721, 251424664, 340759879, 304128232,
+	341645824, 122812654, 253849947, 146368498, 26902813, 192922759, 292011884, 267613230, 296480602, 341669199,
+	291682787, 44883058, 248799275, 282402322, 26694289, 246440729, 353028299, 341624427, 59653078, 154672205,
+	340768561, 30076512, 346111228, 31454046, 258023316, 27878633, 341680275, 277978772, 240486311, 28706827,
+	356724421, 356847934, 301492120, 261295575, 286268818, 97146065, 277200610, 337564298, 30130584, 296744791,
+	12165533, 382994815, 291008749, 134121846, 343501510, 262283244, 18589457, 261095456, 270298568, 341017327,
+	307179500, 295691141, 296115230, 75089112, 291219451, 311806708, 279630669, 340806017, 341365610, 246273980,
+	340669472, 303844588, 307348482, 296703548, 282854777, 158586057, 303636567, 340805398, 296524785, 296823397,
+	296754312, 267474064, 265213162, 87714911, 33971627, 151482359, 208544054, 357792108, 312165155, 256089610,
+	240111920, 255137037, 358650450, 357472832, 358245283, 285686536, 28011542, 340771602, 241279713, 312771884,
+	87690219, 148613702, 17169980, 36738254, 322918119, 345165782, 357804181, 358613951, 208210165, 296496211,
+	79358707, 342647339, 21176323, 67952466, 30954363, 258923219, 320100370, 343963932, 357573745, 353362369,
+	253876134, 330785123, 300441349, 253808526, 100753664, 23483848, 122775503, 253784900, 348437900, 358282584,
+	348712890, 248990708, 339883645, 17715651, 337602414, 28774287, 297542121, 348187475, 23383987, 317917094,
+	344398265, 241587497, 245267960, 341034666, 340552265, 300801547, 341054367, 309911792, 149021952, 245872010,
+	341867223, 74239546, 318199013, 80123370, 135402988, 342324401, 296742226, 348794936, 154097549, 23675559,
+	28438803, 340679974, 296426078, 320192960, 204025540, 340821633, 336673960, 284432116, 315125999, 61271297,
+	301947838, 24597855, 296657632, 269945543, 282361216, 335647707, 210716093, 340750756, 31916416, 74152938,
+	125346674, 153830992, 357305305, 270497231, 341676849, 337511852, 248993514, 209898576, 24494525}
+
 //
 //
 //
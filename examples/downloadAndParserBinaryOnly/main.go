@@ -16,7 +16,6 @@ import (
 func main() {
 
 	var err error
-	var done = make(chan struct{})
 	var terminalInterval = 2000 * time.Millisecond
 	var fileDownloadName = "http://download.geofabrik.de/south-america/brazil/sul-latest.osm.pbf"
 	var fileSaveName = "../commonFiles/sul-latest.osm.pbf"
@@ -67,24 +66,9 @@ func main() {
 	// Português: Define a interface da busca binária para processar o arquivo do Create Street Maps
 	osmFileProcess.SetCompress(compressData)
 
-	parcialReportTicker := time.NewTicker(terminalInterval)
-	go func() {
-		for {
-			select {
-			case <-parcialReportTicker.C:
-				nodes := uint64(0)
-				ways := uint64(0)
-				nodes, ways = osmFileProcess.GetPartialNumberOfProcessedData()
-				log.Println("Partial report:")
-				log.Printf("nodes: %v\n", nodes)
-				log.Printf("ways: %v\n\n", ways)
-
-			case <-done:
-				parcialReportTicker.Stop()
-				return
-			}
-		}
-	}()
+	// English: writes the phase, the speed and the counters of the processing in the log
+	// Português: escreve a fase, a velocidade e os contadores do processamento no log
+	osmFileProcess.SetProgress(&goosm.ProgressLog{})
 
 	// English: process the file. although the unique responsibility is three functions, binary search, database for nodes
 	//   and database for ways, 7.9 trillion of points greatly increases the computational cost.
@@ -96,8 +80,6 @@ func main() {
 		panic(err)
 	}
 
-	done <- struct{}{}
-
 }
 
 // downloadGeoFabrikMap
@@ -7,6 +7,7 @@ require (
 	github.com/helmutkemper/iotmaker.docker.builder.network v0.0.0-20210517125645-e0b15cc3b594
 	github.com/qedus/osmpbf v1.2.0
 	go.mongodb.org/mongo-driver v1.12.1
+	google.golang.org/protobuf v1.27.1
 )
 
 require (
@@ -49,6 +50,5 @@ require (
 	golang.org/x/sync v0.0.0-20220722155255-886fb9371eb4 // indirect
 	golang.org/x/sys v0.0.0-20220722155257-8c9f86f7a55f // indirect
 	golang.org/x/text v0.7.0 // indirect
-	google.golang.org/protobuf v1.27.1 // indirect
 	gopkg.in/warnings.v0 v0.1.2 // indirect
 )
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 // osmNode
@@ -34,6 +35,29 @@ type osmNode struct {
 	} `xml:"node"`
 }
 
+// osmNodeList
+//
+// English:
+//
+// List of nodes returned by the URL https://www.openstreetmap.org/api/0.6/nodes?nodes=273316,273317
+//
+// Português:
+//
+// Lista de nodes devolvida pela URL https://www.openstreetmap.org/api/0.6/nodes?nodes=273316,273317
+type osmNodeList struct {
+	XMLName xml.Name `xml:"osm"`
+	Node    []struct {
+		ID      string `xml:"id,attr"`
+		Visible string `xml:"visible,attr"`
+		Lat     string `xml:"lat,attr"`
+		Lon     string `xml:"lon,attr"`
+		Tag     []struct {
+			K string `xml:"k,attr"`
+			V string `xml:"v,attr"`
+		} `xml:"tag"`
+	} `xml:"node"`
+}
+
 // osmWay
 //
 // English:
@@ -146,6 +170,87 @@ func (e DownloadApiV06) DownloadNode(id int64) (node goosm.Node, err error) {
 	return
 }
 
+// DownloadNodes
+//
+// English:
+//
+// Downloads many initialized nodes with one request, implements goosm.InterfaceDownloadOsmNodes.
+//
+// Português:
+//
+// Faz o download de vários nodes inicializados com uma requisição, implementa goosm.InterfaceDownloadOsmNodes.
+func (e DownloadApiV06) DownloadNodes(ids []int64) (nodes []goosm.Node, err error) {
+	if len(ids) == 0 {
+		return
+	}
+
+	var list = make([]string, len(ids))
+	for key, id := range ids {
+		list[key] = strconv.FormatInt(id, 10)
+	}
+
+	var resp *http.Response
+	resp, err = http.Get("https://www.openstreetmap.org/api/0.6/nodes?nodes=" + strings.Join(list, ","))
+	if err != nil {
+		err = fmt.Errorf("downloadApiV06.DownloadNodes().Get().error: %v", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("downloadApiV06.DownloadNodes().Get().error: status %v", resp.Status)
+		return
+	}
+
+	var data []byte
+	data, err = io.ReadAll(resp.Body)
+	if err != nil {
+		err = fmt.Errorf("downloadApiV06.DownloadNodes().ReadAll().error: %v", err)
+		return
+	}
+
+	var nodeListXml osmNodeList
+	err = xml.Unmarshal(data, &nodeListXml)
+	if err != nil {
+		err = fmt.Errorf("downloadApiV06.DownloadNodes().Unmarshal().error: %v", err)
+		return
+	}
+
+	nodes = make([]goosm.Node, 0, len(nodeListXml.Node))
+	for _, nodeXml := range nodeListXml.Node {
+		var id int64
+		var longitude, latitude float64
+		id, err = strconv.ParseInt(nodeXml.ID, 10, 64)
+		if err != nil {
+			err = fmt.Errorf("downloadApiV06.DownloadNodes().ParseInt().error: %v", err)
+			return
+		}
+
+		longitude, err = strconv.ParseFloat(nodeXml.Lon, 64)
+		if err != nil {
+			err = fmt.Errorf("downloadApiV06.DownloadNodes().ParseFloat(0).error: %v", err)
+			return
+		}
+
+		latitude, err = strconv.ParseFloat(nodeXml.Lat, 64)
+		if err != nil {
+			err = fmt.Errorf("downloadApiV06.DownloadNodes().ParseFloat(1).error: %v", err)
+			return
+		}
+
+		tags := make(map[string]string)
+		for _, tag := range nodeXml.Tag {
+			tags[tag.K] = tag.V
+		}
+
+		var node goosm.Node
+		node.Init(id, longitude, latitude, &tags)
+		nodes = append(nodes, node)
+	}
+
+	return
+}
+
 // DownloadWay
 //
 // English:
@@ -162,8 +267,8 @@ func (e DownloadApiV06) DownloadWay(id int64) (way goosm.Way, err error) {
 	var nodeID int64
 	var data []byte
 	data, err = e.download(id, "way")
-	err = fmt.Errorf("downloadApiV06.DownloadWay().download(%v, %v).error: %v", id, "way", err)
 	if err != nil {
+		err = fmt.Errorf("downloadApiV06.DownloadWay().download(%v, %v).error: %v", id, "way", err)
 		return
 	}
 
@@ -195,6 +300,7 @@ func (e DownloadApiV06) DownloadWay(id int64) (way goosm.Way, err error) {
 	for _, tag := range wayXml.Way.Tag {
 		tags[tag.K] = tag.V
 	}
+	way.Id = id
 	way.Tag = tags
 	err = way.Init()
 	if err != nil {
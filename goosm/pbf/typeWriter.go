@@ -0,0 +1,732 @@
+package pbf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/qedus/osmpbf/OSMPBF"
+	"google.golang.org/protobuf/proto"
+	"goosm/goosm"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"time"
+)
+
+// WriterDefaultBlockSize
+//
+// English:
+//
+// # Number of elements of each OSMData block, the value used by osmium and osmosis
+//
+// Português:
+//
+// Quantidade de elementos de cada bloco OSMData, o valor usado pelo osmium e pelo osmosis
+const WriterDefaultBlockSize = 8000
+
+// writerGranularity
+//
+// English:
+//
+// # Coordinates are written in units of 1e-7 degrees, the default granularity of the format
+//
+// Português:
+//
+// As coordenadas são escritas em unidades de 1e-7 graus, a granularidade padrão do formato
+const writerGranularity = 1e7
+
+// writerBoundingBoxGranularity
+//
+// English:
+//
+// # The bounding box of the OSMHeader block is always written in nanodegrees, it does not use the granularity
+//
+// Português:
+//
+// A caixa delimitadora do bloco OSMHeader é sempre escrita em nanograus, ela não usa a granularidade
+const writerBoundingBoxGranularity = 1e9
+
+const (
+	blockNone = iota
+	blockNodes
+	blockWays
+	blockRelations
+)
+
+// Writer
+//
+// English:
+//
+// Writes an .osm.pbf file with OSMHeader and OSMData blobs compressed by zlib, dense nodes and one string table per
+// block.
+//
+//	Note:
+//	  * The elements must be written in the order of the pbf files, all nodes, then all ways and then all relations,
+//	    PbfProcess and most readers rely on it.
+//	  * The ways carry the IDs of their nodes and also the coordinates of Loc, the optional feature LocationsOnWays.
+//	  * The metadata of Node and Way, see goosm.PbfProcess.SetMetadata(), and of Relation is written when present.
+//	  * Only current data is supported, not history files: Info.Visible is not written and the header does not declare
+//	    the feature HistoricalInformation, so every element is read back as visible.
+//
+//	Example:
+//	  writer, err := pbf.Create("city.osm.pbf")
+//	  ...
+//	  err = writer.WriteNode(&node)
+//	  err = writer.WriteWay(&way, nodeIDs)
+//	  err = writer.WriteRelation(&relation)
+//	  err = writer.Close()
+//
+// Português:
+//
+// Escreve um arquivo .osm.pbf com blobs OSMHeader e OSMData compactados pelo zlib, nodes densos e uma tabela de
+// strings por bloco.
+//
+//	Nota:
+//	  * Os elementos devem ser escritos na ordem dos arquivos pbf, todos os nodes, depois todos os ways e depois todas
+//	    as relations, PbfProcess e a maioria dos leitores dependem disso.
+//	  * Os ways levam os IDs dos seus nodes e também as coordenadas de Loc, a funcionalidade opcional LocationsOnWays.
+//	  * Os metadados de Node e Way, veja goosm.PbfProcess.SetMetadata(), e de Relation são escritos quando presentes.
+//	  * Só dados atuais são suportados, não arquivos de histórico: Info.Visible não é escrito e o cabeçalho não declara
+//	    a funcionalidade HistoricalInformation, então todo elemento é lido de volta como visível.
+//
+//	Exemplo:
+//	  writer, err := pbf.Create("city.osm.pbf")
+//	  ...
+//	  err = writer.WriteNode(&node)
+//	  err = writer.WriteWay(&way, nodeIDs)
+//	  err = writer.WriteRelation(&relation)
+//	  err = writer.Close()
+type Writer struct {
+	writer io.Writer
+	closer io.Closer
+
+	blockSize     int
+	bbox          *OSMPBF.HeaderBBox
+	headerWritten bool
+
+	// English: type of the current block and the greatest type already written
+	// Português: tipo do bloco atual e o maior tipo já escrito
+	block int
+	order int
+
+	strings   stringTable
+	dense     denseNodes
+	ways      []*OSMPBF.Way
+	relations []*OSMPBF.Relation
+}
+
+// NewWriter
+//
+// English:
+//
+// # Returns a writer over an io.Writer, Close() writes the last block and does not close the io.Writer
+//
+// Português:
+//
+// Devolve um escritor sobre um io.Writer, Close() escreve o último bloco e não fecha o io.Writer
+func NewWriter(writer io.Writer) (pbfWriter *Writer) {
+	pbfWriter = &Writer{writer: writer, blockSize: WriterDefaultBlockSize}
+	pbfWriter.strings.reset()
+	return
+}
+
+// Create
+//
+// English:
+//
+// # Creates the file and returns a writer over it, Close() also closes the file
+//
+// Português:
+//
+// Cria o arquivo e devolve um escritor sobre ele, Close() também fecha o arquivo
+func Create(path string) (pbfWriter *Writer, err error) {
+	var file *os.File
+	file, err = os.Create(path)
+	if err != nil {
+		err = fmt.Errorf("pbf.Create().Create().Error: %v", err)
+		return
+	}
+
+	pbfWriter = NewWriter(file)
+	pbfWriter.closer = file
+	return
+}
+
+// SetBlockSize
+//
+// English:
+//
+// # Defines the number of elements of each OSMData block, zero uses WriterDefaultBlockSize
+//
+// Português:
+//
+// Define a quantidade de elementos de cada bloco OSMData, zero usa WriterDefaultBlockSize
+func (e *Writer) SetBlockSize(size int) {
+	if size <= 0 {
+		size = WriterDefaultBlockSize
+	}
+	e.blockSize = size
+}
+
+// SetBoundingBox
+//
+// English:
+//
+// # Defines the bounding box of the OSMHeader block, must be called before the first element is written
+//
+// Português:
+//
+// Define a caixa delimitadora do bloco OSMHeader, deve ser chamada antes do primeiro elemento ser escrito
+func (e *Writer) SetBoundingBox(box goosm.Box) (err error) {
+	if e.headerWritten {
+		err = errors.New("pbf.Writer.SetBoundingBox().error: the header was already written")
+		return
+	}
+
+	e.bbox = &OSMPBF.HeaderBBox{
+		Left:   proto.Int64(int64(math.Round(math.Min(box.BottomLeft.Loc[goosm.Longitude], box.UpperRight.Loc[goosm.Longitude]) * writerBoundingBoxGranularity))),
+		Right:  proto.Int64(int64(math.Round(math.Max(box.BottomLeft.Loc[goosm.Longitude], box.UpperRight.Loc[goosm.Longitude]) * writerBoundingBoxGranularity))),
+		Top:    proto.Int64(int64(math.Round(math.Max(box.BottomLeft.Loc[goosm.Latitude], box.UpperRight.Loc[goosm.Latitude]) * writerBoundingBoxGranularity))),
+		Bottom: proto.Int64(int64(math.Round(math.Min(box.BottomLeft.Loc[goosm.Latitude], box.UpperRight.Loc[goosm.Latitude]) * writerBoundingBoxGranularity))),
+	}
+	return
+}
+
+// WriteNode
+//
+// English:
+//
+// # Adds a node to the current block of dense nodes
+//
+// Português:
+//
+// Adiciona um node ao bloco atual de nodes densos
+func (e *Writer) WriteNode(node *goosm.Node) (err error) {
+	err = e.next(blockNodes, "WriteNode")
+	if err != nil {
+		return
+	}
+
+	e.dense.add(&e.strings, node)
+	return e.flushFull()
+}
+
+// WriteWay
+//
+// English:
+//
+// Adds a way to the current block.
+//
+//	Input:
+//	  way: way with its tags and, optionally, the coordinates of each node in Loc;
+//	  nodeIDs: IDs of the nodes of the way, goosm.Way does not keep them.
+//
+// Português:
+//
+// Adiciona um way ao bloco atual.
+//
+//	Entrada:
+//	  way: way com suas tags e, opcionalmente, as coordenadas de cada node em Loc;
+//	  nodeIDs: IDs dos nodes do way, goosm.Way não os guarda.
+func (e *Writer) WriteWay(way *goosm.Way, nodeIDs []int64) (err error) {
+	if len(way.Loc) != 0 && len(way.Loc) != len(nodeIDs) {
+		err = fmt.Errorf("pbf.Writer.WriteWay().error: way %v has %v coordinates and %v node IDs", way.Id, len(way.Loc), len(nodeIDs))
+		return
+	}
+
+	err = e.next(blockWays, "WriteWay")
+	if err != nil {
+		return
+	}
+
+	var pbfWay = &OSMPBF.Way{Id: proto.Int64(way.Id), Refs: make([]int64, len(nodeIDs))}
+	pbfWay.Keys, pbfWay.Vals = e.strings.tags(way.Tag)
+	pbfWay.Info = e.strings.metadata(way.Metadata)
+
+	var last int64
+	for key, id := range nodeIDs {
+		pbfWay.Refs[key] = id - last
+		last = id
+	}
+
+	if len(way.Loc) != 0 {
+		pbfWay.Lat = make([]int64, len(way.Loc))
+		pbfWay.Lon = make([]int64, len(way.Loc))
+
+		var lastLon, lastLat int64
+		for key, loc := range way.Loc {
+			var lon, lat = toGranularity(loc[goosm.Longitude]), toGranularity(loc[goosm.Latitude])
+			pbfWay.Lon[key] = lon - lastLon
+			pbfWay.Lat[key] = lat - lastLat
+			lastLon, lastLat = lon, lat
+		}
+	}
+
+	e.ways = append(e.ways, pbfWay)
+	return e.flushFull()
+}
+
+// WriteRelation
+//
+// English:
+//
+// Adds a relation to the current block, with the members of Members or, when empty, the IDs of IdNode, IdWay and
+// IdRelation without role.
+//
+// Português:
+//
+// Adiciona uma relation ao bloco atual, com os membros de Members ou, quando vazio, os IDs de IdNode, IdWay e
+// IdRelation sem papel.
+func (e *Writer) WriteRelation(relation *goosm.Relation) (err error) {
+	err = e.next(blockRelations, "WriteRelation")
+	if err != nil {
+		return
+	}
+
+	var members = relation.Members
+	if len(members) == 0 {
+		for _, id := range relation.IdNode {
+			members = append(members, goosm.Members{Type: "node", Ref: id})
+		}
+		for _, id := range relation.IdWay {
+			members = append(members, goosm.Members{Type: "way", Ref: id})
+		}
+		for _, id := range relation.IdRelation {
+			members = append(members, goosm.Members{Type: "relation", Ref: id})
+		}
+	}
+
+	var pbfRelation = &OSMPBF.Relation{Id: proto.Int64(relation.Id)}
+	pbfRelation.Keys, pbfRelation.Vals = e.strings.tags(relation.Tag)
+	if relation.Version != 0 || !relation.TimeStamp.IsZero() {
+		pbfRelation.Info = e.strings.metadata(&goosm.Metadata{
+			Version:   relation.Version,
+			TimeStamp: relation.TimeStamp,
+			ChangeSet: relation.ChangeSet,
+			UId:       relation.UId,
+			User:      relation.User,
+		})
+	}
+
+	var last int64
+	for _, member := range members {
+		var memberType OSMPBF.Relation_MemberType
+		switch member.Type {
+		case "node":
+			memberType = OSMPBF.Relation_NODE
+		case "way":
+			memberType = OSMPBF.Relation_WAY
+		case "relation":
+			memberType = OSMPBF.Relation_RELATION
+		default:
+			err = fmt.Errorf("pbf.Writer.WriteRelation().error: relation %v: unexpected member type %q", relation.Id, member.Type)
+			return
+		}
+
+		pbfRelation.RolesSid = append(pbfRelation.RolesSid, int32(e.strings.id(member.Role)))
+		pbfRelation.Memids = append(pbfRelation.Memids, member.Ref-last)
+		pbfRelation.Types = append(pbfRelation.Types, memberType)
+		last = member.Ref
+	}
+
+	e.relations = append(e.relations, pbfRelation)
+	return e.flushFull()
+}
+
+// Close
+//
+// English:
+//
+// # Writes the last block, and the header of an empty file, and closes the file made by Create()
+//
+// Português:
+//
+// Escreve o último bloco, e o cabeçalho de um arquivo vazio, e fecha o arquivo feito por Create()
+func (e *Writer) Close() (err error) {
+	err = e.flush()
+	if err == nil && !e.headerWritten {
+		err = e.writeHeader()
+	}
+
+	if e.closer != nil {
+		var closeErr = e.closer.Close()
+		e.closer = nil
+		if err == nil && closeErr != nil {
+			err = fmt.Errorf("pbf.Writer.Close().Close().Error: %v", closeErr)
+		}
+	}
+	return
+}
+
+// next
+//
+// English:
+//
+// # Checks the order of the elements and writes the current block when the type changes
+//
+// Português:
+//
+// Verifica a ordem dos elementos e escreve o bloco atual quando o tipo muda
+func (e *Writer) next(block int, name string) (err error) {
+	if block < e.order {
+		err = fmt.Errorf("pbf.Writer.%v().error: the elements must be written as nodes, ways and relations", name)
+		return
+	}
+	e.order = block
+
+	if e.block != block {
+		err = e.flush()
+		e.block = block
+	}
+	return
+}
+
+// flushFull
+//
+// English:
+//
+// # Writes the current block when it has blockSize elements
+//
+// Português:
+//
+// Escreve o bloco atual quando ele tem blockSize elementos
+func (e *Writer) flushFull() (err error) {
+	if e.dense.size()+len(e.ways)+len(e.relations) >= e.blockSize {
+		err = e.flush()
+	}
+	return
+}
+
+// flush
+//
+// English:
+//
+// # Writes the current block as an OSMData blob, the header is written before the first block
+//
+// Português:
+//
+// Escreve o bloco atual como um blob OSMData, o cabeçalho é escrito antes do primeiro bloco
+func (e *Writer) flush() (err error) {
+	var group = &OSMPBF.PrimitiveGroup{}
+	switch {
+	case e.dense.size() != 0:
+		group.Dense = e.dense.message()
+	case len(e.ways) != 0:
+		group.Ways = e.ways
+	case len(e.relations) != 0:
+		group.Relations = e.relations
+	default:
+		return
+	}
+
+	if !e.headerWritten {
+		err = e.writeHeader()
+		if err != nil {
+			return
+		}
+	}
+
+	var block = &OSMPBF.PrimitiveBlock{
+		Stringtable:    &OSMPBF.StringTable{S: e.strings.list},
+		Primitivegroup: []*OSMPBF.PrimitiveGroup{group},
+	}
+
+	err = e.writeBlob("OSMData", block)
+	if err != nil {
+		return
+	}
+
+	e.strings.reset()
+	e.dense = denseNodes{}
+	e.ways = nil
+	e.relations = nil
+	return
+}
+
+// writeHeader
+//
+// English:
+//
+// # Writes the OSMHeader blob
+//
+// Português:
+//
+// Escreve o blob OSMHeader
+func (e *Writer) writeHeader() (err error) {
+	e.headerWritten = true
+
+	var header = &OSMPBF.HeaderBlock{
+		Bbox:             e.bbox,
+		RequiredFeatures: []string{"OsmSchema-V0.6", "DenseNodes"},
+		OptionalFeatures: []string{"LocationsOnWays"},
+		Writingprogram:   proto.String("goosm"),
+	}
+	return e.writeBlob("OSMHeader", header)
+}
+
+// writeBlob
+//
+// English:
+//
+// # Writes the size of the BlobHeader, the BlobHeader and the Blob with the message compressed by zlib
+//
+// Português:
+//
+// Escreve o tamanho do BlobHeader, o BlobHeader e o Blob com a mensagem compactada pelo zlib
+func (e *Writer) writeBlob(blobType string, message proto.Message) (err error) {
+	var raw []byte
+	raw, err = proto.Marshal(message)
+	if err != nil {
+		err = fmt.Errorf("pbf.Writer.writeBlob().Marshal().Error: %v", err)
+		return
+	}
+
+	var compressed bytes.Buffer
+	var zlibWriter = zlib.NewWriter(&compressed)
+	_, err = zlibWriter.Write(raw)
+	if err == nil {
+		err = zlibWriter.Close()
+	}
+	if err != nil {
+		err = fmt.Errorf("pbf.Writer.writeBlob().zlib.Error: %v", err)
+		return
+	}
+
+	var blob []byte
+	blob, err = proto.Marshal(&OSMPBF.Blob{RawSize: proto.Int32(int32(len(raw))), Data: &OSMPBF.Blob_ZlibData{ZlibData: compressed.Bytes()}})
+	if err != nil {
+		err = fmt.Errorf("pbf.Writer.writeBlob().Marshal().Error: %v", err)
+		return
+	}
+
+	var header []byte
+	header, err = proto.Marshal(&OSMPBF.BlobHeader{Type: proto.String(blobType), Datasize: proto.Int32(int32(len(blob)))})
+	if err != nil {
+		err = fmt.Errorf("pbf.Writer.writeBlob().Marshal().Error: %v", err)
+		return
+	}
+
+	var size = make([]byte, 4)
+	binary.BigEndian.PutUint32(size, uint32(len(header)))
+	for _, data := range [][]byte{size, header, blob} {
+		_, err = e.writer.Write(data)
+		if err != nil {
+			err = fmt.Errorf("pbf.Writer.writeBlob().Write().Error: %v", err)
+			return
+		}
+	}
+	return
+}
+
+// toGranularity
+//
+// English:
+//
+// # Converts degrees into the integer coordinates of the format, in units of 1e-7 degrees, see writerGranularity
+//
+// Português:
+//
+// Converte graus nas coordenadas inteiras do formato, em unidades de 1e-7 graus, veja writerGranularity
+func toGranularity(degrees float64) int64 {
+	return int64(math.Round(degrees * writerGranularity))
+}
+
+// stringTable
+//
+// English:
+//
+// # String table of one block, the index 0 is the empty string, used as separator by the dense nodes
+//
+// Português:
+//
+// Tabela de strings de um bloco, o índice 0 é a string vazia, usada como separador pelos nodes densos
+type stringTable struct {
+	index map[string]uint32
+	list  []string
+}
+
+func (e *stringTable) reset() {
+	e.index = map[string]uint32{"": 0}
+	e.list = []string{""}
+}
+
+func (e *stringTable) id(value string) uint32 {
+	if id, found := e.index[value]; found {
+		return id
+	}
+
+	var id = uint32(len(e.list))
+	e.index[value] = id
+	e.list = append(e.list, value)
+	return id
+}
+
+func (e *stringTable) tags(tags map[string]string) (keys, vals []uint32) {
+	for _, key := range sortedKeys(tags) {
+		keys = append(keys, e.id(key))
+		vals = append(vals, e.id(tags[key]))
+	}
+	return
+}
+
+// sortedKeys
+//
+// English:
+//
+// # Returns the keys of the tags in order, so the same elements always make the same file
+//
+// Português:
+//
+// Devolve as chaves das tags em ordem, para que os mesmos elementos sempre façam o mesmo arquivo
+func sortedKeys(tags map[string]string) (keys []string) {
+	keys = make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return
+}
+
+// metadata
+//
+// English:
+//
+// # Converts the metadata into the Info message, nil when there is no metadata
+//
+// Português:
+//
+// Converte os metadados na mensagem Info, nil quando não há metadados
+func (e *stringTable) metadata(metadata *goosm.Metadata) (info *OSMPBF.Info) {
+	if metadata == nil {
+		return
+	}
+
+	return &OSMPBF.Info{
+		Version:   proto.Int32(int32(metadata.Version)),
+		Timestamp: proto.Int64(timestamp(metadata.TimeStamp)),
+		Changeset: proto.Int64(metadata.ChangeSet),
+		Uid:       proto.Int32(int32(metadata.UId)),
+		UserSid:   proto.Uint32(e.id(metadata.User)),
+	}
+}
+
+// timestamp
+//
+// English:
+//
+// # Converts the time into seconds, the default date granularity of the format
+//
+// Português:
+//
+// Converte o momento em segundos, a granularidade de datas padrão do formato
+func timestamp(value time.Time) int64 {
+	if value.IsZero() {
+		return 0
+	}
+	return value.Unix()
+}
+
+// denseNodes
+//
+// English:
+//
+// Dense nodes of one block, the values are kept absolute and delta coded by message(), the metadata is only written
+// when one of the nodes has it.
+//
+// Português:
+//
+// Nodes densos de um bloco, os valores são mantidos absolutos e codificados em delta por message(), os metadados só
+// são escritos quando um dos nodes os tem.
+type denseNodes struct {
+	id       []int64
+	lat      []int64
+	lon      []int64
+	keysVals []int32
+
+	hasInfo   bool
+	version   []int32
+	timestamp []int64
+	changeset []int64
+	uid       []int32
+	userSid   []int32
+}
+
+func (e *denseNodes) size() int {
+	return len(e.id)
+}
+
+func (e *denseNodes) add(strings *stringTable, node *goosm.Node) {
+	e.id = append(e.id, node.Id)
+	e.lon = append(e.lon, toGranularity(node.Loc[goosm.Longitude]))
+	e.lat = append(e.lat, toGranularity(node.Loc[goosm.Latitude]))
+
+	for _, key := range sortedKeys(node.Tag) {
+		e.keysVals = append(e.keysVals, int32(strings.id(key)), int32(strings.id(node.Tag[key])))
+	}
+	e.keysVals = append(e.keysVals, 0)
+
+	var metadata = node.Metadata
+	if metadata == nil {
+		metadata = &goosm.Metadata{}
+	} else {
+		e.hasInfo = true
+	}
+
+	e.version = append(e.version, int32(metadata.Version))
+	e.timestamp = append(e.timestamp, timestamp(metadata.TimeStamp))
+	e.changeset = append(e.changeset, metadata.ChangeSet)
+	e.uid = append(e.uid, int32(metadata.UId))
+	e.userSid = append(e.userSid, int32(strings.id(metadata.User)))
+}
+
+func (e *denseNodes) message() (dense *OSMPBF.DenseNodes) {
+	dense = &OSMPBF.DenseNodes{
+		Id:       delta64(e.id),
+		Lat:      delta64(e.lat),
+		Lon:      delta64(e.lon),
+		KeysVals: e.keysVals,
+	}
+
+	if e.hasInfo {
+		dense.Denseinfo = &OSMPBF.DenseInfo{
+			Version:   e.version,
+			Timestamp: delta64(e.timestamp),
+			Changeset: delta64(e.changeset),
+			Uid:       delta32(e.uid),
+			UserSid:   delta32(e.userSid),
+		}
+	}
+	return
+}
+
+// delta64
+//
+// English:
+//
+// # Returns the difference of each value to the previous one
+//
+// Português:
+//
+// Devolve a diferença de cada valor para o anterior
+func delta64(values []int64) (deltas []int64) {
+	deltas = make([]int64, len(values))
+	var last int64
+	for key, value := range values {
+		deltas[key] = value - last
+		last = value
+	}
+	return
+}
+
+func delta32(values []int32) (deltas []int32) {
+	deltas = make([]int32, len(values))
+	var last int32
+	for key, value := range values {
+		deltas[key] = value - last
+		last = value
+	}
+	return
+}
@@ -0,0 +1,152 @@
+package pbf
+
+import (
+	"github.com/qedus/osmpbf"
+	"goosm/goosm"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestWriter
+//
+// English:
+//
+// Writes nodes, ways and a relation in blocks of two elements and reads the file back with the osmpbf decoder.
+//
+// Português:
+//
+// Escreve nodes, ways e uma relation em blocos de dois elementos e lê o arquivo de volta com o decoder osmpbf.
+func TestWriter(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "writer.osm.pbf")
+	writer, err := Create(path)
+	if err != nil {
+		t.Logf("Create() error: %v", err)
+		t.FailNow()
+	}
+	writer.SetBlockSize(2)
+
+	err = writer.SetBoundingBox(goosm.Box{BottomLeft: goosm.Node{Loc: [2]float64{-48.2, -27.2}}, UpperRight: goosm.Node{Loc: [2]float64{-48.0, -27.0}}})
+	if err != nil {
+		t.Logf("SetBoundingBox() error: %v", err)
+		t.FailNow()
+	}
+
+	var metadata = &goosm.Metadata{Version: 4, TimeStamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), ChangeSet: 99, UId: 7, User: "mapper"}
+	var loc = [][2]float64{{-48.0, -27.0}, {-48.0000123, -27.1}, {-48.2, -27.2}}
+	for key, point := range loc {
+		var node = goosm.Node{Id: int64(key + 1), Loc: point}
+		if key == 0 {
+			node.Tag = map[string]string{"amenity": "bench", "name": "Praça"}
+			node.Metadata = metadata
+		}
+
+		err = writer.WriteNode(&node)
+		if err != nil {
+			t.Logf("WriteNode() error: %v", err)
+			t.FailNow()
+		}
+	}
+
+	err = writer.WriteWay(&goosm.Way{Id: 10, Tag: map[string]string{"highway": "residential"}, Loc: loc, Metadata: metadata}, []int64{1, 2, 3})
+	if err == nil {
+		err = writer.WriteWay(&goosm.Way{Id: 11, Tag: map[string]string{"barrier": "fence"}}, []int64{3, 1})
+	}
+	if err != nil {
+		t.Logf("WriteWay() error: %v", err)
+		t.FailNow()
+	}
+
+	err = writer.WriteRelation(&goosm.Relation{Id: 20, Tag: map[string]string{"type": "multipolygon"}, Members: []goosm.Members{
+		{Type: "way", Ref: 10, Role: "outer"},
+		{Type: "way", Ref: 11, Role: "outer"},
+	}})
+	if err != nil {
+		t.Logf("WriteRelation() error: %v", err)
+		t.FailNow()
+	}
+
+	err = writer.WriteNode(&goosm.Node{Id: 4})
+	if err == nil {
+		t.Logf("WriteNode() must fail after the relations")
+		t.FailNow()
+	}
+
+	err = writer.Close()
+	if err != nil {
+		t.Logf("Close() error: %v", err)
+		t.FailNow()
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Logf("Open() error: %v", err)
+		t.FailNow()
+	}
+	defer file.Close()
+
+	var decoder = osmpbf.NewDecoder(file)
+	err = decoder.Start(1)
+	if err != nil {
+		t.Logf("Start() error: %v", err)
+		t.FailNow()
+	}
+
+	header, err := decoder.Header()
+	if err != nil || header.BoundingBox == nil {
+		t.Logf("Header() error: %v", err)
+		t.FailNow()
+	}
+
+	var box = header.BoundingBox
+	if math.Abs(box.Left+48.2) > 1e-9 || math.Abs(box.Right+48.0) > 1e-9 || math.Abs(box.Top+27.0) > 1e-9 || math.Abs(box.Bottom+27.2) > 1e-9 {
+		t.Logf("BoundingBox error: %+v", *box)
+		t.FailNow()
+	}
+
+	var elements []interface{}
+	for {
+		element, err := decoder.Decode()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Logf("Decode() error: %v", err)
+			t.FailNow()
+		}
+		elements = append(elements, element)
+	}
+
+	if len(elements) != 6 {
+		t.Logf("elements error: %v", len(elements))
+		t.FailNow()
+	}
+
+	var node = elements[0].(*osmpbf.Node)
+	if node.ID != 1 || node.Lon != -48.0 || node.Lat != -27.0 || node.Tags["name"] != "Praça" || node.Info.Version != 4 ||
+		node.Info.User != "mapper" || !node.Info.Timestamp.Equal(metadata.TimeStamp) || node.Info.Changeset != 99 {
+		t.Logf("node 1 error: %+v", node)
+		t.FailNow()
+	}
+
+	node = elements[1].(*osmpbf.Node)
+	if node.ID != 2 || node.Lon != -48.0000123 || node.Lat != -27.1 || len(node.Tags) != 0 || !node.Info.Visible {
+		t.Logf("node 2 error: %+v", node)
+		t.FailNow()
+	}
+
+	var way = elements[3].(*osmpbf.Way)
+	if way.ID != 10 || len(way.NodeIDs) != 3 || way.NodeIDs[2] != 3 || way.Tags["highway"] != "residential" || way.Info.Uid != 7 {
+		t.Logf("way 10 error: %+v", way)
+		t.FailNow()
+	}
+
+	var relation = elements[5].(*osmpbf.Relation)
+	if relation.ID != 20 || len(relation.Members) != 2 || relation.Members[1] != (osmpbf.Member{ID: 11, Type: osmpbf.WayType, Role: "outer"}) {
+		t.Logf("relation error: %+v", relation)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,163 @@
+package goosm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/qedus/osmpbf"
+	"github.com/qedus/osmpbf/OSMPBF"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"os"
+	"runtime"
+)
+
+// pbfSegmentBlobs
+//
+// English:
+//
+// # Number of data blobs decoded by each decoder, the resume points are the first blob of each segment
+//
+// Português:
+//
+// Quantidade de blobs de dados decodificados por cada decoder, os pontos de retomada são o primeiro blob de cada
+// segmento
+const pbfSegmentBlobs = 32
+
+// pbfBlob
+//
+// English:
+//
+// Position of a file block of the pbf file, the 4 bytes of size, the BlobHeader and the Blob.
+//
+// Português:
+//
+// Posição de um bloco do arquivo pbf, os 4 bytes de tamanho, o BlobHeader e o Blob.
+type pbfBlob struct {
+	offset int64
+	size   int64
+}
+
+// readPbfBlobs
+//
+// English:
+//
+// Reads only the BlobHeader of each file block, without decompressing the data, and returns the bytes of the OSMHeader
+// block and the position of the OSMData blocks.
+//
+// Português:
+//
+// Lê apenas o BlobHeader de cada bloco do arquivo, sem descompactar os dados, e devolve os bytes do bloco OSMHeader e
+// a posição dos blocos OSMData.
+func readPbfBlobs(file *os.File) (header []byte, blobs []pbfBlob, err error) {
+	var size = make([]byte, 4)
+	var offset int64
+	for {
+		_, err = file.ReadAt(size, offset)
+		if err == io.EOF {
+			err = nil
+			break
+		}
+		if err != nil {
+			return
+		}
+
+		var headerSize = int64(binary.BigEndian.Uint32(size))
+		if headerSize >= 64*1024 {
+			err = fmt.Errorf("blob header at %v: size >= 64Kb", offset)
+			return
+		}
+
+		var data = make([]byte, headerSize)
+		_, err = file.ReadAt(data, offset+4)
+		if err != nil {
+			err = fmt.Errorf("blob header at %v: %v", offset, err)
+			return
+		}
+
+		var blobHeader = new(OSMPBF.BlobHeader)
+		err = proto.Unmarshal(data, blobHeader)
+		if err != nil {
+			err = fmt.Errorf("blob header at %v: %v", offset, err)
+			return
+		}
+
+		var blob = pbfBlob{offset: offset, size: 4 + headerSize + int64(blobHeader.GetDatasize())}
+		switch blobHeader.GetType() {
+		case "OSMHeader":
+			header = make([]byte, blob.size)
+			_, err = file.ReadAt(header, offset)
+			if err != nil {
+				err = fmt.Errorf("blob at %v: %v", offset, err)
+				return
+			}
+		case "OSMData":
+			blobs = append(blobs, blob)
+		default:
+			err = fmt.Errorf("blob at %v: unexpected type %v", offset, blobHeader.GetType())
+			return
+		}
+
+		offset += blob.size
+	}
+
+	if header == nil {
+		err = errors.New("OSMHeader block not found")
+	}
+	return
+}
+
+// newPbfSegmentDecoder
+//
+// English:
+//
+// Starts a decoder over the OSMHeader block followed only by the blocks of the segment, so the decoding can start at
+// any block of the file, counting the bytes read in counters.
+//
+// Português:
+//
+// Inicia um decoder sobre o bloco OSMHeader seguido apenas pelos blocos do segmento, assim a decodificação pode começar
+// em qualquer bloco do arquivo, contando os bytes lidos em counters.
+func newPbfSegmentDecoder(file *os.File, header []byte, segment []pbfBlob, counters *progressCounters) (osmDecoder *osmpbf.Decoder, err error) {
+	var start = segment[0].offset
+	var end = segment[len(segment)-1].offset + segment[len(segment)-1].size
+
+	// English: the bytes of the blobs are counted as consumed when the decoder reads them
+	// Português: os bytes dos blobs são contados como consumidos quando o decoder os lê
+	counters.bytesRead.Store(start)
+	var section = &progressReader{reader: io.NewSectionReader(file, start, end-start), counters: counters}
+
+	osmDecoder = osmpbf.NewDecoder(io.MultiReader(bytes.NewReader(header), section))
+
+	// use more memory from the start, it is faster
+	osmDecoder.SetBufferSize(osmpbf.MaxBlobSize)
+
+	// start decoding with several goroutines, it is faster
+	err = osmDecoder.Start(runtime.GOMAXPROCS(-1))
+	return
+}
+
+// drainDecoder
+//
+// English:
+//
+// Reads the decoder to the end, so its goroutines are not left blocked when the reading stops early, the OSM XML
+// decoder has no goroutines and only stops reading.
+//
+// Português:
+//
+// Lê o decoder até o fim, para que suas goroutines não fiquem bloqueadas quando a leitura para antes, o decoder OSM
+// XML não tem goroutines e apenas para de ler.
+func drainDecoder(osmDecoder osmElementDecoder) {
+	if xmlDecoder, isXml := osmDecoder.(*osmXmlDecoder); isXml {
+		xmlDecoder.stop()
+		return
+	}
+
+	for {
+		if _, err := osmDecoder.Decode(); err != nil {
+			return
+		}
+	}
+}
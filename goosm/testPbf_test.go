@@ -0,0 +1,419 @@
+package goosm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/qedus/osmpbf"
+	"github.com/qedus/osmpbf/OSMPBF"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"sync"
+)
+
+// writeTestPbf
+//
+// English:
+//
+// Writes a small pbf file for the tests, each block of elements, *osmpbf.Node, *osmpbf.Way or *osmpbf.Relation,
+// becomes one uncompressed blob.
+//
+// Português:
+//
+// Escreve um arquivo pbf pequeno para os testes, cada bloco de elementos, *osmpbf.Node, *osmpbf.Way ou
+// *osmpbf.Relation, vira um blob sem compactação.
+func writeTestPbf(path string, blocks ...[]interface{}) (err error) {
+	var file *os.File
+	file, err = os.Create(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	var header = &OSMPBF.HeaderBlock{RequiredFeatures: []string{"OsmSchema-V0.6"}}
+	err = writeTestPbfBlob(file, "OSMHeader", header)
+	if err != nil {
+		return
+	}
+
+	for _, block := range blocks {
+		var strings = testPbfStrings{index: map[string]uint32{"": 0}, list: []string{""}}
+		var group = &OSMPBF.PrimitiveGroup{}
+		for _, element := range block {
+			switch converted := element.(type) {
+			case *osmpbf.Node:
+				keys, vals := strings.tags(converted.Tags)
+				group.Nodes = append(group.Nodes, &OSMPBF.Node{
+					Id:   proto.Int64(converted.ID),
+					Keys: keys,
+					Vals: vals,
+					Lat:  proto.Int64(int64(math.Round(converted.Lat * 1e7))),
+					Lon:  proto.Int64(int64(math.Round(converted.Lon * 1e7))),
+				})
+
+			case *osmpbf.Way:
+				keys, vals := strings.tags(converted.Tags)
+				var refs = make([]int64, len(converted.NodeIDs))
+				var last int64
+				for key, id := range converted.NodeIDs {
+					refs[key] = id - last
+					last = id
+				}
+				group.Ways = append(group.Ways, &OSMPBF.Way{Id: proto.Int64(converted.ID), Keys: keys, Vals: vals, Refs: refs})
+
+			case *osmpbf.Relation:
+				keys, vals := strings.tags(converted.Tags)
+				var relation = &OSMPBF.Relation{Id: proto.Int64(converted.ID), Keys: keys, Vals: vals}
+				var last int64
+				for _, member := range converted.Members {
+					relation.RolesSid = append(relation.RolesSid, int32(strings.id(member.Role)))
+					relation.Memids = append(relation.Memids, member.ID-last)
+					relation.Types = append(relation.Types, OSMPBF.Relation_MemberType(member.Type))
+					last = member.ID
+				}
+				group.Relations = append(group.Relations, relation)
+
+			default:
+				return fmt.Errorf("unexpected element %T", element)
+			}
+		}
+
+		var primitive = &OSMPBF.PrimitiveBlock{
+			Stringtable:    &OSMPBF.StringTable{S: strings.list},
+			Primitivegroup: []*OSMPBF.PrimitiveGroup{group},
+		}
+		err = writeTestPbfBlob(file, "OSMData", primitive)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// writeTestPbfBlob
+//
+// English:
+//
+// # Writes the size, the BlobHeader and the uncompressed Blob of one message
+//
+// Português:
+//
+// Escreve o tamanho, o BlobHeader e o Blob sem compactação de uma mensagem
+func writeTestPbfBlob(file io.Writer, blobType string, message proto.Message) (err error) {
+	var raw []byte
+	raw, err = proto.Marshal(message)
+	if err != nil {
+		return
+	}
+
+	var blob []byte
+	blob, err = proto.Marshal(&OSMPBF.Blob{RawSize: proto.Int32(int32(len(raw))), Data: &OSMPBF.Blob_Raw{Raw: raw}})
+	if err != nil {
+		return
+	}
+
+	var header []byte
+	header, err = proto.Marshal(&OSMPBF.BlobHeader{Type: proto.String(blobType), Datasize: proto.Int32(int32(len(blob)))})
+	if err != nil {
+		return
+	}
+
+	var size = make([]byte, 4)
+	binary.BigEndian.PutUint32(size, uint32(len(header)))
+	for _, data := range [][]byte{size, header, blob} {
+		_, err = file.Write(data)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// testPbfStrings
+//
+// English:
+//
+// # String table of one test block
+//
+// Português:
+//
+// Tabela de strings de um bloco de teste
+type testPbfStrings struct {
+	index map[string]uint32
+	list  []string
+}
+
+func (e *testPbfStrings) id(value string) uint32 {
+	if id, found := e.index[value]; found {
+		return id
+	}
+
+	e.index[value] = uint32(len(e.list))
+	e.list = append(e.list, value)
+	return e.index[value]
+}
+
+func (e *testPbfStrings) tags(tags map[string]string) (keys, vals []uint32) {
+	var sorted = make([]string, 0, len(tags))
+	for key := range tags {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		keys = append(keys, e.id(key))
+		vals = append(vals, e.id(tags[key]))
+	}
+	return
+}
+
+// testNodeFile
+//
+// English:
+//
+// # Node file kept in a map, implements CompressInterface
+//
+// Português:
+//
+// Arquivo de nodes mantido em um mapa, implementa CompressInterface
+type testNodeFile struct {
+	nodes   map[int64][2]float64
+	mounted int
+}
+
+func (e *testNodeFile) Init(_ int64)                    { e.nodes = make(map[int64][2]float64) }
+func (e *testNodeFile) Round(value, _ float64) float64  { return value }
+func (e *testNodeFile) Create(_ string) (err error)     { return }
+func (e *testNodeFile) Close()                          {}
+func (e *testNodeFile) IndexToMemory() (err error)      { return }
+func (e *testNodeFile) WriteFileHeaders() (err error)   { return }
+func (e *testNodeFile) ReadFileHeaders() (err error)    { return }
+func (e *testNodeFile) MountIndexIntoFile() (err error) { e.mounted++; return }
+func (e *testNodeFile) WriteNode(id int64, lon, lat float64) error {
+	e.nodes[id] = [2]float64{lon, lat}
+	return nil
+}
+
+func (e *testNodeFile) FindNodeByID(id int64) (longitude, latitude float64, err error) {
+	loc, found := e.nodes[id]
+	if !found {
+		err = io.EOF
+		return
+	}
+	return loc[0], loc[1], nil
+}
+
+// testDownload
+//
+// English:
+//
+// # Download api without network, every element is missing
+//
+// Português:
+//
+// Api de download sem rede, todos os elementos estão ausentes
+type testDownload struct{}
+
+func (e *testDownload) DownloadNode(id int64) (node Node, err error) {
+	return node, fmt.Errorf("node %v not found", id)
+}
+
+func (e *testDownload) DownloadWay(id int64) (way Way, err error) {
+	return way, fmt.Errorf("way %v not found", id)
+}
+
+func (e *testDownload) DownloadRelation(id int64) (relation Relation, err error) {
+	return relation, fmt.Errorf("relation %v not found", id)
+}
+
+// testDatabase
+//
+// English:
+//
+// Database kept in maps, implements InterfaceDbNode, InterfaceDbWay and InterfaceDbPolygon through its fields and
+// fails on duplicate IDs, as InsertMany() does.
+//
+// Português:
+//
+// Banco de dados mantido em mapas, implementa InterfaceDbNode, InterfaceDbWay e InterfaceDbPolygon através dos seus
+// campos e falha em IDs duplicados, como InsertMany() faz.
+type testDatabase struct {
+	sync.Mutex
+	nodes    map[int64]Node
+	ways     map[int64]Way
+	polygons map[int64]PolygonList
+
+	// English: called after each SetMany(), with the kind of the batch
+	// Português: chamada depois de cada SetMany(), com o tipo do lote
+	onSetMany func(kind string)
+}
+
+func newTestDatabase() *testDatabase {
+	return &testDatabase{nodes: make(map[int64]Node), ways: make(map[int64]Way), polygons: make(map[int64]PolygonList)}
+}
+
+func (e *testDatabase) setMany(kind string, insert func() error) (err error) {
+	e.Lock()
+	err = insert()
+	e.Unlock()
+
+	if err == nil && e.onSetMany != nil {
+		e.onSetMany(kind)
+	}
+	return
+}
+
+type testDbNode struct{ *testDatabase }
+
+func (e testDbNode) SetOne(node *Node) error { return e.SetMany(&[]Node{*node}) }
+func (e testDbNode) GetById(id int64) (node Node, err error) {
+	e.Lock()
+	defer e.Unlock()
+	node, found := e.nodes[id]
+	if !found {
+		err = errors.New("not found")
+	}
+	return
+}
+func (e testDbNode) SetMany(list *[]Node) error {
+	return e.setMany("node", func() error {
+		for _, node := range *list {
+			if _, found := e.nodes[node.Id]; found {
+				return fmt.Errorf("duplicate node %v", node.Id)
+			}
+			e.nodes[node.Id] = node
+		}
+		return nil
+	})
+}
+
+type testDbWay struct{ *testDatabase }
+
+func (e testDbWay) SetOne(way *Way) error { return e.SetMany(&[]Way{*way}) }
+func (e testDbWay) GetById(id int64) (way Way, err error) {
+	e.Lock()
+	defer e.Unlock()
+	way, found := e.ways[id]
+	if !found {
+		err = errors.New("not found")
+	}
+	return
+}
+func (e testDbWay) SetMany(list *[]Way) error {
+	return e.setMany("way", func() error {
+		for _, way := range *list {
+			if _, found := e.ways[way.Id]; found {
+				return fmt.Errorf("duplicate way %v", way.Id)
+			}
+			e.ways[way.Id] = way
+		}
+		return nil
+	})
+}
+
+type testDbPolygon struct{ *testDatabase }
+
+func (e testDbPolygon) SetOne(polygon *PolygonList) error {
+	return e.SetMany(&[]PolygonList{*polygon})
+}
+func (e testDbPolygon) GetById(id int64) (polygon PolygonList, err error) {
+	e.Lock()
+	defer e.Unlock()
+	polygon, found := e.polygons[id]
+	if !found {
+		err = errors.New("not found")
+	}
+	return
+}
+func (e testDbPolygon) SetMany(list *[]PolygonList) error {
+	return e.setMany("polygon", func() error {
+		for _, polygon := range *list {
+			if _, found := e.polygons[polygon.Id]; found {
+				return fmt.Errorf("duplicate polygon %v", polygon.Id)
+			}
+			e.polygons[polygon.Id] = polygon
+		}
+		return nil
+	})
+}
+
+// testPbfGrid
+//
+// English:
+//
+// Writes a pbf file with a grid of tagged nodes, blocks of 5 nodes, and ways between neighbour nodes, blocks of 5
+// ways, followed by one multipolygon relation over the first three ways and a closing way.
+//
+// Português:
+//
+// Escreve um arquivo pbf com uma grade de nodes com tags, blocos de 5 nodes, e ways entre nodes vizinhos, blocos de 5
+// ways, seguidos por uma relation multipolygon sobre os três primeiros ways e um way de fechamento.
+func testPbfGrid(path string, totalOfNodes int) (err error) {
+	return testPbfGridBlocks(path, totalOfNodes, 5)
+}
+
+// testPbfGridBlocks
+//
+// English:
+//
+// # Same as testPbfGrid(), with blockSize elements per block
+//
+// Português:
+//
+// Igual a testPbfGrid(), com blockSize elementos por bloco
+func testPbfGridBlocks(path string, totalOfNodes, blockSize int) (err error) {
+	var blocks [][]interface{}
+	var block []interface{}
+	var flush = func() {
+		if len(block) != 0 {
+			blocks = append(blocks, block)
+			block = nil
+		}
+	}
+
+	for id := 1; id <= totalOfNodes; id++ {
+		block = append(block, &osmpbf.Node{
+			ID:   int64(id),
+			Lon:  -48.0 + float64(id%100)*0.001,
+			Lat:  -27.0 + float64(id/100)*0.001,
+			Tags: map[string]string{"amenity": "bench"},
+		})
+		if len(block) == blockSize {
+			flush()
+		}
+	}
+	flush()
+
+	for id := 1; id < totalOfNodes; id++ {
+		block = append(block, &osmpbf.Way{
+			ID:      int64(id),
+			NodeIDs: []int64{int64(id), int64(id + 1)},
+			Tags:    map[string]string{"highway": "residential"},
+		})
+		if len(block) == blockSize {
+			flush()
+		}
+	}
+
+	// English: closes the ring of the relation, nodes 1, 2, 3, 4 and 1
+	// Português: fecha o anel da relation, nodes 1, 2, 3, 4 e 1
+	block = append(block, &osmpbf.Way{ID: int64(totalOfNodes), NodeIDs: []int64{4, 1}, Tags: map[string]string{"barrier": "fence"}})
+	flush()
+
+	block = append(block, &osmpbf.Relation{
+		ID:   1,
+		Tags: map[string]string{"type": "multipolygon", "landuse": "grass"},
+		Members: []osmpbf.Member{
+			{ID: 1, Type: osmpbf.WayType, Role: "outer"},
+			{ID: 2, Type: osmpbf.WayType, Role: "outer"},
+			{ID: 3, Type: osmpbf.WayType, Role: "outer"},
+			{ID: int64(totalOfNodes), Type: osmpbf.WayType, Role: "outer"},
+		},
+	})
+	flush()
+
+	return writeTestPbf(path, blocks...)
+}
@@ -0,0 +1,202 @@
+package goosm
+
+// AreaMode
+//
+// English:
+//
+// # How the values of a key of AreaRules make a closed way an area
+//
+// Português:
+//
+// Como os valores de uma chave de AreaRules tornam um way fechado uma área
+type AreaMode int
+
+const (
+	// English: every value, except "no", makes an area
+	// Português: todo valor, exceto "no", forma uma área
+	AreaModeAll AreaMode = iota
+
+	// English: only the values of the list make an area
+	// Português: apenas os valores da lista formam uma área
+	AreaModeWhitelist
+
+	// English: every value, except the values of the list and "no", makes an area
+	// Português: todo valor, exceto os valores da lista e "no", forma uma área
+	AreaModeBlacklist
+)
+
+// areaKeyRule
+//
+// English:
+//
+// # Rule of one key of AreaRules
+//
+// Português:
+//
+// Regra de uma chave de AreaRules
+type areaKeyRule struct {
+	mode   AreaMode
+	values map[string]bool
+}
+
+// AreaRules
+//
+// English:
+//
+// Decides when a way is an area, Way.IsPolygon, and its GeoJSON geometry is a Polygon instead of a LineString.
+//
+// The rules follow the OSM wiki "Overpass turbo/Polygon Features", the table of osmtogeojson, close to the area keys
+// of id-tagging-schema and osm2pgsql:
+//
+//   - the way must be closed, with at least four points, except with area=yes and at least three points, when the
+//     ring of the GeoJSON is closed;
+//   - area=no makes a line and area=yes makes an area;
+//   - otherwise, the way is an area when one of its tags matches the rule of its key, see NewAreaRules().
+//
+// Português:
+//
+// Decide quando um way é uma área, Way.IsPolygon, e sua geometria GeoJSON é um Polygon em vez de um LineString.
+//
+// As regras seguem a wiki do OSM "Overpass turbo/Polygon Features", a tabela do osmtogeojson, próxima das chaves de
+// área do id-tagging-schema e do osm2pgsql:
+//
+//   - o way deve ser fechado, com pelo menos quatro pontos, exceto com area=yes e pelo menos três pontos, quando o anel
+//     do GeoJSON é fechado;
+//   - area=no forma uma linha e area=yes forma uma área;
+//   - caso contrário, o way é uma área quando uma de suas tags combina com a regra de sua chave, veja NewAreaRules().
+type AreaRules struct {
+	keys map[string]areaKeyRule
+}
+
+// defaultAreaRules
+//
+// English:
+//
+// # Rules used by Way.Init() when the way has no rules of its own, see PbfProcess.SetAreaRules()
+//
+// Português:
+//
+// Regras usadas por Way.Init() quando o way não tem regras próprias, veja PbfProcess.SetAreaRules()
+var defaultAreaRules = NewAreaRules()
+
+// NewAreaRules
+//
+// English:
+//
+// Returns the default table, the keys building, landuse, amenity, leisure, shop, tourism and others are areas with any
+// value, natural, man_made and aeroway are areas except for the linear values, as natural=coastline, and highway,
+// waterway, barrier, railway and power are lines except for a few values, as highway=services or barrier=wall.
+//
+// Português:
+//
+// Devolve a tabela padrão, as chaves building, landuse, amenity, leisure, shop, tourism e outras são áreas com
+// qualquer valor, natural, man_made e aeroway são áreas exceto pelos valores lineares, como natural=coastline, e
+// highway, waterway, barrier, railway e power são linhas exceto por alguns valores, como highway=services ou
+// barrier=wall.
+func NewAreaRules() (rules *AreaRules) {
+	rules = &AreaRules{keys: make(map[string]areaKeyRule)}
+
+	for _, key := range []string{
+		"building", "building:part", "landuse", "amenity", "leisure", "area", "area:highway", "boundary", "place",
+		"shop", "office", "craft", "tourism", "historic", "public_transport", "military", "ruins", "golf", "indoor",
+	} {
+		rules.SetKey(key, AreaModeAll)
+	}
+
+	rules.SetKey("natural", AreaModeBlacklist, "coastline", "cliff", "ridge", "arete", "tree_row")
+	rules.SetKey("man_made", AreaModeBlacklist, "cutline", "embankment", "pipeline")
+	rules.SetKey("aeroway", AreaModeBlacklist, "taxiway")
+
+	rules.SetKey("highway", AreaModeWhitelist, "services", "rest_area", "escape", "elevator")
+	rules.SetKey("waterway", AreaModeWhitelist, "riverbank", "dock", "boatyard", "dam")
+	rules.SetKey("barrier", AreaModeWhitelist, "city_wall", "ditch", "hedge", "retaining_wall", "wall", "spikes")
+	rules.SetKey("railway", AreaModeWhitelist, "station", "turntable", "roundhouse", "platform")
+	rules.SetKey("power", AreaModeWhitelist, "plant", "substation", "generator", "transformer")
+	return
+}
+
+// SetKey
+//
+// English:
+//
+// Adds or replaces the rule of a key, eg. SetKey("highway", AreaModeWhitelist, "services", "platform").
+//
+// Português:
+//
+// Adiciona ou substitui a regra de uma chave, ex. SetKey("highway", AreaModeWhitelist, "services", "platform").
+func (e *AreaRules) SetKey(key string, mode AreaMode, values ...string) {
+	var rule = areaKeyRule{mode: mode, values: make(map[string]bool, len(values))}
+	for _, value := range values {
+		rule.values[value] = true
+	}
+	e.keys[key] = rule
+}
+
+// DeleteKey
+//
+// English:
+//
+// # Removes the rule of a key, its tags no longer make an area
+//
+// Português:
+//
+// Remove a regra de uma chave, suas tags deixam de formar uma área
+func (e *AreaRules) DeleteKey(key string) {
+	delete(e.keys, key)
+}
+
+// IsArea
+//
+// English:
+//
+// # Returns true when the tags make an area, without looking at the geometry
+//
+// Português:
+//
+// Devolve true quando as tags formam uma área, sem olhar para a geometria
+func (e *AreaRules) IsArea(tags map[string]string) bool {
+	switch tags["area"] {
+	case "no":
+		return false
+	case "yes":
+		return true
+	}
+
+	for key, value := range tags {
+		var rule, found = e.keys[key]
+		if !found || value == "no" {
+			continue
+		}
+
+		switch rule.mode {
+		case AreaModeAll:
+			return true
+		case AreaModeWhitelist:
+			if rule.values[value] {
+				return true
+			}
+		case AreaModeBlacklist:
+			if !rule.values[value] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isPolygon
+//
+// English:
+//
+// # Returns true when the way is an area, by its geometry and its tags
+//
+// Português:
+//
+// Devolve true quando o way é uma área, pela sua geometria e suas tags
+func (e *AreaRules) isPolygon(way *Way) bool {
+	if way.closed() {
+		return e.IsArea(way.Tag)
+	}
+
+	return len(way.Loc) >= 3 && way.Tag["area"] == "yes"
+}
@@ -0,0 +1,88 @@
+package goosm
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestWay_IsPolygon
+//
+// English:
+//
+// # Initializes closed and open ways with the default rules and with changed rules
+//
+// Português:
+//
+// Inicializa ways fechados e abertos com as regras padrão e com regras alteradas
+func TestWay_IsPolygon(t *testing.T) {
+	var closed = [][2]float64{{-48.0, -27.0}, {-48.1, -27.0}, {-48.1, -27.1}, {-48.0, -27.0}}
+	var open = [][2]float64{{-48.0, -27.0}, {-48.1, -27.0}, {-48.1, -27.1}}
+
+	var custom = NewAreaRules()
+	custom.DeleteKey("building")
+	custom.SetKey("highway", AreaModeAll)
+
+	for _, test := range []struct {
+		loc       [][2]float64
+		tag       map[string]string
+		rules     *AreaRules
+		isPolygon bool
+		points    int
+	}{
+		{loc: closed, tag: map[string]string{"highway": "residential", "junction": "roundabout"}},
+		{loc: closed, tag: map[string]string{"barrier": "fence"}},
+		{loc: closed, tag: map[string]string{"barrier": "wall"}, isPolygon: true},
+		{loc: closed, tag: map[string]string{"building": "yes"}, isPolygon: true},
+		{loc: closed, tag: map[string]string{"building": "yes", "area": "no"}},
+		{loc: closed, tag: map[string]string{"highway": "services"}, isPolygon: true},
+		{loc: closed, tag: map[string]string{"natural": "coastline"}},
+		{loc: closed, tag: map[string]string{"natural": "wood"}, isPolygon: true},
+		{loc: closed, tag: map[string]string{"amenity": "no"}},
+		{loc: closed},
+		{loc: open, tag: map[string]string{"building": "yes"}},
+		{loc: open, tag: map[string]string{"highway": "pedestrian", "area": "yes"}, isPolygon: true, points: 4},
+		{loc: open[:2], tag: map[string]string{"area": "yes"}},
+		{loc: closed, tag: map[string]string{"building": "yes"}, rules: custom},
+		{loc: closed, tag: map[string]string{"highway": "residential"}, rules: custom, isPolygon: true},
+	} {
+		var way = Way{Id: 1, Loc: test.loc, Tag: test.tag, areaRules: test.rules}
+		err := way.Init()
+		if err != nil {
+			t.Logf("%v: Init() error: %v", test.tag, err)
+			t.FailNow()
+		}
+
+		if way.IsPolygon != test.isPolygon {
+			t.Logf("%v: IsPolygon error: %v", test.tag, way.IsPolygon)
+			t.FailNow()
+		}
+
+		var feature struct {
+			Geometry struct {
+				Type        string          `json:"type"`
+				Coordinates json.RawMessage `json:"coordinates"`
+			} `json:"geometry"`
+		}
+		err = json.Unmarshal([]byte(way.MakeGeoJSonFeature()), &feature)
+		if err != nil {
+			t.Logf("%v: GeoJSON error: %v", test.tag, err)
+			t.FailNow()
+		}
+
+		if !test.isPolygon {
+			if feature.Geometry.Type != "LineString" {
+				t.Logf("%v: geometry error: %v", test.tag, feature.Geometry.Type)
+				t.FailNow()
+			}
+			continue
+		}
+
+		var rings [][][3]float64
+		err = json.Unmarshal(feature.Geometry.Coordinates, &rings)
+		if err != nil || feature.Geometry.Type != "Polygon" || len(rings) != 1 || len(rings[0]) != max(test.points, len(test.loc)) ||
+			rings[0][0] != rings[0][len(rings[0])-1] {
+			t.Logf("%v: polygon error: %v, %v", test.tag, err, way.GeoJSonFeature)
+			t.FailNow()
+		}
+	}
+}
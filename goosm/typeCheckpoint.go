@@ -0,0 +1,184 @@
+package goosm
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+
+	// PhaseNodes
+	//
+	// English:
+	//
+	// # Phase of the import reading the nodes of the pbf file
+	//
+	// Português:
+	//
+	// Fase da importação lendo os nodes do arquivo pbf
+	PhaseNodes = "nodes"
+
+	// PhaseWays
+	//
+	// English:
+	//
+	// # Phase of the import reading the ways of the pbf file
+	//
+	// Português:
+	//
+	// Fase da importação lendo os ways do arquivo pbf
+	PhaseWays = "ways"
+
+	// PhaseRelations
+	//
+	// English:
+	//
+	// # Phase of the import reading the relations of the pbf file
+	//
+	// Português:
+	//
+	// Fase da importação lendo as relations do arquivo pbf
+	PhaseRelations = "relations"
+
+	// PhaseDone
+	//
+	// English:
+	//
+	// # Import finished, resuming it does nothing
+	//
+	// Português:
+	//
+	// Importação terminada, retomá-la não faz nada
+	PhaseDone = "done"
+)
+
+// CheckpointDefaultInterval
+//
+// English:
+//
+// # Minimum time between two checkpoint writes when SetCheckpoint() received zero
+//
+// Português:
+//
+// Tempo mínimo entre duas escritas do checkpoint quando SetCheckpoint() recebeu zero
+const CheckpointDefaultInterval = time.Minute
+
+// Checkpoint
+//
+// English:
+//
+// Progress of an import, written by PbfProcess to the file defined by SetCheckpoint() and read by Resume().
+//
+// All the elements before the resume point, the first BlobElements elements decoded from the blob at BlobOffset, were
+// already flushed to the database when the checkpoint was written.
+//
+// Português:
+//
+// Progresso de uma importação, escrito por PbfProcess no arquivo definido por SetCheckpoint() e lido por Resume().
+//
+// Todos os elementos antes do ponto de retomada, os primeiros BlobElements elementos decodificados a partir do blob em
+// BlobOffset, já tinham sido enviados ao banco de dados quando o checkpoint foi escrito.
+type Checkpoint struct {
+
+	// English: path and size of the pbf file, used to refuse the resume with another file
+	// Português: caminho e tamanho do arquivo pbf, usados para recusar a retomada com outro arquivo
+	File     string `json:"file"`
+	FileSize int64  `json:"fileSize"`
+
+	// English: entry point of the import, CompleteParser or DatabaseOnly
+	// Português: ponto de entrada da importação, CompleteParser ou DatabaseOnly
+	Mode string `json:"mode"`
+
+	// English: PhaseNodes, PhaseWays, PhaseRelations or PhaseDone
+	// Português: PhaseNodes, PhaseWays, PhaseRelations ou PhaseDone
+	Phase string `json:"phase"`
+
+	// English: offset of the blob where the reading resumes and number of its elements already processed
+	// Português: deslocamento do blob onde a leitura é retomada e quantidade dos seus elementos já processados
+	BlobOffset   int64  `json:"blobOffset"`
+	BlobElements uint64 `json:"blobElements"`
+
+	// English: elements read from the pbf file
+	// Português: elementos lidos do arquivo pbf
+	Nodes     uint64 `json:"nodes"`
+	Ways      uint64 `json:"ways"`
+	Relations uint64 `json:"relations"`
+
+	// English: elements sent to the database
+	// Português: elementos enviados ao banco de dados
+	FlushedNodes    uint64 `json:"flushedNodes"`
+	FlushedWays     uint64 `json:"flushedWays"`
+	FlushedPolygons uint64 `json:"flushedPolygons"`
+
+	// English: counters of the missing nodes
+	// Português: contadores dos nodes ausentes
+	MissingNodes MissingNodeReport `json:"missingNodes"`
+
+	// English: invalid elements skipped, see SetMaxErrors()
+	// Português: elementos inválidos pulados, veja SetMaxErrors()
+	Errors uint64 `json:"errors"`
+
+	// English: time of the write
+	// Português: horário da escrita
+	Time time.Time `json:"time"`
+}
+
+// Load
+//
+// English:
+//
+// Reads the checkpoint file.
+//
+// Português:
+//
+// Lê o arquivo de checkpoint.
+func (e *Checkpoint) Load(path string) (err error) {
+	var data []byte
+	data, err = os.ReadFile(path)
+	if err != nil {
+		err = fmt.Errorf("Checkpoint.Load().ReadFile().Error: %v", err)
+		return
+	}
+
+	err = json.Unmarshal(data, e)
+	if err != nil {
+		err = fmt.Errorf("Checkpoint.Load().Unmarshal().Error: %v", err)
+	}
+	return
+}
+
+// Save
+//
+// English:
+//
+// Writes the checkpoint file, first to path + ".tmp" and then renamed, so a crash during the write does not destroy
+// the previous checkpoint.
+//
+// Português:
+//
+// Escreve o arquivo de checkpoint, primeiro em path + ".tmp" e depois renomeado, assim uma queda durante a escrita não
+// destrói o checkpoint anterior.
+func (e *Checkpoint) Save(path string) (err error) {
+	e.Time = time.Now()
+
+	var data []byte
+	data, err = json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		err = fmt.Errorf("Checkpoint.Save().Marshal().Error: %v", err)
+		return
+	}
+
+	err = os.WriteFile(path+".tmp", data, 0644)
+	if err != nil {
+		err = fmt.Errorf("Checkpoint.Save().WriteFile().Error: %v", err)
+		return
+	}
+
+	err = os.Rename(path+".tmp", path)
+	if err != nil {
+		err = fmt.Errorf("Checkpoint.Save().Rename().Error: %v", err)
+	}
+	return
+}
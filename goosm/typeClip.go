@@ -0,0 +1,552 @@
+package goosm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+)
+
+// ClipMode
+//
+// English:
+//
+// # What is done with the ways that cross the border of the clip area, defined by SetClipMode()
+//
+// Português:
+//
+// O que é feito com os ways que cruzam a borda da área de recorte, definido por SetClipMode()
+type ClipMode int
+
+const (
+
+	// ClipKeepWays
+	//
+	// English:
+	//
+	// # The ways with at least one node inside the area are kept whole, default value
+	//
+	// Português:
+	//
+	// Os ways com pelo menos um node dentro da área são mantidos inteiros, valor padrão
+	ClipKeepWays ClipMode = iota
+
+	// ClipCutWays
+	//
+	// English:
+	//
+	// The open ways are cut at the border, the nodes outside the area are removed and the crossing points are added.
+	// When the way leaves and enters the area again, every inside piece is kept in Way.Pieces, with a MultiLineString
+	// GeoJSON, and Loc is the longest piece. Closed ways are areas and are kept whole.
+	//
+	// Português:
+	//
+	// Os ways abertos são cortados na borda, os nodes fora da área são removidos e os pontos de cruzamento são
+	// adicionados. Quando o way sai e entra na área de novo, todos os pedaços de dentro são mantidos em Way.Pieces, com
+	// um GeoJSON MultiLineString, e Loc é o pedaço mais longo. Ways fechados são áreas e são mantidos inteiros.
+	ClipCutWays
+)
+
+// clipRing
+//
+// English:
+//
+// # Ring of the clip area, [longitude, latitude] in degrees, with its bounding box
+//
+// Português:
+//
+// Anel da área de recorte, [longitude, latitude] em graus, com sua caixa de perímetro
+type clipRing struct {
+	outer          bool
+	loc            [][2]float64
+	minLon, minLat float64
+	maxLon, maxLat float64
+}
+
+// contains
+//
+// English:
+//
+// # Ray casting test of the point against the ring
+//
+// Português:
+//
+// Teste de lançamento de raio do ponto contra o anel
+func (e clipRing) contains(point [2]float64) bool {
+	if point[Longitude] < e.minLon || point[Longitude] > e.maxLon || point[Latitude] < e.minLat || point[Latitude] > e.maxLat {
+		return false
+	}
+	return pointInRing(e.loc, point)
+}
+
+// pointInRing
+//
+// English:
+//
+// # Ray casting test of a point, [longitude, latitude], against a ring
+//
+// Português:
+//
+// Teste de lançamento de raio de um ponto, [longitude, latitude], contra um anel
+func pointInRing(ring [][2]float64, point [2]float64) (inside bool) {
+	var last = len(ring) - 1
+	for i := range ring {
+		var a, b = ring[i], ring[last]
+		if (a[Latitude] > point[Latitude]) != (b[Latitude] > point[Latitude]) &&
+			point[Longitude] < (b[Longitude]-a[Longitude])*(point[Latitude]-a[Latitude])/(b[Latitude]-a[Latitude])+a[Longitude] {
+			inside = !inside
+		}
+		last = i
+	}
+	return
+}
+
+// Clip
+//
+// English:
+//
+// Area used by PbfProcess to import only part of the pbf file, made from a Box, a Polygon, a PolygonList or a GeoJSON
+// file.
+//
+// A point is inside the area when it is inside one of the outer rings and outside all the inner rings.
+//
+// Português:
+//
+// Área usada por PbfProcess para importar apenas parte do arquivo pbf, feita a partir de um Box, um Polygon, um
+// PolygonList ou um arquivo GeoJSON.
+//
+// Um ponto está dentro da área quando está dentro de um dos anéis externos e fora de todos os anéis internos.
+type Clip struct {
+	rings []clipRing
+
+	minLon, minLat float64
+	maxLon, maxLat float64
+}
+
+// NewClip
+//
+// English:
+//
+// Makes the clip area from a goosm.Box, goosm.Polygon or goosm.PolygonList, or pointers to them.
+//
+//	Note:
+//	  * The polygons with role "inner" are holes of the area, all the others are outer rings.
+//
+// Português:
+//
+// Monta a área de recorte a partir de um goosm.Box, goosm.Polygon ou goosm.PolygonList, ou ponteiros para eles.
+//
+//	Nota:
+//	  * Os polígonos com papel "inner" são buracos da área, todos os outros são anéis externos.
+func NewClip(area interface{}) (clip *Clip, err error) {
+	clip = new(Clip)
+
+	switch converted := area.(type) {
+	case Box:
+		clip.addBox(converted)
+	case *Box:
+		clip.addBox(*converted)
+	case Polygon:
+		clip.addPolygon(converted)
+	case *Polygon:
+		clip.addPolygon(*converted)
+	case PolygonList:
+		for _, polygon := range converted.List {
+			clip.addPolygon(polygon)
+		}
+	case *PolygonList:
+		for _, polygon := range converted.List {
+			clip.addPolygon(polygon)
+		}
+	default:
+		err = fmt.Errorf("NewClip().error: unsupported area type %T", area)
+		clip = nil
+		return
+	}
+
+	err = clip.init()
+	if err != nil {
+		err = fmt.Errorf("NewClip().error: %v", err)
+		clip = nil
+	}
+	return
+}
+
+// NewClipGeoJSon
+//
+// English:
+//
+// Makes the clip area from the Polygon and MultiPolygon geometries of a GeoJSON document, a FeatureCollection, a
+// Feature, a GeometryCollection or a bare geometry.
+//
+// Português:
+//
+// Monta a área de recorte a partir das geometrias Polygon e MultiPolygon de um documento GeoJSON, uma
+// FeatureCollection, uma Feature, uma GeometryCollection ou uma geometria simples.
+func NewClipGeoJSon(data []byte) (clip *Clip, err error) {
+	var document clipGeoJSon
+	err = json.Unmarshal(data, &document)
+	if err != nil {
+		err = fmt.Errorf("NewClipGeoJSon().Unmarshal().Error: %v", err)
+		return
+	}
+
+	clip = new(Clip)
+	err = clip.addGeoJSon(document)
+	if err == nil {
+		err = clip.init()
+	}
+
+	if err != nil {
+		err = fmt.Errorf("NewClipGeoJSon().error: %v", err)
+		clip = nil
+	}
+	return
+}
+
+// LoadClipGeoJSon
+//
+// English:
+//
+// # Reads a GeoJSON file and makes the clip area, see NewClipGeoJSon()
+//
+// Português:
+//
+// Lê um arquivo GeoJSON e monta a área de recorte, veja NewClipGeoJSon()
+func LoadClipGeoJSon(path string) (clip *Clip, err error) {
+	var data []byte
+	data, err = os.ReadFile(path)
+	if err != nil {
+		err = fmt.Errorf("LoadClipGeoJSon().ReadFile().Error: %v", err)
+		return
+	}
+
+	return NewClipGeoJSon(data)
+}
+
+// clipGeoJSon
+//
+// English:
+//
+// # Fields of GeoJSON documents used by the clip area
+//
+// Português:
+//
+// Campos dos documentos GeoJSON usados pela área de recorte
+type clipGeoJSon struct {
+	Type        string          `json:"type"`
+	Features    []clipGeoJSon   `json:"features"`
+	Geometry    *clipGeoJSon    `json:"geometry"`
+	Geometries  []clipGeoJSon   `json:"geometries"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// addGeoJSon
+//
+// English:
+//
+// # Adds the rings of a GeoJSON object, walking collections and features
+//
+// Português:
+//
+// Adiciona os anéis de um objeto GeoJSON, percorrendo coleções e features
+func (e *Clip) addGeoJSon(document clipGeoJSon) (err error) {
+	switch document.Type {
+	case "FeatureCollection":
+		for _, feature := range document.Features {
+			err = e.addGeoJSon(feature)
+			if err != nil {
+				return
+			}
+		}
+
+	case "Feature":
+		if document.Geometry != nil {
+			err = e.addGeoJSon(*document.Geometry)
+		}
+
+	case "GeometryCollection":
+		for _, geometry := range document.Geometries {
+			err = e.addGeoJSon(geometry)
+			if err != nil {
+				return
+			}
+		}
+
+	case "Polygon":
+		var polygon [][][]float64
+		err = json.Unmarshal(document.Coordinates, &polygon)
+		if err != nil {
+			return
+		}
+		err = e.addGeoJSonPolygon(polygon)
+
+	case "MultiPolygon":
+		var multiPolygon [][][][]float64
+		err = json.Unmarshal(document.Coordinates, &multiPolygon)
+		if err != nil {
+			return
+		}
+
+		for _, polygon := range multiPolygon {
+			err = e.addGeoJSonPolygon(polygon)
+			if err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// addGeoJSonPolygon
+//
+// English:
+//
+// # Adds a GeoJSON polygon, the first ring is the outer ring and the others are holes
+//
+// Português:
+//
+// Adiciona um polígono GeoJSON, o primeiro anel é o anel externo e os outros são buracos
+func (e *Clip) addGeoJSonPolygon(polygon [][][]float64) (err error) {
+	for key, ring := range polygon {
+		var loc = make([][2]float64, len(ring))
+		for pointKey, point := range ring {
+			if len(point) < 2 {
+				err = errors.New("position with less than two coordinates")
+				return
+			}
+			loc[pointKey] = [2]float64{point[Longitude], point[Latitude]}
+		}
+		e.rings = append(e.rings, clipRing{outer: key == 0, loc: loc})
+	}
+	return
+}
+
+// addBox
+//
+// English:
+//
+// # Adds the rectangle of the box as an outer ring
+//
+// Português:
+//
+// Adiciona o retângulo da caixa como um anel externo
+func (e *Clip) addBox(box Box) {
+	var minLon = math.Min(box.BottomLeft.Loc[Longitude], box.UpperRight.Loc[Longitude])
+	var maxLon = math.Max(box.BottomLeft.Loc[Longitude], box.UpperRight.Loc[Longitude])
+	var minLat = math.Min(box.BottomLeft.Loc[Latitude], box.UpperRight.Loc[Latitude])
+	var maxLat = math.Max(box.BottomLeft.Loc[Latitude], box.UpperRight.Loc[Latitude])
+
+	e.rings = append(e.rings, clipRing{
+		outer: true,
+		loc:   [][2]float64{{minLon, minLat}, {maxLon, minLat}, {maxLon, maxLat}, {minLon, maxLat}},
+	})
+}
+
+// addPolygon
+//
+// English:
+//
+// # Adds the points of the polygon as a ring
+//
+// Português:
+//
+// Adiciona os pontos do polígono como um anel
+func (e *Clip) addPolygon(polygon Polygon) {
+	var loc = make([][2]float64, len(polygon.PointsList))
+	for key, point := range polygon.PointsList {
+		loc[key] = point.Loc
+	}
+	e.rings = append(e.rings, clipRing{outer: polygon.Role != "inner", loc: loc})
+}
+
+// init
+//
+// English:
+//
+// # Calculates the bounding boxes of the rings and of the area
+//
+// Português:
+//
+// Calcula as caixas de perímetro dos anéis e da área
+func (e *Clip) init() (err error) {
+	e.minLon, e.minLat = math.Inf(1), math.Inf(1)
+	e.maxLon, e.maxLat = math.Inf(-1), math.Inf(-1)
+
+	var outer = false
+	for key := range e.rings {
+		var ring = &e.rings[key]
+		if len(ring.loc) < 3 {
+			return fmt.Errorf("ring %v has less than three points", key)
+		}
+
+		ring.minLon, ring.minLat = math.Inf(1), math.Inf(1)
+		ring.maxLon, ring.maxLat = math.Inf(-1), math.Inf(-1)
+		for _, point := range ring.loc {
+			ring.minLon = math.Min(ring.minLon, point[Longitude])
+			ring.maxLon = math.Max(ring.maxLon, point[Longitude])
+			ring.minLat = math.Min(ring.minLat, point[Latitude])
+			ring.maxLat = math.Max(ring.maxLat, point[Latitude])
+		}
+
+		if ring.outer {
+			outer = true
+			e.minLon = math.Min(e.minLon, ring.minLon)
+			e.maxLon = math.Max(e.maxLon, ring.maxLon)
+			e.minLat = math.Min(e.minLat, ring.minLat)
+			e.maxLat = math.Max(e.maxLat, ring.maxLat)
+		}
+	}
+
+	if !outer {
+		return errors.New("the area has no outer ring")
+	}
+	return
+}
+
+// Contains
+//
+// English:
+//
+// # Returns true when the point, [longitude, latitude] in degrees, is inside the area
+//
+// Português:
+//
+// Devolve true quando o ponto, [longitude, latitude] em graus, está dentro da área
+func (e *Clip) Contains(point [2]float64) bool {
+	if point[Longitude] < e.minLon || point[Longitude] > e.maxLon || point[Latitude] < e.minLat || point[Latitude] > e.maxLat {
+		return false
+	}
+
+	var inside = false
+	for _, ring := range e.rings {
+		if !ring.contains(point) {
+			continue
+		}
+
+		if !ring.outer {
+			return false
+		}
+		inside = true
+	}
+	return inside
+}
+
+// clipWay
+//
+// English:
+//
+// Returns the coordinates of the way to be imported, keep false when the way is out of the area and cut true when the
+// coordinates were cut at the border, see ClipMode. When cut, clipped is the longest piece inside the area and pieces
+// has all of them, in the order of the way.
+//
+// Português:
+//
+// Devolve as coordenadas do way a serem importadas, keep false quando o way está fora da área e cut true quando as
+// coordenadas foram cortadas na borda, veja ClipMode. Quando cortado, clipped é o pedaço mais longo dentro da área e
+// pieces tem todos eles, na ordem do way.
+func (e *Clip) clipWay(loc [][2]float64, mode ClipMode) (clipped [][2]float64, pieces [][][2]float64, keep, cut bool) {
+	var inside = make([]bool, len(loc))
+	var all = true
+	for key, point := range loc {
+		inside[key] = e.Contains(point)
+		keep = keep || inside[key]
+		all = all && inside[key]
+	}
+
+	var closed = len(loc) > 2 && loc[0] == loc[len(loc)-1]
+	if !keep || all || mode != ClipCutWays || closed {
+		return loc, nil, keep, false
+	}
+
+	var piece [][2]float64
+	var longestLength, pieceLength float64
+	var closePiece = func() {
+		if pieceLength > longestLength || clipped == nil {
+			clipped, longestLength = piece, pieceLength
+		}
+		pieces = append(pieces, piece)
+		piece, pieceLength = nil, 0
+	}
+
+	for key, point := range loc {
+		if inside[key] {
+			if len(piece) == 0 && key != 0 {
+				piece = append(piece, e.border(point, loc[key-1]))
+			}
+			if len(piece) != 0 {
+				pieceLength += math.Hypot(point[Longitude]-piece[len(piece)-1][Longitude], point[Latitude]-piece[len(piece)-1][Latitude])
+			}
+			piece = append(piece, point)
+			continue
+		}
+
+		if len(piece) == 0 {
+			continue
+		}
+
+		var crossing = e.border(loc[key-1], point)
+		pieceLength += math.Hypot(crossing[Longitude]-piece[len(piece)-1][Longitude], crossing[Latitude]-piece[len(piece)-1][Latitude])
+		piece = append(piece, crossing)
+		closePiece()
+	}
+
+	if len(piece) != 0 {
+		closePiece()
+	}
+	return clipped, pieces, true, true
+}
+
+// border
+//
+// English:
+//
+// Returns the point where the segment from inside to outside crosses the border of the area, by bisection, with a
+// precision better than the 7 decimal places of the coordinates.
+//
+// Português:
+//
+// Devolve o ponto onde o segmento de inside para outside cruza a borda da área, por bisseção, com uma precisão melhor
+// do que as 7 casas decimais das coordenadas.
+func (e *Clip) border(inside, outside [2]float64) [2]float64 {
+	for i := 0; i < 40; i++ {
+		var middle = [2]float64{(inside[Longitude] + outside[Longitude]) / 2, (inside[Latitude] + outside[Latitude]) / 2}
+		if e.Contains(middle) {
+			inside = middle
+		} else {
+			outside = middle
+		}
+	}
+	return inside
+}
+
+// intersectsPolygonList
+//
+// English:
+//
+// Returns true when one point of the rings of the polygon is inside the area or the area is inside one of the rings,
+// as a state boundary around the area.
+//
+// Português:
+//
+// Devolve true quando um ponto dos anéis do polígono está dentro da área ou a área está dentro de um dos anéis, como
+// o limite de um estado em volta da área.
+func (e *Clip) intersectsPolygonList(polygonList *PolygonList) bool {
+	for _, polygon := range polygonList.List {
+		var ring = make([][2]float64, len(polygon.PointsList))
+		for key, point := range polygon.PointsList {
+			if e.Contains(point.Loc) {
+				return true
+			}
+			ring[key] = point.Loc
+		}
+
+		for _, clipRing := range e.rings {
+			if clipRing.outer && pointInRing(ring, clipRing.loc[0]) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,178 @@
+package goosm
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestClip_Contains
+//
+// English:
+//
+// # Tests a box, a GeoJSON polygon with a hole and the cut of a way at the border
+//
+// Português:
+//
+// Testa uma caixa, um polígono GeoJSON com um buraco e o corte de um way na borda
+func TestClip_Contains(t *testing.T) {
+	var box = Box{}
+	box.BottomLeft.Loc = [2]float64{-49.0, -28.0}
+	box.UpperRight.Loc = [2]float64{-48.0, -27.0}
+
+	clip, err := NewClip(box)
+	if err != nil {
+		t.Logf("NewClip() error: %v", err)
+		t.FailNow()
+	}
+
+	if !clip.Contains([2]float64{-48.5, -27.5}) || clip.Contains([2]float64{-47.5, -27.5}) {
+		t.Logf("box Contains() error")
+		t.FailNow()
+	}
+
+	var path = filepath.Join(t.TempDir(), "clip.geojson")
+	err = os.WriteFile(path, []byte(`{"type": "FeatureCollection", "features": [{"type": "Feature", "properties": {}, "geometry": {
+		"type": "Polygon", "coordinates": [
+			[[-49.0, -28.0], [-48.0, -28.0], [-48.0, -27.0], [-49.0, -27.0], [-49.0, -28.0]],
+			[[-48.6, -27.6], [-48.4, -27.6], [-48.4, -27.4], [-48.6, -27.4], [-48.6, -27.6]]
+		]}}]}`), 0644)
+	if err != nil {
+		t.Logf("WriteFile() error: %v", err)
+		t.FailNow()
+	}
+
+	clip, err = LoadClipGeoJSon(path)
+	if err != nil {
+		t.Logf("LoadClipGeoJSon() error: %v", err)
+		t.FailNow()
+	}
+
+	if !clip.Contains([2]float64{-48.8, -27.5}) || clip.Contains([2]float64{-48.5, -27.5}) {
+		t.Logf("GeoJSON Contains() error, the hole must be out of the area")
+		t.FailNow()
+	}
+
+	// English: the way leaves the area at -48.0 and enters again, the longest piece is the first one
+	// Português: o way sai da área em -48.0 e entra de novo, o pedaço mais longo é o primeiro
+	var loc = [][2]float64{{-48.9, -27.9}, {-48.1, -27.9}, {-47.9, -27.9}, {-48.05, -27.8}}
+	clipped, pieces, keep, cut := clip.clipWay(loc, ClipCutWays)
+	if !keep || !cut || len(clipped) != 3 || clipped[0] != loc[0] || clipped[2][Longitude] > -48.0 || clipped[2][Longitude] < -48.0000001 ||
+		len(pieces) != 2 || len(pieces[1]) != 2 || pieces[1][1] != loc[3] {
+		t.Logf("clipWay() error: %v, %v, %v", keep, clipped, pieces)
+		t.FailNow()
+	}
+
+	clipped, pieces, keep, cut = clip.clipWay(loc, ClipKeepWays)
+	if !keep || cut || len(clipped) != len(loc) || pieces != nil {
+		t.Logf("clipWay() keep error: %v, %v", keep, clipped)
+		t.FailNow()
+	}
+
+	// English: the way crosses the area twice, the three pieces inside are kept and make a MultiLineString
+	// Português: o way cruza a área duas vezes, os três pedaços de dentro são mantidos e formam um MultiLineString
+	loc = [][2]float64{{-48.3, -27.9}, {-47.9, -27.9}, {-48.3, -27.8}, {-47.9, -27.8}, {-48.3, -27.7}}
+	clipped, pieces, keep, cut = clip.clipWay(loc, ClipCutWays)
+	if !keep || !cut || len(pieces) != 3 || len(pieces[0]) != 2 || len(pieces[1]) != 3 || len(pieces[2]) != 2 || len(clipped) != 3 {
+		t.Logf("clipWay() twice error: %v, %v", clipped, pieces)
+		t.FailNow()
+	}
+
+	for key, piece := range pieces {
+		for _, point := range piece {
+			if point[Longitude] > -48.0 || !clip.Contains(point) {
+				t.Logf("piece %v error: %v", key, piece)
+				t.FailNow()
+			}
+		}
+	}
+
+	var way = Way{Id: 1, Loc: clipped, Pieces: pieces, Tag: map[string]string{"highway": "residential"}}
+	err = way.Init()
+	if err != nil {
+		t.Logf("Init() error: %v", err)
+		t.FailNow()
+	}
+
+	var feature struct {
+		Geometry struct {
+			Type        string         `json:"type"`
+			Coordinates [][][3]float64 `json:"coordinates"`
+		} `json:"geometry"`
+	}
+	err = json.Unmarshal([]byte(way.MakeGeoJSonFeature()), &feature)
+	if err != nil || feature.Geometry.Type != "MultiLineString" || len(feature.Geometry.Coordinates) != 3 || len(feature.Geometry.Coordinates[1]) != 3 {
+		t.Logf("GeoJSON error: %v, %v", err, way.GeoJSonFeature)
+		t.FailNow()
+	}
+
+	_, err = NewClipGeoJSon([]byte(`{"type": "Point", "coordinates": [-48.0, -27.0]}`))
+	if err == nil {
+		t.Logf("NewClipGeoJSon() must fail without polygons")
+		t.FailNow()
+	}
+}
+
+// TestPbfProcess_SetClip
+//
+// English:
+//
+// # Imports the grid clipped to the half west of the grid
+//
+// Português:
+//
+// Importa a grade recortada na metade oeste da grade
+func TestPbfProcess_SetClip(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "grid.pbf")
+	err := testPbfGrid(path, 200)
+	if err != nil {
+		t.Logf("testPbfGrid() error: %v", err)
+		t.FailNow()
+	}
+
+	// English: the nodes with id%100 between 0 and 49, 1 to 49, 100 to 149 and 200
+	// Português: os nodes com id%100 entre 0 e 49, 1 a 49, 100 a 149 e 200
+	var box = Box{}
+	box.BottomLeft.Loc = [2]float64{-48.0005, -27.0005}
+	box.UpperRight.Loc = [2]float64{-47.9505, -26.9975}
+
+	for _, mode := range []ClipMode{ClipKeepWays, ClipCutWays} {
+		var database = newTestDatabase()
+		var nodeFile = &testNodeFile{}
+		nodeFile.Init(0)
+
+		var process = newTestPbfProcess(database, nodeFile)
+		process.SetWayStore(&testWayStore{ways: make(map[int64][][2]float64)})
+		process.SetClipMode(mode)
+		err = process.SetClip(box)
+		if err != nil {
+			t.Logf("SetClip() error: %v", err)
+			t.FailNow()
+		}
+
+		_, _, err = process.CompleteParser(path)
+		if err != nil {
+			t.Logf("%v: CompleteParser() error: %v", mode, err)
+			t.FailNow()
+		}
+
+		// English: ways 1 to 49, 99 to 149, 199 and the closing way 200
+		// Português: ways 1 a 49, 99 a 149, 199 e o way de fechamento 200
+		if len(database.nodes) != 100 || len(database.ways) != 102 || len(database.polygons) != 1 {
+			t.Logf("%v: database error: %v, %v, %v", mode, len(database.nodes), len(database.ways), len(database.polygons))
+			t.FailNow()
+		}
+
+		var way49 = database.ways[49].Loc
+		if mode == ClipCutWays && (len(way49) != 2 || way49[1][Longitude] > -47.9505 || way49[1][Longitude] < -47.9506) {
+			t.Logf("way 49 cut error: %v", way49)
+			t.FailNow()
+		}
+
+		if mode == ClipKeepWays && way49[1] != [2]float64{-47.95, -27.0} {
+			t.Logf("way 49 keep error: %v", way49)
+			t.FailNow()
+		}
+	}
+}
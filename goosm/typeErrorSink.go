@@ -0,0 +1,262 @@
+package goosm
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrorReason
+//
+// English:
+//
+// # Reason code of an element reported to the ErrorSink
+//
+// Português:
+//
+// Código do motivo de um elemento informado ao ErrorSink
+type ErrorReason string
+
+const (
+
+	// ErrorReasonInvalidWay
+	//
+	// English:
+	//
+	// The way failed Way.Init(), usually a coordinate rejected by SetLngLatDegrees(). Counted by SetMaxErrors().
+	//
+	// Português:
+	//
+	// O way falhou em Way.Init(), normalmente uma coordenada rejeitada por SetLngLatDegrees(). Contado por
+	// SetMaxErrors().
+	ErrorReasonInvalidWay ErrorReason = "invalidWay"
+
+	// ErrorReasonMissingNodes
+	//
+	// English:
+	//
+	// # The way was dropped by the missing node policy, see SetMissingNodePolicy()
+	//
+	// Português:
+	//
+	// O way foi descartado pela política de nodes ausentes, veja SetMissingNodePolicy()
+	ErrorReasonMissingNodes ErrorReason = "missingNodes"
+
+	// ErrorReasonRelationWayNotFound
+	//
+	// English:
+	//
+	// # The relation was dropped, a member way was not found in the database nor in the download api
+	//
+	// Português:
+	//
+	// A relation foi descartada, um way membro não foi encontrado no banco de dados nem na api de download
+	ErrorReasonRelationWayNotFound ErrorReason = "relationWayNotFound"
+
+	// ErrorReasonInvalidRelation
+	//
+	// English:
+	//
+	// # The relation was dropped, its rings could not be assembled
+	//
+	// Português:
+	//
+	// A relation foi descartada, seus anéis não puderam ser montados
+	ErrorReasonInvalidRelation ErrorReason = "invalidRelation"
+)
+
+// ElementError
+//
+// English:
+//
+// # Element of the OSM file not imported, sent to the ErrorSink
+//
+// Português:
+//
+// Elemento do arquivo OSM não importado, enviado ao ErrorSink
+type ElementError struct {
+
+	// English: "node", "way" or "relation"
+	// Português: "node", "way" ou "relation"
+	Element string `bson:"element" json:"element"`
+	Id      int64  `bson:"id" json:"id"`
+
+	Reason  ErrorReason `bson:"reason" json:"reason"`
+	Message string      `bson:"message" json:"message"`
+
+	// English: the element as read from the file, or the way with its coordinates for ErrorReasonInvalidWay
+	// Português: o elemento como lido do arquivo, ou o way com suas coordenadas para ErrorReasonInvalidWay
+	Raw interface{} `bson:"raw,omitempty" json:"raw,omitempty"`
+
+	// English: OSM file and entry point of the run
+	// Português: arquivo OSM e ponto de entrada da execução
+	File string `bson:"file" json:"file"`
+	Mode string `bson:"mode" json:"mode"`
+
+	Time time.Time `bson:"time" json:"time"`
+}
+
+// ErrorSink
+//
+// English:
+//
+// Receives the elements not imported, called by the resolvers of the pipeline at the same time. An error returned
+// stops the import.
+//
+// Português:
+//
+// Recebe os elementos não importados, chamado pelos resolvedores do pipeline ao mesmo tempo. Um erro devolvido para a
+// importação.
+type ErrorSink interface {
+	ElementError(report ElementError) (err error)
+}
+
+// ErrorSinkNDJSON
+//
+// English:
+//
+// # ErrorSink that appends one JSON object per line to a file
+//
+// Português:
+//
+// ErrorSink que acrescenta um objeto JSON por linha a um arquivo
+type ErrorSinkNDJSON struct {
+	mutex   sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// NewErrorSinkNDJSON
+//
+// English:
+//
+// Opens the file in append mode, so the lines of an interrupted run are kept when it is resumed.
+//
+// Português:
+//
+// Abre o arquivo no modo de acréscimo, assim as linhas de uma execução interrompida são mantidas quando ela é
+// retomada.
+func NewErrorSinkNDJSON(path string) (sink *ErrorSinkNDJSON, err error) {
+	sink = new(ErrorSinkNDJSON)
+	sink.file, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		err = fmt.Errorf("NewErrorSinkNDJSON().OpenFile().Error: %v", err)
+		return
+	}
+
+	sink.encoder = json.NewEncoder(sink.file)
+	sink.encoder.SetEscapeHTML(false)
+	return
+}
+
+// ElementError
+//
+// English:
+//
+// # Writes the report as one line
+//
+// Português:
+//
+// Escreve o relatório como uma linha
+func (e *ErrorSinkNDJSON) ElementError(report ElementError) (err error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	err = e.encoder.Encode(report)
+	if err != nil {
+		err = fmt.Errorf("ErrorSinkNDJSON.ElementError().Encode().Error: %v", err)
+	}
+	return
+}
+
+// Close
+//
+// English:
+//
+// # Closes the file
+//
+// Português:
+//
+// Fecha o arquivo
+func (e *ErrorSinkNDJSON) Close() (err error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	err = e.file.Close()
+	if err != nil {
+		err = fmt.Errorf("ErrorSinkNDJSON.Close().Error: %v", err)
+	}
+	return
+}
+
+// reportElement
+//
+// English:
+//
+// # Sends an element not imported to the sink of SetErrorSink(), when defined
+//
+// Português:
+//
+// Envia um elemento não importado ao receptor de SetErrorSink(), quando definido
+func (e *PbfProcess) reportElement(run *pbfRun, element string, id int64, reason ErrorReason, cause error, raw interface{}) (err error) {
+	if e.errorSink == nil {
+		return
+	}
+
+	err = e.errorSink.ElementError(ElementError{
+		Element: element,
+		Id:      id,
+		Reason:  reason,
+		Message: cause.Error(),
+		Raw:     raw,
+		File:    run.checkpoint.File,
+		Mode:    run.name,
+		Time:    time.Now().UTC(),
+	})
+	if err != nil {
+		err = fmt.Errorf("PbfProcess.%v().ElementError().Error: %v", run.name, err)
+	}
+	return
+}
+
+// elementError
+//
+// English:
+//
+// Reports an invalid element and counts it, the error is returned when the limit of SetMaxErrors() is passed.
+//
+// Português:
+//
+// Informa um elemento inválido e o conta, o erro é devolvido quando o limite de SetMaxErrors() é ultrapassado.
+func (e *PbfProcess) elementError(run *pbfRun, element string, id int64, reason ErrorReason, cause error, raw interface{}) (err error) {
+	var count = run.errors.Add(1)
+
+	err = e.reportElement(run, element, id, reason, cause, raw)
+	if err != nil {
+		return
+	}
+
+	if e.maxErrors >= 0 && count > uint64(e.maxErrors) {
+		err = fmt.Errorf("%v %v: %v", element, id, cause)
+		return
+	}
+
+	log.Printf("PbfProcess.%v().event: %v %v ignored: %v", run.name, element, id, cause)
+	return
+}
+
+// GetErrorCount
+//
+// English:
+//
+// # Returns the number of invalid elements of the last run, counted by SetMaxErrors()
+//
+// Português:
+//
+// Devolve a quantidade de elementos inválidos da última execução, contados por SetMaxErrors()
+func (e *PbfProcess) GetErrorCount() (errors uint64) {
+	return e.errorCount
+}
@@ -0,0 +1,112 @@
+package goosm
+
+import (
+	"bufio"
+	"encoding/json"
+	"github.com/qedus/osmpbf"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestPbfProcess_SetErrorSink
+//
+// English:
+//
+// Imports two ways with a node out of the world, a way with a missing node and a relation with a missing way, with
+// the limits 0, 1 and 2 of SetMaxErrors(), the reports are written into a NDJSON file.
+//
+// Português:
+//
+// Importa dois ways com um node fora do mundo, um way com um node ausente e uma relation com um way ausente, com os
+// limites 0, 1 e 2 de SetMaxErrors(), os relatórios são escritos em um arquivo NDJSON.
+func TestPbfProcess_SetErrorSink(t *testing.T) {
+	var dir = t.TempDir()
+	var path = filepath.Join(dir, "errors.pbf")
+	var tags = map[string]string{"highway": "residential"}
+	err := writeTestPbf(path,
+		[]interface{}{
+			&osmpbf.Node{ID: 1, Lon: -48.0, Lat: -27.0},
+			&osmpbf.Node{ID: 2, Lon: -48.1, Lat: -27.0},
+			&osmpbf.Node{ID: 3, Lon: -48.2, Lat: 95.0},
+		},
+		[]interface{}{
+			&osmpbf.Way{ID: 1, NodeIDs: []int64{1, 2}, Tags: tags},
+			&osmpbf.Way{ID: 2, NodeIDs: []int64{1, 3}, Tags: tags},
+			&osmpbf.Way{ID: 3, NodeIDs: []int64{3, 2}, Tags: tags},
+			&osmpbf.Way{ID: 4, NodeIDs: []int64{1, 99}, Tags: tags},
+		},
+		[]interface{}{
+			&osmpbf.Relation{ID: 1, Tags: map[string]string{"type": "multipolygon"}, Members: []osmpbf.Member{
+				{ID: 50, Type: osmpbf.WayType, Role: "outer"},
+			}},
+		},
+	)
+	if err != nil {
+		t.Logf("writeTestPbf() error: %v", err)
+		t.FailNow()
+	}
+
+	for _, max := range []int{0, 1, 2} {
+		var database = newTestDatabase()
+		var nodeFile = &testNodeFile{}
+		nodeFile.Init(0)
+
+		var logPath = filepath.Join(dir, "errors.ndjson")
+		_ = os.Remove(logPath)
+		sink, err := NewErrorSinkNDJSON(logPath)
+		if err != nil {
+			t.Logf("NewErrorSinkNDJSON() error: %v", err)
+			t.FailNow()
+		}
+
+		var process = newTestPbfProcess(database, nodeFile)
+		process.SetMissingNodePolicy(MissingNodeDropWay)
+		process.SetErrorSink(sink)
+		process.SetMaxErrors(max)
+		_, _, err = process.CompleteParser(path)
+		_ = sink.Close()
+
+		if max < 2 {
+			if err == nil {
+				t.Logf("%v: CompleteParser() must fail", max)
+				t.FailNow()
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Logf("%v: CompleteParser() error: %v", max, err)
+			t.FailNow()
+		}
+
+		if len(database.ways) != 1 || database.ways[1].Id != 1 || process.GetErrorCount() != 2 {
+			t.Logf("%v: error: %v ways, %v errors", max, len(database.ways), process.GetErrorCount())
+			t.FailNow()
+		}
+
+		file, err := os.Open(logPath)
+		if err != nil {
+			t.Logf("Open() error: %v", err)
+			t.FailNow()
+		}
+
+		var reasons = make(map[ErrorReason]int)
+		var scanner = bufio.NewScanner(file)
+		for scanner.Scan() {
+			var report ElementError
+			err = json.Unmarshal(scanner.Bytes(), &report)
+			if err != nil || report.File != path || report.Raw == nil || report.Message == "" {
+				t.Logf("%v: line error: %v, %s", max, err, scanner.Bytes())
+				t.FailNow()
+			}
+			reasons[report.Reason]++
+		}
+		_ = file.Close()
+
+		if len(reasons) != 3 || reasons[ErrorReasonInvalidWay] != 2 || reasons[ErrorReasonMissingNodes] != 1 || reasons[ErrorReasonRelationWayNotFound] != 1 {
+			t.Logf("%v: reasons error: %v", max, reasons)
+			t.FailNow()
+		}
+	}
+}
@@ -71,14 +71,34 @@ func (e *GeoJSon) AddTag(key, value string) {
 }
 
 func (e *GeoJSon) AddGeoMathWay(id string, way *Way) {
-	e.NewFeature(id, GeojsonLineString)
-	for _, coordinates := range way.Loc {
-		e.AddLngLat(coordinates[0], coordinates[1])
+	if len(way.Pieces) > 1 {
+		e.NewFeature(id, GeojsonMultiLineString)
+		for k, piece := range way.Pieces {
+			if k != 0 {
+				e.NewSetOfCoordinates()
+			}
+			for _, coordinates := range piece {
+				e.AddLngLat(coordinates[0], coordinates[1])
+			}
+		}
+	} else {
+		if !way.IsPolygon {
+			e.NewFeature(id, GeojsonLineString)
+		} else {
+			e.NewFeature(id, GeojsonPolygon)
+		}
+		for _, coordinates := range way.Loc {
+			e.AddLngLat(coordinates[0], coordinates[1])
+		}
+		if way.IsPolygon && !way.closed() {
+			e.ClosePolygon()
+		}
 	}
 	for tagKey, tagValue := range way.Tag {
 		e.AddProperties(tagKey, tagValue)
 		e.AddTag(tagKey, tagValue)
 	}
+	way.Metadata.addProperties(e)
 	e.MakeBoundingBox()
 }
 
@@ -88,6 +108,7 @@ func (e *GeoJSon) AddGeoMathPoint(id string, point *Node) {
 		e.AddProperties(tagKey, tagValue)
 		e.AddTag(tagKey, tagValue)
 	}
+	point.Metadata.addProperties(e)
 	e.AddLngLat(point.Loc[0], point.Loc[1])
 }
 
@@ -298,19 +319,21 @@ func (e *GeoJSon) MakeBoundingBox() {
 		}
 
 	case GeojsonMultiLineString:
-		for k, v := range e.Features[e.setOfFeatures].Geometry.Coordinates.([]multiLineString) {
-			if k == 0 {
-				latMin = v[k][1]
-				latMax = v[k][1]
-
-				lngMin = v[k][0]
-				lngMax = v[k][0]
-			} else {
-				latMin = math.Min(latMin, v[k][1])
-				latMax = math.Max(latMax, v[k][1])
-
-				lngMin = math.Min(lngMin, v[k][0])
-				lngMax = math.Max(lngMax, v[k][0])
+		for kl, vl := range e.Features[e.setOfFeatures].Geometry.Coordinates.([]multiLineString) {
+			for k, v := range vl {
+				if k == 0 && kl == 0 {
+					latMin = v[1]
+					latMax = v[1]
+
+					lngMin = v[0]
+					lngMax = v[0]
+				} else {
+					latMin = math.Min(latMin, v[1])
+					latMax = math.Max(latMax, v[1])
+
+					lngMin = math.Min(lngMin, v[0])
+					lngMax = math.Max(lngMax, v[0])
+				}
 			}
 		}
 
@@ -0,0 +1,350 @@
+package goosm
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/qedus/osmpbf"
+	"io"
+	"os"
+	"time"
+)
+
+// ImportStatus
+//
+// English:
+//
+// # State of an import run
+//
+// Português:
+//
+// Estado de uma execução de importação
+type ImportStatus string
+
+const (
+	// English: the run started and did not write its end, it may still be running or the process was killed
+	// Português: a execução começou e não escreveu seu fim, ela pode estar em execução ou o processo foi morto
+	ImportStatusRunning ImportStatus = "running"
+
+	// English: all the elements are in the database
+	// Português: todos os elementos estão no banco de dados
+	ImportStatusDone ImportStatus = "done"
+
+	// English: the context was canceled, the run may be continued by Resume()
+	// Português: o contexto foi cancelado, a execução pode ser continuada por Resume()
+	ImportStatusInterrupted ImportStatus = "interrupted"
+
+	// English: the run stopped with the error in the field Error
+	// Português: a execução parou com o erro no campo Error
+	ImportStatusFailed ImportStatus = "failed"
+)
+
+// ImportRun
+//
+// English:
+//
+// Document of one run of CompleteParser(), DatabaseOnly() or Resume(), written at the start and at the end of the run
+// into the collection DB_OSM_IMPORT_LOG_COLLECTIONS.
+//
+// Português:
+//
+// Documento de uma execução de CompleteParser(), DatabaseOnly() ou Resume(), escrito no início e no fim da execução
+// na coleção DB_OSM_IMPORT_LOG_COLLECTIONS.
+type ImportRun struct {
+	Id string `bson:"_id" json:"id"`
+
+	// English: source file and the replication timestamp of the pbf header, zero when absent
+	// Português: arquivo de origem e o momento de replicação do cabeçalho pbf, zero quando ausente
+	File                 string    `bson:"file" json:"file"`
+	Size                 int64     `bson:"size" json:"size"`
+	SHA256               string    `bson:"sha256" json:"sha256"`
+	Format               string    `bson:"format" json:"format"`
+	ReplicationTimestamp time.Time `bson:"replicationTimestamp" json:"replicationTimestamp"`
+
+	Mode    string `bson:"mode" json:"mode"`
+	Resumed bool   `bson:"resumed" json:"resumed"`
+	Forced  bool   `bson:"forced" json:"forced"`
+
+	Start  time.Time    `bson:"start" json:"start"`
+	End    time.Time    `bson:"end" json:"end"`
+	Status ImportStatus `bson:"status" json:"status"`
+	Error  string       `bson:"error,omitempty" json:"error,omitempty"`
+
+	// English: elements read from the file and sent to the database, and invalid elements, see SetMaxErrors()
+	// Português: elementos lidos do arquivo e enviados ao banco de dados, e elementos inválidos, veja SetMaxErrors()
+	Nodes           uint64 `bson:"nodes" json:"nodes"`
+	Ways            uint64 `bson:"ways" json:"ways"`
+	Relations       uint64 `bson:"relations" json:"relations"`
+	FlushedNodes    uint64 `bson:"flushedNodes" json:"flushedNodes"`
+	FlushedWays     uint64 `bson:"flushedWays" json:"flushedWays"`
+	FlushedPolygons uint64 `bson:"flushedPolygons" json:"flushedPolygons"`
+	Errors          uint64 `bson:"errors" json:"errors"`
+}
+
+// SourceFile
+//
+// English:
+//
+// Document of one source file, identified by its SHA-256, written into the collection
+// DB_OSM_SOURCE_FILES_LOG_COLLECTIONS.
+//
+// Português:
+//
+// Documento de um arquivo de origem, identificado pelo seu SHA-256, escrito na coleção
+// DB_OSM_SOURCE_FILES_LOG_COLLECTIONS.
+type SourceFile struct {
+	SHA256               string    `bson:"_id" json:"sha256"`
+	File                 string    `bson:"file" json:"file"`
+	Size                 int64     `bson:"size" json:"size"`
+	ReplicationTimestamp time.Time `bson:"replicationTimestamp" json:"replicationTimestamp"`
+
+	// English: ID of the last run and of the last run done, empty while no run is done
+	// Português: ID da última execução e da última execução concluída, vazio enquanto nenhuma execução está concluída
+	LastRun  string `bson:"lastRun" json:"lastRun"`
+	Imported string `bson:"imported,omitempty" json:"imported,omitempty"`
+
+	FirstSeen time.Time `bson:"firstSeen" json:"firstSeen"`
+	LastSeen  time.Time `bson:"lastSeen" json:"lastSeen"`
+}
+
+// InterfaceDbImportLog
+//
+// English:
+//
+// # Interface of the database of the import runs and source files
+//
+// Português:
+//
+// Interface do banco de dados das execuções de importação e arquivos de origem
+type InterfaceDbImportLog interface {
+
+	// SetImportRun
+	//
+	// English:
+	//
+	// # Inserts the run or replaces the run with the same Id
+	//
+	// Português:
+	//
+	// Insere a execução ou substitui a execução com o mesmo Id
+	SetImportRun(run *ImportRun) (err error)
+
+	// GetImportRuns
+	//
+	// English:
+	//
+	// # Returns all the runs, the newest first
+	//
+	// Português:
+	//
+	// Devolve todas as execuções, a mais nova primeiro
+	GetImportRuns() (runs []ImportRun, err error)
+
+	// SetSourceFile
+	//
+	// English:
+	//
+	// # Inserts the source file or replaces the source file with the same SHA256
+	//
+	// Português:
+	//
+	// Insere o arquivo de origem ou substitui o arquivo de origem com o mesmo SHA256
+	SetSourceFile(file *SourceFile) (err error)
+
+	// GetSourceFile
+	//
+	// English:
+	//
+	// # Returns the source file by its SHA-256, found is false when it is not in the database
+	//
+	// Português:
+	//
+	// Devolve o arquivo de origem pelo seu SHA-256, found é false quando ele não está no banco de dados
+	GetSourceFile(sha256 string) (file SourceFile, found bool, err error)
+}
+
+// GetImportRuns
+//
+// English:
+//
+// # Returns the runs of the database of SetImportLog(), the newest first
+//
+// Português:
+//
+// Devolve as execuções do banco de dados de SetImportLog(), a mais nova primeiro
+func (e *PbfProcess) GetImportRuns() (runs []ImportRun, err error) {
+	if e.importLog == nil {
+		err = fmt.Errorf("PbfProcess.GetImportRuns().error: the import log must be defined by SetImportLog()")
+		return
+	}
+
+	runs, err = e.importLog.GetImportRuns()
+	if err != nil {
+		err = fmt.Errorf("PbfProcess.GetImportRuns().GetImportRuns().Error: %v", err)
+	}
+	return
+}
+
+// startImportRun
+//
+// English:
+//
+// Hashes the source file, stopped by ctx, refuses a file already imported, unless SetForceImport(true) or resumed, and
+// writes the run and the source file.
+//
+// Português:
+//
+// Calcula o hash do arquivo de origem, interrompido por ctx, recusa um arquivo já importado, a menos que
+// SetForceImport(true) ou retomado, e escreve a execução e o arquivo de origem.
+func (e *PbfProcess) startImportRun(ctx context.Context, run *pbfRun, osmFile *os.File, header []byte, resumed bool) (importRun *ImportRun, err error) {
+	var start = time.Now().UTC()
+	var hash = sha256.New()
+	_, err = io.Copy(hash, &contextReader{ctx: ctx, reader: io.NewSectionReader(osmFile, 0, run.checkpoint.FileSize)})
+	if err != nil {
+		err = fmt.Errorf("PbfProcess.%v().Copy().Error: %w", run.name, err)
+		return
+	}
+
+	importRun = &ImportRun{
+		File:    run.checkpoint.File,
+		Size:    run.checkpoint.FileSize,
+		SHA256:  hex.EncodeToString(hash.Sum(nil)),
+		Format:  run.format.String(),
+		Mode:    run.name,
+		Resumed: resumed,
+		Forced:  e.forceImport,
+		Start:   start,
+		Status:  ImportStatusRunning,
+	}
+	importRun.Id = fmt.Sprintf("%v-%v", importRun.SHA256[:16], importRun.Start.UnixNano())
+
+	if len(header) != 0 {
+		var pbfHeader *osmpbf.Header
+		pbfHeader, err = osmpbf.NewDecoder(bytes.NewReader(header)).Header()
+		if err != nil {
+			err = fmt.Errorf("PbfProcess.%v().Header().Error: %v", run.name, err)
+			return
+		}
+		if !pbfHeader.OsmosisReplicationTimestamp.IsZero() {
+			importRun.ReplicationTimestamp = pbfHeader.OsmosisReplicationTimestamp.UTC()
+		}
+	}
+
+	var sourceFile SourceFile
+	var found bool
+	sourceFile, found, err = e.importLog.GetSourceFile(importRun.SHA256)
+	if err != nil {
+		err = fmt.Errorf("PbfProcess.%v().GetSourceFile().Error: %v", run.name, err)
+		return
+	}
+
+	if found && sourceFile.Imported != "" && !e.forceImport && !resumed {
+		err = fmt.Errorf("PbfProcess.%v().error: the file %v, sha256 %v, was already imported by the run %v, see SetForceImport()", run.name, importRun.File, importRun.SHA256, sourceFile.Imported)
+		return
+	}
+
+	if !found {
+		sourceFile = SourceFile{SHA256: importRun.SHA256, FirstSeen: importRun.Start}
+	}
+	sourceFile.File = importRun.File
+	sourceFile.Size = importRun.Size
+	sourceFile.ReplicationTimestamp = importRun.ReplicationTimestamp
+	sourceFile.LastRun = importRun.Id
+	sourceFile.LastSeen = importRun.Start
+
+	err = e.importLog.SetSourceFile(&sourceFile)
+	if err != nil {
+		err = fmt.Errorf("PbfProcess.%v().SetSourceFile().Error: %v", run.name, err)
+		return
+	}
+
+	err = e.importLog.SetImportRun(importRun)
+	if err != nil {
+		err = fmt.Errorf("PbfProcess.%v().SetImportRun().Error: %v", run.name, err)
+	}
+	return
+}
+
+// finishImportRun
+//
+// English:
+//
+// # Writes the end of the run, with the counters and the error of the run, and marks the source file as imported
+//
+// Português:
+//
+// Escreve o fim da execução, com os contadores e o erro da execução, e marca o arquivo de origem como importado
+func (e *PbfProcess) finishImportRun(run *pbfRun, importRun *ImportRun, runErr error) (err error) {
+	importRun.End = time.Now().UTC()
+	importRun.Nodes = run.checkpoint.Nodes
+	importRun.Ways = run.checkpoint.Ways
+	importRun.Relations = run.checkpoint.Relations
+	importRun.FlushedNodes = run.flushedNodes.Load()
+	importRun.FlushedWays = run.flushedWays.Load()
+	importRun.FlushedPolygons = run.flushedPolygons.Load()
+	importRun.Errors = run.errors.Load()
+
+	switch {
+	case runErr == nil:
+		importRun.Status = ImportStatusDone
+	case errors.Is(runErr, context.Canceled) || errors.Is(runErr, context.DeadlineExceeded):
+		importRun.Status = ImportStatusInterrupted
+		importRun.Error = runErr.Error()
+	default:
+		importRun.Status = ImportStatusFailed
+		importRun.Error = runErr.Error()
+	}
+
+	err = e.importLog.SetImportRun(importRun)
+	if err != nil {
+		err = fmt.Errorf("PbfProcess.%v().SetImportRun().Error: %v", run.name, err)
+		return
+	}
+
+	if importRun.Status != ImportStatusDone {
+		return
+	}
+
+	var sourceFile SourceFile
+	var found bool
+	sourceFile, found, err = e.importLog.GetSourceFile(importRun.SHA256)
+	if err != nil {
+		err = fmt.Errorf("PbfProcess.%v().GetSourceFile().Error: %v", run.name, err)
+		return
+	}
+	if !found {
+		sourceFile = SourceFile{SHA256: importRun.SHA256, File: importRun.File, Size: importRun.Size, FirstSeen: importRun.Start, LastSeen: importRun.Start}
+	}
+
+	sourceFile.Imported = importRun.Id
+	err = e.importLog.SetSourceFile(&sourceFile)
+	if err != nil {
+		err = fmt.Errorf("PbfProcess.%v().SetSourceFile().Error: %v", run.name, err)
+	}
+	return
+}
+
+// contextReader
+//
+// English:
+//
+// # Reader that returns the error of the context after it is canceled, so a long io.Copy() stops with the run
+//
+// Português:
+//
+// Leitor que devolve o erro do contexto depois que ele é cancelado, assim um io.Copy() longo para com a execução
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (e *contextReader) Read(p []byte) (n int, err error) {
+	err = e.ctx.Err()
+	if err != nil {
+		return
+	}
+	return e.reader.Read(p)
+}
@@ -0,0 +1,139 @@
+package goosm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// testImportLog
+//
+// English:
+//
+// # Import log kept in maps, implements InterfaceDbImportLog
+//
+// Português:
+//
+// Log de importação mantido em mapas, implementa InterfaceDbImportLog
+type testImportLog struct {
+	runs  map[string]ImportRun
+	files map[string]SourceFile
+}
+
+func (e *testImportLog) SetImportRun(run *ImportRun) (err error) {
+	e.runs[run.Id] = *run
+	return
+}
+
+func (e *testImportLog) GetImportRuns() (runs []ImportRun, err error) {
+	for _, run := range e.runs {
+		runs = append(runs, run)
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].Start.After(runs[j].Start) })
+	return
+}
+
+func (e *testImportLog) SetSourceFile(file *SourceFile) (err error) {
+	e.files[file.SHA256] = *file
+	return
+}
+
+func (e *testImportLog) GetSourceFile(sha256 string) (file SourceFile, found bool, err error) {
+	file, found = e.files[sha256]
+	return
+}
+
+// TestPbfProcess_SetImportLog
+//
+// English:
+//
+// # Imports the grid, the second import of the same file is refused unless forced
+//
+// Português:
+//
+// Importa a grade, a segunda importação do mesmo arquivo é recusada a menos que forçada
+func TestPbfProcess_SetImportLog(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "grid.pbf")
+	err := testPbfGrid(path, 200)
+	if err != nil {
+		t.Logf("testPbfGrid() error: %v", err)
+		t.FailNow()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Logf("ReadFile() error: %v", err)
+		t.FailNow()
+	}
+	var sum = sha256.Sum256(data)
+	var hash = hex.EncodeToString(sum[:])
+
+	var importLog = &testImportLog{runs: make(map[string]ImportRun), files: make(map[string]SourceFile)}
+	for _, force := range []bool{false, false, true} {
+		var database = newTestDatabase()
+		var nodeFile = &testNodeFile{}
+		nodeFile.Init(0)
+
+		var process = newTestPbfProcess(database, nodeFile)
+		process.SetImportLog(importLog)
+		process.SetForceImport(force)
+		var refused = len(importLog.runs) != 0 && !force
+		_, _, err = process.CompleteParser(path)
+		if refused {
+			if err == nil || !strings.Contains(err.Error(), "already imported") {
+				t.Logf("CompleteParser() must refuse the file: %v", err)
+				t.FailNow()
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Logf("%v: CompleteParser() error: %v", force, err)
+			t.FailNow()
+		}
+	}
+
+	var process = &PbfProcess{}
+	process.SetImportLog(importLog)
+	runs, err := process.GetImportRuns()
+	if err != nil || len(runs) != 2 {
+		t.Logf("GetImportRuns() error: %v, %v", err, len(runs))
+		t.FailNow()
+	}
+
+	var run = runs[0]
+	if run.SHA256 != hash || run.Size != int64(len(data)) || run.File != path || run.Mode != pbfModeCompleteParser || !run.Forced ||
+		run.Status != ImportStatusDone || run.Nodes != 200 || run.Ways != 200 || run.Relations != 1 || run.FlushedPolygons != 1 ||
+		run.End.Before(run.Start) || runs[1].Forced {
+		t.Logf("run error: %+v", run)
+		t.FailNow()
+	}
+
+	var file = importLog.files[hash]
+	if file.Imported != run.Id || file.LastRun != run.Id || file.FirstSeen != runs[1].Start {
+		t.Logf("source file error: %+v", file)
+		t.FailNow()
+	}
+
+	// English: the hash stops with the context, before the run and the source file are written
+	// Português: o hash para com o contexto, antes da execução e do arquivo de origem serem escritos
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var nodeFile = &testNodeFile{}
+	nodeFile.Init(0)
+	importLog = &testImportLog{runs: make(map[string]ImportRun), files: make(map[string]SourceFile)}
+	process = newTestPbfProcess(newTestDatabase(), nodeFile)
+	process.SetImportLog(importLog)
+	_, _, err = process.CompleteParserContext(ctx, path)
+	if !errors.Is(err, context.Canceled) || len(importLog.runs) != 0 || len(importLog.files) != 0 {
+		t.Logf("CompleteParserContext() must stop while hashing: %v, %v runs", err, len(importLog.runs))
+		t.FailNow()
+	}
+}
@@ -0,0 +1,464 @@
+package goosm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/qedus/osmpbf"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"time"
+)
+
+// InspectDefaultTop
+//
+// English:
+//
+// # Number of tag keys and values of the report of Inspect()
+//
+// Português:
+//
+// Quantidade de chaves e valores de tags do relatório de Inspect()
+const InspectDefaultTop = 20
+
+// inspectMaxValues
+//
+// English:
+//
+// Greatest number of key=value pairs counted at the same time, beyond it the rarest pairs are discarded and the
+// counts of the values become approximate.
+//
+// Português:
+//
+// Maior quantidade de pares chave=valor contados ao mesmo tempo, além dela os pares mais raros são descartados e as
+// contagens dos valores se tornam aproximadas.
+const inspectMaxValues = 1000000
+
+// InspectElements
+//
+// English:
+//
+// # Statistics of one type of element of the file
+//
+// Português:
+//
+// Estatísticas de um tipo de elemento do arquivo
+type InspectElements struct {
+	Count uint64 `json:"count"`
+	MinID int64  `json:"minId"`
+	MaxID int64  `json:"maxId"`
+
+	// English: the IDs never go down, the versions of an element of a history file are in a row, see History
+	// Português: os IDs nunca diminuem, as versões de um elemento de um arquivo de histórico ficam em sequência, veja
+	// History
+	Sorted bool `json:"sorted"`
+
+	// English: elements with visible=false, only found in history files
+	// Português: elementos com visible=false, encontrados apenas em arquivos de histórico
+	Deleted uint64 `json:"deleted"`
+}
+
+// InspectBBox
+//
+// English:
+//
+// # Bounding box of the nodes of the file, in degrees
+//
+// Português:
+//
+// Caixa delimitadora dos nodes do arquivo, em graus
+type InspectBBox struct {
+	MinLon float64 `json:"minLon"`
+	MinLat float64 `json:"minLat"`
+	MaxLon float64 `json:"maxLon"`
+	MaxLat float64 `json:"maxLat"`
+}
+
+// InspectTag
+//
+// English:
+//
+// # Tag key, or key and value, with the number of elements that have it
+//
+// Português:
+//
+// Chave de tag, ou chave e valor, com a quantidade de elementos que a têm
+type InspectTag struct {
+	Key   string `json:"key"`
+	Value string `json:"value,omitempty"`
+	Count uint64 `json:"count"`
+}
+
+// InspectReport
+//
+// English:
+//
+// # Report of Inspect(), with json tags for dashboards
+//
+// Português:
+//
+// Relatório de Inspect(), com tags json para painéis
+type InspectReport struct {
+	File   string `json:"file"`
+	Format string `json:"format"`
+	Size   int64  `json:"size"`
+
+	Nodes     InspectElements `json:"nodes"`
+	Ways      InspectElements `json:"ways"`
+	Relations InspectElements `json:"relations"`
+
+	// English: nodes, ways and relations in this order, each one sorted by ID, as required by PbfProcess
+	// Português: nodes, ways e relations nesta ordem, cada um ordenado por ID, como exigido por PbfProcess
+	Sorted bool `json:"sorted"`
+
+	// English: the file has deleted elements or more than one version of an element
+	// Português: o arquivo tem elementos apagados ou mais de uma versão de um elemento
+	History bool `json:"history"`
+
+	// English: nil when the file has no nodes
+	// Português: nil quando o arquivo não tem nodes
+	BBox *InspectBBox `json:"bbox,omitempty"`
+
+	// English: first and last timestamps of the elements, nil when the file has no metadata
+	// Português: primeiro e último momentos dos elementos, nil quando o arquivo não tem metadados
+	FirstTimestamp *time.Time `json:"firstTimestamp,omitempty"`
+	LastTimestamp  *time.Time `json:"lastTimestamp,omitempty"`
+
+	// English: most used tag keys and key=value pairs, of all types of elements
+	// Português: chaves de tags e pares chave=valor mais usados, de todos os tipos de elemento
+	TopKeys   []InspectTag `json:"topKeys"`
+	TopValues []InspectTag `json:"topValues"`
+
+	// English: true when the file has so many different values that the rarest were discarded during the counting
+	// Português: true quando o arquivo tem tantos valores diferentes que os mais raros foram descartados durante a
+	// contagem
+	ValuesApproximate bool `json:"valuesApproximate"`
+}
+
+// JSON
+//
+// English:
+//
+// # Returns the report as indented JSON
+//
+// Português:
+//
+// Devolve o relatório como JSON indentado
+func (e InspectReport) JSON() (data []byte, err error) {
+	return json.MarshalIndent(e, "", "  ")
+}
+
+// Inspect
+//
+// English:
+//
+// Reads the file once and returns the counts by element type, the ranges of IDs, the order, the bounding box, the
+// range of timestamps and the InspectDefaultTop most used tags, to check a file before a long import.
+//
+//	Note:
+//	  * Accepts the same formats of PbfProcess, see DetectOsmFormat().
+//
+// Português:
+//
+// Lê o arquivo uma vez e devolve as contagens por tipo de elemento, as faixas de IDs, a ordem, a caixa delimitadora,
+// a faixa de momentos e as InspectDefaultTop tags mais usadas, para verificar um arquivo antes de uma importação longa.
+//
+//	Nota:
+//	  * Aceita os mesmos formatos de PbfProcess, veja DetectOsmFormat().
+func Inspect(osmFilePath string) (report InspectReport, err error) {
+	return InspectContext(context.Background(), osmFilePath, InspectDefaultTop)
+}
+
+// InspectContext
+//
+// English:
+//
+// Same as Inspect(), stopping when the context is canceled.
+//
+//	Input:
+//	  top: number of tag keys and values of the report, zero uses InspectDefaultTop.
+//
+// Português:
+//
+// Igual a Inspect(), parando quando o contexto é cancelado.
+//
+//	Entrada:
+//	  top: quantidade de chaves e valores de tags do relatório, zero usa InspectDefaultTop.
+func InspectContext(ctx context.Context, osmFilePath string, top int) (report InspectReport, err error) {
+	if top <= 0 {
+		top = InspectDefaultTop
+	}
+
+	var osmFile *os.File
+	osmFile, err = os.Open(osmFilePath)
+	if err != nil {
+		err = fmt.Errorf("Inspect().Open().Error: %v", err)
+		return
+	}
+	defer osmFile.Close()
+
+	var info os.FileInfo
+	info, err = osmFile.Stat()
+	if err != nil {
+		err = fmt.Errorf("Inspect().Stat().Error: %v", err)
+		return
+	}
+
+	var format OsmFormat
+	format, err = detectOsmFormat(osmFile, osmFilePath)
+	if err != nil {
+		err = fmt.Errorf("Inspect().detectOsmFormat().Error: %v", err)
+		return
+	}
+
+	var osmDecoder osmElementDecoder
+	osmDecoder, err = newOsmFileDecoder(osmFile, format, nil)
+	if err != nil {
+		err = fmt.Errorf("Inspect().Start().Error: %v", err)
+		return
+	}
+
+	var inspector = newInspector()
+	for {
+		if ctx.Err() != nil {
+			_ = osmFile.Close()
+			drainDecoder(osmDecoder)
+			err = fmt.Errorf("Inspect().Error: %w", ctx.Err())
+			return
+		}
+
+		var element interface{}
+		element, err = osmDecoder.Decode()
+		if err == io.EOF {
+			err = nil
+			break
+		} else if err != nil {
+			drainDecoder(osmDecoder)
+			err = fmt.Errorf("Inspect().Decode().Error: %v", err)
+			return
+		}
+
+		inspector.add(element)
+	}
+
+	report = inspector.report(top)
+	report.File = osmFilePath
+	report.Format = format.String()
+	report.Size = info.Size()
+	return
+}
+
+// inspector
+//
+// English:
+//
+// # Counters of Inspect(), updated element by element
+//
+// Português:
+//
+// Contadores de Inspect(), atualizados elemento a elemento
+type inspector struct {
+	elements [3]InspectElements
+	order    int
+	sorted   bool
+	history  bool
+
+	bbox                InspectBBox
+	firstTime, lastTime time.Time
+
+	keys   map[string]uint64
+	values map[[2]string]uint64
+
+	// English: pairs with this count or less were discarded when the map of values was full
+	// Português: pares com esta contagem ou menos foram descartados quando o mapa de valores ficou cheio
+	discarded uint64
+}
+
+// newInspector
+//
+// English:
+//
+// # Returns the counters ready for the first element
+//
+// Português:
+//
+// Devolve os contadores prontos para o primeiro elemento
+func newInspector() (e *inspector) {
+	e = &inspector{
+		sorted: true,
+		keys:   make(map[string]uint64),
+		values: make(map[[2]string]uint64),
+		bbox:   InspectBBox{MinLon: math.Inf(1), MinLat: math.Inf(1), MaxLon: math.Inf(-1), MaxLat: math.Inf(-1)},
+	}
+	for key := range e.elements {
+		e.elements[key].Sorted = true
+	}
+	return
+}
+
+// add
+//
+// English:
+//
+// # Adds one element to the counters
+//
+// Português:
+//
+// Soma um elemento aos contadores
+func (e *inspector) add(element interface{}) {
+	var order int
+	var id int64
+	var info osmpbf.Info
+	var tags map[string]string
+
+	switch converted := element.(type) {
+	case *osmpbf.Node:
+		order, id, info, tags = 0, converted.ID, converted.Info, converted.Tags
+		e.bbox.MinLon = math.Min(e.bbox.MinLon, converted.Lon)
+		e.bbox.MinLat = math.Min(e.bbox.MinLat, converted.Lat)
+		e.bbox.MaxLon = math.Max(e.bbox.MaxLon, converted.Lon)
+		e.bbox.MaxLat = math.Max(e.bbox.MaxLat, converted.Lat)
+	case *osmpbf.Way:
+		order, id, info, tags = 1, converted.ID, converted.Info, converted.Tags
+	case *osmpbf.Relation:
+		order, id, info, tags = 2, converted.ID, converted.Info, converted.Tags
+	default:
+		return
+	}
+
+	if order < e.order {
+		e.sorted = false
+	}
+	e.order = order
+
+	var elements = &e.elements[order]
+	if elements.Count == 0 {
+		elements.MinID, elements.MaxID = id, id
+	} else {
+		// English: the same ID twice in a row is another version of the element, sorted as in the history files
+		// Português: o mesmo ID duas vezes seguidas é outra versão do elemento, ordenado como nos arquivos de histórico
+		if id == elements.MaxID {
+			e.history = true
+		}
+		if id < elements.MaxID {
+			elements.Sorted = false
+		}
+		elements.MinID = min(elements.MinID, id)
+		elements.MaxID = max(elements.MaxID, id)
+	}
+	elements.Count++
+
+	if !info.Visible {
+		elements.Deleted++
+		e.history = true
+	}
+
+	// English: files without metadata have the timestamp zero, 1970
+	// Português: arquivos sem metadados têm o momento zero, 1970
+	if info.Timestamp.Unix() > 0 {
+		if e.firstTime.IsZero() || info.Timestamp.Before(e.firstTime) {
+			e.firstTime = info.Timestamp
+		}
+		if info.Timestamp.After(e.lastTime) {
+			e.lastTime = info.Timestamp
+		}
+	}
+
+	for key, value := range tags {
+		e.keys[key]++
+		e.values[[2]string{key, value}]++
+	}
+
+	if len(e.values) > inspectMaxValues {
+		e.discard()
+	}
+}
+
+// discard
+//
+// English:
+//
+// # Removes the rarest pairs until the map of values has half of its limit
+//
+// Português:
+//
+// Remove os pares mais raros até o mapa de valores ter metade do seu limite
+func (e *inspector) discard() {
+	for len(e.values) > inspectMaxValues/2 {
+		e.discarded++
+		for pair, count := range e.values {
+			if count <= e.discarded {
+				delete(e.values, pair)
+			}
+		}
+	}
+}
+
+// report
+//
+// English:
+//
+// # Makes the report with the top most used keys and values
+//
+// Português:
+//
+// Monta o relatório com as top chaves e valores mais usados
+func (e *inspector) report(top int) (report InspectReport) {
+	report.Nodes = e.elements[0]
+	report.Ways = e.elements[1]
+	report.Relations = e.elements[2]
+	report.History = e.history
+	report.Sorted = e.sorted && report.Nodes.Sorted && report.Ways.Sorted && report.Relations.Sorted
+	report.ValuesApproximate = e.discarded != 0
+
+	if report.Nodes.Count != 0 {
+		var bbox = e.bbox
+		report.BBox = &bbox
+	}
+
+	if !e.firstTime.IsZero() {
+		var first, last = e.firstTime.UTC(), e.lastTime.UTC()
+		report.FirstTimestamp, report.LastTimestamp = &first, &last
+	}
+
+	report.TopKeys = make([]InspectTag, 0, len(e.keys))
+	for key, count := range e.keys {
+		report.TopKeys = append(report.TopKeys, InspectTag{Key: key, Count: count})
+	}
+	report.TopKeys = topInspectTags(report.TopKeys, top)
+
+	report.TopValues = make([]InspectTag, 0, len(e.values))
+	for pair, count := range e.values {
+		report.TopValues = append(report.TopValues, InspectTag{Key: pair[0], Value: pair[1], Count: count})
+	}
+	report.TopValues = topInspectTags(report.TopValues, top)
+	return
+}
+
+// topInspectTags
+//
+// English:
+//
+// # Sorts the tags by count, then by key and value, and returns the first top
+//
+// Português:
+//
+// Ordena as tags pela contagem, depois pela chave e valor, e devolve as primeiras top
+func topInspectTags(tags []InspectTag, top int) []InspectTag {
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		if tags[i].Key != tags[j].Key {
+			return tags[i].Key < tags[j].Key
+		}
+		return tags[i].Value < tags[j].Value
+	})
+
+	if len(tags) > top {
+		tags = tags[:top]
+	}
+	return tags
+}
@@ -0,0 +1,102 @@
+package goosm
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestInspect
+//
+// English:
+//
+// # Inspects the grid and a small history file
+//
+// Português:
+//
+// Inspeciona a grade e um pequeno arquivo de histórico
+func TestInspect(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "grid.pbf")
+	err := testPbfGrid(path, 200)
+	if err != nil {
+		t.Logf("testPbfGrid() error: %v", err)
+		t.FailNow()
+	}
+
+	report, err := InspectContext(context.Background(), path, 2)
+	if err != nil {
+		t.Logf("Inspect() error: %v", err)
+		t.FailNow()
+	}
+
+	if report.Format != "pbf" || report.Nodes != (InspectElements{Count: 200, MinID: 1, MaxID: 200, Sorted: true}) ||
+		report.Ways.Count != 200 || report.Relations.Count != 1 || !report.Sorted || report.History {
+		t.Logf("counts error: %+v", report)
+		t.FailNow()
+	}
+
+	if report.BBox == nil || *report.BBox != (InspectBBox{MinLon: -48.0, MinLat: -27.0, MaxLon: -47.901, MaxLat: -26.998}) {
+		t.Logf("bbox error: %+v", report.BBox)
+		t.FailNow()
+	}
+
+	if len(report.TopKeys) != 2 || report.TopKeys[0] != (InspectTag{Key: "amenity", Count: 200}) || report.TopKeys[1].Key != "highway" ||
+		report.TopValues[0] != (InspectTag{Key: "amenity", Value: "bench", Count: 200}) || report.FirstTimestamp != nil {
+		t.Logf("tags error: %+v, %+v", report.TopKeys, report.TopValues)
+		t.FailNow()
+	}
+
+	data, err := report.JSON()
+	if err != nil || !json.Valid(data) {
+		t.Logf("JSON() error: %v", err)
+		t.FailNow()
+	}
+
+	path = filepath.Join(t.TempDir(), "history.osm")
+	err = os.WriteFile(path, []byte(`<osm>
+  <node id="1" version="1" timestamp="2020-01-01T00:00:00Z" lat="1" lon="1"/>
+  <node id="1" version="2" timestamp="2021-01-01T00:00:00Z" visible="false" lat="1" lon="1"/>
+  <node id="3" version="1" timestamp="2019-06-01T00:00:00Z" lat="2" lon="2"/>
+  <way id="1" version="1" timestamp="2022-01-01T00:00:00Z"><nd ref="1"/><nd ref="3"/></way>
+</osm>`), 0644)
+	if err != nil {
+		t.Logf("WriteFile() error: %v", err)
+		t.FailNow()
+	}
+
+	report, err = Inspect(path)
+	if err != nil {
+		t.Logf("Inspect() error: %v", err)
+		t.FailNow()
+	}
+
+	if !report.History || !report.Sorted || report.Nodes.Deleted != 1 || !report.Nodes.Sorted || report.Format != "xml" ||
+		report.FirstTimestamp == nil || report.FirstTimestamp.Year() != 2019 || report.LastTimestamp.Year() != 2022 {
+		t.Logf("history error: %+v", report)
+		t.FailNow()
+	}
+
+	path = filepath.Join(t.TempDir(), "unsorted.osm")
+	err = os.WriteFile(path, []byte(`<osm>
+  <node id="5" lat="1" lon="1"/>
+  <node id="2" lat="2" lon="2"/>
+  <way id="1"><nd ref="5"/><nd ref="2"/></way>
+</osm>`), 0644)
+	if err != nil {
+		t.Logf("WriteFile() error: %v", err)
+		t.FailNow()
+	}
+
+	report, err = Inspect(path)
+	if err != nil {
+		t.Logf("Inspect() error: %v", err)
+		t.FailNow()
+	}
+
+	if report.History || report.Sorted || report.Nodes.Sorted || !report.Ways.Sorted || report.Nodes.MinID != 2 || report.Nodes.MaxID != 5 {
+		t.Logf("unsorted error: %+v", report)
+		t.FailNow()
+	}
+}
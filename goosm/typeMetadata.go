@@ -0,0 +1,83 @@
+package goosm
+
+import (
+	"github.com/qedus/osmpbf"
+	"strconv"
+	"time"
+)
+
+// Metadata
+//
+// English:
+//
+// Metadata of the last edition of an element, filled into Node and Way by PbfProcess only when SetMetadata(true) was
+// called, with the same fields of Relation and PolygonList.
+//
+// Português:
+//
+// Metadados da última edição de um elemento, preenchidos em Node e Way por PbfProcess apenas quando SetMetadata(true)
+// foi chamado, com os mesmos campos de Relation e PolygonList.
+type Metadata struct {
+	// English: version of the element, incremented at each edition
+	// Português: versão do elemento, incrementada a cada edição
+	Version int64 `bson:"version" json:"version"`
+
+	// English: time of the last edition
+	// Português: momento da última edição
+	TimeStamp time.Time `bson:"timeStamp" json:"timeStamp"`
+
+	// English: changeset of the last edition
+	// Português: changeset da última edição
+	ChangeSet int64 `bson:"changeSet" json:"changeSet"`
+
+	// English: id and name of the user of the last edition
+	// Português: id e nome do usuário da última edição
+	UId  int64  `bson:"userId" json:"userId"`
+	User string `bson:"user,omitempty" json:"user,omitempty"`
+}
+
+// newMetadata
+//
+// English:
+//
+// # Copies the metadata of an element of the pbf file
+//
+// Português:
+//
+// Copia os metadados de um elemento do arquivo pbf
+func newMetadata(info osmpbf.Info) (metadata *Metadata) {
+	return &Metadata{
+		Version:   int64(info.Version),
+		TimeStamp: info.Timestamp,
+		ChangeSet: info.Changeset,
+		UId:       int64(info.Uid),
+		User:      info.User,
+	}
+}
+
+// addProperties
+//
+// English:
+//
+// Adds the metadata to the properties of the last feature, with the prefix @ used by osmium export, so they do not
+// collide with the tags. Nothing is added when the metadata is nil.
+//
+// Português:
+//
+// Adiciona os metadados às propriedades da última feature, com o prefixo @ usado pelo osmium export, para que não
+// colidam com as tags. Nada é adicionado quando os metadados são nil.
+func (e *Metadata) addProperties(geoJSon *GeoJSon) {
+	if e == nil {
+		return
+	}
+
+	geoJSon.AddProperties("@version", strconv.FormatInt(e.Version, 10))
+	geoJSon.AddProperties("@changeset", strconv.FormatInt(e.ChangeSet, 10))
+	geoJSon.AddProperties("@uid", strconv.FormatInt(e.UId, 10))
+	if !e.TimeStamp.IsZero() {
+		geoJSon.AddProperties("@timestamp", e.TimeStamp.UTC().Format(time.RFC3339))
+	}
+	if e.User != "" {
+		geoJSon.AddProperties("@user", e.User)
+	}
+}
@@ -0,0 +1,79 @@
+package goosm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestPbfProcess_SetMetadata
+//
+// English:
+//
+// # Imports a node and a way with metadata, with and without SetMetadata(true)
+//
+// Português:
+//
+// Importa um node e um way com metadados, com e sem SetMetadata(true)
+func TestPbfProcess_SetMetadata(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "metadata.osm")
+	err := os.WriteFile(path, []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<osm version="0.6">
+  <node id="1" version="3" timestamp="2023-05-01T10:00:00Z" changeset="100" uid="7" user="mapper" lat="-27.0" lon="-48.0">
+    <tag k="amenity" v="bench"/>
+  </node>
+  <node id="2" version="1" timestamp="2023-05-01T10:00:00Z" changeset="100" uid="7" user="mapper" lat="-27.0" lon="-48.1"/>
+  <way id="10" version="5" timestamp="2024-01-02T03:04:05Z" changeset="200" uid="8" user="editor">
+    <nd ref="1"/>
+    <nd ref="2"/>
+    <tag k="highway" v="residential"/>
+  </way>
+</osm>`), 0644)
+	if err != nil {
+		t.Logf("WriteFile() error: %v", err)
+		t.FailNow()
+	}
+
+	for _, enabled := range []bool{false, true} {
+		var database = newTestDatabase()
+		var nodeFile = &testNodeFile{}
+		nodeFile.Init(0)
+
+		var process = newTestPbfProcess(database, nodeFile)
+		process.SetMetadata(enabled)
+		_, _, err = process.CompleteParser(path)
+		if err != nil {
+			t.Logf("%v: CompleteParser() error: %v", enabled, err)
+			t.FailNow()
+		}
+
+		var node = database.nodes[1]
+		var way = database.ways[10]
+		if !enabled {
+			if node.Metadata != nil || way.Metadata != nil || strings.Contains(way.GeoJSonFeature, "@version") {
+				t.Logf("metadata must be nil when disabled: %v, %v", node.Metadata, way.Metadata)
+				t.FailNow()
+			}
+			continue
+		}
+
+		if node.Metadata == nil || *node.Metadata != (Metadata{Version: 3, TimeStamp: time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC), ChangeSet: 100, UId: 7, User: "mapper"}) {
+			t.Logf("node metadata error: %+v", node.Metadata)
+			t.FailNow()
+		}
+
+		if way.Metadata == nil || way.Metadata.Version != 5 || way.Metadata.User != "editor" || way.Metadata.ChangeSet != 200 {
+			t.Logf("way metadata error: %+v", way.Metadata)
+			t.FailNow()
+		}
+
+		for _, property := range []string{`"@version":"5"`, `"@timestamp":"2024-01-02T03:04:05Z"`, `"@user":"editor"`, `"highway":"residential"`} {
+			if !strings.Contains(way.GeoJSonFeature, property) {
+				t.Logf("way GeoJSON without %v: %v", property, way.GeoJSonFeature)
+				t.FailNow()
+			}
+		}
+	}
+}
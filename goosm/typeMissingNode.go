@@ -0,0 +1,481 @@
+package goosm
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// MissingNodePolicy
+//
+// English:
+//
+// # What PbfProcess does with the nodes of a way not found in the node file, defined by SetMissingNodePolicy()
+//
+// Português:
+//
+// O que PbfProcess faz com os nodes de um way não encontrados no arquivo de nodes, definido por SetMissingNodePolicy()
+type MissingNodePolicy int
+
+const (
+
+	// MissingNodeDownload
+	//
+	// English:
+	//
+	// The ways are held back and the missing nodes are downloaded in batches, before each checkpoint and at the end of
+	// the way phase, default value. A node that can not be downloaded stops the import.
+	//
+	// Português:
+	//
+	// Os ways são retidos e os nodes ausentes são baixados em lotes, antes de cada checkpoint e no fim da fase de ways,
+	// valor padrão. Um node que não pode ser baixado para a importação.
+	MissingNodeDownload MissingNodePolicy = iota
+
+	// MissingNodeFail
+	//
+	// English:
+	//
+	// # The import stops with an error
+	//
+	// Português:
+	//
+	// A importação para com um erro
+	MissingNodeFail
+
+	// MissingNodeSkip
+	//
+	// English:
+	//
+	// # The missing node is removed from the way, the way is dropped when less than two nodes are left
+	//
+	// Português:
+	//
+	// O node ausente é removido do way, o way é descartado quando sobram menos de dois nodes
+	MissingNodeSkip
+
+	// MissingNodeDropWay
+	//
+	// English:
+	//
+	// # The whole way is dropped
+	//
+	// Português:
+	//
+	// O way inteiro é descartado
+	MissingNodeDropWay
+
+	// MissingNodeRecord
+	//
+	// English:
+	//
+	// # The missing nodes are sent to the sink of SetMissingNodeSink() and the way is dropped
+	//
+	// Português:
+	//
+	// Os nodes ausentes são enviados ao receptor de SetMissingNodeSink() e o way é descartado
+	MissingNodeRecord
+)
+
+// missingNodeDownloadBatch
+//
+// English:
+//
+// # Number of node IDs of each call to DownloadNodes()
+//
+// Português:
+//
+// Quantidade de IDs de nodes de cada chamada a DownloadNodes()
+const missingNodeDownloadBatch = 100
+
+// String
+//
+// English:
+//
+// # Returns the name of the policy
+//
+// Português:
+//
+// Devolve o nome da política
+func (e MissingNodePolicy) String() string {
+	switch e {
+	case MissingNodeDownload:
+		return "download"
+	case MissingNodeFail:
+		return "fail"
+	case MissingNodeSkip:
+		return "skip"
+	case MissingNodeDropWay:
+		return "drop way"
+	case MissingNodeRecord:
+		return "record"
+	}
+	return fmt.Sprintf("MissingNodePolicy(%d)", int(e))
+}
+
+// MissingNodeSink
+//
+// English:
+//
+// Receives the missing nodes of the policy MissingNodeRecord, called by the resolvers of the pipeline at the same time.
+//
+// Português:
+//
+// Recebe os nodes ausentes da política MissingNodeRecord, chamado pelos resolvedores do pipeline ao mesmo tempo.
+type MissingNodeSink interface {
+	MissingNode(wayID, nodeID int64) (err error)
+}
+
+// InterfaceDownloadOsmNodes
+//
+// English:
+//
+// Optional interface of the download object, when implemented the missing nodes are downloaded many at a time.
+//
+//	Output:
+//	  nodes: the nodes found, in any order, the IDs absent from the list are downloaded one by one by DownloadNode().
+//
+// Português:
+//
+// Interface opcional do objeto de download, quando implementada os nodes ausentes são baixados vários de cada vez.
+//
+//	Saída:
+//	  nodes: os nodes encontrados, em qualquer ordem, os IDs ausentes da lista são baixados um a um por
+//	  DownloadNode().
+type InterfaceDownloadOsmNodes interface {
+	DownloadNodes(ids []int64) (nodes []Node, err error)
+}
+
+// MissingNodeReport
+//
+// English:
+//
+// # Counters of the missing nodes of a run, returned by GetMissingNodeReport() and saved in the checkpoint
+//
+// Português:
+//
+// Contadores dos nodes ausentes de uma execução, devolvidos por GetMissingNodeReport() e salvos no checkpoint
+type MissingNodeReport struct {
+	Policy MissingNodePolicy `json:"policy"`
+
+	// English: references to nodes not found and ways with at least one of them
+	// Português: referências a nodes não encontrados e ways com pelo menos uma delas
+	Nodes uint64 `json:"nodes"`
+	Ways  uint64 `json:"ways"`
+
+	// English: ways dropped, nodes removed from the ways, nodes sent to the sink and nodes downloaded
+	// Português: ways descartados, nodes removidos dos ways, nodes enviados ao receptor e nodes baixados
+	DroppedWays     uint64 `json:"droppedWays"`
+	SkippedNodes    uint64 `json:"skippedNodes"`
+	RecordedNodes   uint64 `json:"recordedNodes"`
+	DownloadedNodes uint64 `json:"downloadedNodes"`
+}
+
+// String
+//
+// English:
+//
+// # Returns the report in one line, for the log
+//
+// Português:
+//
+// Devolve o relatório em uma linha, para o log
+func (e MissingNodeReport) String() string {
+	return fmt.Sprintf("policy %v: %v missing nodes in %v ways, %v ways dropped, %v nodes skipped, %v nodes recorded, %v nodes downloaded",
+		e.Policy, e.Nodes, e.Ways, e.DroppedWays, e.SkippedNodes, e.RecordedNodes, e.DownloadedNodes)
+}
+
+// missingNodeCounters
+//
+// English:
+//
+// # Counters of MissingNodeReport, updated by the resolvers at the same time
+//
+// Português:
+//
+// Contadores de MissingNodeReport, atualizados pelos resolvedores ao mesmo tempo
+type missingNodeCounters struct {
+	nodes           atomic.Uint64
+	ways            atomic.Uint64
+	droppedWays     atomic.Uint64
+	skippedNodes    atomic.Uint64
+	recordedNodes   atomic.Uint64
+	downloadedNodes atomic.Uint64
+}
+
+// restore
+//
+// English:
+//
+// # Starts the counters from a report, of the checkpoint of an interrupted run
+//
+// Português:
+//
+// Inicia os contadores a partir de um relatório, do checkpoint de uma execução interrompida
+func (e *missingNodeCounters) restore(report MissingNodeReport) {
+	e.nodes.Store(report.Nodes)
+	e.ways.Store(report.Ways)
+	e.droppedWays.Store(report.DroppedWays)
+	e.skippedNodes.Store(report.SkippedNodes)
+	e.recordedNodes.Store(report.RecordedNodes)
+	e.downloadedNodes.Store(report.DownloadedNodes)
+}
+
+// report
+//
+// English:
+//
+// # Copies the counters into a report
+//
+// Português:
+//
+// Copia os contadores para um relatório
+func (e *missingNodeCounters) report(policy MissingNodePolicy) MissingNodeReport {
+	return MissingNodeReport{
+		Policy:          policy,
+		Nodes:           e.nodes.Load(),
+		Ways:            e.ways.Load(),
+		DroppedWays:     e.droppedWays.Load(),
+		SkippedNodes:    e.skippedNodes.Load(),
+		RecordedNodes:   e.recordedNodes.Load(),
+		DownloadedNodes: e.downloadedNodes.Load(),
+	}
+}
+
+// pbfDeferredWay
+//
+// English:
+//
+// # Way held back by MissingNodeDownload, with the positions of the missing nodes in Loc
+//
+// Português:
+//
+// Way retido por MissingNodeDownload, com as posições dos nodes ausentes em Loc
+type pbfDeferredWay struct {
+	way     Way
+	nodeIDs []int64
+	missing []int
+}
+
+// pbfDeferredWays
+//
+// English:
+//
+// # Ways held back by the resolvers, waiting for flushMissingNodes()
+//
+// Português:
+//
+// Ways retidos pelos resolvedores, esperando por flushMissingNodes()
+type pbfDeferredWays struct {
+	mutex sync.Mutex
+	list  []*pbfDeferredWay
+}
+
+// missingNodes
+//
+// English:
+//
+// Applies the missing node policy to a way, missing are the positions in way.Loc of the nodes not found.
+//
+//	Output:
+//	  keep: false when the way is dropped or held back.
+//
+// Português:
+//
+// Aplica a política de nodes ausentes a um way, missing são as posições em way.Loc dos nodes não encontrados.
+//
+//	Saída:
+//	  keep: false quando o way é descartado ou retido.
+func (e *PbfProcess) missingNodes(run *pbfRun, way *Way, nodeIDs []int64, missing []int, wayStoreOnly bool) (keep bool, err error) {
+	run.missing.nodes.Add(uint64(len(missing)))
+	run.missing.ways.Add(1)
+
+	switch e.missingNodePolicy {
+	case MissingNodeFail:
+		err = fmt.Errorf("PbfProcess.%v().error: way %v: node %v not found", run.name, way.Id, nodeIDs[missing[0]])
+		return
+
+	case MissingNodeSkip:
+		var loc = make([][2]float64, 0, len(way.Loc)-len(missing))
+		var next = 0
+		for key, point := range way.Loc {
+			if next < len(missing) && missing[next] == key {
+				next++
+				continue
+			}
+			loc = append(loc, point)
+		}
+		run.missing.skippedNodes.Add(uint64(len(missing)))
+
+		if len(loc) < 2 {
+			run.missing.droppedWays.Add(1)
+			return
+		}
+		way.Loc = loc
+		keep = true
+		return
+
+	case MissingNodeRecord:
+		if e.missingNodeSink == nil {
+			err = fmt.Errorf("PbfProcess.%v().error: the missing node sink must be defined by SetMissingNodeSink()", run.name)
+			return
+		}
+
+		for _, key := range missing {
+			err = e.missingNodeSink.MissingNode(way.Id, nodeIDs[key])
+			if err != nil {
+				err = fmt.Errorf("PbfProcess.%v().MissingNode().Error: %v", run.name, err)
+				return
+			}
+			run.missing.recordedNodes.Add(1)
+		}
+		run.missing.droppedWays.Add(1)
+		return
+
+	case MissingNodeDownload:
+		// English: the ways held back are not written into the way store, that requires ascending IDs, so the ways
+		// only for the way store are dropped and the relations look for them in the database or download them
+		// Português: os ways retidos não são escritos no arquivo de ways, que exige IDs em ordem crescente, então os ways
+		// apenas para o arquivo de ways são descartados e as relations os procuram no banco de dados ou fazem o download
+		if wayStoreOnly {
+			run.missing.droppedWays.Add(1)
+			return
+		}
+
+		run.deferred.mutex.Lock()
+		run.deferred.list = append(run.deferred.list, &pbfDeferredWay{way: *way, nodeIDs: nodeIDs, missing: missing})
+		run.deferred.mutex.Unlock()
+		return
+	}
+
+	run.missing.droppedWays.Add(1)
+	return
+}
+
+// flushMissingNodes
+//
+// English:
+//
+// Waits for the resolvers, downloads the missing nodes of the ways held back, in batches, and sends the ways to the
+// pipeline.
+//
+// Português:
+//
+// Espera os resolvedores, baixa os nodes ausentes dos ways retidos, em lotes, e envia os ways ao pipeline.
+func (e *PbfProcess) flushMissingNodes(run *pbfRun) (err error) {
+	if run.pipeline == nil {
+		return
+	}
+
+	err = e.flushPipeline(run)
+	if err != nil {
+		return
+	}
+
+	var deferred = run.deferred.list
+	run.deferred.list = nil
+	if len(deferred) == 0 {
+		return
+	}
+
+	var unique = make(map[int64]bool)
+	var ids = make([]int64, 0)
+	for _, way := range deferred {
+		for _, key := range way.missing {
+			if !unique[way.nodeIDs[key]] {
+				unique[way.nodeIDs[key]] = true
+				ids = append(ids, way.nodeIDs[key])
+			}
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var loc map[int64][2]float64
+	loc, err = e.downloadNodes(run, ids)
+	if err != nil {
+		return
+	}
+
+	for _, way := range deferred {
+		for _, key := range way.missing {
+			way.way.Loc[key] = loc[way.nodeIDs[key]]
+		}
+
+		err = run.pipeline.send(way, false)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// downloadNodes
+//
+// English:
+//
+// Downloads the nodes, missingNodeDownloadBatch at a time when the download object implements
+// InterfaceDownloadOsmNodes, the nodes left are downloaded one by one.
+//
+// Português:
+//
+// Baixa os nodes, missingNodeDownloadBatch de cada vez quando o objeto de download implementa
+// InterfaceDownloadOsmNodes, os nodes que sobrarem são baixados um a um.
+func (e *PbfProcess) downloadNodes(run *pbfRun, ids []int64) (loc map[int64][2]float64, err error) {
+	loc = make(map[int64][2]float64)
+
+	if batch, ok := e.downloadApi.(InterfaceDownloadOsmNodes); ok {
+		for start := 0; start < len(ids); start += missingNodeDownloadBatch {
+			var end = start + missingNodeDownloadBatch
+			if end > len(ids) {
+				end = len(ids)
+			}
+
+			nodes, err := batch.DownloadNodes(ids[start:end])
+			if err != nil {
+				log.Printf("PbfProcess.%v().event: DownloadNodes() error, the nodes are downloaded one by one: %v", run.name, err)
+				continue
+			}
+
+			for _, node := range nodes {
+				loc[node.Id] = node.Loc
+			}
+		}
+	}
+
+	for _, id := range ids {
+		if _, found := loc[id]; found {
+			e.progress.download("node", id)
+			run.missing.downloadedNodes.Add(1)
+			continue
+		}
+
+		log.Printf("PbfProcess.%v().event: download ID: %v", run.name, id)
+
+		var node Node
+		node, err = e.downloadApi.DownloadNode(id)
+		if err != nil {
+			err = fmt.Errorf("PbfProcess.%v().DownloadNode().Error: %v", run.name, err)
+			return
+		}
+
+		loc[id] = node.Loc
+		e.progress.download("node", id)
+		run.missing.downloadedNodes.Add(1)
+	}
+	return
+}
+
+// GetMissingNodeReport
+//
+// English:
+//
+// # Returns the counters of the missing nodes of the last run, also written to the log at the end of each run
+//
+// Português:
+//
+// Devolve os contadores dos nodes ausentes da última execução, também escritos no log ao fim de cada execução
+func (e *PbfProcess) GetMissingNodeReport() (report MissingNodeReport) {
+	return e.missingNodeReport
+}
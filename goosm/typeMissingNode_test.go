@@ -0,0 +1,145 @@
+package goosm
+
+import (
+	"fmt"
+	"github.com/qedus/osmpbf"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// testBatchDownload
+//
+// English:
+//
+// # Download api without network that finds the nodes of the map, implements InterfaceDownloadOsmNodes
+//
+// Português:
+//
+// Api de download sem rede que encontra os nodes do mapa, implementa InterfaceDownloadOsmNodes
+type testBatchDownload struct {
+	testDownload
+	nodes map[int64][2]float64
+	calls int
+}
+
+func (e *testBatchDownload) DownloadNodes(ids []int64) (nodes []Node, err error) {
+	e.calls++
+	for _, id := range ids {
+		if loc, found := e.nodes[id]; found {
+			nodes = append(nodes, Node{Id: id, Loc: loc})
+		}
+	}
+	return
+}
+
+// testMissingNodeSink
+//
+// English:
+//
+// # Keeps the missing nodes received, implements MissingNodeSink
+//
+// Português:
+//
+// Guarda os nodes ausentes recebidos, implementa MissingNodeSink
+type testMissingNodeSink struct {
+	sync.Mutex
+	list []string
+}
+
+func (e *testMissingNodeSink) MissingNode(wayID, nodeID int64) (err error) {
+	e.Lock()
+	e.list = append(e.list, fmt.Sprintf("%v/%v", wayID, nodeID))
+	e.Unlock()
+	return
+}
+
+// TestPbfProcess_SetMissingNodePolicy
+//
+// English:
+//
+// Imports three ways, the way 2 has the node 99 out of the file and the way 3 has only the nodes 98 and 99, out of
+// the file, with each policy.
+//
+// Português:
+//
+// Importa três ways, o way 2 tem o node 99 fora do arquivo e o way 3 tem apenas os nodes 98 e 99, fora do arquivo,
+// com cada política.
+func TestPbfProcess_SetMissingNodePolicy(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "missing.pbf")
+	var tags = map[string]string{"highway": "residential"}
+	err := writeTestPbf(path,
+		[]interface{}{
+			&osmpbf.Node{ID: 1, Lon: -48.0, Lat: -27.0},
+			&osmpbf.Node{ID: 2, Lon: -48.1, Lat: -27.0},
+			&osmpbf.Node{ID: 3, Lon: -48.2, Lat: -27.0},
+		},
+		[]interface{}{
+			&osmpbf.Way{ID: 1, NodeIDs: []int64{1, 2, 3}, Tags: tags},
+			&osmpbf.Way{ID: 2, NodeIDs: []int64{2, 3, 99}, Tags: tags},
+			&osmpbf.Way{ID: 3, NodeIDs: []int64{98, 99}, Tags: tags},
+		},
+	)
+	if err != nil {
+		t.Logf("writeTestPbf() error: %v", err)
+		t.FailNow()
+	}
+
+	for _, test := range []struct {
+		policy   MissingNodePolicy
+		ways     int
+		recorded int
+		report   MissingNodeReport
+	}{
+		{policy: MissingNodeSkip, ways: 2, report: MissingNodeReport{Nodes: 3, Ways: 2, DroppedWays: 1, SkippedNodes: 3}},
+		{policy: MissingNodeDropWay, ways: 1, report: MissingNodeReport{Nodes: 3, Ways: 2, DroppedWays: 2}},
+		{policy: MissingNodeRecord, ways: 1, recorded: 3, report: MissingNodeReport{Nodes: 3, Ways: 2, DroppedWays: 2, RecordedNodes: 3}},
+		{policy: MissingNodeDownload, ways: 3, report: MissingNodeReport{Nodes: 3, Ways: 2, DownloadedNodes: 2}},
+	} {
+		var database = newTestDatabase()
+		var nodeFile = &testNodeFile{}
+		nodeFile.Init(0)
+
+		var download = &testBatchDownload{nodes: map[int64][2]float64{98: {-48.3, -27.0}, 99: {-48.4, -27.0}}}
+		var sink = &testMissingNodeSink{}
+
+		var process = newTestPbfProcess(database, nodeFile)
+		process.SetDownloadApi(download)
+		process.SetMissingNodePolicy(test.policy)
+		process.SetMissingNodeSink(sink)
+
+		_, _, err = process.CompleteParser(path)
+		if err != nil {
+			t.Logf("%v: CompleteParser() error: %v", test.policy, err)
+			t.FailNow()
+		}
+
+		test.report.Policy = test.policy
+		if len(database.ways) != test.ways || len(sink.list) != test.recorded || process.GetMissingNodeReport() != test.report {
+			t.Logf("%v: error: %v ways, %v recorded, %v", test.policy, len(database.ways), sink.list, process.GetMissingNodeReport())
+			t.FailNow()
+		}
+
+		if test.policy == MissingNodeSkip && len(database.ways[2].Loc) != 2 {
+			t.Logf("skip: way 2 error: %v", database.ways[2].Loc)
+			t.FailNow()
+		}
+
+		if test.policy == MissingNodeDownload && (download.calls != 1 || database.ways[3].Loc[1] != [2]float64{-48.4, -27.0}) {
+			t.Logf("download: way 3 error: %v calls, %v", download.calls, database.ways[3].Loc)
+			t.FailNow()
+		}
+	}
+
+	var database = newTestDatabase()
+	var nodeFile = &testNodeFile{}
+	nodeFile.Init(0)
+
+	var process = newTestPbfProcess(database, nodeFile)
+	process.SetMissingNodePolicy(MissingNodeFail)
+	_, _, err = process.CompleteParser(path)
+	if err == nil {
+		t.Logf("CompleteParser() must fail with MissingNodeFail")
+		t.FailNow()
+	}
+}
@@ -40,9 +40,17 @@ type Node struct {
 	// Português: Point description tag (unnecessary values are deleted).
 	Tag map[string]string
 
+	// English: names by language, from the keys "name:xx", filled by a TagPolicy.
+	// Português: nomes por idioma, das chaves "name:xx", preenchido por uma TagPolicy.
+	International map[string]string
+
 	// English: geoJSon feature (GUI).
 	// Português: geoJSon feature (GUI).
 	GeoJSonFeature string
+
+	// English: metadata of the last edition, nil unless PbfProcess.SetMetadata(true) was called.
+	// Português: metadados da última edição, nil a menos que PbfProcess.SetMetadata(true) tenha sido chamado.
+	Metadata *Metadata
 }
 
 // String
@@ -353,3 +361,16 @@ func (e *Node) BoundingBox(meters float64) (box Box, err error) {
 	box.UpperRight, err = e.DestinationPoint(meters, angle.GetAsDegrees())
 	return
 }
+
+// Name
+//
+// English:
+//
+// # Returns the name of the node in the first language found, see Way.Name()
+//
+// Português:
+//
+// Devolve o nome do node no primeiro idioma encontrado, veja Way.Name()
+func (e *Node) Name(lang ...string) (name string) {
+	return tagName(e.Tag, e.International, lang...)
+}
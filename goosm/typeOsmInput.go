@@ -0,0 +1,568 @@
+package goosm
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/qedus/osmpbf"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// OsmFormat
+//
+// English:
+//
+// # Format of the open street maps file read by PbfProcess, returned by DetectOsmFormat()
+//
+// Português:
+//
+// Formato do arquivo do open street maps lido por PbfProcess, devolvido por DetectOsmFormat()
+type OsmFormat int
+
+const (
+
+	// OsmFormatPbf
+	//
+	// English:
+	//
+	// # Protocol buffer file, .osm.pbf
+	//
+	// Português:
+	//
+	// Arquivo protocol buffer, .osm.pbf
+	OsmFormatPbf OsmFormat = iota
+
+	// OsmFormatXml
+	//
+	// English:
+	//
+	// # OSM XML file, .osm, including the JOSM exports
+	//
+	// Português:
+	//
+	// Arquivo OSM XML, .osm, incluindo as exportações do JOSM
+	OsmFormatXml
+
+	// OsmFormatXmlGzip
+	//
+	// English:
+	//
+	// # OSM XML file compressed by gzip, .osm.gz
+	//
+	// Português:
+	//
+	// Arquivo OSM XML compactado pelo gzip, .osm.gz
+	OsmFormatXmlGzip
+
+	// OsmFormatXmlBzip2
+	//
+	// English:
+	//
+	// # OSM XML file compressed by bzip2, .osm.bz2
+	//
+	// Português:
+	//
+	// Arquivo OSM XML compactado pelo bzip2, .osm.bz2
+	OsmFormatXmlBzip2
+)
+
+// String
+//
+// English:
+//
+// # Returns the name of the format
+//
+// Português:
+//
+// Devolve o nome do formato
+func (e OsmFormat) String() string {
+	switch e {
+	case OsmFormatPbf:
+		return "pbf"
+	case OsmFormatXml:
+		return "xml"
+	case OsmFormatXmlGzip:
+		return "xml.gz"
+	case OsmFormatXmlBzip2:
+		return "xml.bz2"
+	}
+	return fmt.Sprintf("OsmFormat(%d)", int(e))
+}
+
+// DetectOsmFormat
+//
+// English:
+//
+// Returns the format of the open street maps file by the first bytes of the file or, when they are not conclusive, by
+// the file extension.
+//
+//	Note:
+//	  * gzip and bzip2 files are always read as OSM XML, the pbf file is already compressed.
+//
+// Português:
+//
+// Devolve o formato do arquivo do open street maps pelos primeiros bytes do arquivo ou, quando eles não são
+// conclusivos, pela extensão do arquivo.
+//
+//	Nota:
+//	  * Arquivos gzip e bzip2 são sempre lidos como OSM XML, o arquivo pbf já é compactado.
+func DetectOsmFormat(osmFilePath string) (format OsmFormat, err error) {
+	var osmFile *os.File
+	osmFile, err = os.Open(osmFilePath)
+	if err != nil {
+		err = fmt.Errorf("DetectOsmFormat().Open().Error: %v", err)
+		return
+	}
+	defer osmFile.Close()
+
+	format, err = detectOsmFormat(osmFile, osmFilePath)
+	if err != nil {
+		err = fmt.Errorf("DetectOsmFormat().Error: %v", err)
+	}
+	return
+}
+
+// detectOsmFormat
+//
+// English:
+//
+// # Same as DetectOsmFormat() over a file already open, reads the first bytes with ReadAt()
+//
+// Português:
+//
+// Igual a DetectOsmFormat() sobre um arquivo já aberto, lê os primeiros bytes com ReadAt()
+func detectOsmFormat(osmFile *os.File, osmFilePath string) (format OsmFormat, err error) {
+	var head = make([]byte, 512)
+	var n int
+	n, err = osmFile.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return
+	}
+	err = nil
+	head = head[:n]
+
+	switch {
+	case bytes.HasPrefix(head, []byte{0x1f, 0x8b}):
+		return OsmFormatXmlGzip, nil
+	case bytes.HasPrefix(head, []byte("BZh")):
+		return OsmFormatXmlBzip2, nil
+	case bytes.HasPrefix(bytes.TrimLeft(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf")), " \t\r\n"), []byte("<")):
+		return OsmFormatXml, nil
+	case bytes.Contains(head, []byte("OSMHeader")):
+		return OsmFormatPbf, nil
+	}
+
+	switch strings.ToLower(filepath.Ext(osmFilePath)) {
+	case ".pbf":
+		return OsmFormatPbf, nil
+	case ".osm", ".xml":
+		return OsmFormatXml, nil
+	case ".gz":
+		return OsmFormatXmlGzip, nil
+	case ".bz2":
+		return OsmFormatXmlBzip2, nil
+	}
+
+	err = fmt.Errorf("the format of the file %v is unknown", filepath.Base(osmFilePath))
+	return
+}
+
+// osmElementDecoder
+//
+// English:
+//
+// Decoder of the elements of the file, *osmpbf.Decoder or *osmXmlDecoder, Decode() returns *osmpbf.Node,
+// *osmpbf.Way or *osmpbf.Relation and io.EOF at the end of the file.
+//
+// Português:
+//
+// Decodificador dos elementos do arquivo, *osmpbf.Decoder ou *osmXmlDecoder, Decode() devolve *osmpbf.Node,
+// *osmpbf.Way ou *osmpbf.Relation e io.EOF no fim do arquivo.
+type osmElementDecoder interface {
+	Decode() (element interface{}, err error)
+}
+
+// newOsmFileDecoder
+//
+// English:
+//
+// Starts a decoder over the whole file, counting the bytes read in counters when it is not nil.
+//
+// Português:
+//
+// Inicia um decoder sobre o arquivo inteiro, contando os bytes lidos em counters quando ele não é nil.
+func newOsmFileDecoder(osmFile *os.File, format OsmFormat, counters *progressCounters) (osmDecoder osmElementDecoder, err error) {
+	var reader io.Reader = osmFile
+	if counters != nil {
+		reader = &progressReader{reader: osmFile, counters: counters}
+	}
+
+	if format != OsmFormatPbf {
+		return newOsmXmlDecoder(reader, format)
+	}
+
+	var pbfDecoder = osmpbf.NewDecoder(reader)
+
+	// use more memory from the start, it is faster
+	pbfDecoder.SetBufferSize(osmpbf.MaxBlobSize)
+
+	// start decoding with several goroutines, it is faster
+	err = pbfDecoder.Start(runtime.GOMAXPROCS(-1))
+	osmDecoder = pbfDecoder
+	return
+}
+
+// osmXmlTag
+//
+// English:
+//
+// # Tag of an element of the OSM XML file
+//
+// Português:
+//
+// Tag de um elemento do arquivo OSM XML
+type osmXmlTag struct {
+	K string `xml:"k,attr"`
+	V string `xml:"v,attr"`
+}
+
+// osmXmlInfo
+//
+// English:
+//
+// Attributes common to the elements of the OSM XML file, action is written by JOSM for the elements changed in the
+// editor.
+//
+// Português:
+//
+// Atributos comuns aos elementos do arquivo OSM XML, action é escrito pelo JOSM para os elementos alterados no editor.
+type osmXmlInfo struct {
+	ID        int64       `xml:"id,attr"`
+	Visible   string      `xml:"visible,attr"`
+	Action    string      `xml:"action,attr"`
+	Version   int32       `xml:"version,attr"`
+	Uid       int32       `xml:"uid,attr"`
+	User      string      `xml:"user,attr"`
+	Changeset int64       `xml:"changeset,attr"`
+	Timestamp string      `xml:"timestamp,attr"`
+	Tag       []osmXmlTag `xml:"tag"`
+}
+
+// info
+//
+// English:
+//
+// Converts the attributes into osmpbf.Info, an element without the visible attribute is visible and the elements
+// deleted in JOSM are not.
+//
+// Português:
+//
+// Converte os atributos em osmpbf.Info, um elemento sem o atributo visible é visível e os elementos apagados no JOSM
+// não são.
+func (e osmXmlInfo) info() (info osmpbf.Info) {
+	info.Version = e.Version
+	info.Uid = e.Uid
+	info.User = e.User
+	info.Changeset = e.Changeset
+	info.Timestamp, _ = time.Parse(time.RFC3339, e.Timestamp)
+	info.Visible = e.Visible != "false" && e.Action != "delete"
+	return
+}
+
+// tags
+//
+// English:
+//
+// # Returns the tags as a map, as the pbf decoder does
+//
+// Português:
+//
+// Devolve as tags como um mapa, como o decoder pbf faz
+func (e osmXmlInfo) tags() (tags map[string]string) {
+	tags = make(map[string]string, len(e.Tag))
+	for _, tag := range e.Tag {
+		tags[tag.K] = tag.V
+	}
+	return
+}
+
+// osmXmlNode
+//
+// English:
+//
+// # Node of the OSM XML file
+//
+// Português:
+//
+// Node do arquivo OSM XML
+type osmXmlNode struct {
+	osmXmlInfo
+	Lat float64 `xml:"lat,attr"`
+	Lon float64 `xml:"lon,attr"`
+}
+
+// osmXmlWay
+//
+// English:
+//
+// # Way of the OSM XML file
+//
+// Português:
+//
+// Way do arquivo OSM XML
+type osmXmlWay struct {
+	osmXmlInfo
+	NodeIdList []struct {
+		Ref int64 `xml:"ref,attr"`
+	} `xml:"nd"`
+}
+
+// osmXmlRelation
+//
+// English:
+//
+// # Relation of the OSM XML file
+//
+// Português:
+//
+// Relation do arquivo OSM XML
+type osmXmlRelation struct {
+	osmXmlInfo
+	Member []struct {
+		Type string `xml:"type,attr"`
+		Ref  int64  `xml:"ref,attr"`
+		Role string `xml:"role,attr"`
+	} `xml:"member"`
+}
+
+// osmXmlDecoder
+//
+// English:
+//
+// Streaming decoder of the OSM XML file, reads one element at a time with the tokens of encoding/xml and returns the
+// same elements of the pbf decoder.
+//
+//	Note:
+//	  * The elements must be in the order of the OSM XML files, nodes, ways and relations, as the node file is
+//	    finalized before the first way.
+//
+// Português:
+//
+// Decoder em fluxo do arquivo OSM XML, lê um elemento de cada vez com os tokens do encoding/xml e devolve os mesmos
+// elementos do decoder pbf.
+//
+//	Nota:
+//	  * Os elementos devem estar na ordem dos arquivos OSM XML, nodes, ways e relations, já que o arquivo de nodes é
+//	    finalizado antes do primeiro way.
+type osmXmlDecoder struct {
+	decoder *xml.Decoder
+	closer  io.Closer
+
+	// English: 0 for nodes, 1 for ways and 2 for relations, the greatest type already decoded
+	// Português: 0 para nodes, 1 para ways e 2 para relations, o maior tipo já decodificado
+	order int
+
+	// English: error returned by all the calls after the end of the file, an error or stop()
+	// Português: erro devolvido por todas as chamadas depois do fim do arquivo, de um erro ou de stop()
+	err error
+}
+
+// newOsmXmlDecoder
+//
+// English:
+//
+// # Starts the decoder of the OSM XML file, decompressing the reader for OsmFormatXmlGzip and OsmFormatXmlBzip2
+//
+// Português:
+//
+// Inicia o decoder do arquivo OSM XML, descompactando o leitor para OsmFormatXmlGzip e OsmFormatXmlBzip2
+func newOsmXmlDecoder(reader io.Reader, format OsmFormat) (osmDecoder *osmXmlDecoder, err error) {
+	osmDecoder = new(osmXmlDecoder)
+	reader = bufio.NewReaderSize(reader, 1024*1024)
+
+	switch format {
+	case OsmFormatXmlGzip:
+		var gzipReader *gzip.Reader
+		gzipReader, err = gzip.NewReader(reader)
+		if err != nil {
+			err = fmt.Errorf("gzip.NewReader().Error: %v", err)
+			return
+		}
+		osmDecoder.closer = gzipReader
+		reader = gzipReader
+
+	case OsmFormatXmlBzip2:
+		reader = bzip2.NewReader(reader)
+
+	case OsmFormatXml:
+
+	default:
+		err = fmt.Errorf("%v is not an OSM XML format", format)
+		return
+	}
+
+	osmDecoder.decoder = xml.NewDecoder(reader)
+	return
+}
+
+// Decode
+//
+// English:
+//
+// # Returns the next node, way or relation of the file, or io.EOF at the end of the file
+//
+// Português:
+//
+// Devolve o próximo node, way ou relation do arquivo, ou io.EOF no fim do arquivo
+func (e *osmXmlDecoder) Decode() (element interface{}, err error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	element, err = e.decode()
+	if err != nil {
+		e.stop()
+		if err != io.EOF {
+			err = fmt.Errorf("line %v: %v", e.line(), err)
+		}
+		e.err = err
+	}
+	return
+}
+
+// decode
+//
+// English:
+//
+// # Reads the tokens up to the start of the next element and decodes it
+//
+// Português:
+//
+// Lê os tokens até o início do próximo elemento e o decodifica
+func (e *osmXmlDecoder) decode() (element interface{}, err error) {
+	for {
+		var token xml.Token
+		token, err = e.decoder.Token()
+		if err != nil {
+			return
+		}
+
+		start, isStart := token.(xml.StartElement)
+		if !isStart {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "node":
+			var node osmXmlNode
+			err = e.element(0, &node, start)
+			if err != nil {
+				return
+			}
+			return &osmpbf.Node{ID: node.ID, Lat: node.Lat, Lon: node.Lon, Tags: node.tags(), Info: node.info()}, nil
+
+		case "way":
+			var way osmXmlWay
+			err = e.element(1, &way, start)
+			if err != nil {
+				return
+			}
+
+			var nodeIDs = make([]int64, len(way.NodeIdList))
+			for key, nodeRef := range way.NodeIdList {
+				nodeIDs[key] = nodeRef.Ref
+			}
+			return &osmpbf.Way{ID: way.ID, Tags: way.tags(), NodeIDs: nodeIDs, Info: way.info()}, nil
+
+		case "relation":
+			var relation osmXmlRelation
+			err = e.element(2, &relation, start)
+			if err != nil {
+				return
+			}
+
+			var members = make([]osmpbf.Member, len(relation.Member))
+			for key, member := range relation.Member {
+				members[key] = osmpbf.Member{ID: member.Ref, Role: member.Role}
+				switch member.Type {
+				case "node":
+					members[key].Type = osmpbf.NodeType
+				case "way":
+					members[key].Type = osmpbf.WayType
+				case "relation":
+					members[key].Type = osmpbf.RelationType
+				default:
+					err = fmt.Errorf("relation %v: unexpected member type %q", relation.ID, member.Type)
+					return
+				}
+			}
+			return &osmpbf.Relation{ID: relation.ID, Tags: relation.tags(), Members: members, Info: relation.info()}, nil
+		}
+
+		// English: osm, bounds, note, meta and the elements of the changes are only containers or metadata
+		// Português: osm, bounds, note, meta e os elementos das alterações são apenas contêineres ou metadados
+	}
+}
+
+// element
+//
+// English:
+//
+// # Decodes the element, checking the order of nodes, ways and relations
+//
+// Português:
+//
+// Decodifica o elemento, verificando a ordem de nodes, ways e relations
+func (e *osmXmlDecoder) element(order int, value interface{}, start xml.StartElement) (err error) {
+	if order < e.order {
+		err = fmt.Errorf("%v after the %v, the elements must be sorted as nodes, ways and relations",
+			start.Name.Local, []string{"nodes", "ways", "relations"}[e.order])
+		return
+	}
+	e.order = order
+
+	return e.decoder.DecodeElement(value, &start)
+}
+
+// line
+//
+// English:
+//
+// # Returns the line of the file being read, for the error messages
+//
+// Português:
+//
+// Devolve a linha do arquivo sendo lida, para as mensagens de erro
+func (e *osmXmlDecoder) line() int {
+	line, _ := e.decoder.InputPos()
+	return line
+}
+
+// stop
+//
+// English:
+//
+// Stops the reading, the next calls to Decode() return an error without reading the rest of the file.
+//
+// Português:
+//
+// Para a leitura, as próximas chamadas a Decode() devolvem um erro sem ler o resto do arquivo.
+func (e *osmXmlDecoder) stop() {
+	if e.err == nil {
+		e.err = errors.New("osmXmlDecoder: stopped")
+	}
+
+	if e.closer != nil {
+		_ = e.closer.Close()
+		e.closer = nil
+	}
+}
@@ -0,0 +1,136 @@
+package goosm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testOsmXmlGrid
+//
+// English:
+//
+// Writes the grid of testPbfGrid() as OSM XML, in the style of a JOSM export, with an extra node deleted in the editor.
+//
+// Português:
+//
+// Escreve a grade de testPbfGrid() como OSM XML, no estilo de uma exportação do JOSM, com um node extra apagado no
+// editor.
+func testOsmXmlGrid(totalOfNodes int) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("<?xml version='1.0' encoding='UTF-8'?>\n<osm version='0.6' upload='false' generator='JOSM'>\n")
+	buffer.WriteString("  <bounds minlat='-27.0' minlon='-48.0' maxlat='-26.9' maxlon='-47.9' origin='test' />\n")
+
+	for id := 1; id <= totalOfNodes; id++ {
+		fmt.Fprintf(&buffer, "  <node id='%v' version='1' lat='%v' lon='%v'>\n    <tag k='amenity' v='bench' />\n  </node>\n",
+			id, -27.0+float64(id/100)*0.001, -48.0+float64(id%100)*0.001)
+	}
+	fmt.Fprintf(&buffer, "  <node id='-1' action='delete' visible='true' lat='-27.0' lon='-48.0'>\n    <tag k='amenity' v='bench' />\n  </node>\n")
+
+	for id := 1; id < totalOfNodes; id++ {
+		fmt.Fprintf(&buffer, "  <way id='%v' version='1'>\n    <nd ref='%v' />\n    <nd ref='%v' />\n    <tag k='highway' v='residential' />\n  </way>\n",
+			id, id, id+1)
+	}
+	fmt.Fprintf(&buffer, "  <way id='%v'>\n    <nd ref='4' />\n    <nd ref='1' />\n    <tag k='barrier' v='fence' />\n  </way>\n", totalOfNodes)
+
+	fmt.Fprintf(&buffer, "  <relation id='1' visible='true'>\n")
+	for _, id := range []int{1, 2, 3, totalOfNodes} {
+		fmt.Fprintf(&buffer, "    <member type='way' ref='%v' role='outer' />\n", id)
+	}
+	buffer.WriteString("    <tag k='type' v='multipolygon' />\n    <tag k='landuse' v='grass' />\n  </relation>\n</osm>\n")
+	return buffer.Bytes()
+}
+
+// TestPbfProcess_OsmXml
+//
+// English:
+//
+// # Imports the grid from OSM XML, plain and compressed by gzip, with the three import modes
+//
+// Português:
+//
+// Importa a grade a partir de OSM XML, puro e compactado pelo gzip, com os três modos de importação
+func TestPbfProcess_OsmXml(t *testing.T) {
+	var dir = t.TempDir()
+	var data = testOsmXmlGrid(200)
+
+	var compressed bytes.Buffer
+	var writer = gzip.NewWriter(&compressed)
+	_, _ = writer.Write(data)
+	_ = writer.Close()
+
+	for _, test := range []struct {
+		name   string
+		data   []byte
+		format OsmFormat
+	}{
+		{name: "grid.osm", data: data, format: OsmFormatXml},
+		{name: "grid.osm.gz", data: compressed.Bytes(), format: OsmFormatXmlGzip},
+		{name: "grid.data", data: compressed.Bytes(), format: OsmFormatXmlGzip},
+	} {
+		var path = filepath.Join(dir, test.name)
+		err := os.WriteFile(path, test.data, 0644)
+		if err != nil {
+			t.Logf("WriteFile() error: %v", err)
+			t.FailNow()
+		}
+
+		format, err := DetectOsmFormat(path)
+		if err != nil || format != test.format {
+			t.Logf("%v: DetectOsmFormat() error: %v, %v", test.name, format, err)
+			t.FailNow()
+		}
+
+		var database = newTestDatabase()
+		var nodeFile = &testNodeFile{}
+		nodeFile.Init(0)
+
+		nodes, ways, err := newTestPbfProcess(database, nodeFile).CompleteParser(path)
+		if err != nil {
+			t.Logf("%v: CompleteParser() error: %v", test.name, err)
+			t.FailNow()
+		}
+
+		if nodes != 201 || ways != 200 || len(database.nodes) != 200 || len(database.ways) != 200 || len(database.polygons) != 1 {
+			t.Logf("%v: totals error: %v, %v, database %v, %v, %v", test.name, nodes, ways, len(database.nodes), len(database.ways), len(database.polygons))
+			t.FailNow()
+		}
+
+		if database.ways[199].Loc[1] != [2]float64{-48.0, -27.0 + 0.002} {
+			t.Logf("%v: way 199 coordinates error: %v", test.name, database.ways[199].Loc)
+			t.FailNow()
+		}
+
+		database = newTestDatabase()
+		_, _, err = newTestPbfProcess(database, nodeFile).DatabaseOnly(path)
+		if err != nil || len(database.ways) != 200 {
+			t.Logf("%v: DatabaseOnly() error: %v, %v ways", test.name, err, len(database.ways))
+			t.FailNow()
+		}
+
+		nodeFile.Init(0)
+		_, _, err = newTestPbfProcess(database, nodeFile).BinaryNodeOnlyParser(path)
+		if err != nil || len(nodeFile.nodes) != 201 || nodeFile.mounted != 2 {
+			t.Logf("%v: BinaryNodeOnlyParser() error: %v, %v nodes", test.name, err, len(nodeFile.nodes))
+			t.FailNow()
+		}
+	}
+
+	var path = filepath.Join(dir, "unsorted.osm")
+	err := os.WriteFile(path, []byte(`<osm><way id="1"><nd ref="1"/></way><node id="1" lat="0" lon="0"/></osm>`), 0644)
+	if err != nil {
+		t.Logf("WriteFile() error: %v", err)
+		t.FailNow()
+	}
+
+	var nodeFile = &testNodeFile{}
+	nodeFile.Init(0)
+	_, _, err = newTestPbfProcess(newTestDatabase(), nodeFile).CompleteParser(path)
+	if err == nil {
+		t.Logf("CompleteParser() must fail with a node after the ways")
+		t.FailNow()
+	}
+}
@@ -0,0 +1,744 @@
+package goosm
+
+import (
+	"context"
+	"fmt"
+	"github.com/qedus/osmpbf"
+	"runtime"
+	"sync"
+)
+
+// PipelineOrdering
+//
+// English:
+//
+// # Order in which the resolved elements are grouped into the database batches, defined by SetOrdering()
+//
+// Português:
+//
+// Ordem em que os elementos resolvidos são agrupados nos lotes do banco de dados, definida por SetOrdering()
+type PipelineOrdering int
+
+const (
+
+	// OrderingFile
+	//
+	// English:
+	//
+	// The batches are assembled in the order of the pbf file, the results that arrive early wait for the previous ones.
+	// Default value, always used when the way store is defined, because it requires ascending IDs.
+	//
+	// Português:
+	//
+	// Os lotes são montados na ordem do arquivo pbf, os resultados que chegam antes esperam pelos anteriores.
+	// Valor padrão, sempre usado quando o arquivo de ways é definido, porque ele exige IDs em ordem crescente.
+	OrderingFile PipelineOrdering = iota
+
+	// OrderingNone
+	//
+	// English:
+	//
+	// # The batches are assembled in the order the resolvers finish, faster when the resolution time varies a lot
+	//
+	// Português:
+	//
+	// Os lotes são montados na ordem em que os resolvedores terminam, mais rápido quando o tempo de resolução varia muito
+	OrderingNone
+)
+
+// PipelineDefaultBatchSize
+//
+// English:
+//
+// # Number of elements of each SetMany() when SetBatchSize() is not called
+//
+// Português:
+//
+// Quantidade de elementos de cada SetMany() quando SetBatchSize() não é chamada
+const PipelineDefaultBatchSize = 100
+
+// pbfJob
+//
+// English:
+//
+// Element of the pbf file waiting for a resolver, seq is its position in the pipeline and wayStoreOnly marks the ways
+// rejected by the tag filter, written only into the way store for the relations.
+//
+// Português:
+//
+// Elemento do arquivo pbf esperando por um resolvedor, seq é sua posição no pipeline e wayStoreOnly marca os ways
+// rejeitados pelo filtro de tags, escritos apenas no arquivo de ways para as relations.
+type pbfJob struct {
+	seq          uint64
+	element      interface{}
+	wayStoreOnly bool
+}
+
+// pbfResult
+//
+// English:
+//
+// # Element resolved, all fields are nil when the element is not inserted into the database
+//
+// Português:
+//
+// Elemento resolvido, todos os campos são nil quando o elemento não é inserido no banco de dados
+type pbfResult struct {
+	seq          uint64
+	node         *Node
+	way          *Way
+	wayStoreOnly bool
+	polygon      *PolygonList
+
+	// English: whole coordinates of a way cut by the clip area, written into the way store
+	// Português: coordenadas inteiras de um way cortado pela área de recorte, escritas no arquivo de ways
+	storeLoc [][2]float64
+
+	// English: way held back by MissingNodeDownload, out of the order of the way store
+	// Português: way retido por MissingNodeDownload, fora da ordem do arquivo de ways
+	deferred bool
+}
+
+// pbfBatch
+//
+// English:
+//
+// # Batch waiting for a writer, only one of the lists is filled
+//
+// Português:
+//
+// Lote esperando por um escritor, apenas uma das listas é preenchida
+type pbfBatch struct {
+	nodes    []Node
+	ways     []Way
+	polygons []PolygonList
+}
+
+// pbfPipeline
+//
+// English:
+//
+// Stages of the import after the decoder, all connected by bounded channels, so a slow stage holds back the previous
+// ones:
+//
+//	decoder -> jobs -> N resolvers -> results -> collector -> batches -> M writers
+//
+// The resolvers assemble the nodes, ways and polygons, the collector restores the order of the file, writes the way
+// store and groups the results into batches, and the writers call SetMany().
+//
+// A pipeline is not reused, stop() waits for all the elements sent to be in the database and the run starts a new one
+// when it continues, at each checkpoint and phase change.
+//
+// Português:
+//
+// Estágios da importação depois do decoder, todos ligados por canais limitados, assim um estágio lento segura os
+// anteriores:
+//
+//	decoder -> jobs -> N resolvedores -> results -> coletor -> batches -> M escritores
+//
+// Os resolvedores montam os nodes, ways e polígonos, o coletor restaura a ordem do arquivo, escreve o arquivo de ways e
+// agrupa os resultados em lotes, e os escritores chamam SetMany().
+//
+// Um pipeline não é reutilizado, stop() espera todos os elementos enviados estarem no banco de dados e a execução inicia
+// um novo quando continua, a cada checkpoint e mudança de fase.
+type pbfPipeline struct {
+	process   *PbfProcess
+	run       *pbfRun
+	ordered   bool
+	batchSize int
+
+	// English: canceled by the first error, the stages stop working and only drain their channels
+	// Português: cancelado pelo primeiro erro, os estágios param de trabalhar e apenas esvaziam seus canais
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	jobs    chan pbfJob
+	results chan pbfResult
+	batches chan pbfBatch
+
+	// English: when ordered, one slot for each element sent and not yet added to a batch, so send() blocks while the
+	// collector waits for an element late and the results that arrive early are limited to the size of the window
+	// Português: quando ordenado, uma vaga para cada elemento enviado e ainda não adicionado a um lote, assim send()
+	// bloqueia enquanto o coletor espera por um elemento atrasado e os resultados que chegam antes ficam limitados ao
+	// tamanho da janela
+	window chan struct{}
+
+	// English: closed when the collector and all the writers have returned
+	// Português: fechado quando o coletor e todos os escritores retornaram
+	done chan struct{}
+
+	// English: next position, used only by the goroutine of the decoder
+	// Português: próxima posição, usada apenas pela goroutine do decoder
+	seq uint64
+
+	mutex sync.Mutex
+	err   error
+}
+
+// startPipeline
+//
+// English:
+//
+// # Starts the goroutines of a new pipeline for the run
+//
+// Português:
+//
+// Inicia as goroutines de um novo pipeline para a execução
+func (e *PbfProcess) startPipeline(run *pbfRun) {
+	var resolvers, writers, batchSize = e.pipelineConfig()
+
+	var pipeline = &pbfPipeline{
+		process:   e,
+		run:       run,
+		ordered:   e.ordering == OrderingFile || run.useWayStore,
+		batchSize: batchSize,
+		jobs:      make(chan pbfJob, 2*resolvers),
+		results:   make(chan pbfResult, 2*resolvers),
+		batches:   make(chan pbfBatch, writers),
+		done:      make(chan struct{}),
+	}
+	if pipeline.ordered {
+		pipeline.window = make(chan struct{}, 2*resolvers)
+	}
+	pipeline.ctx, pipeline.cancel = context.WithCancel(context.Background())
+
+	var resolving sync.WaitGroup
+	resolving.Add(resolvers)
+	for i := 0; i < resolvers; i++ {
+		go func() {
+			defer resolving.Done()
+			pipeline.resolve()
+		}()
+	}
+
+	go func() {
+		resolving.Wait()
+		close(pipeline.results)
+	}()
+
+	var writing sync.WaitGroup
+	writing.Add(writers + 1)
+	go func() {
+		defer writing.Done()
+		pipeline.collect()
+	}()
+	for i := 0; i < writers; i++ {
+		go func() {
+			defer writing.Done()
+			pipeline.write()
+		}()
+	}
+
+	go func() {
+		writing.Wait()
+		close(pipeline.done)
+	}()
+
+	run.pipeline = pipeline
+}
+
+// flushPipeline
+//
+// English:
+//
+// # Waits for all the elements sent to be in the database and starts a new pipeline
+//
+// Português:
+//
+// Espera todos os elementos enviados estarem no banco de dados e inicia um novo pipeline
+func (e *PbfProcess) flushPipeline(run *pbfRun) (err error) {
+	if run.pipeline == nil {
+		return
+	}
+
+	err = e.stopPipeline(run)
+	if err != nil {
+		return
+	}
+
+	e.startPipeline(run)
+	return
+}
+
+// stopPipeline
+//
+// English:
+//
+// # Waits for all the elements sent to be in the database and stops the pipeline
+//
+// Português:
+//
+// Espera todos os elementos enviados estarem no banco de dados e para o pipeline
+func (e *PbfProcess) stopPipeline(run *pbfRun) (err error) {
+	if run.pipeline == nil {
+		return
+	}
+
+	err = run.pipeline.stop()
+	run.pipeline = nil
+	return
+}
+
+// send
+//
+// English:
+//
+// Sends an element to the resolvers, blocking while the pipeline or the window of the ordered mode is full, and
+// returns the error of the pipeline when one of its stages failed.
+//
+// Português:
+//
+// Envia um elemento aos resolvedores, bloqueando enquanto o pipeline ou a janela do modo ordenado está cheio, e
+// devolve o erro do pipeline quando um dos seus estágios falhou.
+func (e *pbfPipeline) send(element interface{}, wayStoreOnly bool) (err error) {
+	if e.ctx.Err() != nil {
+		return e.error()
+	}
+
+	if e.window != nil {
+		select {
+		case e.window <- struct{}{}:
+		case <-e.ctx.Done():
+			return e.error()
+		}
+	}
+
+	select {
+	case e.jobs <- pbfJob{seq: e.seq, element: element, wayStoreOnly: wayStoreOnly}:
+		e.seq++
+	case <-e.ctx.Done():
+		err = e.error()
+	}
+	return
+}
+
+// stop
+//
+// English:
+//
+// Closes the entry of the pipeline and waits for the stages to finish, the incomplete batches are written at the end.
+//
+// Português:
+//
+// Fecha a entrada do pipeline e espera os estágios terminarem, os lotes incompletos são escritos no fim.
+func (e *pbfPipeline) stop() (err error) {
+	close(e.jobs)
+	<-e.done
+	e.cancel()
+	return e.error()
+}
+
+// abort
+//
+// English:
+//
+// # Stops the pipeline without writing the elements still waiting, used when the run fails
+//
+// Português:
+//
+// Para o pipeline sem escrever os elementos ainda esperando, usado quando a execução falha
+func (e *pbfPipeline) abort() {
+	e.cancel()
+	close(e.jobs)
+	<-e.done
+}
+
+// fail
+//
+// English:
+//
+// # Keeps the first error and cancels the pipeline
+//
+// Português:
+//
+// Guarda o primeiro erro e cancela o pipeline
+func (e *pbfPipeline) fail(err error) {
+	e.mutex.Lock()
+	if e.err == nil {
+		e.err = err
+	}
+	e.mutex.Unlock()
+
+	e.cancel()
+}
+
+// error
+//
+// English:
+//
+// # Returns the first error of the pipeline
+//
+// Português:
+//
+// Devolve o primeiro erro do pipeline
+func (e *pbfPipeline) error() (err error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.err
+}
+
+// resolve
+//
+// English:
+//
+// # Resolver stage, assembles the elements received
+//
+// Português:
+//
+// Estágio resolvedor, monta os elementos recebidos
+func (e *pbfPipeline) resolve() {
+	for job := range e.jobs {
+		if e.ctx.Err() != nil {
+			continue
+		}
+
+		result, err := e.process.resolveElement(e.run, job)
+		if err != nil {
+			e.fail(err)
+			continue
+		}
+
+		select {
+		case e.results <- result:
+		case <-e.ctx.Done():
+		}
+	}
+}
+
+// collect
+//
+// English:
+//
+// Collector stage, restores the order of the file when ordered, writes the way store and groups the results into
+// batches.
+//
+// Português:
+//
+// Estágio coletor, restaura a ordem do arquivo quando ordenado, escreve o arquivo de ways e agrupa os resultados em
+// lotes.
+func (e *pbfPipeline) collect() {
+	defer close(e.batches)
+
+	var batch pbfBatch
+	var pending = make(map[uint64]pbfResult)
+	var next uint64
+
+	for result := range e.results {
+		if e.ctx.Err() != nil {
+			continue
+		}
+
+		if !e.ordered {
+			e.add(&batch, result)
+			continue
+		}
+
+		pending[result.seq] = result
+		for {
+			ready, found := pending[next]
+			if !found {
+				break
+			}
+
+			delete(pending, next)
+			next++
+			<-e.window
+			e.add(&batch, ready)
+		}
+	}
+
+	if e.ctx.Err() != nil {
+		return
+	}
+
+	e.dispatch(pbfBatch{nodes: batch.nodes})
+	e.dispatch(pbfBatch{ways: batch.ways})
+	e.dispatch(pbfBatch{polygons: batch.polygons})
+}
+
+// add
+//
+// English:
+//
+// # Adds a result to the batches, sending the batch full to the writers
+//
+// Português:
+//
+// Adiciona um resultado aos lotes, enviando o lote cheio aos escritores
+func (e *pbfPipeline) add(batch *pbfBatch, result pbfResult) {
+	switch {
+	case result.node != nil:
+		batch.nodes = append(batch.nodes, *result.node)
+		if len(batch.nodes) == e.batchSize {
+			e.dispatch(pbfBatch{nodes: batch.nodes})
+			batch.nodes = nil
+		}
+
+	case result.way != nil:
+		if e.run.useWayStore && !result.deferred {
+			var loc = result.way.Loc
+			if result.storeLoc != nil {
+				loc = result.storeLoc
+			}
+
+			err := e.process.wayStore.WriteWayCoordinates(result.way.Id, loc)
+			if err != nil {
+				e.fail(fmt.Errorf("PbfProcess.%v().WriteWayCoordinates().Error: %v", e.run.name, err))
+				return
+			}
+		}
+
+		if result.wayStoreOnly {
+			return
+		}
+
+		batch.ways = append(batch.ways, *result.way)
+		if len(batch.ways) == e.batchSize {
+			e.dispatch(pbfBatch{ways: batch.ways})
+			batch.ways = nil
+		}
+
+	case result.polygon != nil:
+		batch.polygons = append(batch.polygons, *result.polygon)
+		if len(batch.polygons) == e.batchSize {
+			e.dispatch(pbfBatch{polygons: batch.polygons})
+			batch.polygons = nil
+		}
+	}
+}
+
+// dispatch
+//
+// English:
+//
+// # Sends a batch to the writers, empty batches are ignored
+//
+// Português:
+//
+// Envia um lote aos escritores, lotes vazios são ignorados
+func (e *pbfPipeline) dispatch(batch pbfBatch) {
+	if len(batch.nodes) == 0 && len(batch.ways) == 0 && len(batch.polygons) == 0 {
+		return
+	}
+
+	select {
+	case e.batches <- batch:
+	case <-e.ctx.Done():
+	}
+}
+
+// write
+//
+// English:
+//
+// # Writer stage, inserts the batches into the database
+//
+// Português:
+//
+// Estágio escritor, insere os lotes no banco de dados
+func (e *pbfPipeline) write() {
+	for batch := range e.batches {
+		if e.ctx.Err() != nil {
+			continue
+		}
+
+		err := e.process.writeBatch(e.run, batch)
+		if err != nil {
+			e.fail(err)
+		}
+	}
+}
+
+// pipelineConfig
+//
+// English:
+//
+// # Returns the number of resolvers, writers and the batch size, with the default values for the fields not defined
+//
+// Português:
+//
+// Devolve a quantidade de resolvedores, escritores e o tamanho do lote, com os valores padrão para os campos não
+// definidos
+func (e *PbfProcess) pipelineConfig() (resolvers, writers, batchSize int) {
+	resolvers = e.resolvers
+	if resolvers <= 0 {
+		resolvers = runtime.GOMAXPROCS(-1)
+	}
+
+	writers = e.writers
+	if writers <= 0 {
+		writers = 1
+	}
+
+	batchSize = e.batchSize
+	if batchSize <= 0 {
+		batchSize = PipelineDefaultBatchSize
+	}
+	return
+}
+
+// resolveElement
+//
+// English:
+//
+// Assembles one element of the pbf file, the node or way with its GeoJSON and the polygon of the relation.
+//
+// Português:
+//
+// Monta um elemento do arquivo pbf, o node ou way com seu GeoJSON e o polígono da relation.
+func (e *PbfProcess) resolveElement(run *pbfRun, job pbfJob) (result pbfResult, err error) {
+	result.seq = job.seq
+
+	switch converted := job.element.(type) {
+	case *osmpbf.Node:
+		var node = Node{}
+		if e.tagPolicy != nil {
+			// English: the policy replaces the default drop of Init(), it is applied after Init() without tags
+			// Português: a política substitui o descarte padrão de Init(), ela é aplicada depois de Init() sem tags
+			node.Init(converted.ID, converted.Lon, converted.Lat, nil)
+			node.Tag, node.International = e.tagPolicy.Apply(converted.Tags)
+		} else {
+			var tags = converted.Tags
+			node.Init(converted.ID, converted.Lon, converted.Lat, &tags)
+		}
+		if e.metadata {
+			node.Metadata = newMetadata(converted.Info)
+		}
+		node.MakeGeoJSonFeature()
+		if len(node.Tag) != 0 {
+			result.node = &node
+		}
+
+	case *osmpbf.Way:
+		var way Way
+		var keep bool
+		way, keep, err = e.wayFromPbf(run, converted, job.wayStoreOnly)
+		if err != nil || !keep {
+			return
+		}
+
+		result.wayStoreOnly = job.wayStoreOnly
+		err = e.finishWay(run, &result, way)
+
+	case *pbfDeferredWay:
+		result.deferred = true
+		err = e.finishWay(run, &result, converted.way)
+
+	case *osmpbf.Relation:
+		var polygon PolygonList
+		var isArea bool
+		polygon, isArea, err = e.relationToPolygonList(run, converted)
+		if err != nil {
+			err = fmt.Errorf("PbfProcess.%v().relationToPolygonList().Error: %v", run.name, err)
+			return
+		}
+
+		if isArea && (e.clip == nil || e.clip.intersectsPolygonList(&polygon)) {
+			result.polygon = &polygon
+		}
+	}
+	return
+}
+
+// finishWay
+//
+// English:
+//
+// # Applies the clip area to the way with all its coordinates and makes its GeoJSON
+//
+// Português:
+//
+// Aplica a área de recorte ao way com todas as suas coordenadas e monta seu GeoJSON
+func (e *PbfProcess) finishWay(run *pbfRun, result *pbfResult, way Way) (err error) {
+	if e.clip != nil && !result.wayStoreOnly {
+		var loc, pieces, keep, cut = e.clip.clipWay(way.Loc, e.clipMode)
+		if !keep {
+			if result.deferred || !e.wayStoreForRelations(run) {
+				return
+			}
+			result.wayStoreOnly = true
+		} else if cut {
+			// English: the way store keeps the whole way, for the relations
+			// Português: o arquivo de ways mantém o way inteiro, para as relations
+			result.storeLoc = way.Loc
+			way.Loc = loc
+			if len(pieces) > 1 {
+				way.Pieces = pieces
+			}
+		}
+	}
+
+	if !result.wayStoreOnly {
+		// English: the policy replaces the default drop of Init(), it is applied after Init() without tags
+		// Português: a política substitui o descarte padrão de Init(), ela é aplicada depois de Init() sem tags
+		var tags = way.Tag
+		if e.tagPolicy != nil {
+			way.Tag = nil
+		}
+
+		err = way.Init()
+		if err != nil {
+			way.Tag = tags
+			err = e.elementError(run, "way", way.Id, ErrorReasonInvalidWay, err, way)
+			if err != nil {
+				err = fmt.Errorf("PbfProcess.%v().Init().Error: %v", run.name, err)
+			}
+			return
+		}
+
+		if e.tagPolicy != nil {
+			way.Tag, way.International = e.tagPolicy.Apply(tags)
+			way.IsPolygon = way.isPolygon()
+		}
+		way.MakeGeoJSonFeature()
+	}
+
+	result.way = &way
+	return
+}
+
+// writeBatch
+//
+// English:
+//
+// # Inserts a batch into the database
+//
+// Português:
+//
+// Insere um lote no banco de dados
+func (e *PbfProcess) writeBatch(run *pbfRun, batch pbfBatch) (err error) {
+	switch {
+	case len(batch.nodes) != 0:
+		err = e.databaseNode.SetMany(&batch.nodes)
+		if err != nil {
+			err = fmt.Errorf("PbfProcess.%v().SetMany(1).Error: %v", run.name, err)
+			return
+		}
+
+		run.flushedNodes.Add(uint64(len(batch.nodes)))
+		e.progress.batch("node", len(batch.nodes))
+
+	case len(batch.ways) != 0:
+		// todo: em caso de erro, inserir um por um e devolver os ways com erro
+		err = e.databaseWay.SetMany(&batch.ways)
+		if err != nil {
+			err = fmt.Errorf("PbfProcess.%v().SetMany(2).Error: %v", run.name, err)
+			return
+		}
+
+		run.flushedWays.Add(uint64(len(batch.ways)))
+		e.progress.batch("way", len(batch.ways))
+
+	case len(batch.polygons) != 0:
+		err = e.databasePolygon.SetMany(&batch.polygons)
+		if err != nil {
+			err = fmt.Errorf("PbfProcess.%v().SetMany(3).Error: %v", run.name, err)
+			return
+		}
+
+		run.flushedPolygons.Add(uint64(len(batch.polygons)))
+		e.progress.batch("polygon", len(batch.polygons))
+	}
+	return
+}
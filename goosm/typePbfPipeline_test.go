@@ -0,0 +1,262 @@
+package goosm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestPbfProcess_SetWorkers
+//
+// English:
+//
+// # Imports the grid with several pipeline configurations, the result must be the same
+//
+// Português:
+//
+// Importa a grade com várias configurações do pipeline, o resultado deve ser o mesmo
+func TestPbfProcess_SetWorkers(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "grid.pbf")
+	err := testPbfGrid(path, 200)
+	if err != nil {
+		t.Logf("testPbfGrid() error: %v", err)
+		t.FailNow()
+	}
+
+	for _, test := range []struct {
+		resolvers int
+		writers   int
+		batchSize int
+		ordering  PipelineOrdering
+	}{
+		{resolvers: 1, writers: 1, batchSize: 100, ordering: OrderingFile},
+		{resolvers: 8, writers: 3, batchSize: 7, ordering: OrderingFile},
+		{resolvers: 8, writers: 3, batchSize: 7, ordering: OrderingNone},
+	} {
+		var database = newTestDatabase()
+		var nodeFile = &testNodeFile{}
+		nodeFile.Init(0)
+
+		var batches atomic.Int64
+		database.onSetMany = func(kind string) {
+			batches.Add(1)
+		}
+
+		var process = newTestPbfProcess(database, nodeFile)
+		process.SetWorkers(test.resolvers, test.writers)
+		process.SetBatchSize(test.batchSize)
+		process.SetOrdering(test.ordering)
+
+		nodes, ways, err := process.CompleteParser(path)
+		if err != nil {
+			t.Logf("%+v: CompleteParser() error: %v", test, err)
+			t.FailNow()
+		}
+
+		if nodes != 200 || ways != 200 || len(database.nodes) != 200 || len(database.ways) != 200 || len(database.polygons) != 1 {
+			t.Logf("%+v: totals error: %v, %v, database %v, %v, %v", test, nodes, ways, len(database.nodes), len(database.ways), len(database.polygons))
+			t.FailNow()
+		}
+
+		// English: the batches are full, except the last one of each phase
+		// Português: os lotes estão cheios, exceto o último de cada fase
+		var expected = 2*((200+test.batchSize-1)/test.batchSize) + 1
+		if batches.Load() != int64(expected) {
+			t.Logf("%+v: batches error: %v != %v", test, batches.Load(), expected)
+			t.FailNow()
+		}
+
+		if database.ways[199].Loc[1] != [2]float64{-48.0, -27.0 + 0.002} {
+			t.Logf("%+v: way 199 coordinates error: %v", test, database.ways[199].Loc)
+			t.FailNow()
+		}
+	}
+}
+
+// TestPbfProcess_pipelineError
+//
+// English:
+//
+// # An error of a writer stops the import and is returned
+//
+// Português:
+//
+// Um erro de um escritor para a importação e é devolvido
+func TestPbfProcess_pipelineError(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "grid.pbf")
+	err := testPbfGrid(path, 200)
+	if err != nil {
+		t.Logf("testPbfGrid() error: %v", err)
+		t.FailNow()
+	}
+
+	var database = newTestDatabase()
+	database.ways[5] = Way{Id: 5}
+
+	var nodeFile = &testNodeFile{}
+	nodeFile.Init(0)
+
+	var process = newTestPbfProcess(database, nodeFile)
+	process.SetWorkers(4, 2)
+	process.SetBatchSize(10)
+
+	_, _, err = process.CompleteParser(path)
+	if err == nil || !strings.Contains(err.Error(), "SetMany(2)") || !strings.Contains(err.Error(), "duplicate way 5") {
+		t.Logf("CompleteParser() error: %v", err)
+		t.FailNow()
+	}
+
+	if len(database.polygons) != 0 {
+		t.Logf("the relations must not be processed after the error: %v polygons", len(database.polygons))
+		t.FailNow()
+	}
+}
+
+// TestPbfPipeline_window
+//
+// English:
+//
+// # In ordered mode, send() blocks while the collector waits for the oldest element, the results kept are bounded
+//
+// Português:
+//
+// No modo ordenado, send() bloqueia enquanto o coletor espera pelo elemento mais antigo, os resultados guardados são
+// limitados
+func TestPbfPipeline_window(t *testing.T) {
+	var pipeline = &pbfPipeline{
+		run:       &pbfRun{},
+		ordered:   true,
+		batchSize: 10,
+		jobs:      make(chan pbfJob, 100),
+		results:   make(chan pbfResult, 100),
+		batches:   make(chan pbfBatch, 1),
+		window:    make(chan struct{}, 4),
+	}
+	pipeline.ctx, pipeline.cancel = context.WithCancel(context.Background())
+	defer pipeline.cancel()
+
+	var collected = make(chan struct{})
+	go func() {
+		defer close(collected)
+		pipeline.collect()
+	}()
+
+	for i := 0; i != 4; i++ {
+		err := pipeline.send(nil, false)
+		if err != nil {
+			t.Logf("send() error: %v", err)
+			t.FailNow()
+		}
+	}
+
+	var sent = make(chan error)
+	go func() {
+		sent <- pipeline.send(nil, false)
+	}()
+
+	// English: the results 1 to 3 arrive before the result 0, the window stays full
+	// Português: os resultados 1 a 3 chegam antes do resultado 0, a janela continua cheia
+	for seq := uint64(1); seq != 4; seq++ {
+		pipeline.results <- pbfResult{seq: seq}
+	}
+
+	select {
+	case <-sent:
+		t.Logf("send() must block while the result 0 is missing")
+		t.FailNow()
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	pipeline.results <- pbfResult{seq: 0}
+	select {
+	case err := <-sent:
+		if err != nil {
+			t.Logf("send() error: %v", err)
+			t.FailNow()
+		}
+	case <-time.After(5 * time.Second):
+		t.Logf("send() must continue after the result 0")
+		t.FailNow()
+	}
+
+	close(pipeline.results)
+	<-collected
+}
+
+// BenchmarkPbfProcess_Pipeline
+//
+// English:
+//
+// Measures the throughput of CompleteParser() with several numbers of resolvers and writers, over a database with 1ms
+// of latency per batch.
+//
+// The file is the regional extract of the environment variable GOOSM_BENCH_PBF, such as a state or small country
+// downloaded from download.geofabrik.de, or a synthetic grid of 200000 nodes, in blocks of 8000 elements as in the
+// real files, when not defined.
+//
+//	go test -run none -bench Pipeline ./goosm/
+//	GOOSM_BENCH_PBF=/path/sergipe-latest.osm.pbf go test -run none -bench Pipeline -benchtime 1x ./goosm/
+//
+// Português:
+//
+// Mede a vazão de CompleteParser() com várias quantidades de resolvedores e escritores, sobre um banco de dados com 1ms
+// de latência por lote.
+//
+// O arquivo é o recorte regional da variável de ambiente GOOSM_BENCH_PBF, como um estado ou país pequeno baixado de
+// download.geofabrik.de, ou uma grade sintética de 200000 nodes, em blocos de 8000 elementos como nos arquivos reais,
+// quando não definida.
+//
+//	go test -run none -bench Pipeline ./goosm/
+//	GOOSM_BENCH_PBF=/path/sergipe-latest.osm.pbf go test -run none -bench Pipeline -benchtime 1x ./goosm/
+func BenchmarkPbfProcess_Pipeline(b *testing.B) {
+	var path = os.Getenv("GOOSM_BENCH_PBF")
+	if path == "" {
+		path = filepath.Join(b.TempDir(), "grid.pbf")
+		err := testPbfGridBlocks(path, 200000, 8000)
+		if err != nil {
+			b.Logf("testPbfGrid() error: %v", err)
+			b.FailNow()
+		}
+	}
+
+	for _, config := range []struct {
+		name      string
+		resolvers int
+		writers   int
+	}{
+		{name: "resolvers=1,writers=1", resolvers: 1, writers: 1},
+		{name: "resolvers=4,writers=1", resolvers: 4, writers: 1},
+		{name: "resolvers=4,writers=4", resolvers: 4, writers: 4},
+		{name: "resolvers=max,writers=8", resolvers: runtime.GOMAXPROCS(-1), writers: 8},
+	} {
+		b.Run(config.name, func(b *testing.B) {
+			var elements uint64
+			for i := 0; i < b.N; i++ {
+				var database = newTestDatabase()
+				database.onSetMany = func(kind string) {
+					time.Sleep(time.Millisecond)
+				}
+
+				var nodeFile = &testNodeFile{}
+				nodeFile.Init(0)
+
+				var process = newTestPbfProcess(database, nodeFile)
+				process.SetWorkers(config.resolvers, config.writers)
+
+				_, _, err := process.CompleteParser(path)
+				if err != nil {
+					b.Logf("CompleteParser() error: %v", err)
+					b.FailNow()
+				}
+				elements += process.progress.elements()
+			}
+
+			b.ReportMetric(float64(elements)/b.Elapsed().Seconds(), "elements/s")
+		})
+	}
+}
@@ -1,13 +1,14 @@
 package goosm
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/qedus/osmpbf"
 	"io"
 	"log"
 	"os"
-	"runtime"
+	"sync"
 	"time"
 )
 
@@ -195,6 +196,99 @@ type CompressInterface interface {
 	ReadFileHeaders() (err error)
 }
 
+type WayStoreInterface interface {
+	// WriteWayCoordinates
+	//
+	// English:
+	//
+	// Write the way and its resolved coordinates to the temporary file.
+	//
+	//  Input:
+	//    id: positive number greater than zero, in ascending order;
+	//    loc: list of coordinates, [longitude, latitude], with 7 decimal places.
+	//
+	// Português:
+	//
+	// Escreve o way e suas coordenadas resolvidas no arquivo temporário.
+	//
+	//  Entrada:
+	//    id: número positivo maior do que zero, em ordem crescente;
+	//    loc: lista de coordenadas, [longitude, latitude], com 7 casas decimais.
+	WriteWayCoordinates(id int64, loc [][2]float64) (err error)
+
+	// FindWayByID
+	//
+	// English:
+	//
+	// Search for the way in the temporary file.
+	//
+	//  Output:
+	//    nodeIDs: list of node IDs, when the way was written with node IDs;
+	//    loc: list of coordinates, when the way was written with coordinates;
+	//    err: pattern object, with io.EOF error when value not found in file
+	//
+	// Português:
+	//
+	// Procura pelo way no arquivo temporário.
+	//
+	//  Saída:
+	//    nodeIDs: lista de IDs de nodes, quando o way foi escrito com IDs de nodes;
+	//    loc: lista de coordenadas, quando o way foi escrito com coordenadas;
+	//    err: objeto de padrão, com erro io.EOF quando o valor não é encontrado no arquivo
+	FindWayByID(id int64) (nodeIDs []int64, loc [][2]float64, err error)
+
+	// MountIndexIntoFile
+	//
+	// English:
+	//
+	// Saves the indexes captured during writing at the end of the temporary file.
+	//
+	// Português:
+	//
+	// Salva os índices capturados durante a escrita no fim do arquivo temporário.
+	MountIndexIntoFile() (err error)
+
+	// WriteFileHeaders
+	//
+	// English:
+	//
+	// Write configuration data at the beginning of the file.
+	//
+	// Português:
+	//
+	// Escreve os dados de configuração no início do arquivo.
+	WriteFileHeaders() (err error)
+}
+
+// NodeReaderInterface
+//
+// English:
+//
+// Goroutine-safe search of nodes in a node file already finished, such as compress.Reader, defined by SetNodeReader().
+//
+// Português:
+//
+// Busca de nodes segura entre goroutines em um arquivo de nodes já terminado, como compress.Reader, definida por
+// SetNodeReader().
+type NodeReaderInterface interface {
+	// FindNodeByID
+	//
+	// English:
+	//
+	// Search for longitude and latitude in the node file.
+	//
+	//  Output:
+	//    err: pattern object, with io.EOF error when value not found in file
+	//
+	// Português:
+	//
+	// Procura por longitude e latitude no arquivo de nodes.
+	//
+	//  Saída:
+	//    err: objeto de padrão, com erro io.EOF quando o valor não é encontrado no arquivo
+	FindNodeByID(id int64) (longitude, latitude float64, err error)
+}
+
 type InterfaceDownloadOsm interface {
 
 	// DownloadNode
@@ -362,6 +456,59 @@ type InterfaceDbWay interface {
 	SetMany(list *[]Way) (err error)
 }
 
+type InterfaceDbPolygon interface {
+	// SetOne
+	//
+	// English:
+	//
+	// Insert a single polygon, assembled from a relation, into the database
+	//
+	//  Input:
+	//    polygon: reference to object goosm.PolygonList
+	//
+	// Português:
+	//
+	// Insere um único polígono, montado a partir de uma relation, no banco de dados
+	//
+	//  Entrada:
+	//    polygon: referencia ao objeto goosm.PolygonList
+	SetOne(polygon *PolygonList) (err error)
+
+	// GetById
+	//
+	// English:
+	//
+	// Returns a polygon according to the relation ID
+	//
+	//  Input:
+	//    id: ID in the Create Street Maps project pattern
+	//
+	// Português:
+	//
+	// Retorna um polígono de acordo com o ID da relation
+	//
+	//  Entrada:
+	//    id: ID no padrão do projeto Create Street Maps
+	GetById(id int64) (polygon PolygonList, err error)
+
+	// SetMany
+	//
+	// English:
+	//
+	// Insert a block of polygons into the database
+	//
+	//  Input:
+	//    list: reference to slice with []goosm.PolygonList objects
+	//
+	// Português:
+	//
+	// Insere um bloco de polígonos no banco de dados
+	//
+	//  Entrada:
+	//    list: referência ao slice com os objetos []goosm.PolygonList
+	SetMany(list *[]PolygonList) (err error)
+}
+
 type InterfaceDbNode interface {
 	// SetOne
 	//
@@ -439,13 +586,55 @@ type InterfaceDbNode interface {
 // ou recuperado no banco, inviabilizando o projeto.
 type PbfProcess struct {
 	compress CompressInterface
-
-	totalOfNodesInTmpFile uint64
-	totalOfWaysInTmpFile  uint64
-	downloadApi           InterfaceDownloadOsm
-	databaseNode          InterfaceDbNode
-	databaseWay           InterfaceDbWay
-	databaseTimeout       time.Duration
+	wayStore WayStoreInterface
+
+	wayStoreMounted    bool
+	progress           progressCounters
+	downloadApi        InterfaceDownloadOsm
+	databaseNode       InterfaceDbNode
+	databaseWay        InterfaceDbWay
+	databasePolygon    InterfaceDbPolygon
+	databaseTimeout    time.Duration
+	checkpointPath     string
+	checkpointInterval time.Duration
+
+	// English: configuration of the pipeline, zero uses the default values
+	// Português: configuração do pipeline, zero usa os valores padrão
+	resolvers  int
+	writers    int
+	batchSize  int
+	ordering   PipelineOrdering
+	nodeReader NodeReaderInterface
+
+	tagFilter *TagFilter
+	tagPolicy *TagPolicy
+	areaRules *AreaRules
+	clip      *Clip
+	clipMode  ClipMode
+
+	missingNodePolicy MissingNodePolicy
+	missingNodeSink   MissingNodeSink
+	missingNodeReport MissingNodeReport
+
+	// English: receiver of the elements not imported and limit of invalid elements, see SetMaxErrors()
+	// Português: receptor dos elementos não importados e limite de elementos inválidos, veja SetMaxErrors()
+	errorSink  ErrorSink
+	maxErrors  int
+	errorCount uint64
+
+	// English: database of the import runs, see SetImportLog()
+	// Português: banco de dados das execuções de importação, veja SetImportLog()
+	importLog   InterfaceDbImportLog
+	forceImport bool
+
+	// English: the metadata of the nodes and ways is kept, see SetMetadata()
+	// Português: os metadados dos nodes e ways são mantidos, veja SetMetadata()
+	metadata bool
+
+	// English: serializes the lookups in the node file and in the way store, and the downloads, made by the resolvers
+	// Português: serializa as buscas no arquivo de nodes e no arquivo de ways, e os downloads, feitos pelos resolvedores
+	lookupMutex   sync.Mutex
+	downloadMutex sync.Mutex
 }
 
 // SetDatabaseNode
@@ -474,6 +663,25 @@ func (e *PbfProcess) SetDatabaseWay(database InterfaceDbWay) {
 	e.databaseWay = database
 }
 
+// SetDatabasePolygon
+//
+// English:
+//
+// Defines the insertion object of polygons, assembled from multipolygon and boundary relations, in the database.
+//
+//	Note:
+//	  * When not defined, relations are ignored.
+//
+// Português:
+//
+// Define o objeto de inserção de polígonos, montados a partir das relations multipolygon e boundary, no banco de dados.
+//
+//	Nota:
+//	  * Quando não definido, as relations são ignoradas.
+func (e *PbfProcess) SetDatabasePolygon(database InterfaceDbPolygon) {
+	e.databasePolygon = database
+}
+
 // SetDatabaseTimeout
 //
 // English:
@@ -513,421 +721,624 @@ func (e *PbfProcess) SetCompress(compress CompressInterface) {
 	e.compress = compress
 }
 
-// CompleteParser
+// SetWayStore
 //
 // English:
 //
-// Processes the open street maps file and inserts all the data found in the data source in an optimized way for the
-// planetary file.
+// Defines the binary file of way coordinates, used to assemble relations without querying the way database.
+//
+//	Note:
+//	  * When not defined, the ways of the relations are looked for in the way database.
+//	  * The file is finalized when the first relation is found, or at the end of the pbf file.
 //
 // Português:
 //
-// Faz o processamento do arquivo do open street maps e insere todos os dados encontrados na fonte de dados e forma
-// otimizada para o arquivo planetário.
-func (e *PbfProcess) CompleteParser(osmFilePath string) (nodes, ways uint64, err error) {
+// Define o arquivo binário de coordenadas de ways, usado para montar as relations sem consultar o banco de dados de
+// ways.
+//
+//	Nota:
+//	  * Quando não definido, os ways das relations são procurados no banco de dados de ways.
+//	  * O arquivo é finalizado quando a primeira relation é encontrada, ou no fim do arquivo pbf.
+func (e *PbfProcess) SetWayStore(wayStore WayStoreInterface) {
+	e.wayStore = wayStore
+}
 
-	if e.compress == nil {
-		err = errors.New("PbfProcess.CompleteParser().error: the compression object must be defined before this function is called")
-		return
-	}
+// SetCheckpoint
+//
+// English:
+//
+// Defines the checkpoint file, written by CompleteParser() and DatabaseOnly() during the import and read by Resume().
+//
+//	Input:
+//	  path: path of the checkpoint file, JSON;
+//	  interval: minimum time between two writes, zero uses CheckpointDefaultInterval.
+//
+//	Note:
+//	  * The batches waiting for the database are flushed before each write, so the elements before the resume point
+//	    are never inserted twice.
+//	  * The checkpoint is also written when the context is canceled and at the end of the file, with phase PhaseDone.
+//
+// Português:
+//
+// Define o arquivo de checkpoint, escrito por CompleteParser() e DatabaseOnly() durante a importação e lido por
+// Resume().
+//
+//	Entrada:
+//	  path: caminho do arquivo de checkpoint, JSON;
+//	  interval: tempo mínimo entre duas escritas, zero usa CheckpointDefaultInterval.
+//
+//	Nota:
+//	  * Os lotes esperando pelo banco de dados são enviados antes de cada escrita, assim os elementos antes do ponto de
+//	    retomada nunca são inseridos duas vezes.
+//	  * O checkpoint também é escrito quando o contexto é cancelado e no fim do arquivo, com a fase PhaseDone.
+func (e *PbfProcess) SetCheckpoint(path string, interval time.Duration) {
+	e.checkpointPath = path
+	e.checkpointInterval = interval
+}
 
-	if e.downloadApi == nil {
-		err = errors.New("PbfProcess.CompleteParser().error: the download object must be defined before this function is called")
-		return
-	}
+// SetWorkers
+//
+// English:
+//
+// Defines the number of goroutines of the pipeline of CompleteParser() and DatabaseOnly().
+//
+//	Input:
+//	  resolvers: goroutines that assemble the nodes, ways and polygons, zero uses runtime.GOMAXPROCS();
+//	  writers: goroutines that call SetMany(), zero uses one.
+//
+//	Note:
+//	  * The lookups in the compression object and in the way store are not goroutine-safe and are made one at a time,
+//	    use SetNodeReader() so the resolvers look for the nodes at the same time.
+//	  * With more than one writer, the batches are written at the same time and may reach the database out of order.
+//
+// Português:
+//
+// Define a quantidade de goroutines do pipeline de CompleteParser() e DatabaseOnly().
+//
+//	Entrada:
+//	  resolvers: goroutines que montam os nodes, ways e polígonos, zero usa runtime.GOMAXPROCS();
+//	  writers: goroutines que chamam SetMany(), zero usa uma.
+//
+//	Nota:
+//	  * As buscas no objeto de compressão e no arquivo de ways não são seguras entre goroutines e são feitas uma por
+//	    vez, use SetNodeReader() para que os resolvedores procurem os nodes ao mesmo tempo.
+//	  * Com mais de um escritor, os lotes são escritos ao mesmo tempo e podem chegar fora de ordem ao banco de dados.
+func (e *PbfProcess) SetWorkers(resolvers, writers int) {
+	e.resolvers = resolvers
+	e.writers = writers
+}
 
-	if e.databaseNode == nil {
-		err = errors.New("PbfProcess.CompleteParser().error: the databaseNode object must be defined before this function is called")
-		return
-	}
+// SetBatchSize
+//
+// English:
+//
+// # Defines the number of elements of each SetMany(), zero uses PipelineDefaultBatchSize
+//
+// Português:
+//
+// Define a quantidade de elementos de cada SetMany(), zero usa PipelineDefaultBatchSize
+func (e *PbfProcess) SetBatchSize(size int) {
+	e.batchSize = size
+}
 
-	if e.databaseWay == nil {
-		err = errors.New("PbfProcess.CompleteParser().error: the databaseWay object must be defined before this function is called")
-		return
-	}
+// SetOrdering
+//
+// English:
+//
+// Defines the order in which the resolved elements are grouped into batches, OrderingFile, the default, or
+// OrderingNone.
+//
+//	Note:
+//	  * OrderingFile is always used when the way store is defined.
+//
+// Português:
+//
+// Define a ordem em que os elementos resolvidos são agrupados em lotes, OrderingFile, o padrão, ou OrderingNone.
+//
+//	Nota:
+//	  * OrderingFile é sempre usado quando o arquivo de ways é definido.
+func (e *PbfProcess) SetOrdering(ordering PipelineOrdering) {
+	e.ordering = ordering
+}
 
-	e.totalOfNodesInTmpFile = 0
-	e.totalOfWaysInTmpFile = 0
+// SetNodeReader
+//
+// English:
+//
+// Defines a goroutine-safe node reader, such as compress.Reader, used by the resolvers of DatabaseOnly() instead of
+// the compression object, so the nodes of the ways are looked for at the same time.
+//
+//	Note:
+//	  * CompleteParser() writes the node file during the run, the reader must be opened over a file already finished.
+//
+// Português:
+//
+// Define um leitor de nodes seguro entre goroutines, como compress.Reader, usado pelos resolvedores de DatabaseOnly()
+// no lugar do objeto de compressão, assim os nodes dos ways são procurados ao mesmo tempo.
+//
+//	Nota:
+//	  * CompleteParser() escreve o arquivo de nodes durante a execução, o leitor deve ser aberto sobre um arquivo já
+//	    terminado.
+func (e *PbfProcess) SetNodeReader(reader NodeReaderInterface) {
+	e.nodeReader = reader
+}
 
-	var osmFile *os.File
-	osmFile, err = os.Open(osmFilePath)
-	if err != nil {
-		err = fmt.Errorf("PbfProcess.CompleteParser().Create().Error: %v", err)
-		return
-	}
+// SetTagFilter
+//
+// English:
+//
+// Defines the filter of the elements imported by CompleteParser() and DatabaseOnly(), see TagFilter.
+//
+//	Note:
+//	  * The filter is applied before the resolution, to the tags as they are in the pbf file;
+//	  * All the nodes are still written into the node file, so the ways keep their coordinates;
+//	  * The ways rejected by the filter are still written into the way store, when relations may be imported;
+//	  * Resume() must be called with the same filter of the interrupted run.
+//
+// Português:
+//
+// Define o filtro dos elementos importados por CompleteParser() e DatabaseOnly(), veja TagFilter.
+//
+//	Nota:
+//	  * O filtro é aplicado antes da resolução, às tags como estão no arquivo pbf;
+//	  * Todos os nodes ainda são escritos no arquivo de nodes, assim os ways mantêm suas coordenadas;
+//	  * Os ways rejeitados pelo filtro ainda são escritos no arquivo de ways, quando relations podem ser importadas;
+//	  * Resume() deve ser chamada com o mesmo filtro da execução interrompida.
+func (e *PbfProcess) SetTagFilter(filter *TagFilter) {
+	e.tagFilter = filter
+}
 
-	defer func() {
-		err := osmFile.Close()
+// SetClip
+//
+// English:
+//
+// Imports only the elements inside an area, the nodes inside it, the ways with nodes inside it and the polygons that
+// touch it.
+//
+//	Input:
+//	  area: goosm.Box, goosm.Polygon, goosm.PolygonList, pointers to them, or a *goosm.Clip, such as the one returned
+//	        by LoadClipGeoJSon(); nil removes the clip.
+//
+//	Note:
+//	  * All the nodes are still written into the node file and the ways out of the area into the way store, when
+//	    relations may be imported, so the relations on the border keep their rings;
+//	  * Use SetClipMode() to cut the ways at the border.
+//
+// Português:
+//
+// Importa apenas os elementos dentro de uma área, os nodes dentro dela, os ways com nodes dentro dela e os polígonos
+// que a tocam.
+//
+//	Entrada:
+//	  area: goosm.Box, goosm.Polygon, goosm.PolygonList, ponteiros para eles, ou um *goosm.Clip, como o devolvido por
+//	        LoadClipGeoJSon(); nil remove o recorte.
+//
+//	Nota:
+//	  * Todos os nodes ainda são escritos no arquivo de nodes e os ways fora da área no arquivo de ways, quando
+//	    relations podem ser importadas, assim as relations na borda mantêm seus anéis;
+//	  * Use SetClipMode() para cortar os ways na borda.
+func (e *PbfProcess) SetClip(area interface{}) (err error) {
+	switch converted := area.(type) {
+	case nil:
+		e.clip = nil
+	case *Clip:
+		e.clip = converted
+	default:
+		var clip *Clip
+		clip, err = NewClip(area)
 		if err != nil {
-			log.Printf("error closing main osm source file: %v", err.Error())
+			err = fmt.Errorf("PbfProcess.SetClip().Error: %v", err)
+			return
 		}
-	}()
-
-	osmDecoder := osmpbf.NewDecoder(osmFile)
-
-	// use more memory from the start, it is faster
-	osmDecoder.SetBufferSize(osmpbf.MaxBlobSize)
-
-	// start decoding with several goroutines, it is faster
-	err = osmDecoder.Start(runtime.GOMAXPROCS(-1))
-	if err != nil {
-		err = fmt.Errorf("PbfProcess.CompleteParser().Start().Error: %v", err)
-		return
+		e.clip = clip
 	}
+	return
+}
 
-	writeHeaders := true
-	nodeList := make([]Node, 0)
-	lon := 0.0
-	lat := 0.0
-	wayList := make([]Way, 0)
-	counter := 0
-
-	tmpNode := Node{}
+// SetClipMode
+//
+// English:
+//
+// # Defines what is done with the ways that cross the border of the clip area, ClipKeepWays or ClipCutWays
+//
+// Português:
+//
+// Define o que é feito com os ways que cruzam a borda da área de recorte, ClipKeepWays ou ClipCutWays
+func (e *PbfProcess) SetClipMode(mode ClipMode) {
+	e.clipMode = mode
+}
 
-	for {
-		var osmPbfElement interface{}
-		if osmPbfElement, err = osmDecoder.Decode(); err == io.EOF {
-			err = nil
-			break
-		} else if err != nil {
-			err = fmt.Errorf("PbfProcess.CompleteParser().Decode().Error: %v", err)
-			return
-		} else {
-			switch converted := osmPbfElement.(type) {
-			case *osmpbf.Node:
+// SetMissingNodePolicy
+//
+// English:
+//
+// Defines what is done with the nodes of a way not found in the node file, MissingNodeDownload, the default,
+// MissingNodeFail, MissingNodeSkip, MissingNodeDropWay or MissingNodeRecord.
+//
+//	Note:
+//	  * The counters of each run are written to the log at the end and returned by GetMissingNodeReport().
+//
+// Português:
+//
+// Define o que é feito com os nodes de um way não encontrados no arquivo de nodes, MissingNodeDownload, o padrão,
+// MissingNodeFail, MissingNodeSkip, MissingNodeDropWay ou MissingNodeRecord.
+//
+//	Nota:
+//	  * Os contadores de cada execução são escritos no log ao fim e devolvidos por GetMissingNodeReport().
+func (e *PbfProcess) SetMissingNodePolicy(policy MissingNodePolicy) {
+	e.missingNodePolicy = policy
+}
 
-				e.totalOfNodesInTmpFile++
+// SetMissingNodeSink
+//
+// English:
+//
+// # Defines the receiver of the missing nodes of the policy MissingNodeRecord
+//
+// Português:
+//
+// Define o receptor dos nodes ausentes da política MissingNodeRecord
+func (e *PbfProcess) SetMissingNodeSink(sink MissingNodeSink) {
+	e.missingNodeSink = sink
+}
 
-				err = e.compress.WriteNode(converted.ID, converted.Lon, converted.Lat)
-				if err != nil {
-					err = fmt.Errorf("PbfProcess.CompleteParser()WriteNode().Error: %v", err)
-					return
-				}
-
-				if converted.Info.Visible && len(converted.Tags) != 0 {
-
-					if len(converted.Tags) != 0 {
-						node := Node{}
-						node.Init(converted.ID, converted.Lon, converted.Lat, &converted.Tags)
-						node.MakeGeoJSonFeature()
-						if len(node.Tag) == 0 {
-							continue
-						}
-
-						nodeList = append(nodeList, node)
-						if len(nodeList) == 100 {
-							err = e.databaseNode.SetMany(&nodeList)
-							if err != nil {
-								err = fmt.Errorf("PbfProcess.CompleteParser().SetMany().Error: %v", err)
-								return
-							}
-
-							nodeList = make([]Node, 0)
-						}
-					}
-				}
-
-			case *osmpbf.Way:
+// SetMetadata
+//
+// English:
+//
+// Keeps the version, timestamp, changeset and user of the nodes and ways in the field Metadata, written into the
+// database and into the GeoJSON properties, with the prefix @. Disabled by default, the metadata uses memory and disk
+// space.
+//
+// Português:
+//
+// Mantém a versão, o momento, o changeset e o usuário dos nodes e ways no campo Metadata, escritos no banco de dados e
+// nas propriedades do GeoJSON, com o prefixo @. Desabilitado por padrão, os metadados usam memória e espaço em disco.
+func (e *PbfProcess) SetMetadata(enabled bool) {
+	e.metadata = enabled
+}
 
-				e.totalOfWaysInTmpFile++
-
-				if writeHeaders {
-					writeHeaders = false
-
-					err = e.compress.WriteFileHeaders()
-					if err != nil {
-						err = fmt.Errorf("PbfProcess.CompleteParser().WriteFileHeaders().Error: %v", err)
-						return
-					}
-
-					err = e.compress.MountIndexIntoFile()
-					if err != nil {
-						err = fmt.Errorf("PbfProcess.CompleteParser().MountIndexIntoFile().Error: %v", err)
-						return
-					}
-
-					err = e.compress.ReadFileHeaders()
-					if err != nil {
-						err = fmt.Errorf("PbfProcess.CompleteParser().ReadFileHeaders().Error: %v", err)
-						return
-					}
-
-					err = e.compress.IndexToMemory()
-					if err != nil {
-						err = fmt.Errorf("PbfProcess.CompleteParser().IndexToMemory().Error: %v", err)
-						return
-					}
-				}
+// SetTagPolicy
+//
+// English:
+//
+// Defines the normalization of the tags of the nodes, ways and relations imported, in place of the keys removed by
+// the Init() of Node and Way, see NewTagPolicy().
+//
+//	Note:
+//	  * The tag filter of SetTagFilter() sees the tags of the file, before the policy.
+//
+// Português:
+//
+// Define a normalização das tags dos nodes, ways e relations importados, no lugar das chaves removidas pelo Init() de
+// Node e Way, veja NewTagPolicy().
+//
+//	Nota:
+//	  * O filtro de tags de SetTagFilter() vê as tags do arquivo, antes da política.
+func (e *PbfProcess) SetTagPolicy(policy *TagPolicy) {
+	e.tagPolicy = policy
+}
 
-				// English: The amount of data in the planetary file is very large and comparing with nil is faster.
-				// Português: A quantidade de dados no arquivo planetário é muito grande e comparar com nil é mais rápido.
-				if nodeList != nil && len(nodeList) != 0 { //nolint:gosimple
-					err = e.databaseNode.SetMany(&nodeList)
-					if err != nil {
-						err = fmt.Errorf("PbfProcess.CompleteParser().SetMany().Error: %v", err)
-						return
-					}
+// SetAreaRules
+//
+// English:
+//
+// Defines the rules that decide which ways are areas, Way.IsPolygon and the Polygon geometry of the GeoJSON, nil, the
+// default, uses NewAreaRules().
+//
+// Português:
+//
+// Define as regras que decidem quais ways são áreas, Way.IsPolygon e a geometria Polygon do GeoJSON, nil, o padrão,
+// usa NewAreaRules().
+func (e *PbfProcess) SetAreaRules(rules *AreaRules) {
+	e.areaRules = rules
+}
 
-					nodeList = nil
-				}
+// SetErrorSink
+//
+// English:
+//
+// Defines the receiver of the elements not imported, the invalid ways and the ways and relations dropped, see
+// ErrorReason. The elements left out by the tag filter or by the clip area are not reported.
+//
+// Português:
+//
+// Define o receptor dos elementos não importados, os ways inválidos e os ways e relations descartados, veja
+// ErrorReason. Os elementos deixados de fora pelo filtro de tags ou pela área de recorte não são informados.
+func (e *PbfProcess) SetErrorSink(sink ErrorSink) {
+	e.errorSink = sink
+}
 
-				if !converted.Info.Visible {
-					continue
-				}
+// SetMaxErrors
+//
+// English:
+//
+// Defines how many invalid elements are reported and skipped before the import stops, zero, the default, stops at
+// the first one and a negative value never stops.
+//
+//	Note:
+//	  * The count is saved in the checkpoint and returned by GetErrorCount();
+//	  * The elements dropped by the policies, ErrorReasonMissingNodes and the relations, are not counted.
+//
+// Português:
+//
+// Define quantos elementos inválidos são informados e pulados antes da importação parar, zero, o padrão, para no
+// primeiro e um valor negativo nunca para.
+//
+//	Nota:
+//	  * A contagem é salva no checkpoint e devolvida por GetErrorCount();
+//	  * Os elementos descartados pelas políticas, ErrorReasonMissingNodes e as relations, não são contados.
+func (e *PbfProcess) SetMaxErrors(max int) {
+	e.maxErrors = max
+}
 
-				way := Way{}
-				way.Id = converted.ID
-				way.Loc = make([][2]float64, len(converted.NodeIDs))
-				//way.IdList = converted.NodeIDs
-				way.Tag = converted.Tags
-
-				for nodeKey, nodeID := range converted.NodeIDs {
-					lon, lat, err = e.compress.FindNodeByID(nodeID)
-
-					// English: downloads points not present in binary file
-					// Português: faz o download de pontos não presentes no arquivo binário
-					if err != nil && err == io.EOF {
-						log.Printf("PbfProcess.CompleteParser().event: download ID: %v", nodeID)
-						tmpNode, err = e.downloadApi.DownloadNode(nodeID)
-						if err != nil {
-							err = fmt.Errorf("PbfProcess.CompleteParser().DownloadNode().Error: %v", err)
-							return
-						}
-						lon = tmpNode.Loc[Longitude]
-						lat = tmpNode.Loc[Latitude]
-					}
-
-					if err != nil {
-						err = fmt.Errorf("PbfProcess.CompleteParser().FindNodeByID().Error: %v", err)
-						return
-					}
-
-					way.Loc[nodeKey] = [2]float64{lon, lat}
-				}
+// SetImportLog
+//
+// English:
+//
+// Defines the database of the import runs, each run of CompleteParser(), DatabaseOnly() and Resume() writes an
+// ImportRun and the SourceFile with the SHA-256 of the file, and a file already imported is refused.
+//
+//	Note:
+//	  * The whole file is read once more, before the import, to calculate the SHA-256.
+//
+// Português:
+//
+// Define o banco de dados das execuções de importação, cada execução de CompleteParser(), DatabaseOnly() e Resume()
+// escreve um ImportRun e o SourceFile com o SHA-256 do arquivo, e um arquivo já importado é recusado.
+//
+//	Nota:
+//	  * O arquivo inteiro é lido mais uma vez, antes da importação, para calcular o SHA-256.
+func (e *PbfProcess) SetImportLog(database InterfaceDbImportLog) {
+	e.importLog = database
+}
 
-				err = way.Init()
-				if err != nil {
-					err = fmt.Errorf("PbfProcess.CompleteParser().Init().Error: %v", err)
-					return
-				}
-				way.MakeGeoJSonFeature()
-
-				wayList = append(wayList, way)
-				counter++
-
-				if counter == 100 {
-					err = e.databaseWay.SetMany(&wayList)
-					// todo: em caso de erro, inserir um por um e devolver os ways com erro
-					if err != nil {
-						err = fmt.Errorf("PbfProcess.CompleteParser().SetMany(1).Error: %v", err)
-						return
-					}
-					counter = 0
-					wayList = make([]Way, 0)
-				}
+// SetForceImport
+//
+// English:
+//
+// # Imports a file with the same SHA-256 of a file already imported, see SetImportLog()
+//
+// Português:
+//
+// Importa um arquivo com o mesmo SHA-256 de um arquivo já importado, veja SetImportLog()
+func (e *PbfProcess) SetForceImport(force bool) {
+	e.forceImport = force
+}
 
-			case *osmpbf.Relation:
+// CompleteParser
+//
+// English:
+//
+// Processes the open street maps file and inserts all the data found in the data source in an optimized way for the
+// planetary file.
+//
+//	Note:
+//	  * The file may be .osm.pbf, .osm, .osm.gz or .osm.bz2, the format is chosen by DetectOsmFormat(), the same for
+//	    DatabaseOnly() and BinaryNodeOnlyParser().
+//
+// Português:
+//
+// Faz o processamento do arquivo do open street maps e insere todos os dados encontrados na fonte de dados e forma
+// otimizada para o arquivo planetário.
+//
+//	Nota:
+//	  * O arquivo pode ser .osm.pbf, .osm, .osm.gz ou .osm.bz2, o formato é escolhido por DetectOsmFormat(), o mesmo
+//	    para DatabaseOnly() e BinaryNodeOnlyParser().
+func (e *PbfProcess) CompleteParser(osmFilePath string) (nodes, ways uint64, err error) {
+	return e.CompleteParserContext(context.Background(), osmFilePath)
+}
 
-				err = e.databaseWay.SetMany(&wayList)
-				err = fmt.Errorf("PbfProcess.CompleteParser().SetMany(2).Error: %v", err)
-				return
+// CompleteParserContext
+//
+// English:
+//
+// Same as CompleteParser(), stopping when the context is canceled.
+//
+//	Note:
+//	  * When canceled, the batches are flushed to the database, the checkpoint is written, when defined, and the error
+//	    wraps ctx.Err().
+//
+// Português:
+//
+// Igual a CompleteParser(), parando quando o contexto é cancelado.
+//
+//	Nota:
+//	  * Quando cancelado, os lotes são enviados ao banco de dados, o checkpoint é escrito, quando definido, e o erro
+//	    envolve ctx.Err().
+func (e *PbfProcess) CompleteParserContext(ctx context.Context, osmFilePath string) (nodes, ways uint64, err error) {
+	return e.parse(ctx, pbfModeCompleteParser, osmFilePath, nil)
+}
 
-			default:
-				err = errors.New("PbfProcess.CompleteParser().error: formato de dado não previsto no arquivo pbf do open street maps")
-				return
-			}
-		}
-	}
+// DatabaseOnly
+//
+// English:
+//
+// Processes the open street maps file and inserts the data into the database, using a node file already made by
+// CompleteParser() or BinaryNodeOnlyParser().
+//
+// Português:
+//
+// Faz o processamento do arquivo do open street maps e insere os dados no banco de dados, usando um arquivo de nodes
+// já feito por CompleteParser() ou BinaryNodeOnlyParser().
+func (e *PbfProcess) DatabaseOnly(osmFilePath string) (nodes, ways uint64, err error) {
+	return e.DatabaseOnlyContext(context.Background(), osmFilePath)
+}
 
-	ways = e.totalOfWaysInTmpFile
-	nodes = e.totalOfNodesInTmpFile
-	return
+// DatabaseOnlyContext
+//
+// English:
+//
+// Same as DatabaseOnly(), stopping when the context is canceled.
+//
+// Português:
+//
+// Igual a DatabaseOnly(), parando quando o contexto é cancelado.
+func (e *PbfProcess) DatabaseOnlyContext(ctx context.Context, osmFilePath string) (nodes, ways uint64, err error) {
+	return e.parse(ctx, pbfModeDatabaseOnly, osmFilePath, nil)
 }
 
-func (e *PbfProcess) DatabaseOnly(osmFilePath string) (nodes, ways uint64, err error) {
+// Resume
+//
+// English:
+//
+// Continues an interrupted CompleteParser() or DatabaseOnly() from its checkpoint file, skipping the blobs already
+// committed to the database.
+//
+//	Input:
+//	  osmFilePath: path of the same pbf file of the interrupted run;
+//	  checkpoint: path of the checkpoint file, it keeps being updated when SetCheckpoint() was not called.
+//
+//	Note:
+//	  * The objects must be defined as in the interrupted run.
+//	  * When the checkpoint is in the node phase, CompleteParser() rebuilds the node file from the start, so the
+//	    compression object must be created as for a new import. In the other phases the node file must be complete,
+//	    the compression object must be created over the existing file and is loaded by ReadFileHeaders() and
+//	    IndexToMemory().
+//	  * The way store is only used when resuming from the node phase, otherwise the relations use the way database.
+//
+// Português:
+//
+// Continua um CompleteParser() ou DatabaseOnly() interrompido a partir do seu arquivo de checkpoint, pulando os blobs
+// já gravados no banco de dados.
+//
+//	Entrada:
+//	  osmFilePath: caminho do mesmo arquivo pbf da execução interrompida;
+//	  checkpoint: caminho do arquivo de checkpoint, ele continua sendo atualizado quando SetCheckpoint() não foi chamado.
+//
+//	Nota:
+//	  * Os objetos devem ser definidos como na execução interrompida.
+//	  * Quando o checkpoint está na fase de nodes, CompleteParser() reconstrói o arquivo de nodes desde o início, então o
+//	    objeto de compressão deve ser criado como para uma nova importação. Nas outras fases o arquivo de nodes deve
+//	    estar completo, o objeto de compressão deve ser criado sobre o arquivo existente e é carregado por
+//	    ReadFileHeaders() e IndexToMemory().
+//	  * O arquivo de ways só é usado ao retomar da fase de nodes, caso contrário as relations usam o banco de dados de
+//	    ways.
+func (e *PbfProcess) Resume(osmFilePath, checkpoint string) (nodes, ways uint64, err error) {
+	return e.ResumeContext(context.Background(), osmFilePath, checkpoint)
+}
 
-	if e.compress == nil {
-		err = errors.New("PbfProcess.DatabaseOnly().error: the compression object must be defined before this function is called")
+// ResumeContext
+//
+// English:
+//
+// Same as Resume(), stopping when the context is canceled.
+//
+// Português:
+//
+// Igual a Resume(), parando quando o contexto é cancelado.
+func (e *PbfProcess) ResumeContext(ctx context.Context, osmFilePath, checkpoint string) (nodes, ways uint64, err error) {
+	var resume Checkpoint
+	err = resume.Load(checkpoint)
+	if err != nil {
+		err = fmt.Errorf("PbfProcess.Resume().Load().Error: %v", err)
 		return
 	}
 
-	if e.downloadApi == nil {
-		err = errors.New("PbfProcess.DatabaseOnly().error: the download object must be defined before this function is called")
+	if resume.Mode != pbfModeCompleteParser && resume.Mode != pbfModeDatabaseOnly {
+		err = fmt.Errorf("PbfProcess.Resume().error: unknown checkpoint mode %v", resume.Mode)
 		return
 	}
 
-	if e.databaseNode == nil {
-		err = errors.New("PbfProcess.DatabaseOnly().error: the databaseNode object must be defined before this function is called")
-		return
+	if e.checkpointPath == "" {
+		e.checkpointPath = checkpoint
+		defer func() {
+			e.checkpointPath = ""
+		}()
 	}
 
-	if e.databaseWay == nil {
-		err = errors.New("PbfProcess.DatabaseOnly().error: the databaseWay object must be defined before this function is called")
-		return
-	}
+	return e.parse(ctx, resume.Mode, osmFilePath, &resume)
+}
 
-	e.totalOfNodesInTmpFile = 0
-	e.totalOfWaysInTmpFile = 0
+// NodeStatistics
+//
+// English:
+//
+// Returns the number of nodes and the greatest node ID of the pbf file, used by compress.NewNodeStore() to choose
+// between the dense file and the binary search.
+//
+//	Note:
+//	  * The pbf header does not contain the number of nodes, so the node section is read. Nodes are sorted and come
+//	    before ways, so the reading stops at the first way.
+//
+// Português:
+//
+// Devolve a quantidade de nodes e o maior ID de node do arquivo pbf, usados por compress.NewNodeStore() para escolher
+// entre o arquivo denso e a busca binária.
+//
+//	Nota:
+//	  * O cabeçalho pbf não contém a quantidade de nodes, então a seção de nodes é lida. Os nodes são ordenados e vêm
+//	    antes dos ways, então a leitura para no primeiro way.
+func (e *PbfProcess) NodeStatistics(osmFilePath string) (totalOfNodes, maxNodeID int64, err error) {
+	return e.NodeStatisticsContext(context.Background(), osmFilePath)
+}
 
+// NodeStatisticsContext
+//
+// English:
+//
+// Same as NodeStatistics(), stopping when the context is canceled.
+//
+// Português:
+//
+// Igual a NodeStatistics(), parando quando o contexto é cancelado.
+func (e *PbfProcess) NodeStatisticsContext(ctx context.Context, osmFilePath string) (totalOfNodes, maxNodeID int64, err error) {
 	var osmFile *os.File
 	osmFile, err = os.Open(osmFilePath)
 	if err != nil {
-		err = fmt.Errorf("PbfProcess.DatabaseOnly().Create().Error: %v", err)
+		err = fmt.Errorf("PbfProcess.NodeStatistics().Open().Error: %v", err)
 		return
 	}
 
-	defer func() {
-		err := osmFile.Close()
-		if err != nil {
-			log.Printf("error closing main osm source file: %v", err.Error())
-		}
-	}()
-
-	osmDecoder := osmpbf.NewDecoder(osmFile)
-
-	// use more memory from the start, it is faster
-	osmDecoder.SetBufferSize(osmpbf.MaxBlobSize)
-
-	// start decoding with several goroutines, it is faster
-	err = osmDecoder.Start(runtime.GOMAXPROCS(-1))
+	var format OsmFormat
+	format, err = detectOsmFormat(osmFile, osmFilePath)
 	if err != nil {
-		err = fmt.Errorf("PbfProcess.DatabaseOnly().Start().Error: %v", err)
+		_ = osmFile.Close()
+		err = fmt.Errorf("PbfProcess.NodeStatistics().detectOsmFormat().Error: %v", err)
 		return
 	}
 
-	nodeList := make([]Node, 0)
-	lon := 0.0
-	lat := 0.0
-	wayList := make([]Way, 0)
-	counter := 0
-
-	tmpNode := Node{}
+	var osmDecoder osmElementDecoder
+	osmDecoder, err = newOsmFileDecoder(osmFile, format, nil)
+	if err != nil {
+		_ = osmFile.Close()
+		err = fmt.Errorf("PbfProcess.NodeStatistics().Start().Error: %v", err)
+		return
+	}
 
+	var osmPbfElement interface{}
 	for {
-		var osmPbfElement interface{}
-		if osmPbfElement, err = osmDecoder.Decode(); err == io.EOF {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("PbfProcess.NodeStatistics().Error: %w", ctx.Err())
+			break
+		}
+
+		osmPbfElement, err = osmDecoder.Decode()
+		if err == io.EOF {
 			err = nil
 			break
 		} else if err != nil {
-			err = fmt.Errorf("PbfProcess.DatabaseOnly().Decode().Error: %v", err)
+			_ = osmFile.Close()
+			err = fmt.Errorf("PbfProcess.NodeStatistics().Decode().Error: %v", err)
 			return
-		} else {
-			switch converted := osmPbfElement.(type) {
-			case *osmpbf.Node:
-
-				e.totalOfNodesInTmpFile++
-
-				if converted.Info.Visible && len(converted.Tags) != 0 {
-
-					if len(converted.Tags) != 0 {
-						node := Node{}
-						node.Init(converted.ID, converted.Lon, converted.Lat, &converted.Tags)
-						node.MakeGeoJSonFeature()
-						if len(node.Tag) == 0 {
-							continue
-						}
-
-						nodeList = append(nodeList, node)
-						if len(nodeList) == 100 {
-							err = e.databaseNode.SetMany(&nodeList)
-							if err != nil {
-								err = fmt.Errorf("PbfProcess.DatabaseOnly().SetMany().Error: %v", err)
-								return
-							}
-
-							nodeList = make([]Node, 0)
-						}
-					}
-				}
-
-			case *osmpbf.Way:
-
-				e.totalOfWaysInTmpFile++
-
-				// English: The amount of data in the planetary file is very large and comparing with nil is faster.
-				// Português: A quantidade de dados no arquivo planetário é muito grande e comparar com nil é mais rápido.
-				if nodeList != nil && len(nodeList) != 0 { //nolint:gosimple
-					err = e.databaseNode.SetMany(&nodeList)
-					if err != nil {
-						err = fmt.Errorf("PbfProcess.DatabaseOnly().SetMany().Error: %v", err)
-						return
-					}
-
-					nodeList = nil
-				}
-
-				if !converted.Info.Visible {
-					continue
-				}
-
-				way := Way{}
-				way.Id = converted.ID
-				way.Loc = make([][2]float64, len(converted.NodeIDs))
-				//way.IdList = converted.NodeIDs
-				way.Tag = converted.Tags
-
-				for nodeKey, nodeID := range converted.NodeIDs {
-					lon, lat, err = e.compress.FindNodeByID(nodeID)
-
-					// English: downloads points not present in binary file
-					// Português: faz o download de pontos não presentes no arquivo binário
-					if err != nil && err == io.EOF {
-						log.Printf("PbfProcess.DatabaseOnly().event: download ID: %v", nodeID)
-						tmpNode, err = e.downloadApi.DownloadNode(nodeID)
-						if err != nil {
-							err = fmt.Errorf("PbfProcess.DatabaseOnly().DownloadNode().Error: %v", err)
-							return
-						}
-						lon = tmpNode.Loc[Longitude]
-						lat = tmpNode.Loc[Latitude]
-					}
-
-					if err != nil {
-						err = fmt.Errorf("PbfProcess.DatabaseOnly().FindNodeByID().Error: %v", err)
-						return
-					}
-
-					way.Loc[nodeKey] = [2]float64{lon, lat}
-				}
-
-				err = way.Init()
-				if err != nil {
-					err = fmt.Errorf("PbfProcess.DatabaseOnly().Init().Error: %v", err)
-					return
-				}
-				way.MakeGeoJSonFeature()
-
-				wayList = append(wayList, way)
-				counter++
-
-				if counter == 100 {
-					err = e.databaseWay.SetMany(&wayList)
-					// todo: em caso de erro, inserir um por um e devolver os ways com erro
-					if err != nil {
-						err = fmt.Errorf("PbfProcess.DatabaseOnly().SetMany(1).Error: %v", err)
-						return
-					}
-					counter = 0
-					wayList = make([]Way, 0)
-				}
-
-			case *osmpbf.Relation:
+		}
 
-				err = e.databaseWay.SetMany(&wayList)
-				if err != nil {
-					err = fmt.Errorf("PbfProcess.DatabaseOnly().SetMany(2).Error: %v", err)
-					return
-				}
+		converted, isNode := osmPbfElement.(*osmpbf.Node)
+		if !isNode {
+			break
+		}
 
-			default:
-				err = errors.New("PbfProcess.DatabaseOnly().error: formato de dado não previsto no arquivo pbf do open street maps")
-				return
-			}
+		totalOfNodes++
+		if converted.ID > maxNodeID {
+			maxNodeID = converted.ID
 		}
 	}
 
-	ways = e.totalOfWaysInTmpFile
-	nodes = e.totalOfNodesInTmpFile
+	// English: closing the file makes the decoder stop with a read error, the blocks already read are discarded
+	// Português: fechar o arquivo faz o decoder parar com um erro de leitura, os blocos já lidos são descartados
+	_ = osmFile.Close()
+	drainDecoder(osmDecoder)
+
 	return
 }
 
@@ -941,6 +1352,25 @@ func (e *PbfProcess) DatabaseOnly(osmFilePath string) (nodes, ways uint64, err e
 //
 // Faz o processamento do arquivo do open street maps e faz apenas o arquivo binário.
 func (e *PbfProcess) BinaryNodeOnlyParser(osmFilePath string) (nodes, ways uint64, err error) {
+	return e.BinaryNodeOnlyParserContext(context.Background(), osmFilePath)
+}
+
+// BinaryNodeOnlyParserContext
+//
+// English:
+//
+// Same as BinaryNodeOnlyParser(), stopping when the context is canceled.
+//
+//	Note:
+//	  * The node file is not resumable, when canceled it is left incomplete.
+//
+// Português:
+//
+// Igual a BinaryNodeOnlyParser(), parando quando o contexto é cancelado.
+//
+//	Nota:
+//	  * O arquivo de nodes não é retomável, quando cancelado ele fica incompleto.
+func (e *PbfProcess) BinaryNodeOnlyParserContext(ctx context.Context, osmFilePath string) (nodes, ways uint64, err error) {
 
 	if e.compress == nil {
 		err = errors.New("PbfProcess.BinaryNodeOnlyParser().error: the compression object must be defined before this function is called")
@@ -952,9 +1382,6 @@ func (e *PbfProcess) BinaryNodeOnlyParser(osmFilePath string) (nodes, ways uint6
 		return
 	}
 
-	e.totalOfNodesInTmpFile = 0
-	e.totalOfWaysInTmpFile = 0
-
 	var osmFile *os.File
 	osmFile, err = os.Open(osmFilePath)
 	if err != nil {
@@ -963,25 +1390,51 @@ func (e *PbfProcess) BinaryNodeOnlyParser(osmFilePath string) (nodes, ways uint6
 	}
 
 	defer func() {
+		// English: the file is already closed when the context is canceled
+		// Português: o arquivo já está fechado quando o contexto é cancelado
 		err := osmFile.Close()
-		if err != nil {
+		if err != nil && !errors.Is(err, os.ErrClosed) {
 			log.Printf("error closing main osm source file: %v", err.Error())
 		}
 	}()
 
-	osmDecoder := osmpbf.NewDecoder(osmFile)
+	var info os.FileInfo
+	info, err = osmFile.Stat()
+	if err != nil {
+		err = fmt.Errorf("PbfProcess.BinaryNodeOnlyParser().Stat().Error: %v", err)
+		return
+	}
 
-	// use more memory from the start, it is faster
-	osmDecoder.SetBufferSize(osmpbf.MaxBlobSize)
+	e.progress.reset(info.Size())
+	e.progress.setPhase(PhaseNodes)
+	defer func() {
+		e.progress.done(err)
+	}()
+
+	var format OsmFormat
+	format, err = detectOsmFormat(osmFile, osmFilePath)
+	if err != nil {
+		err = fmt.Errorf("PbfProcess.BinaryNodeOnlyParser().detectOsmFormat().Error: %v", err)
+		return
+	}
 
-	// start decoding with several goroutines, it is faster
-	err = osmDecoder.Start(runtime.GOMAXPROCS(-1))
+	var osmDecoder osmElementDecoder
+	osmDecoder, err = newOsmFileDecoder(osmFile, format, &e.progress)
 	if err != nil {
 		err = fmt.Errorf("PbfProcess.BinaryNodeOnlyParser().Start().Error: %v", err)
 		return
 	}
 
 	for {
+		if ctx.Err() != nil {
+			// English: closing the file makes the decoder stop with a read error, the blocks already read are discarded
+			// Português: fechar o arquivo faz o decoder parar com um erro de leitura, os blocos já lidos são descartados
+			_ = osmFile.Close()
+			drainDecoder(osmDecoder)
+			err = fmt.Errorf("PbfProcess.BinaryNodeOnlyParser().Error: %w", ctx.Err())
+			return
+		}
+
 		var osmPbfElement interface{}
 		if osmPbfElement, err = osmDecoder.Decode(); err == io.EOF {
 			err = nil
@@ -993,7 +1446,8 @@ func (e *PbfProcess) BinaryNodeOnlyParser(osmFilePath string) (nodes, ways uint6
 			switch converted := osmPbfElement.(type) {
 			case *osmpbf.Node:
 
-				e.totalOfNodesInTmpFile++
+				e.progress.nodes.Add(1)
+				e.progress.tick()
 
 				err = e.compress.WriteNode(converted.ID, converted.Lon, converted.Lat)
 				if err != nil {
@@ -1036,23 +1490,192 @@ func (e *PbfProcess) BinaryNodeOnlyParser(osmFilePath string) (nodes, ways uint6
 		}
 	}
 
-	ways = e.totalOfWaysInTmpFile
-	nodes = e.totalOfNodesInTmpFile
+	ways = e.progress.ways.Load()
+	nodes = e.progress.nodes.Load()
 	return
 }
 
+// relationToPolygonList
+//
+// English:
+//
+// Converts a multipolygon or boundary relation into a polygon list, looking for the member ways in the way database
+// and, when not found, in the download api.
+//
+//	Output:
+//	  polygonList: polygon list ready to be inserted into the database;
+//	  isArea: false when the relation is not a multipolygon or boundary, or its rings could not be assembled;
+//	  err: golang error object.
+//
+// Português:
+//
+// Converte uma relation multipolygon ou boundary em uma lista de polígonos, procurando os ways membros no banco de
+// dados de ways e, quando não encontrados, na api de download.
+//
+//	Saída:
+//	  polygonList: lista de polígonos pronta para ser inserida no banco de dados;
+//	  isArea: false quando a relation não é multipolygon ou boundary, ou seus anéis não puderam ser montados;
+//	  err: objeto golang error.
+func (e *PbfProcess) relationToPolygonList(run *pbfRun, converted *osmpbf.Relation) (polygonList PolygonList, isArea bool, err error) {
+	if converted.Tags["type"] != "multipolygon" && converted.Tags["type"] != "boundary" {
+		return
+	}
+
+	var relation = Relation{}
+	relation.Id = converted.ID
+	relation.Version = int64(converted.Info.Version)
+	relation.TimeStamp = converted.Info.Timestamp
+	relation.ChangeSet = converted.Info.Changeset
+	relation.Visible = converted.Info.Visible
+	relation.UId = int64(converted.Info.Uid)
+	relation.User = converted.Info.User
+	relation.Tag = converted.Tags
+	if e.tagPolicy != nil {
+		relation.Tag, relation.International = e.tagPolicy.Apply(converted.Tags)
+	}
+	relation.Members = make([]Members, len(converted.Members))
+
+	var ways = make([]Way, 0)
+	for memberKey, member := range converted.Members {
+		switch member.Type {
+		case osmpbf.NodeType:
+			relation.Members[memberKey] = Members{Type: "node", Ref: member.ID, Role: member.Role}
+			relation.IdNode = append(relation.IdNode, member.ID)
+
+		case osmpbf.WayType:
+			relation.Members[memberKey] = Members{Type: "way", Ref: member.ID, Role: member.Role}
+			relation.IdWay = append(relation.IdWay, member.ID)
+
+			var way Way
+			way, err = e.findWayByID(member.ID)
+			if err != nil {
+				log.Printf("PbfProcess.relationToPolygonList().event: relation %v ignored, way %v not found: %v", converted.ID, member.ID, err)
+				err = e.reportElement(run, "relation", converted.ID, ErrorReasonRelationWayNotFound, fmt.Errorf("way %v not found: %v", member.ID, err), converted)
+				return
+			}
+			ways = append(ways, way)
+
+		case osmpbf.RelationType:
+			relation.Members[memberKey] = Members{Type: "relation", Ref: member.ID, Role: member.Role}
+			relation.IdRelation = append(relation.IdRelation, member.ID)
+		}
+	}
+
+	err = polygonList.AddRelationWays(&relation, ways)
+	if err != nil {
+		log.Printf("PbfProcess.relationToPolygonList().event: relation %v ignored: %v", converted.ID, err)
+		err = e.reportElement(run, "relation", converted.ID, ErrorReasonInvalidRelation, err, converted)
+		return
+	}
+
+	polygonList.MakeGeoJSonFeature()
+	isArea = true
+	return
+}
+
+// findWayByID
+//
+// English:
+//
+// Looks for a way already processed in the way binary file, then in the way database and, when not found, downloads
+// it.
+//
+// Português:
+//
+// Procura um way já processado no arquivo binário de ways, depois no banco de dados de ways e, quando não encontrado,
+// faz o download.
+func (e *PbfProcess) findWayByID(id int64) (way Way, err error) {
+	if e.wayStoreMounted {
+		way.Id = id
+		e.lookupMutex.Lock()
+		_, way.Loc, err = e.wayStore.FindWayByID(id)
+		e.lookupMutex.Unlock()
+		if err == nil {
+			return
+		}
+
+		if err != io.EOF {
+			err = fmt.Errorf("PbfProcess.findWayByID().FindWayByID().Error: %v", err)
+			return
+		}
+	}
+
+	way, err = e.databaseWay.GetById(id)
+	if err == nil {
+		return
+	}
+
+	log.Printf("PbfProcess.findWayByID().event: download ID: %v", id)
+	e.downloadMutex.Lock()
+	way, err = e.downloadApi.DownloadWay(id)
+	e.downloadMutex.Unlock()
+	if err != nil {
+		err = fmt.Errorf("PbfProcess.findWayByID().DownloadWay().Error: %v", err)
+		return
+	}
+	e.progress.download("way", id)
+
+	way.Id = id
+	return
+}
+
+// mountWayStore
+//
+// English:
+//
+// Writes the headers and indexes of the way binary file, only once, after the last way of the pbf file.
+//
+// Português:
+//
+// Escreve os cabeçalhos e índices do arquivo binário de ways, apenas uma vez, depois do último way do arquivo pbf.
+func (e *PbfProcess) mountWayStore() (err error) {
+	if e.wayStore == nil || e.wayStoreMounted {
+		return
+	}
+
+	err = e.wayStore.WriteFileHeaders()
+	if err != nil {
+		err = fmt.Errorf("PbfProcess.mountWayStore().WriteFileHeaders().Error: %v", err)
+		return
+	}
+
+	err = e.wayStore.MountIndexIntoFile()
+	if err != nil {
+		err = fmt.Errorf("PbfProcess.mountWayStore().MountIndexIntoFile().Error: %v", err)
+		return
+	}
+
+	e.wayStoreMounted = true
+	return
+}
+
+// SetProgress
+//
+// English:
+//
+// Defines the receiver of the progress events of the import, such as ProgressLog or ProgressTerminal.
+//
+// Português:
+//
+// Define o receptor dos eventos de progresso da importação, como ProgressLog ou ProgressTerminal.
+func (e *PbfProcess) SetProgress(reporter ProgressReporter) {
+	e.progress.mutex.Lock()
+	e.progress.reporter = reporter
+	e.progress.mutex.Unlock()
+}
+
 // GetPartialNumberOfProcessedData
 //
 // English:
 //
-// # Returns the partial amount of processed data
+// Returns the partial amount of processed data, safe to be called from another goroutine during the import.
 //
 // Português:
 //
-// Retorna a quantidade parcial de dados processados
+// Retorna a quantidade parcial de dados processados, seguro para ser chamado de outra goroutine durante a importação.
 func (e *PbfProcess) GetPartialNumberOfProcessedData() (nodes uint64, ways uint64) {
-	nodes = e.totalOfNodesInTmpFile
-	ways = e.totalOfWaysInTmpFile
+	nodes = e.progress.nodes.Load()
+	ways = e.progress.ways.Load()
 
 	return
 }
@@ -0,0 +1,864 @@
+package goosm
+
+import (
+	"context"
+	"fmt"
+	"github.com/qedus/osmpbf"
+	"io"
+	"log"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+const (
+
+	// pbfModeCompleteParser
+	//
+	// English:
+	//
+	// # Run of CompleteParser(), writes the node file and the database
+	//
+	// Português:
+	//
+	// Execução de CompleteParser(), escreve o arquivo de nodes e o banco de dados
+	pbfModeCompleteParser = "CompleteParser"
+
+	// pbfModeDatabaseOnly
+	//
+	// English:
+	//
+	// # Run of DatabaseOnly(), writes only the database, over a node file already made
+	//
+	// Português:
+	//
+	// Execução de DatabaseOnly(), escreve apenas o banco de dados, sobre um arquivo de nodes já feito
+	pbfModeDatabaseOnly = "DatabaseOnly"
+)
+
+// pbfRun
+//
+// English:
+//
+// State of one run of CompleteParser() or DatabaseOnly().
+//
+// Português:
+//
+// Estado de uma execução de CompleteParser() ou DatabaseOnly().
+type pbfRun struct {
+
+	// English: name of the entry point, used in the error messages and in the checkpoint
+	// Português: nome do ponto de entrada, usado nas mensagens de erro e no checkpoint
+	name string
+
+	// English: the nodes are written into the node file
+	// Português: os nodes são escritos no arquivo de nodes
+	writeNodeFile bool
+
+	// English: the node file is ready for FindNodeByID()
+	// Português: o arquivo de nodes está pronto para FindNodeByID()
+	nodeFileMounted bool
+
+	// English: format of the file, the OSM XML file is read as one segment
+	// Português: formato do arquivo, o arquivo OSM XML é lido como um segmento
+	format OsmFormat
+
+	// English: the coordinates of the ways are written into the way store
+	// Português: as coordenadas dos ways são escritas no arquivo de ways
+	useWayStore bool
+
+	// English: progress of the run, saved in the checkpoint file
+	// Português: progresso da execução, salvo no arquivo de checkpoint
+	checkpoint         Checkpoint
+	checkpointPath     string
+	checkpointInterval time.Duration
+	checkpointTime     time.Time
+
+	// English: number of elements, from the start of the file, flushed by the interrupted run, the database writes of
+	// these elements are skipped
+	// Português: quantidade de elementos, a partir do início do arquivo, enviados pela execução interrompida, as escritas
+	// no banco de dados desses elementos são puladas
+	committed uint64
+
+	// English: stages that resolve the elements and write the batches, nil when stopped
+	// Português: estágios que resolvem os elementos e escrevem os lotes, nil quando parados
+	pipeline *pbfPipeline
+
+	// English: elements inserted by the writers of the pipeline, copied into the checkpoint when it is saved
+	// Português: elementos inseridos pelos escritores do pipeline, copiados para o checkpoint quando ele é salvo
+	flushedNodes    atomic.Uint64
+	flushedWays     atomic.Uint64
+	flushedPolygons atomic.Uint64
+
+	// English: counters of the missing nodes and ways held back by MissingNodeDownload
+	// Português: contadores dos nodes ausentes e ways retidos por MissingNodeDownload
+	missing  missingNodeCounters
+	deferred pbfDeferredWays
+
+	// English: invalid elements, see SetMaxErrors()
+	// Português: elementos inválidos, veja SetMaxErrors()
+	errors atomic.Uint64
+}
+
+// parse
+//
+// English:
+//
+// Reads the pbf file segment by segment, pbfSegmentBlobs blobs each, and inserts the data into the database.
+//
+//	Input:
+//	  ctx: when canceled, the batches are flushed, the checkpoint is written and the reading stops;
+//	  name: pbfModeCompleteParser or pbfModeDatabaseOnly;
+//	  osmFilePath: path of the pbf or OSM XML file, see DetectOsmFormat();
+//	  resume: checkpoint of an interrupted run, or nil.
+//
+//	Note:
+//	  * The OSM XML file has no blobs and is read as one segment, the resume point is the number of elements read from
+//	    the start of the file.
+//
+// Português:
+//
+// Lê o arquivo pbf segmento por segmento, pbfSegmentBlobs blobs cada, e insere os dados no banco de dados.
+//
+//	Entrada:
+//	  ctx: quando cancelado, os lotes são enviados, o checkpoint é escrito e a leitura para;
+//	  name: pbfModeCompleteParser ou pbfModeDatabaseOnly;
+//	  osmFilePath: caminho do arquivo pbf ou OSM XML, veja DetectOsmFormat();
+//	  resume: checkpoint de uma execução interrompida, ou nil.
+//
+//	Nota:
+//	  * O arquivo OSM XML não tem blobs e é lido como um segmento, o ponto de retomada é a quantidade de elementos lidos
+//	    a partir do início do arquivo.
+func (e *PbfProcess) parse(ctx context.Context, name, osmFilePath string, resume *Checkpoint) (nodes, ways uint64, err error) {
+
+	if e.compress == nil {
+		err = fmt.Errorf("PbfProcess.%v().error: the compression object must be defined before this function is called", name)
+		return
+	}
+
+	if e.downloadApi == nil {
+		err = fmt.Errorf("PbfProcess.%v().error: the download object must be defined before this function is called", name)
+		return
+	}
+
+	if e.databaseNode == nil {
+		err = fmt.Errorf("PbfProcess.%v().error: the databaseNode object must be defined before this function is called", name)
+		return
+	}
+
+	if e.databaseWay == nil {
+		err = fmt.Errorf("PbfProcess.%v().error: the databaseWay object must be defined before this function is called", name)
+		return
+	}
+
+	e.wayStoreMounted = false
+
+	var run = pbfRun{
+		name:               name,
+		writeNodeFile:      name == pbfModeCompleteParser,
+		useWayStore:        e.wayStore != nil,
+		checkpointPath:     e.checkpointPath,
+		checkpointInterval: e.checkpointInterval,
+		checkpointTime:     time.Now(),
+	}
+
+	if run.checkpointInterval <= 0 {
+		run.checkpointInterval = CheckpointDefaultInterval
+	}
+
+	var osmFile *os.File
+	osmFile, err = os.Open(osmFilePath)
+	if err != nil {
+		err = fmt.Errorf("PbfProcess.%v().Open().Error: %v", name, err)
+		return
+	}
+
+	defer func() {
+		err := osmFile.Close()
+		if err != nil {
+			log.Printf("error closing main osm source file: %v", err.Error())
+		}
+	}()
+
+	var info os.FileInfo
+	info, err = osmFile.Stat()
+	if err != nil {
+		err = fmt.Errorf("PbfProcess.%v().Stat().Error: %v", name, err)
+		return
+	}
+
+	e.progress.reset(info.Size())
+	defer func() {
+		e.progress.done(err)
+	}()
+
+	run.format, err = detectOsmFormat(osmFile, osmFilePath)
+	if err != nil {
+		err = fmt.Errorf("PbfProcess.%v().detectOsmFormat().Error: %v", name, err)
+		return
+	}
+
+	var header []byte
+	var blobs []pbfBlob
+	if run.format == OsmFormatPbf {
+		header, blobs, err = readPbfBlobs(osmFile)
+		if err != nil {
+			err = fmt.Errorf("PbfProcess.%v().readPbfBlobs().Error: %v", name, err)
+			return
+		}
+	} else {
+		blobs = []pbfBlob{{offset: 0, size: info.Size()}}
+	}
+
+	run.checkpoint = Checkpoint{File: osmFilePath, FileSize: info.Size(), Mode: name, Phase: PhaseNodes}
+
+	var first = 0
+	var skip uint64
+	if resume != nil {
+		first, skip, err = e.resumeRun(&run, resume, blobs)
+		if err != nil {
+			return
+		}
+	}
+
+	if e.importLog != nil {
+		var importRun *ImportRun
+		importRun, err = e.startImportRun(ctx, &run, osmFile, header, resume != nil)
+		if err != nil {
+			return
+		}
+
+		defer func() {
+			var errLog = e.finishImportRun(&run, importRun, err)
+			if err == nil {
+				err = errLog
+			}
+		}()
+	}
+
+	run.missing.restore(run.checkpoint.MissingNodes)
+	run.errors.Store(run.checkpoint.Errors)
+	defer func() {
+		e.errorCount = run.errors.Load()
+		e.missingNodeReport = run.missing.report(e.missingNodePolicy)
+		if e.missingNodeReport.Ways != 0 {
+			log.Printf("PbfProcess.%v().event: missing nodes, %v", name, e.missingNodeReport)
+		}
+	}()
+
+	if run.checkpoint.Phase != PhaseDone {
+		e.progress.setPhase(run.checkpoint.Phase)
+
+		run.flushedNodes.Store(run.checkpoint.FlushedNodes)
+		run.flushedWays.Store(run.checkpoint.FlushedWays)
+		run.flushedPolygons.Store(run.checkpoint.FlushedPolygons)
+
+		e.startPipeline(&run)
+		defer func() {
+			if run.pipeline != nil {
+				run.pipeline.abort()
+			}
+		}()
+
+		for start := first; start < len(blobs); start += pbfSegmentBlobs {
+			var end = start + pbfSegmentBlobs
+			if end > len(blobs) {
+				end = len(blobs)
+			}
+
+			err = e.parseSegment(ctx, &run, osmFile, header, blobs[start:end], skip)
+			if err != nil {
+				return
+			}
+			skip = 0
+		}
+
+		// English: saves what is left in the buffers at the end of the file
+		// Português: salva o que sobrou nos buffers ao fim do arquivo
+		err = e.flushMissingNodes(&run)
+		if err != nil {
+			return
+		}
+
+		err = e.stopPipeline(&run)
+		if err != nil {
+			return
+		}
+
+		// English: a file without ways still has a complete node file
+		// Português: um arquivo sem ways ainda tem um arquivo de nodes completo
+		err = e.mountNodeFile(&run)
+		if err != nil {
+			return
+		}
+
+		if run.useWayStore {
+			err = e.mountWayStore()
+			if err != nil {
+				err = fmt.Errorf("PbfProcess.%v().mountWayStore().Error: %v", name, err)
+				return
+			}
+		}
+
+		run.checkpoint.Phase = PhaseDone
+		err = e.saveCheckpoint(&run, true)
+		if err != nil {
+			return
+		}
+	}
+
+	ways = e.progress.ways.Load()
+	nodes = e.progress.nodes.Load()
+	return
+}
+
+// resumeRun
+//
+// English:
+//
+// Prepares the run to continue from the checkpoint of an interrupted run.
+//
+//	Output:
+//	  first: index of the first blob to be read;
+//	  skip: number of elements of the first segment already processed;
+//	  err: golang error object.
+//
+//	Note:
+//	  * The node file of CompleteParser() is not resumable during the node phase, it is rebuilt from the first blob and
+//	    only the database writes of the nodes already flushed are skipped. After the node phase, the node file must be
+//	    complete and is opened by ReadFileHeaders() and IndexToMemory().
+//	  * The way store is only written when the run is resumed from the node phase, otherwise the relations look for the
+//	    ways in the way database.
+//
+// Português:
+//
+// Prepara a execução para continuar a partir do checkpoint de uma execução interrompida.
+//
+//	Saída:
+//	  first: índice do primeiro blob a ser lido;
+//	  skip: quantidade de elementos do primeiro segmento já processados;
+//	  err: objeto golang error.
+//
+//	Nota:
+//	  * O arquivo de nodes de CompleteParser() não é retomável durante a fase de nodes, ele é reconstruído a partir do
+//	    primeiro blob e apenas as escritas no banco de dados dos nodes já enviados são puladas. Depois da fase de nodes,
+//	    o arquivo de nodes deve estar completo e é aberto por ReadFileHeaders() e IndexToMemory().
+//	  * O arquivo de ways só é escrito quando a execução é retomada da fase de nodes, caso contrário as relations
+//	    procuram os ways no banco de dados de ways.
+func (e *PbfProcess) resumeRun(run *pbfRun, resume *Checkpoint, blobs []pbfBlob) (first int, skip uint64, err error) {
+	if resume.Mode != run.name {
+		err = fmt.Errorf("PbfProcess.%v().error: the checkpoint was written by %v", run.name, resume.Mode)
+		return
+	}
+
+	if resume.FileSize != run.checkpoint.FileSize {
+		err = fmt.Errorf("PbfProcess.%v().error: the checkpoint was written for another file, of %v bytes", run.name, resume.FileSize)
+		return
+	}
+
+	if resume.Phase == PhaseDone {
+		run.checkpoint = *resume
+		e.resumeCounters(resume)
+		return
+	}
+
+	if resume.Phase == PhaseNodes && run.writeNodeFile {
+		run.committed = resume.Nodes + resume.Ways + resume.Relations
+		run.checkpoint.FlushedNodes = resume.FlushedNodes
+		return
+	}
+
+	// English: the checkpoint written at the end of the last segment points to the end of the file
+	// Português: o checkpoint escrito no fim do último segmento aponta para o fim do arquivo
+	first = len(blobs)
+	for key, blob := range blobs {
+		if blob.offset == resume.BlobOffset {
+			first = key
+			break
+		}
+	}
+
+	if first == len(blobs) && (len(blobs) == 0 || resume.BlobOffset != blobs[len(blobs)-1].offset+blobs[len(blobs)-1].size) {
+		err = fmt.Errorf("PbfProcess.%v().error: the checkpoint blob offset %v was not found in the pbf file", run.name, resume.BlobOffset)
+		return
+	}
+
+	run.checkpoint = *resume
+	skip = resume.BlobElements
+	e.resumeCounters(resume)
+
+	if resume.Phase == PhaseNodes {
+		return
+	}
+
+	if run.writeNodeFile {
+		err = e.compress.ReadFileHeaders()
+		if err != nil {
+			err = fmt.Errorf("PbfProcess.%v().ReadFileHeaders().Error: %v", run.name, err)
+			return
+		}
+
+		err = e.compress.IndexToMemory()
+		if err != nil {
+			err = fmt.Errorf("PbfProcess.%v().IndexToMemory().Error: %v", run.name, err)
+			return
+		}
+	}
+	run.nodeFileMounted = true
+
+	if run.useWayStore {
+		log.Printf("PbfProcess.%v().event: the way store is not resumed after the node phase, the relations use the way database", run.name)
+		run.useWayStore = false
+	}
+	return
+}
+
+// resumeCounters
+//
+// English:
+//
+// # Starts the progress counters from the counters of the checkpoint
+//
+// Português:
+//
+// Inicia os contadores de progresso a partir dos contadores do checkpoint
+func (e *PbfProcess) resumeCounters(resume *Checkpoint) {
+	e.progress.nodes.Store(resume.Nodes)
+	e.progress.ways.Store(resume.Ways)
+	e.progress.relations.Store(resume.Relations)
+	e.progress.bytesRead.Store(resume.BlobOffset)
+}
+
+// parseSegment
+//
+// English:
+//
+// Decodes one segment of the pbf file, or the whole OSM XML file, skipping the first elements already processed by an
+// interrupted run.
+//
+// Português:
+//
+// Decodifica um segmento do arquivo pbf, ou o arquivo OSM XML inteiro, pulando os primeiros elementos já processados
+// por uma execução interrompida.
+func (e *PbfProcess) parseSegment(ctx context.Context, run *pbfRun, osmFile *os.File, header []byte, segment []pbfBlob, skip uint64) (err error) {
+	run.checkpoint.BlobOffset = segment[0].offset
+	run.checkpoint.BlobElements = 0
+
+	var osmDecoder osmElementDecoder
+	if run.format == OsmFormatPbf {
+		osmDecoder, err = newPbfSegmentDecoder(osmFile, header, segment, &e.progress)
+	} else {
+		e.progress.bytesRead.Store(0)
+		osmDecoder, err = newOsmFileDecoder(osmFile, run.format, &e.progress)
+	}
+	if err != nil {
+		err = fmt.Errorf("PbfProcess.%v().Start().Error: %v", run.name, err)
+		return
+	}
+
+	for {
+		if ctx.Err() != nil {
+			return e.cancelRun(ctx, run, osmDecoder)
+		}
+
+		var osmPbfElement interface{}
+		osmPbfElement, err = osmDecoder.Decode()
+		if err == io.EOF {
+			err = nil
+			break
+		} else if err != nil {
+			err = fmt.Errorf("PbfProcess.%v().Decode().Error: %v", run.name, err)
+			return
+		}
+
+		if skip != 0 {
+			run.checkpoint.BlobElements++
+			skip--
+			continue
+		}
+
+		var phase = pbfElementPhase(run.checkpoint.Phase, osmPbfElement)
+		if phase != run.checkpoint.Phase {
+			// English: the elements of the previous phase must be in the database before the next phase starts, the
+			// cancellation is checked again so the checkpoint keeps the previous phase
+			// Português: os elementos da fase anterior devem estar no banco de dados antes da próxima fase começar, o
+			// cancelamento é verificado de novo para que o checkpoint mantenha a fase anterior
+			err = e.flushMissingNodes(run)
+			if err == nil {
+				err = e.flushPipeline(run)
+			}
+			if err != nil {
+				drainDecoder(osmDecoder)
+				return
+			}
+
+			if ctx.Err() != nil {
+				return e.cancelRun(ctx, run, osmDecoder)
+			}
+
+			err = e.enterPhase(run, phase)
+			if err != nil {
+				drainDecoder(osmDecoder)
+				return
+			}
+		}
+
+		run.checkpoint.BlobElements++
+		err = e.parseElement(run, osmPbfElement)
+		if err != nil {
+			drainDecoder(osmDecoder)
+			return
+		}
+
+		e.progress.tick()
+
+		// English: the clock is read only once every 1024 elements
+		// Português: o relógio é lido apenas uma vez a cada 1024 elementos
+		if run.checkpoint.BlobElements&1023 == 0 {
+			err = e.saveCheckpoint(run, false)
+			if err != nil {
+				drainDecoder(osmDecoder)
+				return
+			}
+		}
+	}
+
+	var last = segment[len(segment)-1]
+	run.checkpoint.BlobOffset = last.offset + last.size
+	run.checkpoint.BlobElements = 0
+	return e.saveCheckpoint(run, false)
+}
+
+// cancelRun
+//
+// English:
+//
+// Stops the run when the context is canceled, the elements already sent are written and the checkpoint is saved.
+//
+// Português:
+//
+// Para a execução quando o contexto é cancelado, os elementos já enviados são escritos e o checkpoint é salvo.
+func (e *PbfProcess) cancelRun(ctx context.Context, run *pbfRun, osmDecoder osmElementDecoder) (err error) {
+	drainDecoder(osmDecoder)
+
+	err = e.flushMissingNodes(run)
+	if err != nil {
+		return
+	}
+
+	err = e.stopPipeline(run)
+	if err != nil {
+		return
+	}
+
+	err = e.saveCheckpoint(run, true)
+	if err != nil {
+		return
+	}
+
+	return fmt.Errorf("PbfProcess.%v().Error: %w", run.name, ctx.Err())
+}
+
+// pbfElementPhase
+//
+// English:
+//
+// Returns the phase of the run after the element, the phases only move forward, nodes, ways and relations.
+//
+// Português:
+//
+// Devolve a fase da execução depois do elemento, as fases só avançam, nodes, ways e relations.
+func pbfElementPhase(phase string, osmPbfElement interface{}) string {
+	switch osmPbfElement.(type) {
+	case *osmpbf.Way:
+		if phase == PhaseNodes {
+			return PhaseWays
+		}
+	case *osmpbf.Relation:
+		return PhaseRelations
+	}
+	return phase
+}
+
+// enterPhase
+//
+// English:
+//
+// Starts a new phase, the node file is finalized before the ways and the way store before the relations.
+//
+// Português:
+//
+// Começa uma nova fase, o arquivo de nodes é finalizado antes dos ways e o arquivo de ways antes das relations.
+func (e *PbfProcess) enterPhase(run *pbfRun, phase string) (err error) {
+	run.checkpoint.Phase = phase
+	e.progress.setPhase(phase)
+
+	err = e.mountNodeFile(run)
+	if err != nil {
+		return
+	}
+
+	// English: ways are always saved before relations in the pbf file and must be in the database before the
+	// relations are assembled.
+	// Português: ways sempre são salvos antes das relations no arquivo pbf e devem estar no banco de dados antes das
+	// relations serem montadas.
+	if phase == PhaseRelations && run.useWayStore {
+		err = e.mountWayStore()
+		if err != nil {
+			err = fmt.Errorf("PbfProcess.%v().mountWayStore().Error: %v", run.name, err)
+		}
+	}
+	return
+}
+
+// parseElement
+//
+// English:
+//
+// Writes one element of the pbf file into the node file and sends it to the pipeline, when it goes to the database.
+//
+// Português:
+//
+// Escreve um elemento do arquivo pbf no arquivo de nodes e o envia ao pipeline, quando ele vai para o banco de dados.
+func (e *PbfProcess) parseElement(run *pbfRun, osmPbfElement interface{}) (err error) {
+	var committed = run.checkpoint.Nodes+run.checkpoint.Ways+run.checkpoint.Relations < run.committed
+
+	switch converted := osmPbfElement.(type) {
+	case *osmpbf.Node:
+
+		e.progress.nodes.Add(1)
+		run.checkpoint.Nodes++
+
+		if run.writeNodeFile {
+			err = e.compress.WriteNode(converted.ID, converted.Lon, converted.Lat)
+			if err != nil {
+				err = fmt.Errorf("PbfProcess.%v().WriteNode().Error: %v", run.name, err)
+				return
+			}
+		}
+
+		if committed || !converted.Info.Visible || len(converted.Tags) == 0 || !e.tagFilter.MatchNode(converted.Tags) {
+			return
+		}
+
+		if e.clip != nil && !e.clip.Contains([2]float64{converted.Lon, converted.Lat}) {
+			return
+		}
+
+	case *osmpbf.Way:
+
+		e.progress.ways.Add(1)
+		run.checkpoint.Ways++
+
+		if !converted.Info.Visible {
+			return
+		}
+
+		// English: the ways rejected by the filter are still written into the way store when relations may be imported
+		// Português: os ways rejeitados pelo filtro ainda são escritos no arquivo de ways quando relations podem ser
+		// importadas
+		if !e.tagFilter.MatchWay(converted.Tags) {
+			if !e.wayStoreForRelations(run) {
+				return
+			}
+			return run.pipeline.send(osmPbfElement, true)
+		}
+
+	case *osmpbf.Relation:
+
+		e.progress.relations.Add(1)
+		run.checkpoint.Relations++
+
+		if e.databasePolygon == nil || !converted.Info.Visible || !e.tagFilter.MatchRelation(converted.Tags) {
+			return
+		}
+
+	default:
+		err = fmt.Errorf("PbfProcess.%v().error: formato de dado não previsto no arquivo pbf do open street maps", run.name)
+		return
+	}
+
+	return run.pipeline.send(osmPbfElement, false)
+}
+
+// wayFromPbf
+//
+// English:
+//
+// Fills the coordinates of the way with the node file, the nodes not present in the file follow the missing node
+// policy.
+//
+//	Output:
+//	  keep: false when the way was dropped or held back by the policy.
+//
+//	Note:
+//	  * Called by the resolvers of the pipeline at the same time, the node file is locked once per way, unless a node
+//	    reader was defined by SetNodeReader().
+//
+// Português:
+//
+// Preenche as coordenadas do way com o arquivo de nodes, os nodes não presentes no arquivo seguem a política de nodes
+// ausentes.
+//
+//	Saída:
+//	  keep: false quando o way foi descartado ou retido pela política.
+//
+//	Nota:
+//	  * Chamada pelos resolvedores do pipeline ao mesmo tempo, o arquivo de nodes é travado uma vez por way, a menos que
+//	    um leitor de nodes tenha sido definido por SetNodeReader().
+func (e *PbfProcess) wayFromPbf(run *pbfRun, converted *osmpbf.Way, wayStoreOnly bool) (way Way, keep bool, err error) {
+	way.Id = converted.ID
+	way.Loc = make([][2]float64, len(converted.NodeIDs))
+	way.Tag = converted.Tags
+	way.areaRules = e.areaRules
+	if e.metadata {
+		way.Metadata = newMetadata(converted.Info)
+	}
+
+	var findNodeByID = e.compress.FindNodeByID
+	if e.nodeReader != nil {
+		findNodeByID = e.nodeReader.FindNodeByID
+	} else {
+		e.lookupMutex.Lock()
+	}
+
+	var missing []int
+	var lon, lat float64
+	for nodeKey, nodeID := range converted.NodeIDs {
+		lon, lat, err = findNodeByID(nodeID)
+		if err == io.EOF {
+			missing = append(missing, nodeKey)
+			err = nil
+			continue
+		}
+
+		if err != nil {
+			err = fmt.Errorf("PbfProcess.%v().FindNodeByID().Error: %v", run.name, err)
+			break
+		}
+
+		way.Loc[nodeKey] = [2]float64{lon, lat}
+	}
+
+	if e.nodeReader == nil {
+		e.lookupMutex.Unlock()
+	}
+
+	if err != nil {
+		return
+	}
+
+	if len(missing) == 0 {
+		keep = true
+		return
+	}
+
+	keep, err = e.missingNodes(run, &way, converted.NodeIDs, missing, wayStoreOnly)
+	if err != nil || keep || wayStoreOnly || e.missingNodePolicy == MissingNodeDownload {
+		return
+	}
+
+	err = e.reportElement(run, "way", way.Id, ErrorReasonMissingNodes, fmt.Errorf("%v of %v nodes not found", len(missing), len(converted.NodeIDs)), converted)
+	return
+}
+
+// wayStoreForRelations
+//
+// English:
+//
+// # Returns true when the ways out of the import must still be written into the way store, for the relations
+//
+// Português:
+//
+// Devolve true quando os ways fora da importação ainda devem ser escritos no arquivo de ways, para as relations
+func (e *PbfProcess) wayStoreForRelations(run *pbfRun) bool {
+	return run.useWayStore && e.databasePolygon != nil && e.tagFilter.selects(tagFilterRelation)
+}
+
+// mountNodeFile
+//
+// English:
+//
+// Writes the headers and indexes of the node file and loads the index, only once, after the last node.
+//
+// Português:
+//
+// Escreve os cabeçalhos e índices do arquivo de nodes e carrega o índice, apenas uma vez, depois do último node.
+func (e *PbfProcess) mountNodeFile(run *pbfRun) (err error) {
+	if !run.writeNodeFile || run.nodeFileMounted {
+		return
+	}
+	run.nodeFileMounted = true
+
+	err = e.compress.WriteFileHeaders()
+	if err != nil {
+		err = fmt.Errorf("PbfProcess.%v().WriteFileHeaders().Error: %v", run.name, err)
+		return
+	}
+
+	err = e.compress.MountIndexIntoFile()
+	if err != nil {
+		err = fmt.Errorf("PbfProcess.%v().MountIndexIntoFile().Error: %v", run.name, err)
+		return
+	}
+
+	err = e.compress.ReadFileHeaders()
+	if err != nil {
+		err = fmt.Errorf("PbfProcess.%v().ReadFileHeaders().Error: %v", run.name, err)
+		return
+	}
+
+	err = e.compress.IndexToMemory()
+	if err != nil {
+		err = fmt.Errorf("PbfProcess.%v().IndexToMemory().Error: %v", run.name, err)
+	}
+	return
+}
+
+// saveCheckpoint
+//
+// English:
+//
+// Waits for the pipeline to write all the elements sent and writes the checkpoint file, when a file was defined and
+// the interval has passed since the last write, or always when force is true.
+//
+// Português:
+//
+// Espera o pipeline escrever todos os elementos enviados e escreve o arquivo de checkpoint, quando um arquivo foi
+// definido e o intervalo passou desde a última escrita, ou sempre quando force é true.
+func (e *PbfProcess) saveCheckpoint(run *pbfRun, force bool) (err error) {
+	if run.checkpointPath == "" {
+		return
+	}
+
+	if !force && time.Since(run.checkpointTime) < run.checkpointInterval {
+		return
+	}
+
+	// English: the checkpoint is only valid when all the elements before it are in the database
+	// Português: o checkpoint só é válido quando todos os elementos antes dele estão no banco de dados
+	err = e.flushMissingNodes(run)
+	if err != nil {
+		return
+	}
+
+	err = e.flushPipeline(run)
+	if err != nil {
+		return
+	}
+
+	run.checkpoint.MissingNodes = run.missing.report(e.missingNodePolicy)
+	run.checkpoint.Errors = run.errors.Load()
+	run.checkpoint.FlushedNodes = run.flushedNodes.Load()
+	run.checkpoint.FlushedWays = run.flushedWays.Load()
+	run.checkpoint.FlushedPolygons = run.flushedPolygons.Load()
+
+	err = run.checkpoint.Save(run.checkpointPath)
+	if err != nil {
+		err = fmt.Errorf("PbfProcess.%v().Save().Error: %v", run.name, err)
+		return
+	}
+
+	run.checkpointTime = time.Now()
+	return
+}
@@ -0,0 +1,158 @@
+package goosm
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestPbfProcess
+//
+// English:
+//
+// # PbfProcess over the test node file and database
+//
+// Português:
+//
+// PbfProcess sobre o arquivo de nodes e o banco de dados de teste
+func newTestPbfProcess(database *testDatabase, nodeFile *testNodeFile) (process *PbfProcess) {
+	process = &PbfProcess{}
+	process.SetCompress(nodeFile)
+	process.SetDownloadApi(&testDownload{})
+	process.SetDatabaseNode(testDbNode{database})
+	process.SetDatabaseWay(testDbWay{database})
+	process.SetDatabasePolygon(testDbPolygon{database})
+	return
+}
+
+// TestPbfProcess_CompleteParser
+//
+// English:
+//
+// # Imports a grid with more blobs than one segment
+//
+// Português:
+//
+// Importa uma grade com mais blobs do que um segmento
+func TestPbfProcess_CompleteParser(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "grid.pbf")
+	err := testPbfGrid(path, 200)
+	if err != nil {
+		t.Logf("testPbfGrid() error: %v", err)
+		t.FailNow()
+	}
+
+	var database = newTestDatabase()
+	var nodeFile = &testNodeFile{}
+	nodeFile.Init(0)
+
+	nodes, ways, err := newTestPbfProcess(database, nodeFile).CompleteParser(path)
+	if err != nil {
+		t.Logf("CompleteParser() error: %v", err)
+		t.FailNow()
+	}
+
+	if nodes != 200 || ways != 200 {
+		t.Logf("totals error: 200, 200 != %v, %v", nodes, ways)
+		t.FailNow()
+	}
+
+	if len(database.nodes) != 200 || len(database.ways) != 200 || len(database.polygons) != 1 || nodeFile.mounted != 1 {
+		t.Logf("database error: %v nodes, %v ways, %v polygons, node file mounted %v times", len(database.nodes), len(database.ways), len(database.polygons), nodeFile.mounted)
+		t.FailNow()
+	}
+
+	if database.ways[199].Loc[1] != [2]float64{-48.0, -27.0 + 0.002} {
+		t.Logf("way 199 coordinates error: %v", database.ways[199].Loc)
+		t.FailNow()
+	}
+}
+
+// TestPbfProcess_Resume
+//
+// English:
+//
+// Cancels the import during the node phase and during the way phase and resumes it from the checkpoint, each element
+// must be inserted only once.
+//
+// Português:
+//
+// Cancela a importação durante a fase de nodes e durante a fase de ways e a retoma a partir do checkpoint, cada
+// elemento deve ser inserido apenas uma vez.
+func TestPbfProcess_Resume(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "grid.pbf")
+	err := testPbfGrid(path, 200)
+	if err != nil {
+		t.Logf("testPbfGrid() error: %v", err)
+		t.FailNow()
+	}
+
+	for _, test := range []struct {
+		kind  string
+		phase string
+	}{
+		{kind: "node", phase: PhaseNodes},
+		{kind: "way", phase: PhaseWays},
+	} {
+		var checkpoint = filepath.Join(t.TempDir(), "checkpoint.json")
+		var database = newTestDatabase()
+		var nodeFile = &testNodeFile{}
+		nodeFile.Init(0)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		database.onSetMany = func(kind string) {
+			if kind == test.kind {
+				cancel()
+			}
+		}
+
+		var process = newTestPbfProcess(database, nodeFile)
+		process.SetCheckpoint(checkpoint, time.Nanosecond)
+		_, _, err = process.CompleteParserContext(ctx, path)
+		cancel()
+		if !errors.Is(err, context.Canceled) {
+			t.Logf("%v: CompleteParserContext() error: %v", test.kind, err)
+			t.FailNow()
+		}
+
+		var saved Checkpoint
+		err = saved.Load(checkpoint)
+		if err != nil {
+			t.Logf("%v: Load() error: %v", test.kind, err)
+			t.FailNow()
+		}
+
+		if saved.Phase != test.phase || saved.BlobOffset == 0 {
+			t.Logf("%v: checkpoint error: %+v", test.kind, saved)
+			t.FailNow()
+		}
+
+		database.onSetMany = nil
+		nodes, ways, err := newTestPbfProcess(database, nodeFile).Resume(path, checkpoint)
+		if err != nil {
+			t.Logf("%v: Resume() error: %v", test.kind, err)
+			t.FailNow()
+		}
+
+		if nodes != 200 || ways != 200 || len(database.nodes) != 200 || len(database.ways) != 200 || len(database.polygons) != 1 {
+			t.Logf("%v: totals error: %v, %v, database %v, %v, %v", test.kind, nodes, ways, len(database.nodes), len(database.ways), len(database.polygons))
+			t.FailNow()
+		}
+
+		err = saved.Load(checkpoint)
+		if err != nil || saved.Phase != PhaseDone || saved.FlushedNodes != 200 || saved.FlushedWays != 200 {
+			t.Logf("%v: final checkpoint error: %v, %+v", test.kind, err, saved)
+			t.FailNow()
+		}
+
+		// English: a finished import does nothing
+		// Português: uma importação terminada não faz nada
+		nodes, ways, err = newTestPbfProcess(database, nodeFile).Resume(path, checkpoint)
+		if err != nil || nodes != 200 || ways != 200 {
+			t.Logf("%v: Resume() after the end error: %v, %v, %v", test.kind, err, nodes, ways)
+			t.FailNow()
+		}
+	}
+}
@@ -3,8 +3,6 @@ package goosm
 import (
 	"errors"
 	"goosm/module/util"
-	"log"
-	"math"
 	"strconv"
 )
 
@@ -129,6 +127,11 @@ type Polygon struct {
 	// Português: caixa de perímetro em BSon para o MongoDB
 	//BBoxBSon       bson.M   `bson:"bBoxBSon"`
 
+	// English: role of the polygon inside a multipolygon relation, "outer" or "inner"
+	//
+	// Português: papel do polígono dentro de uma relation multipolygon, "outer" ou "inner"
+	Role string `bson:"role"`
+
 	GeoJSonFeature string `bson:"geoJSonFeature"`
 	tmp            []Way
 
@@ -289,6 +292,75 @@ func (el *Polygon) AddWayAsPreProcessingPolygon(way *Way) {
 	el.tmp = append(el.tmp, *way)
 }
 
+// English: Joins the open ways added by AddWayAsPreProcessingPolygon() into a single closed ring, reversing the ways
+// when necessary, so that the last point of one way is the first point of the next.
+//
+// Português: Une os ways abertos adicionados por AddWayAsPreProcessingPolygon() em um único anel fechado, invertendo
+// os ways quando necessário, de forma que o último ponto de um way seja o primeiro ponto do próximo.
+
+func (el *Polygon) mergeTmpWays() (err error) {
+	var ways = make([]Way, 0, len(el.tmp))
+	for _, way := range el.tmp {
+		if len(way.Loc) != 0 {
+			ways = append(ways, way)
+		}
+	}
+
+	if len(ways) == 0 {
+		el.tmp = nil
+		return
+	}
+
+	var used = make([]bool, len(ways))
+	var ring = make([][2]float64, 0)
+
+	ring = append(ring, ways[0].Loc...)
+	used[0] = true
+
+	for counter := 1; counter != len(ways); counter += 1 {
+		var last = ring[len(ring)-1]
+		var found = false
+
+		for k := range ways {
+			if used[k] {
+				continue
+			}
+
+			var length = len(ways[k].Loc) - 1
+			if ways[k].Loc[0] == last {
+				ring = append(ring, ways[k].Loc[1:]...)
+			} else if ways[k].Loc[length] == last {
+				for i := length - 1; i != -1; i -= 1 {
+					ring = append(ring, ways[k].Loc[i])
+				}
+			} else {
+				continue
+			}
+
+			used[k] = true
+			found = true
+			break
+		}
+
+		if !found {
+			err = errors.New("the ways of the polygon do not form a continuous line")
+			return
+		}
+	}
+
+	if ring[0] != ring[len(ring)-1] {
+		err = errors.New("the ways of the polygon do not form a closed ring")
+		return
+	}
+
+	for _, loc := range ring {
+		el.AddLngLatDegrees(loc[Longitude], loc[Latitude])
+	}
+
+	el.tmp = nil
+	return
+}
+
 // English: Transforms the type PointList in a polygon
 //
 // Português: Transforma o tipo PointList em um polígono
@@ -426,117 +498,20 @@ func (el *Polygon) AddTag(keyAStr, valueAStr string) {
 // Note que esta função deve ser chamada a cada alteração nos pontos do polígono.
 
 func (el *Polygon) Init() (err error) {
-	var distanceList []float64
-	var distance float64
-	var distanceKey int
-	//var distanceAStartBStart float64 = math.MaxFloat64
-	//var distanceAStartBEnd float64   = math.MaxFloat64
-	//var distanceAEndBStart float64   = math.MaxFloat64
-	//var distanceAEndBEnd float64     = math.MaxFloat64
-	var k1, k2, lengthStart, lengthTmp int
-	//var inverter bool = false
-	var pass bool = false
-	//var addToEndOfTheSet bool = false
-	var pythagorasAStartBStart, pythagorasAStartBEnd, pythagorasAEndBStart, pythagorasAEndBEnd []float64
-	var xStart, xEnd, xTmpStart, xTmpEnd float64
-	var yStart, yEnd, yTmpStart, yTmpEnd float64
+	// English: open ways added by AddWayAsPreProcessingPolygon() must be joined before the points are tested
+	// Português: ways abertos adicionados por AddWayAsPreProcessingPolygon() devem ser unidos antes dos pontos serem testados
+	if len(el.tmp) > 0 {
+		err = el.mergeTmpWays()
+		if err != nil {
+			return
+		}
+	}
 
 	if len(el.PointsList) < 3 {
 		err = errors.New("minimal number of points is 3")
 		return
 	}
 
-	if el.PointsList[0].Loc[0] != el.PointsList[len(el.PointsList)-1].Loc[0] || el.PointsList[0].Loc[1] != el.PointsList[len(el.PointsList)-1].Loc[1] {
-		el.PointsList = append(el.PointsList, el.PointsList[0])
-	}
-
-	if len(el.tmp) > 0 {
-
-		for _, loc := range el.tmp[0].Loc {
-			el.AddLngLatDegrees(loc[0], loc[1])
-		}
-
-		pythagorasAStartBStart = make([]float64, len(el.tmp))
-		pythagorasAStartBEnd = make([]float64, len(el.tmp))
-		pythagorasAEndBStart = make([]float64, len(el.tmp))
-		pythagorasAEndBEnd = make([]float64, len(el.tmp))
-
-		distanceList = make([]float64, len(el.tmp))
-
-		for k2 = 1; k2 != len(el.tmp); k2 += 1 {
-			distance = math.MaxFloat64
-
-			for k1 = 0; k1 != len(el.tmp); k1 += 1 {
-				distanceList[k1] = math.MaxFloat64
-			}
-
-			lengthStart = len(el.PointsList) - 1
-
-			xStart = el.PointsList[0].Loc[0]
-			yStart = el.PointsList[0].Loc[1]
-			xEnd = el.PointsList[lengthStart].Loc[0]
-			yEnd = el.PointsList[lengthStart].Loc[1]
-
-			for k1 = 1; k1 != len(el.tmp); k1 += 1 {
-
-				lengthTmp = len(el.tmp[k1].Loc) - 1
-
-				xTmpStart = el.tmp[k1].Loc[0][0]
-				yTmpStart = el.tmp[k1].Loc[0][1]
-				xTmpEnd = el.tmp[k1].Loc[lengthTmp][0]
-				yTmpEnd = el.tmp[k1].Loc[lengthTmp][1]
-
-				pythagorasAStartBStart[k1] = util.Pythagoras(xStart, yStart, xTmpStart, yTmpStart)
-				pythagorasAStartBEnd[k1] = util.Pythagoras(xStart, yStart, xTmpEnd, yTmpEnd)
-				pythagorasAEndBStart[k1] = util.Pythagoras(xEnd, yEnd, xTmpStart, yTmpStart)
-				pythagorasAEndBEnd[k1] = util.Pythagoras(xEnd, yEnd, xTmpEnd, yTmpEnd)
-
-				distanceList[k1] = math.Min(distanceList[k1], pythagorasAStartBStart[k1])
-				distanceList[k1] = math.Min(distanceList[k1], pythagorasAStartBEnd[k1])
-				distanceList[k1] = math.Min(distanceList[k1], pythagorasAEndBStart[k1])
-				distanceList[k1] = math.Min(distanceList[k1], pythagorasAEndBEnd[k1])
-
-				if distanceList[k1] < distance {
-					distance = distanceList[k1]
-					distanceKey = k1
-				}
-			}
-
-			if pythagorasAStartBStart[distanceKey] < pythagorasAStartBEnd[distanceKey] && pythagorasAStartBStart[distanceKey] < pythagorasAEndBStart[distanceKey] && pythagorasAStartBStart[distanceKey] < pythagorasAEndBEnd[distanceKey] {
-				pass = true
-				for _, loc := range el.tmp[distanceKey].Loc {
-					el.AddLngLatDegreesAtStart(loc[0], loc[1])
-				}
-			} else if pythagorasAStartBStart[distanceKey] < pythagorasAStartBEnd[distanceKey] && pythagorasAEndBStart[distanceKey] < pythagorasAStartBEnd[distanceKey] && pythagorasAEndBEnd[distanceKey] < pythagorasAStartBEnd[distanceKey] {
-				pass = true
-				for _, loc := range el.tmp[distanceKey].Loc {
-					el.AddLngLatDegrees(loc[0], loc[1])
-				}
-			} else if pythagorasAStartBStart[distanceKey] < pythagorasAEndBStart[distanceKey] && pythagorasAStartBEnd[distanceKey] < pythagorasAEndBStart[distanceKey] && pythagorasAEndBEnd[distanceKey] < pythagorasAEndBStart[distanceKey] {
-				pass = true
-				//for _, loc := range el.tmp[k1].Loc {
-				for i := len(el.tmp[distanceKey].Loc) - 1; i != -1; i -= 1 {
-					el.AddLngLatDegreesAtStart(el.tmp[distanceKey].Loc[i][0], el.tmp[distanceKey].Loc[i][1])
-				}
-			} else if pythagorasAStartBStart[distanceKey] < pythagorasAEndBEnd[distanceKey] && pythagorasAStartBEnd[distanceKey] < pythagorasAEndBEnd[distanceKey] && pythagorasAEndBStart[distanceKey] < pythagorasAEndBEnd[distanceKey] {
-				pass = true
-				for i := len(el.tmp[distanceKey].Loc) - 1; i != -1; i -= 1 {
-					el.AddLngLatDegrees(el.tmp[distanceKey].Loc[i][0], el.tmp[distanceKey].Loc[i][1])
-				}
-			} else {
-				pass = true
-				//for _, loc := range el.tmp[k1].Loc {
-				for i := len(el.tmp[distanceKey].Loc) - 1; i != -1; i -= 1 {
-					el.AddLngLatDegrees(el.tmp[distanceKey].Loc[i][0], el.tmp[distanceKey].Loc[i][1])
-				}
-			}
-
-			if pass == true {
-				log.Fatalf("")
-			}
-		}
-	}
-
 	if len(el.PointsList) == 0 {
 		return errors.New("polygon has't points")
 	}
@@ -2,6 +2,7 @@ package goosm
 
 import (
 	"encoding/binary"
+	"fmt"
 	"strconv"
 	"time"
 )
@@ -62,6 +63,19 @@ func (el *PolygonList) GetIdAsByte() []byte {
 	return ret
 }
 
+// Name
+//
+// English:
+//
+// # Returns the name of the polygon, copied from the relation, in the first language found, see Way.Name()
+//
+// Português:
+//
+// Devolve o nome do polígono, copiado da relation, no primeiro idioma encontrado, veja Way.Name()
+func (el *PolygonList) Name(lang ...string) (name string) {
+	return tagName(el.Tag, el.International, lang...)
+}
+
 // en: Copies the data of the relation in the polygon.
 //
 // pt: Copia os dados de uma relação no polígono.
@@ -137,6 +151,171 @@ func (el *PolygonList) AddPolygon(polygon *Polygon) {
 	el.List = append(el.List, *polygon)
 }
 
+// AddRelationWays
+//
+// English:
+//
+// Assembles the outer and inner rings of a multipolygon or boundary relation from the member ways.
+//
+//	Input:
+//	  relation: relation that owns the ways;
+//	  ways: member ways of the relation with the geographic coordinates already filled in.
+//
+//	Note:
+//	  * A closed way forms a ring on its own;
+//	  * Open ways that touch each other are joined using Polygon.AddWayAsPreProcessingPolygon();
+//	  * Members with an empty role are treated as "outer" and other roles are ignored.
+//
+// Português:
+//
+// Monta os anéis externos e internos de uma relation multipolygon ou boundary a partir dos ways membros.
+//
+//	Entrada:
+//	  relation: relation dona dos ways;
+//	  ways: ways membros da relation com as coordenadas geográficas já preenchidas.
+//
+//	Nota:
+//	  * Um way fechado forma um anel sozinho;
+//	  * Ways abertos que se tocam são unidos usando Polygon.AddWayAsPreProcessingPolygon();
+//	  * Membros com papel vazio são tratados como "outer" e os demais papéis são ignorados.
+func (el *PolygonList) AddRelationWays(relation *Relation, ways []Way) (err error) {
+	el.Id = relation.Id
+	el.AddRelationDataAsPolygonData(relation)
+
+	var roleByWayId = make(map[int64]string)
+	for _, member := range relation.Members {
+		if member.Type != "way" {
+			continue
+		}
+
+		if member.Role == "" {
+			roleByWayId[member.Ref] = "outer"
+			continue
+		}
+
+		roleByWayId[member.Ref] = member.Role
+	}
+
+	for _, role := range []string{"outer", "inner"} {
+		var closedWays = make([]Way, 0)
+		var openWays = make([]Way, 0)
+
+		for _, way := range ways {
+			if roleByWayId[way.Id] != role || len(way.Loc) == 0 {
+				continue
+			}
+
+			if len(el.TagFromWay) == 0 {
+				el.TagFromWay = make(map[string]map[string]string)
+			}
+			el.TagFromWay[strconv.FormatInt(way.Id, 10)] = way.Tag
+
+			if len(el.idWayUnique) == 0 {
+				el.idWayUnique = make(map[int64]int64)
+			}
+			if el.idWayUnique[way.Id] != way.Id {
+				el.idWayUnique[way.Id] = way.Id
+				el.IdWay = append(el.IdWay, way.Id)
+			}
+
+			if way.Loc[0] == way.Loc[len(way.Loc)-1] {
+				closedWays = append(closedWays, way)
+				continue
+			}
+
+			openWays = append(openWays, way)
+		}
+
+		var rings = el.groupOpenWays(openWays)
+		for k := range closedWays {
+			rings = append(rings, closedWays[k:k+1])
+		}
+
+		for _, ring := range rings {
+			var polygon = Polygon{}
+			polygon.Id = ring[0].Id
+			polygon.Role = role
+			if role == "outer" {
+				polygon.AddRelationDataAsPolygonData(relation)
+			}
+
+			for k := range ring {
+				polygon.AddWayAsPreProcessingPolygon(&ring[k])
+			}
+
+			if !polygon.Initialize {
+				err = polygon.Init()
+				if err != nil {
+					err = fmt.Errorf("PolygonList.AddRelationWays().Init(%v).error: %v", relation.Id, err)
+					return
+				}
+			}
+
+			el.AddPolygon(&polygon)
+		}
+	}
+
+	if len(el.List) == 0 {
+		err = fmt.Errorf("PolygonList.AddRelationWays().error: relation %v has no closed ring", relation.Id)
+		return
+	}
+
+	err = el.Initialize()
+	return
+}
+
+// groupOpenWays
+//
+// English:
+//
+// Separates the open ways into groups of ways that touch each other by the end points, where each group must form a
+// single ring.
+//
+// Português:
+//
+// Separa os ways abertos em grupos de ways que se tocam pelas pontas, onde cada grupo deve formar um único anel.
+func (el *PolygonList) groupOpenWays(ways []Way) (groups [][]Way) {
+	var parent = make([]int, len(ways))
+	for k := range parent {
+		parent[k] = k
+	}
+
+	var find func(k int) int
+	find = func(k int) int {
+		for parent[k] != k {
+			parent[k] = parent[parent[k]]
+			k = parent[k]
+		}
+		return k
+	}
+
+	var wayByEndPoint = make(map[[2]float64]int)
+	for k, way := range ways {
+		for _, loc := range [][2]float64{way.Loc[0], way.Loc[len(way.Loc)-1]} {
+			if other, found := wayByEndPoint[loc]; found {
+				parent[find(k)] = find(other)
+				continue
+			}
+			wayByEndPoint[loc] = k
+		}
+	}
+
+	var groupByRoot = make(map[int]int)
+	groups = make([][]Way, 0)
+	for k, way := range ways {
+		var root = find(k)
+		if index, found := groupByRoot[root]; found {
+			groups[index] = append(groups[index], way)
+			continue
+		}
+
+		groupByRoot[root] = len(groups)
+		groups = append(groups, []Way{way})
+	}
+
+	return
+}
+
 /*func ( el *PolygonList ) FindFromPolygon( queryAObj bson.M ) error {
   err := el.DbStt.TestConnection()
   if err != nil{
@@ -0,0 +1,101 @@
+package goosm
+
+import (
+	"testing"
+)
+
+// TestPolygonList_AddRelationWays
+//
+// English:
+//
+// # Assembles a square outer ring from two open ways and a closed inner ring
+//
+// Português:
+//
+// Monta um anel externo quadrado a partir de dois ways abertos e um anel interno fechado
+func TestPolygonList_AddRelationWays(t *testing.T) {
+	var err error
+
+	relation := Relation{
+		Id:  1,
+		Tag: map[string]string{"type": "multipolygon", "natural": "water"},
+		Members: []Members{
+			{Type: "way", Ref: 10, Role: "outer"},
+			{Type: "way", Ref: 11, Role: "outer"},
+			{Type: "way", Ref: 12, Role: "inner"},
+		},
+	}
+
+	ways := []Way{
+		{Id: 10, Loc: [][2]float64{{-48.0, -27.0}, {-47.0, -27.0}, {-47.0, -26.0}}},
+		// English: reversed on purpose, it must be turned around during the assembly
+		// Português: invertido de propósito, deve ser desvirado durante a montagem
+		{Id: 11, Loc: [][2]float64{{-48.0, -27.0}, {-48.0, -26.0}, {-47.0, -26.0}}},
+		{Id: 12, Loc: [][2]float64{{-47.6, -26.6}, {-47.4, -26.6}, {-47.4, -26.4}, {-47.6, -26.6}}},
+	}
+
+	polygonList := PolygonList{}
+	err = polygonList.AddRelationWays(&relation, ways)
+	if err != nil {
+		t.Logf("AddRelationWays() error: %v", err)
+		t.FailNow()
+	}
+
+	if len(polygonList.List) != 2 {
+		t.Logf("number of rings error: 2 != %v", len(polygonList.List))
+		t.FailNow()
+	}
+
+	if polygonList.List[0].Role != "outer" || polygonList.List[1].Role != "inner" {
+		t.Logf("role error: %v, %v", polygonList.List[0].Role, polygonList.List[1].Role)
+		t.FailNow()
+	}
+
+	// English: 4 corners of the square, plus the closing point
+	// Português: 4 cantos do quadrado, mais o ponto de fechamento
+	if len(polygonList.List[0].PointsList) != 5 {
+		t.Logf("outer ring length error: 5 != %v", len(polygonList.List[0].PointsList))
+		t.FailNow()
+	}
+
+	point := Node{}
+	point.Init(0, -47.2, -26.8, nil)
+	inside, _ := polygonList.List[0].PointInPolygon(point)
+	if !inside {
+		t.Logf("point in polygon error: %v is outside the outer ring", point.Loc)
+		t.FailNow()
+	}
+
+	if len(polygonList.IdWay) != 3 {
+		t.Logf("way id list error: 3 != %v", len(polygonList.IdWay))
+		t.FailNow()
+	}
+}
+
+// TestPolygonList_AddRelationWaysOpenRing
+//
+// English:
+//
+// # A relation whose ways do not close a ring must return an error
+//
+// Português:
+//
+// Uma relation cujos ways não fecham um anel deve retornar um erro
+func TestPolygonList_AddRelationWaysOpenRing(t *testing.T) {
+	relation := Relation{
+		Id:      2,
+		Tag:     map[string]string{"type": "multipolygon"},
+		Members: []Members{{Type: "way", Ref: 10, Role: "outer"}},
+	}
+
+	ways := []Way{
+		{Id: 10, Loc: [][2]float64{{-48.0, -27.0}, {-47.0, -27.0}, {-47.0, -26.0}}},
+	}
+
+	polygonList := PolygonList{}
+	err := polygonList.AddRelationWays(&relation, ways)
+	if err == nil {
+		t.Logf("AddRelationWays() must fail for an open ring")
+		t.FailNow()
+	}
+}
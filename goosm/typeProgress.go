@@ -0,0 +1,434 @@
+package goosm
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+
+	// ProgressEventPhase
+	//
+	// English:
+	//
+	// # The import started a new phase, PhaseNodes, PhaseWays or PhaseRelations
+	//
+	// Português:
+	//
+	// A importação começou uma nova fase, PhaseNodes, PhaseWays ou PhaseRelations
+	ProgressEventPhase = "phase"
+
+	// ProgressEventTick
+	//
+	// English:
+	//
+	// # Periodic event, at most one per ProgressInterval, with the elements per second since the previous tick
+	//
+	// Português:
+	//
+	// Evento periódico, no máximo um por ProgressInterval, com os elementos por segundo desde o tick anterior
+	ProgressEventTick = "tick"
+
+	// ProgressEventBatch
+	//
+	// English:
+	//
+	// # A batch was flushed to the database, Element and Size describe the batch
+	//
+	// Português:
+	//
+	// Um lote foi enviado ao banco de dados, Element e Size descrevem o lote
+	ProgressEventBatch = "batch"
+
+	// ProgressEventDownload
+	//
+	// English:
+	//
+	// # An element not found in the files was downloaded, Element and ID describe the element
+	//
+	// Português:
+	//
+	// Um elemento não encontrado nos arquivos foi baixado, Element e ID descrevem o elemento
+	ProgressEventDownload = "download"
+
+	// ProgressEventDone
+	//
+	// English:
+	//
+	// # The import finished, Err is nil on success
+	//
+	// Português:
+	//
+	// A importação terminou, Err é nil em caso de sucesso
+	ProgressEventDone = "done"
+)
+
+// ProgressInterval
+//
+// English:
+//
+// # Minimum time between two ProgressEventTick events
+//
+// Português:
+//
+// Tempo mínimo entre dois eventos ProgressEventTick
+const ProgressInterval = time.Second
+
+// ProgressReporter
+//
+// English:
+//
+// Receives the progress events of PbfProcess, defined by SetProgress().
+//
+// The calls are serialized, one event at a time, but may come from different goroutines. The import waits for the
+// method to return, so it must be fast.
+//
+// Português:
+//
+// Recebe os eventos de progresso de PbfProcess, definido por SetProgress().
+//
+// As chamadas são serializadas, um evento por vez, mas podem vir de goroutines diferentes. A importação espera o método
+// retornar, então ele deve ser rápido.
+type ProgressReporter interface {
+	Progress(event ProgressEvent)
+}
+
+// ProgressEvent
+//
+// English:
+//
+// Progress event, with a snapshot of the counters of the import at the time of the event.
+//
+// Português:
+//
+// Evento de progresso, com uma cópia dos contadores da importação no momento do evento.
+type ProgressEvent struct {
+
+	// English: ProgressEventPhase, ProgressEventTick, ProgressEventBatch, ProgressEventDownload or ProgressEventDone
+	// Português: ProgressEventPhase, ProgressEventTick, ProgressEventBatch, ProgressEventDownload ou ProgressEventDone
+	Kind string
+
+	// English: current phase of the import
+	// Português: fase atual da importação
+	Phase string
+
+	// English: bytes of the pbf file consumed by the decoder and size of the file
+	// Português: bytes do arquivo pbf consumidos pelo decoder e tamanho do arquivo
+	BytesRead int64
+	FileSize  int64
+
+	// English: elements read from the pbf file
+	// Português: elementos lidos do arquivo pbf
+	Nodes     uint64
+	Ways      uint64
+	Relations uint64
+
+	// English: batches flushed to the database and elements downloaded
+	// Português: lotes enviados ao banco de dados e elementos baixados
+	Batches   uint64
+	Downloads uint64
+
+	// English: elements per second since the previous tick in ProgressEventTick, since the start in the other events
+	// Português: elementos por segundo desde o tick anterior em ProgressEventTick, desde o início nos outros eventos
+	ElementsPerSecond float64
+
+	// English: time since the start of the import
+	// Português: tempo desde o início da importação
+	Elapsed time.Duration
+
+	// English: "node", "way" or "polygon", for ProgressEventBatch and ProgressEventDownload
+	// Português: "node", "way" ou "polygon", para ProgressEventBatch e ProgressEventDownload
+	Element string
+
+	// English: number of elements of the batch, for ProgressEventBatch
+	// Português: quantidade de elementos do lote, para ProgressEventBatch
+	Size int
+
+	// English: ID of the downloaded element, for ProgressEventDownload
+	// Português: ID do elemento baixado, para ProgressEventDownload
+	ID int64
+
+	// English: error of the import, for ProgressEventDone
+	// Português: erro da importação, para ProgressEventDone
+	Err error
+}
+
+// Percent
+//
+// English:
+//
+// # Returns the percentage of the file consumed, between 0 and 100
+//
+// Português:
+//
+// Devolve a porcentagem do arquivo consumida, entre 0 e 100
+func (e ProgressEvent) Percent() (percent float64) {
+	if e.FileSize <= 0 {
+		return
+	}
+
+	percent = float64(e.BytesRead) * 100.0 / float64(e.FileSize)
+	if percent > 100.0 {
+		percent = 100.0
+	}
+	return
+}
+
+// Remaining
+//
+// English:
+//
+// # Returns the estimated time to the end of the file, by the average speed of the bytes consumed
+//
+// Português:
+//
+// Devolve o tempo estimado até o fim do arquivo, pela velocidade média dos bytes consumidos
+func (e ProgressEvent) Remaining() (remaining time.Duration) {
+	if e.BytesRead <= 0 || e.FileSize <= e.BytesRead {
+		return
+	}
+
+	return time.Duration(float64(e.Elapsed) * float64(e.FileSize-e.BytesRead) / float64(e.BytesRead))
+}
+
+// progressCounters
+//
+// English:
+//
+// Counters of the import, updated with atomic operations so they can be read while the import runs, and the reporter
+// of the events.
+//
+// Português:
+//
+// Contadores da importação, atualizados com operações atômicas para que possam ser lidos enquanto a importação roda, e
+// o receptor dos eventos.
+type progressCounters struct {
+	nodes     atomic.Uint64
+	ways      atomic.Uint64
+	relations atomic.Uint64
+	batches   atomic.Uint64
+	downloads atomic.Uint64
+	bytesRead atomic.Int64
+	fileSize  atomic.Int64
+
+	// English: protects the fields below and serializes the calls to the reporter
+	// Português: protege os campos abaixo e serializa as chamadas ao receptor
+	mutex        sync.Mutex
+	reporter     ProgressReporter
+	phase        string
+	start        time.Time
+	tickTime     time.Time
+	tickElements uint64
+}
+
+// reset
+//
+// English:
+//
+// # Zeroes the counters at the start of an import
+//
+// Português:
+//
+// Zera os contadores no início de uma importação
+func (e *progressCounters) reset(fileSize int64) {
+	e.nodes.Store(0)
+	e.ways.Store(0)
+	e.relations.Store(0)
+	e.batches.Store(0)
+	e.downloads.Store(0)
+	e.bytesRead.Store(0)
+	e.fileSize.Store(fileSize)
+
+	e.mutex.Lock()
+	e.phase = ""
+	e.start = time.Now()
+	e.tickTime = e.start
+	e.tickElements = 0
+	e.mutex.Unlock()
+}
+
+// elements
+//
+// English:
+//
+// # Returns the number of elements read
+//
+// Português:
+//
+// Devolve a quantidade de elementos lidos
+func (e *progressCounters) elements() uint64 {
+	return e.nodes.Load() + e.ways.Load() + e.relations.Load()
+}
+
+// snapshot
+//
+// English:
+//
+// # Copies the counters into an event, must be called with the mutex locked
+//
+// Português:
+//
+// Copia os contadores para um evento, deve ser chamada com o mutex travado
+func (e *progressCounters) snapshot(kind string) (event ProgressEvent) {
+	event = ProgressEvent{
+		Kind:      kind,
+		Phase:     e.phase,
+		BytesRead: e.bytesRead.Load(),
+		FileSize:  e.fileSize.Load(),
+		Nodes:     e.nodes.Load(),
+		Ways:      e.ways.Load(),
+		Relations: e.relations.Load(),
+		Batches:   e.batches.Load(),
+		Downloads: e.downloads.Load(),
+		Elapsed:   time.Since(e.start),
+	}
+
+	if event.Elapsed > 0 {
+		event.ElementsPerSecond = float64(event.Nodes+event.Ways+event.Relations) / event.Elapsed.Seconds()
+	}
+	return
+}
+
+// send
+//
+// English:
+//
+// # Sends an event to the reporter, when defined
+//
+// Português:
+//
+// Envia um evento ao receptor, quando definido
+func (e *progressCounters) send(kind string, fill func(event *ProgressEvent)) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.reporter == nil {
+		return
+	}
+
+	var event = e.snapshot(kind)
+	if fill != nil {
+		fill(&event)
+	}
+	e.reporter.Progress(event)
+}
+
+// setPhase
+//
+// English:
+//
+// # Changes the phase and sends ProgressEventPhase
+//
+// Português:
+//
+// Muda a fase e envia ProgressEventPhase
+func (e *progressCounters) setPhase(phase string) {
+	e.mutex.Lock()
+	e.phase = phase
+	e.mutex.Unlock()
+
+	e.send(ProgressEventPhase, nil)
+}
+
+// tick
+//
+// English:
+//
+// Sends ProgressEventTick when ProgressInterval has passed since the previous tick, the clock is read only once every
+// 1024 elements.
+//
+// Português:
+//
+// Envia ProgressEventTick quando ProgressInterval passou desde o tick anterior, o relógio é lido apenas uma vez a cada
+// 1024 elementos.
+func (e *progressCounters) tick() {
+	var elements = e.elements()
+	if elements&1023 != 0 {
+		return
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	var now = time.Now()
+	if e.reporter == nil || now.Sub(e.tickTime) < ProgressInterval {
+		return
+	}
+
+	var event = e.snapshot(ProgressEventTick)
+	event.ElementsPerSecond = float64(elements-e.tickElements) / now.Sub(e.tickTime).Seconds()
+	e.tickTime = now
+	e.tickElements = elements
+	e.reporter.Progress(event)
+}
+
+// batch
+//
+// English:
+//
+// # Counts a batch flushed to the database and sends ProgressEventBatch
+//
+// Português:
+//
+// Conta um lote enviado ao banco de dados e envia ProgressEventBatch
+func (e *progressCounters) batch(element string, size int) {
+	e.batches.Add(1)
+	e.send(ProgressEventBatch, func(event *ProgressEvent) {
+		event.Element = element
+		event.Size = size
+	})
+}
+
+// download
+//
+// English:
+//
+// # Counts a downloaded element and sends ProgressEventDownload
+//
+// Português:
+//
+// Conta um elemento baixado e envia ProgressEventDownload
+func (e *progressCounters) download(element string, id int64) {
+	e.downloads.Add(1)
+	e.send(ProgressEventDownload, func(event *ProgressEvent) {
+		event.Element = element
+		event.ID = id
+	})
+}
+
+// done
+//
+// English:
+//
+// # Sends ProgressEventDone with the error of the import
+//
+// Português:
+//
+// Envia ProgressEventDone com o erro da importação
+func (e *progressCounters) done(err error) {
+	e.send(ProgressEventDone, func(event *ProgressEvent) {
+		event.Err = err
+	})
+}
+
+// progressReader
+//
+// English:
+//
+// # Reader that adds the bytes read to the counter of bytes consumed
+//
+// Português:
+//
+// Leitor que soma os bytes lidos ao contador de bytes consumidos
+type progressReader struct {
+	reader   io.Reader
+	counters *progressCounters
+}
+
+func (e *progressReader) Read(p []byte) (n int, err error) {
+	n, err = e.reader.Read(p)
+	e.counters.bytesRead.Add(int64(n))
+	return
+}
@@ -0,0 +1,62 @@
+package goosm
+
+import (
+	"log"
+	"time"
+)
+
+// ProgressLog
+//
+// English:
+//
+// ProgressReporter that writes the phase changes, the ticks and the end of the import with the log package.
+//
+//	Note:
+//	  * Batches are not written, they are counted in the ticks.
+//
+// Português:
+//
+// ProgressReporter que escreve as mudanças de fase, os ticks e o fim da importação com o pacote log.
+//
+//	Nota:
+//	  * Lotes não são escritos, eles são contados nos ticks.
+type ProgressLog struct{}
+
+// Progress
+//
+// English:
+//
+// # Writes the event
+//
+// Português:
+//
+// Escreve o evento
+func (e *ProgressLog) Progress(event ProgressEvent) {
+	switch event.Kind {
+	case ProgressEventPhase:
+		log.Printf("PbfProcess.progress: phase %v, %.1f%%, nodes: %v, ways: %v, relations: %v", event.Phase, event.Percent(), event.Nodes, event.Ways, event.Relations)
+
+	case ProgressEventTick:
+		log.Printf(
+			"PbfProcess.progress: %v %.1f%% of %v, %.0f elements/s, nodes: %v, ways: %v, relations: %v, batches: %v, downloads: %v, remaining: %v",
+			event.Phase,
+			event.Percent(),
+			formatBytes(event.FileSize),
+			event.ElementsPerSecond,
+			event.Nodes,
+			event.Ways,
+			event.Relations,
+			event.Batches,
+			event.Downloads,
+			event.Remaining().Round(time.Second),
+		)
+
+	case ProgressEventDone:
+		if event.Err != nil {
+			log.Printf("PbfProcess.progress: stopped after %v, nodes: %v, ways: %v, relations: %v, error: %v", event.Elapsed.Round(time.Second), event.Nodes, event.Ways, event.Relations, event.Err)
+			return
+		}
+
+		log.Printf("PbfProcess.progress: done in %v, nodes: %v, ways: %v, relations: %v, batches: %v, downloads: %v", event.Elapsed.Round(time.Second), event.Nodes, event.Ways, event.Relations, event.Batches, event.Downloads)
+	}
+}
@@ -0,0 +1,101 @@
+package goosm
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// ProgressTerminal
+//
+// English:
+//
+// ProgressReporter that keeps a single status line in the terminal, rewritten with a carriage return, and ends it with
+// a new line when the import finishes.
+//
+// Português:
+//
+// ProgressReporter que mantém uma única linha de estado no terminal, reescrita com um retorno de carro, e a termina com
+// uma nova linha quando a importação acaba.
+type ProgressTerminal struct {
+
+	// English: destination of the line, os.Stderr when nil
+	// Português: destino da linha, os.Stderr quando nil
+	Writer io.Writer
+
+	// English: elements per second of the last tick
+	// Português: elementos por segundo do último tick
+	rate float64
+}
+
+// Progress
+//
+// English:
+//
+// # Rewrites the status line
+//
+// Português:
+//
+// Reescreve a linha de estado
+func (e *ProgressTerminal) Progress(event ProgressEvent) {
+	var writer = e.Writer
+	if writer == nil {
+		writer = os.Stderr
+	}
+
+	switch event.Kind {
+	case ProgressEventTick:
+		e.rate = event.ElementsPerSecond
+	case ProgressEventBatch:
+		return
+	}
+
+	_, _ = fmt.Fprintf(
+		writer,
+		"\r%-9v %5.1f%% %v/%v  %.0f el/s  nodes %v  ways %v  relations %v  downloads %v  eta %v   ",
+		event.Phase,
+		event.Percent(),
+		formatBytes(event.BytesRead),
+		formatBytes(event.FileSize),
+		e.rate,
+		event.Nodes,
+		event.Ways,
+		event.Relations,
+		event.Downloads,
+		event.Remaining().Round(time.Second),
+	)
+
+	if event.Kind != ProgressEventDone {
+		return
+	}
+
+	if event.Err != nil {
+		_, _ = fmt.Fprintf(writer, "\nerror: %v\n", event.Err)
+		return
+	}
+	_, _ = fmt.Fprintf(writer, "\ndone in %v\n", event.Elapsed.Round(time.Second))
+}
+
+// formatBytes
+//
+// English:
+//
+// # Formats a number of bytes with a binary unit, eg. 1.5 GiB
+//
+// Português:
+//
+// Formata uma quantidade de bytes com uma unidade binária, ex. 1.5 GiB
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%v B", size)
+	}
+
+	var divisor, exponent = int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		divisor *= unit
+		exponent++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(divisor), "KMGTPE"[exponent])
+}
@@ -0,0 +1,149 @@
+package goosm
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testProgress
+//
+// English:
+//
+// # ProgressReporter that keeps the events
+//
+// Português:
+//
+// ProgressReporter que guarda os eventos
+type testProgress struct {
+	sync.Mutex
+	events []ProgressEvent
+}
+
+func (e *testProgress) Progress(event ProgressEvent) {
+	e.Lock()
+	e.events = append(e.events, event)
+	e.Unlock()
+}
+
+// TestPbfProcess_SetProgress
+//
+// English:
+//
+// Imports the grid while another goroutine reads the counters, the events must follow the phases of the file and end
+// with all the bytes consumed.
+//
+// Português:
+//
+// Importa a grade enquanto outra goroutine lê os contadores, os eventos devem seguir as fases do arquivo e terminar com
+// todos os bytes consumidos.
+func TestPbfProcess_SetProgress(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "grid.pbf")
+	err := testPbfGrid(path, 200)
+	if err != nil {
+		t.Logf("testPbfGrid() error: %v", err)
+		t.FailNow()
+	}
+
+	var nodeFile = &testNodeFile{}
+	nodeFile.Init(0)
+	var reporter = &testProgress{}
+	var terminal = &bytes.Buffer{}
+	var process = newTestPbfProcess(newTestDatabase(), nodeFile)
+	process.SetProgress(reporterList{reporter, &ProgressTerminal{Writer: terminal}})
+
+	var done = make(chan struct{})
+	var polled = make(chan struct{})
+	go func() {
+		defer close(polled)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				process.GetPartialNumberOfProcessedData()
+			}
+		}
+	}()
+
+	_, _, err = process.CompleteParser(path)
+	close(done)
+	<-polled
+	if err != nil {
+		t.Logf("CompleteParser() error: %v", err)
+		t.FailNow()
+	}
+
+	var phases []string
+	var batches uint64
+	for _, event := range reporter.events {
+		switch event.Kind {
+		case ProgressEventPhase:
+			phases = append(phases, event.Phase)
+		case ProgressEventBatch:
+			batches++
+			if event.Batches != batches || event.Size == 0 {
+				t.Logf("batch event error: %+v", event)
+				t.FailNow()
+			}
+		}
+	}
+
+	if strings.Join(phases, ",") != "nodes,ways,relations" {
+		t.Logf("phases error: %v", phases)
+		t.FailNow()
+	}
+
+	var last = reporter.events[len(reporter.events)-1]
+	if last.Kind != ProgressEventDone || last.Err != nil || last.Percent() != 100.0 || last.Nodes != 200 || last.Ways != 200 || last.Relations != 1 {
+		t.Logf("done event error: %+v", last)
+		t.FailNow()
+	}
+
+	if !strings.Contains(terminal.String(), "done in") {
+		t.Logf("terminal error: %q", terminal.String())
+		t.FailNow()
+	}
+}
+
+// reporterList
+//
+// English:
+//
+// # Sends each event to all the reporters of the list
+//
+// Português:
+//
+// Envia cada evento para todos os receptores da lista
+type reporterList []ProgressReporter
+
+func (e reporterList) Progress(event ProgressEvent) {
+	for _, reporter := range e {
+		reporter.Progress(event)
+	}
+}
+
+// TestFormatBytes
+//
+// English:
+//
+// # Formats sizes below and above the binary units
+//
+// Português:
+//
+// Formata tamanhos abaixo e acima das unidades binárias
+func TestFormatBytes(t *testing.T) {
+	for size, expected := range map[int64]string{
+		512:             "512 B",
+		1536:            "1.5 KiB",
+		3 * 1024 * 1024: "3.0 MiB",
+		5 << 30:         "5.0 GiB",
+	} {
+		if formatBytes(size) != expected {
+			t.Logf("formatBytes(%v) error: %v != %v", size, formatBytes(size), expected)
+			t.FailNow()
+		}
+	}
+}
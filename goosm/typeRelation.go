@@ -49,3 +49,16 @@ func (el *Relation) GetIdAsByte() []byte {
 
 	return ret
 }
+
+// Name
+//
+// English:
+//
+// # Returns the name of the relation in the first language found, see Way.Name()
+//
+// Português:
+//
+// Devolve o nome da relation no primeiro idioma encontrado, veja Way.Name()
+func (el *Relation) Name(lang ...string) (name string) {
+	return tagName(el.Tag, el.International, lang...)
+}
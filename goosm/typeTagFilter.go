@@ -0,0 +1,418 @@
+package goosm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// tagFilterType
+//
+// English:
+//
+// # Bit mask of the element types of an expression
+//
+// Português:
+//
+// Máscara de bits dos tipos de elemento de uma expressão
+type tagFilterType uint8
+
+const (
+	tagFilterNode tagFilterType = 1 << iota
+	tagFilterWay
+	tagFilterRelation
+
+	tagFilterAll = tagFilterNode | tagFilterWay | tagFilterRelation
+)
+
+// tagPattern
+//
+// English:
+//
+// # Key or value of an expression, with an optional * at the start and/or at the end
+//
+// Português:
+//
+// Chave ou valor de uma expressão, com um * opcional no início e/ou no fim
+type tagPattern struct {
+	text      string
+	anyPrefix bool
+	anySuffix bool
+}
+
+// newTagPattern
+//
+// English:
+//
+// # Parses a key or value, "*" alone matches any text
+//
+// Português:
+//
+// Interpreta uma chave ou valor, "*" sozinho combina com qualquer texto
+func newTagPattern(text string) (pattern tagPattern) {
+	if strings.HasPrefix(text, "*") {
+		pattern.anyPrefix = true
+		text = text[1:]
+	}
+
+	if strings.HasSuffix(text, "*") {
+		pattern.anySuffix = true
+		text = text[:len(text)-1]
+	}
+
+	pattern.text = text
+	return
+}
+
+// wildcard
+//
+// English:
+//
+// # Returns true when the pattern has a *
+//
+// Português:
+//
+// Devolve true quando o padrão tem um *
+func (e tagPattern) wildcard() bool {
+	return e.anyPrefix || e.anySuffix
+}
+
+// match
+//
+// English:
+//
+// # Returns true when the text matches the pattern
+//
+// Português:
+//
+// Devolve true quando o texto combina com o padrão
+func (e tagPattern) match(text string) bool {
+	switch {
+	case e.anyPrefix && e.anySuffix:
+		return strings.Contains(text, e.text)
+	case e.anyPrefix:
+		return strings.HasSuffix(text, e.text)
+	case e.anySuffix:
+		return strings.HasPrefix(text, e.text)
+	}
+	return text == e.text
+}
+
+// tagExpression
+//
+// English:
+//
+// # One expression of the filter, already parsed
+//
+// Português:
+//
+// Uma expressão do filtro, já interpretada
+type tagExpression struct {
+	types    tagFilterType
+	negated  bool
+	key      tagPattern
+	values   []tagPattern
+	notEqual bool
+}
+
+// match
+//
+// English:
+//
+// # Returns true when one of the tags matches the key and the values of the expression
+//
+// Português:
+//
+// Devolve true quando uma das tags combina com a chave e os valores da expressão
+func (e tagExpression) match(tags map[string]string) bool {
+	if !e.key.wildcard() {
+		value, found := tags[e.key.text]
+		return found && e.matchValue(value)
+	}
+
+	for key, value := range tags {
+		if e.key.match(key) && e.matchValue(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchValue
+//
+// English:
+//
+// # Returns true when the value is in the list of values, or out of it for !=
+//
+// Português:
+//
+// Devolve true quando o valor está na lista de valores, ou fora dela para !=
+func (e tagExpression) matchValue(value string) bool {
+	if len(e.values) == 0 {
+		return true
+	}
+
+	var found = false
+	for _, pattern := range e.values {
+		if pattern.match(value) {
+			found = true
+			break
+		}
+	}
+	return found != e.notEqual
+}
+
+// TagFilter
+//
+// English:
+//
+// Selects the elements imported by PbfProcess by their tags, with expressions in the style of osmium tags-filter.
+//
+//	Syntax:
+//	  [types/][!]key[=value[,value...]]
+//	  [types/][!]key!=value[,value...]
+//
+//	  types: any combination of n, w and r, for nodes, ways and relations, all types when omitted;
+//	  key: name of the tag, a * at the start or at the end matches any text, as in name:*;
+//	  value: value of the tag, with the same * rule, any value when omitted;
+//	  !=: the key must exist with a value out of the list;
+//	  ! at the start: the elements that match the expression are excluded.
+//
+//	Examples:
+//	  w/highway=primary,secondary  ways with highway=primary or highway=secondary;
+//	  n/amenity                    nodes with any amenity tag;
+//	  r/boundary=administrative    administrative boundary relations;
+//	  !building=no                 excludes the elements with building=no.
+//
+//	Rules:
+//	  * An element is imported when it matches one of the expressions of its type and none of the excluding
+//	    expressions of its type;
+//	  * When the filter has no expression for a type, other than excluding ones, the elements of that type are not
+//	    imported, unless the filter has only excluding expressions.
+//
+// Português:
+//
+// Seleciona os elementos importados por PbfProcess pelas suas tags, com expressões no estilo do osmium tags-filter.
+//
+//	Sintaxe:
+//	  [tipos/][!]chave[=valor[,valor...]]
+//	  [tipos/][!]chave!=valor[,valor...]
+//
+//	  tipos: qualquer combinação de n, w e r, para nodes, ways e relations, todos os tipos quando omitido;
+//	  chave: nome da tag, um * no início ou no fim combina com qualquer texto, como em name:*;
+//	  valor: valor da tag, com a mesma regra do *, qualquer valor quando omitido;
+//	  !=: a chave deve existir com um valor fora da lista;
+//	  ! no início: os elementos que combinam com a expressão são excluídos.
+//
+//	Exemplos:
+//	  w/highway=primary,secondary  ways com highway=primary ou highway=secondary;
+//	  n/amenity                    nodes com qualquer tag amenity;
+//	  r/boundary=administrative    relations de limites administrativos;
+//	  !building=no                 exclui os elementos com building=no.
+//
+//	Regras:
+//	  * Um elemento é importado quando combina com uma das expressões do seu tipo e com nenhuma das expressões de
+//	    exclusão do seu tipo;
+//	  * Quando o filtro não tem expressão para um tipo, fora as de exclusão, os elementos desse tipo não são
+//	    importados, a menos que o filtro tenha apenas expressões de exclusão.
+type TagFilter struct {
+	expressions []tagExpression
+
+	// English: types with at least one expression that is not excluding
+	// Português: tipos com pelo menos uma expressão que não é de exclusão
+	selected tagFilterType
+}
+
+// ParseTagFilter
+//
+// English:
+//
+// Parses the expressions of the filter, see TagFilter for the syntax.
+//
+//	Example:
+//	  filter, err := goosm.ParseTagFilter("w/highway=primary,secondary", "!building=no")
+//
+// Português:
+//
+// Interpreta as expressões do filtro, veja TagFilter para a sintaxe.
+//
+//	Exemplo:
+//	  filter, err := goosm.ParseTagFilter("w/highway=primary,secondary", "!building=no")
+func ParseTagFilter(expressions ...string) (filter *TagFilter, err error) {
+	if len(expressions) == 0 {
+		err = fmt.Errorf("ParseTagFilter().error: at least one expression must be informed")
+		return
+	}
+
+	filter = new(TagFilter)
+	for _, text := range expressions {
+		var expression tagExpression
+		expression, err = parseTagExpression(text)
+		if err != nil {
+			err = fmt.Errorf("ParseTagFilter().error: expression %q: %v", text, err)
+			filter = nil
+			return
+		}
+
+		if !expression.negated {
+			filter.selected |= expression.types
+		}
+		filter.expressions = append(filter.expressions, expression)
+	}
+	return
+}
+
+// parseTagExpression
+//
+// English:
+//
+// # Parses one expression of the filter
+//
+// Português:
+//
+// Interpreta uma expressão do filtro
+func parseTagExpression(text string) (expression tagExpression, err error) {
+	text = strings.TrimSpace(text)
+	expression.types = tagFilterAll
+
+	if index := strings.Index(text, "/"); index != -1 && strings.Trim(text[:index], "nwr") == "" {
+		if index == 0 {
+			err = fmt.Errorf("empty element type")
+			return
+		}
+
+		expression.types = 0
+		for _, char := range text[:index] {
+			switch char {
+			case 'n':
+				expression.types |= tagFilterNode
+			case 'w':
+				expression.types |= tagFilterWay
+			case 'r':
+				expression.types |= tagFilterRelation
+			}
+		}
+		text = text[index+1:]
+	}
+
+	if strings.HasPrefix(text, "!") {
+		expression.negated = true
+		text = text[1:]
+	}
+
+	var key = text
+	if index := strings.Index(text, "="); index != -1 {
+		key = text[:index]
+		if strings.HasSuffix(key, "!") {
+			expression.notEqual = true
+			key = key[:len(key)-1]
+		}
+
+		for _, value := range strings.Split(text[index+1:], ",") {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				err = fmt.Errorf("empty value")
+				return
+			}
+			expression.values = append(expression.values, newTagPattern(value))
+		}
+	}
+
+	key = strings.TrimSpace(key)
+	if key == "" {
+		err = fmt.Errorf("empty key")
+		return
+	}
+
+	expression.key = newTagPattern(key)
+	return
+}
+
+// MatchNode
+//
+// English:
+//
+// # Returns true when a node with these tags is imported
+//
+// Português:
+//
+// Devolve true quando um node com essas tags é importado
+func (e *TagFilter) MatchNode(tags map[string]string) bool {
+	return e.match(tagFilterNode, tags)
+}
+
+// MatchWay
+//
+// English:
+//
+// # Returns true when a way with these tags is imported
+//
+// Português:
+//
+// Devolve true quando um way com essas tags é importado
+func (e *TagFilter) MatchWay(tags map[string]string) bool {
+	return e.match(tagFilterWay, tags)
+}
+
+// MatchRelation
+//
+// English:
+//
+// # Returns true when a relation with these tags is imported
+//
+// Português:
+//
+// Devolve true quando uma relation com essas tags é importada
+func (e *TagFilter) MatchRelation(tags map[string]string) bool {
+	return e.match(tagFilterRelation, tags)
+}
+
+// match
+//
+// English:
+//
+// # Applies the rules of TagFilter to an element of the type
+//
+// Português:
+//
+// Aplica as regras de TagFilter a um elemento do tipo
+func (e *TagFilter) match(elementType tagFilterType, tags map[string]string) bool {
+	if e == nil {
+		return true
+	}
+
+	var matched = false
+	for _, expression := range e.expressions {
+		if expression.types&elementType == 0 {
+			continue
+		}
+
+		if expression.negated {
+			if expression.match(tags) {
+				return false
+			}
+			continue
+		}
+
+		if !matched && expression.match(tags) {
+			matched = true
+		}
+	}
+
+	if e.selected == 0 {
+		return true
+	}
+	return matched
+}
+
+// selects
+//
+// English:
+//
+// # Returns true when the filter may import elements of the type
+//
+// Português:
+//
+// Devolve true quando o filtro pode importar elementos do tipo
+func (e *TagFilter) selects(elementType tagFilterType) bool {
+	return e == nil || e.selected == 0 || e.selected&elementType != 0
+}
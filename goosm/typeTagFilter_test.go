@@ -0,0 +1,157 @@
+package goosm
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+)
+
+// TestTagFilter_Match
+//
+// English:
+//
+// # Matches the expressions of the request against nodes, ways and relations
+//
+// Português:
+//
+// Compara as expressões do pedido com nodes, ways e relations
+func TestTagFilter_Match(t *testing.T) {
+	for _, test := range []struct {
+		expressions []string
+		element     string
+		tags        map[string]string
+		expected    bool
+	}{
+		{[]string{"w/highway=primary,secondary"}, "way", map[string]string{"highway": "secondary"}, true},
+		{[]string{"w/highway=primary,secondary"}, "way", map[string]string{"highway": "residential"}, false},
+		{[]string{"w/highway=primary,secondary"}, "node", map[string]string{"highway": "primary"}, false},
+		{[]string{"n/amenity"}, "node", map[string]string{"amenity": "bench"}, true},
+		{[]string{"n/amenity"}, "node", map[string]string{"shop": "bakery"}, false},
+		{[]string{"r/boundary=administrative"}, "relation", map[string]string{"boundary": "administrative"}, true},
+		{[]string{"!building=no"}, "way", map[string]string{"building": "no"}, false},
+		{[]string{"!building=no"}, "way", map[string]string{"building": "yes"}, true},
+		{[]string{"!building=no"}, "node", map[string]string{"amenity": "bench"}, true},
+		{[]string{"building", "!building=no"}, "way", map[string]string{"building": "no"}, false},
+		{[]string{"nw/highway!=footway,path"}, "way", map[string]string{"highway": "primary"}, true},
+		{[]string{"nw/highway!=footway,path"}, "way", map[string]string{"highway": "path"}, false},
+		{[]string{"nw/highway!=footway,path"}, "way", map[string]string{"barrier": "fence"}, false},
+		{[]string{"name:*"}, "node", map[string]string{"name:pt": "Praça"}, true},
+		{[]string{"highway=*_link"}, "way", map[string]string{"highway": "primary_link"}, true},
+		{[]string{"highway=*_link"}, "way", map[string]string{"highway": "primary"}, false},
+	} {
+		filter, err := ParseTagFilter(test.expressions...)
+		if err != nil {
+			t.Logf("%v: ParseTagFilter() error: %v", test.expressions, err)
+			t.FailNow()
+		}
+
+		var matched bool
+		switch test.element {
+		case "node":
+			matched = filter.MatchNode(test.tags)
+		case "way":
+			matched = filter.MatchWay(test.tags)
+		case "relation":
+			matched = filter.MatchRelation(test.tags)
+		}
+
+		if matched != test.expected {
+			t.Logf("%v: %v %v: %v != %v", test.expressions, test.element, test.tags, matched, test.expected)
+			t.FailNow()
+		}
+	}
+
+	for _, expression := range []string{"", "w/", "/highway", "highway=", "highway=primary,", "!"} {
+		_, err := ParseTagFilter(expression)
+		if err == nil {
+			t.Logf("%q: ParseTagFilter() must fail", expression)
+			t.FailNow()
+		}
+	}
+}
+
+// testWayStore
+//
+// English:
+//
+// # Way store kept in a map, implements WayStoreInterface
+//
+// Português:
+//
+// Arquivo de ways mantido em um mapa, implementa WayStoreInterface
+type testWayStore struct {
+	ways map[int64][][2]float64
+}
+
+func (e *testWayStore) WriteWayCoordinates(id int64, loc [][2]float64) (err error) {
+	e.ways[id] = loc
+	return
+}
+
+func (e *testWayStore) FindWayByID(id int64) (nodeIDs []int64, loc [][2]float64, err error) {
+	loc, found := e.ways[id]
+	if !found {
+		err = io.EOF
+	}
+	return
+}
+
+func (e *testWayStore) MountIndexIntoFile() (err error) { return }
+func (e *testWayStore) WriteFileHeaders() (err error)   { return }
+
+// TestPbfProcess_SetTagFilter
+//
+// English:
+//
+// Imports the grid with filters, the ways rejected by the filter must still be found by the relations in the way
+// store.
+//
+// Português:
+//
+// Importa a grade com filtros, os ways rejeitados pelo filtro ainda devem ser encontrados pelas relations no arquivo
+// de ways.
+func TestPbfProcess_SetTagFilter(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "grid.pbf")
+	err := testPbfGrid(path, 200)
+	if err != nil {
+		t.Logf("testPbfGrid() error: %v", err)
+		t.FailNow()
+	}
+
+	for _, test := range []struct {
+		expressions []string
+		nodes       int
+		ways        int
+		polygons    int
+	}{
+		{expressions: []string{"w/highway"}, nodes: 0, ways: 199, polygons: 0},
+		{expressions: []string{"n/amenity"}, nodes: 200, ways: 0, polygons: 0},
+		{expressions: []string{"r/landuse=grass"}, nodes: 0, ways: 0, polygons: 1},
+		{expressions: []string{"!barrier=fence"}, nodes: 200, ways: 199, polygons: 1},
+	} {
+		filter, err := ParseTagFilter(test.expressions...)
+		if err != nil {
+			t.Logf("%v: ParseTagFilter() error: %v", test.expressions, err)
+			t.FailNow()
+		}
+
+		var database = newTestDatabase()
+		var nodeFile = &testNodeFile{}
+		nodeFile.Init(0)
+
+		var process = newTestPbfProcess(database, nodeFile)
+		process.SetWayStore(&testWayStore{ways: make(map[int64][][2]float64)})
+		process.SetTagFilter(filter)
+
+		nodes, ways, err := process.CompleteParser(path)
+		if err != nil {
+			t.Logf("%v: CompleteParser() error: %v", test.expressions, err)
+			t.FailNow()
+		}
+
+		if nodes != 200 || ways != 200 || len(database.nodes) != test.nodes || len(database.ways) != test.ways || len(database.polygons) != test.polygons {
+			t.Logf("%v: totals error: %v, %v, database %v, %v, %v", test.expressions, nodes, ways, len(database.nodes), len(database.ways), len(database.polygons))
+			t.FailNow()
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package goosm
+
+import (
+	"sort"
+	"strings"
+)
+
+// TagPolicyDefaultDrop
+//
+// English:
+//
+// # Keys dropped by NewTagPolicy(), the same keys dropped by the Init() of Node and Way
+//
+// Português:
+//
+// Chaves descartadas por NewTagPolicy(), as mesmas chaves descartadas pelo Init() de Node e Way
+var TagPolicyDefaultDrop = []string{"source", "Source", "history", "converted_by", "created_by", "wikipedia", "wikidata"}
+
+// TagPolicy
+//
+// English:
+//
+// Normalization of the tags of nodes, ways and relations, defined by PbfProcess.SetTagPolicy().
+//
+// The keys are renamed first, then the keys of the drop list, unless also in the keep list, are removed and, at last,
+// the keys "name:xx" are moved into the map International, with the language "xx" as key. The lists accept a * at the
+// start and/or at the end of the key, as in TagFilter.
+//
+// Português:
+//
+// Normalização das tags de nodes, ways e relations, definida por PbfProcess.SetTagPolicy().
+//
+// As chaves são renomeadas primeiro, depois as chaves da lista de descarte, a menos que também estejam na lista de
+// manutenção, são removidas e, por último, as chaves "name:xx" são movidas para o mapa International, com o idioma
+// "xx" como chave. As listas aceitam um * no início e/ou no fim da chave, como em TagFilter.
+type TagPolicy struct {
+	drop          []tagPattern
+	keep          []tagPattern
+	rename        map[string]string
+	international bool
+}
+
+// NewTagPolicy
+//
+// English:
+//
+// # Returns a policy that drops the keys of TagPolicyDefaultDrop and moves the keys "name:xx" into International
+//
+// Português:
+//
+// Devolve uma política que descarta as chaves de TagPolicyDefaultDrop e move as chaves "name:xx" para International
+func NewTagPolicy() (policy *TagPolicy) {
+	policy = &TagPolicy{international: true}
+	policy.SetDrop(TagPolicyDefaultDrop...)
+	return
+}
+
+// SetDrop
+//
+// English:
+//
+// # Replaces the list of keys removed from the tags, eg. "source", "tiger:*"
+//
+// Português:
+//
+// Substitui a lista de chaves removidas das tags, ex. "source", "tiger:*"
+func (e *TagPolicy) SetDrop(keys ...string) {
+	e.drop = make([]tagPattern, len(keys))
+	for key, text := range keys {
+		e.drop[key] = newTagPattern(text)
+	}
+}
+
+// SetKeep
+//
+// English:
+//
+// # Replaces the list of keys kept even when they are in the drop list, eg. "wikidata"
+//
+// Português:
+//
+// Substitui a lista de chaves mantidas mesmo quando estão na lista de descarte, ex. "wikidata"
+func (e *TagPolicy) SetKeep(keys ...string) {
+	e.keep = make([]tagPattern, len(keys))
+	for key, text := range keys {
+		e.keep[key] = newTagPattern(text)
+	}
+}
+
+// SetRename
+//
+// English:
+//
+// Replaces the key renames, old key to new key, eg. {"addr:street_name": "addr:street"}. When the new key is already in
+// the tags, its value is kept and the old key is removed. When several old keys have the same new key, the first old key
+// in alphabetical order wins.
+//
+// Português:
+//
+// Substitui as renomeações de chaves, chave antiga para chave nova, ex. {"addr:street_name": "addr:street"}. Quando a
+// chave nova já está nas tags, seu valor é mantido e a chave antiga é removida. Quando várias chaves antigas têm a mesma
+// chave nova, a primeira chave antiga em ordem alfabética vence.
+func (e *TagPolicy) SetRename(rename map[string]string) {
+	e.rename = rename
+}
+
+// SetInternational
+//
+// English:
+//
+// # Enables the move of the keys "name:xx" into the map International, enabled by NewTagPolicy()
+//
+// Português:
+//
+// Habilita a movimentação das chaves "name:xx" para o mapa International, habilitada por NewTagPolicy()
+func (e *TagPolicy) SetInternational(enabled bool) {
+	e.international = enabled
+}
+
+// Apply
+//
+// English:
+//
+// Returns the normalized tags, the map received is not changed.
+//
+//	Output:
+//	  tag: tags left, nil when tags is nil;
+//	  international: names by language, nil when there is none.
+//
+// Português:
+//
+// Devolve as tags normalizadas, o mapa recebido não é alterado.
+//
+//	Saída:
+//	  tag: tags restantes, nil quando tags é nil;
+//	  international: nomes por idioma, nil quando não há nenhum.
+func (e *TagPolicy) Apply(tags map[string]string) (tag, international map[string]string) {
+	if tags == nil {
+		return
+	}
+
+	tag = make(map[string]string, len(tags))
+	var written = make(map[string]bool, len(tags))
+	var add = func(key, value string) {
+		written[key] = true
+		if e.dropped(key) {
+			return
+		}
+
+		if e.international {
+			if language, found := nameLanguage(key); found {
+				if international == nil {
+					international = make(map[string]string)
+				}
+				international[language] = value
+				return
+			}
+		}
+
+		tag[key] = value
+	}
+
+	var renamed []string
+	for key, value := range tags {
+		if _, found := e.rename[key]; found {
+			renamed = append(renamed, key)
+			continue
+		}
+		add(key, value)
+	}
+
+	// English: the renames go last and in sorted order, so the key kept does not depend on the order of the map
+	// Português: as renomeações vão por último e em ordem, assim a chave mantida não depende da ordem do mapa
+	sort.Strings(renamed)
+	for _, key := range renamed {
+		if newKey := e.rename[key]; !written[newKey] {
+			add(newKey, tags[key])
+		}
+	}
+	return
+}
+
+// dropped
+//
+// English:
+//
+// # Returns true when the key is in the drop list and not in the keep list
+//
+// Português:
+//
+// Devolve true quando a chave está na lista de descarte e não está na lista de manutenção
+func (e *TagPolicy) dropped(key string) bool {
+	for _, drop := range e.drop {
+		if !drop.match(key) {
+			continue
+		}
+
+		for _, keep := range e.keep {
+			if keep.match(key) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// nameLanguage
+//
+// English:
+//
+// Returns the language of a key "name:xx", with two or three lowercase letters and optional subtags, eg. "pt",
+// "zh-Hans" or "be-tarask". Keys as "name:etymology" or "name:pronunciation" are not names by language.
+//
+// Português:
+//
+// Devolve o idioma de uma chave "name:xx", com duas ou três letras minúsculas e subtags opcionais, ex. "pt", "zh-Hans"
+// ou "be-tarask". Chaves como "name:etymology" ou "name:pronunciation" não são nomes por idioma.
+func nameLanguage(key string) (language string, found bool) {
+	if !strings.HasPrefix(key, "name:") {
+		return
+	}
+
+	language = key[len("name:"):]
+	var parts = strings.FieldsFunc(language, func(r rune) bool { return r == '-' || r == '_' })
+	if len(parts) == 0 || len(parts[0]) < 2 || len(parts[0]) > 3 || strings.Count(language, "-")+strings.Count(language, "_") != len(parts)-1 {
+		return
+	}
+
+	for key, part := range parts {
+		for _, r := range part {
+			var lower = r >= 'a' && r <= 'z'
+			var other = r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+			if !lower && (key == 0 || !other) {
+				return
+			}
+		}
+	}
+
+	found = true
+	return
+}
+
+// tagName
+//
+// English:
+//
+// Returns the name in the first language found, looking in international and in the keys "name:xx" of tag, and
+// "name" when no language is found.
+//
+// Português:
+//
+// Devolve o nome no primeiro idioma encontrado, procurando em international e nas chaves "name:xx" de tag, e "name"
+// quando nenhum idioma é encontrado.
+func tagName(tag, international map[string]string, lang ...string) (name string) {
+	for _, language := range lang {
+		if name = international[language]; name != "" {
+			return
+		}
+		if name = tag["name:"+language]; name != "" {
+			return
+		}
+	}
+	return tag["name"]
+}
@@ -0,0 +1,160 @@
+package goosm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestTagPolicy_Apply
+//
+// English:
+//
+// # Renames, drops and keeps keys and moves the names by language
+//
+// Português:
+//
+// Renomeia, descarta e mantém chaves e move os nomes por idioma
+func TestTagPolicy_Apply(t *testing.T) {
+	var policy = NewTagPolicy()
+	policy.SetKeep("wikidata")
+	policy.SetDrop(append(TagPolicyDefaultDrop, "tiger:*")...)
+	policy.SetRename(map[string]string{"addr:street_name": "addr:street", "old": "name"})
+
+	var tags = map[string]string{
+		"name":             "Praça XV",
+		"old":              "Praça antiga",
+		"name:en":          "XV Square",
+		"name:zh-Hans":     "十五广场",
+		"name:etymology":   "15 de novembro",
+		"addr:street_name": "Rua A",
+		"wikidata":         "Q1",
+		"wikipedia":        "pt:Praça",
+		"source":           "survey",
+		"tiger:county":     "x",
+	}
+
+	tag, international := policy.Apply(tags)
+	if len(tag) != 4 || tag["name"] != "Praça XV" || tag["addr:street"] != "Rua A" || tag["wikidata"] != "Q1" ||
+		tag["name:etymology"] != "15 de novembro" || len(tags) != 10 {
+		t.Logf("tag error: %v", tag)
+		t.FailNow()
+	}
+
+	if len(international) != 2 || international["en"] != "XV Square" || international["zh-Hans"] != "十五广场" {
+		t.Logf("international error: %v", international)
+		t.FailNow()
+	}
+
+	var way = Way{Tag: tag, International: international}
+	for _, test := range []struct {
+		lang []string
+		name string
+	}{
+		{lang: []string{"pt-BR", "en"}, name: "XV Square"},
+		{lang: []string{"zh-Hans"}, name: "十五广场"},
+		{lang: []string{"de"}, name: "Praça XV"},
+		{name: "Praça XV"},
+	} {
+		if name := way.Name(test.lang...); name != test.name {
+			t.Logf("Name(%v) error: %v", test.lang, name)
+			t.FailNow()
+		}
+	}
+
+	policy.SetInternational(false)
+	tag, international = policy.Apply(tags)
+	if international != nil || tag["name:en"] != "XV Square" {
+		t.Logf("SetInternational(false) error: %v, %v", tag, international)
+		t.FailNow()
+	}
+
+	// English: two old keys with the same new key, the first in alphabetical order wins at every run
+	// Português: duas chaves antigas com a mesma chave nova, a primeira em ordem alfabética vence em toda execução
+	policy.SetRename(map[string]string{"b": "n", "a": "n", "c": "d"})
+	for i := 0; i != 20; i++ {
+		tag, _ = policy.Apply(map[string]string{"b": "y", "a": "x", "c": "z", "d": "w"})
+		if len(tag) != 2 || tag["n"] != "x" || tag["d"] != "w" {
+			t.Logf("rename collision error: %v", tag)
+			t.FailNow()
+		}
+	}
+}
+
+// TestPbfProcess_SetTagPolicy
+//
+// English:
+//
+// # Imports a node, a closed way and a multipolygon with names by language and a wikidata key
+//
+// Português:
+//
+// Importa um node, um way fechado e um multipolygon com nomes por idioma e uma chave wikidata
+func TestPbfProcess_SetTagPolicy(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "names.osm")
+	err := os.WriteFile(path, []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<osm version="0.6">
+  <node id="1" lat="-27.0" lon="-48.0">
+    <tag k="amenity" v="bench"/>
+    <tag k="name:pt" v="Banco"/>
+    <tag k="wikidata" v="Q1"/>
+    <tag k="source" v="survey"/>
+  </node>
+  <node id="2" lat="-27.0" lon="-48.1"/>
+  <node id="3" lat="-27.1" lon="-48.1"/>
+  <way id="10">
+    <nd ref="1"/>
+    <nd ref="2"/>
+    <nd ref="3"/>
+    <nd ref="1"/>
+    <tag k="landuse" v="grass"/>
+    <tag k="wikidata" v="Q2"/>
+    <tag k="name" v="Gramado"/>
+    <tag k="name:en" v="Lawn"/>
+  </way>
+  <relation id="20">
+    <member type="way" ref="10" role="outer"/>
+    <tag k="type" v="multipolygon"/>
+    <tag k="leisure" v="park"/>
+    <tag k="name:de" v="Park"/>
+  </relation>
+</osm>`), 0644)
+	if err != nil {
+		t.Logf("WriteFile() error: %v", err)
+		t.FailNow()
+	}
+
+	var policy = NewTagPolicy()
+	policy.SetKeep("wikidata")
+
+	var database = newTestDatabase()
+	var nodeFile = &testNodeFile{}
+	nodeFile.Init(0)
+
+	var process = newTestPbfProcess(database, nodeFile)
+	process.SetTagPolicy(policy)
+	_, _, err = process.CompleteParser(path)
+	if err != nil {
+		t.Logf("CompleteParser() error: %v", err)
+		t.FailNow()
+	}
+
+	var node = database.nodes[1]
+	if node.Tag["wikidata"] != "Q1" || node.Tag["source"] != "" || node.Tag["name:pt"] != "" || node.Name("pt") != "Banco" {
+		t.Logf("node error: %v, %v", node.Tag, node.International)
+		t.FailNow()
+	}
+
+	var way = database.ways[10]
+	if way.International["en"] != "Lawn" || way.Name("en") != "Lawn" || way.Name("fr") != "Gramado" || way.Tag["wikidata"] != "Q2" ||
+		!way.IsPolygon {
+		t.Logf("way error: %v, %v", way.Tag, way.International)
+		t.FailNow()
+	}
+
+	var polygon = database.polygons[20]
+	if polygon.International["de"] != "Park" || polygon.Name("de") != "Park" || polygon.Tag["name:de"] != "" {
+		t.Logf("polygon error: %v, %v", polygon.Tag, polygon.International)
+		t.FailNow()
+	}
+}
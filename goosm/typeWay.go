@@ -12,12 +12,24 @@ type Way struct {
 	Id             int64             `bson:"_id"`
 	IsPolygon      bool              `bson:"isPolygon"`
 	Tag            map[string]string `bson:"tag,omitempty"`
+	International  map[string]string `bson:"international,omitempty"`
 	Loc            [][2]float64      `bson:"loc"`
 	LocFirst       [2]float64        `bson:"locFirst"`
 	LocLast        [2]float64        `bson:"locLast"`
 	DistanceTotal  float64           `bson:"distanceTotal"`
 	BBox           Box               `bson:"bbox"`
 	GeoJSonFeature string            `bson:"geoJSonFeature,omitempty"`
+	Metadata       *Metadata         `bson:"metadata,omitempty"`
+
+	// English: pieces inside the clip area, ClipCutWays, when the way leaves and enters the area again, Loc is the
+	// longest one and the GeoJSON is a MultiLineString
+	// Português: pedaços dentro da área de recorte, ClipCutWays, quando o way sai e entra na área de novo, Loc é o mais
+	// longo e o GeoJSON é um MultiLineString
+	Pieces [][][2]float64 `bson:"pieces,omitempty"`
+
+	// English: rules of IsPolygon, nil uses NewAreaRules()
+	// Português: regras de IsPolygon, nil usa NewAreaRules()
+	areaRules *AreaRules
 }
 
 func (e *Way) Init() (err error) {
@@ -30,17 +42,24 @@ func (e *Way) Init() (err error) {
 	var pointA = Node{}
 	var pointB = Node{}
 
-	for k := range e.Loc {
-		longitudeMax = math.Max(longitudeMax, e.Loc[k][0])
-		longitudeMin = math.Min(longitudeMin, e.Loc[k][0])
-		latitudeMax = math.Max(latitudeMax, e.Loc[k][1])
-		latitudeMin = math.Min(latitudeMin, e.Loc[k][1])
+	var lines = [][][2]float64{e.Loc}
+	if len(e.Pieces) != 0 {
+		lines = e.Pieces
+	}
+
+	for _, loc := range lines {
+		for k := range loc {
+			longitudeMax = math.Max(longitudeMax, loc[k][0])
+			longitudeMin = math.Min(longitudeMin, loc[k][0])
+			latitudeMax = math.Max(latitudeMax, loc[k][1])
+			latitudeMin = math.Min(latitudeMin, loc[k][1])
 
-		if k != 0 {
-			pointA.Init(0, e.Loc[k-1][Longitude], e.Loc[k-1][Latitude], nil)
-			pointB.Init(0, e.Loc[k][Longitude], e.Loc[k][Latitude], nil)
+			if k != 0 {
+				pointA.Init(0, loc[k-1][Longitude], loc[k-1][Latitude], nil)
+				pointB.Init(0, loc[k][Longitude], loc[k][Latitude], nil)
 
-			e.DistanceTotal += pointA.DistanceBetweenTwoPoints(pointB)
+				e.DistanceTotal += pointA.DistanceBetweenTwoPoints(pointB)
+			}
 		}
 	}
 
@@ -76,17 +95,39 @@ func (e *Way) makeBBox(longitudeMin, longitudeMax, latitudeMin, latitudeMax floa
 	return
 }
 
+// isPolygon
+//
+// English:
+//
+// # Returns true when the way is an area, see AreaRules
+//
+// Português:
+//
+// Devolve true quando o way é uma área, veja AreaRules
 func (e *Way) isPolygon() (isPolygon bool) {
-	var length = len(e.Loc) - 1
-	if length < 2 {
-		return false
+	if e.areaRules != nil {
+		return e.areaRules.isPolygon(e)
 	}
 
-	if e.Loc[0][0] == e.Loc[length][0] && e.Loc[0][1] == e.Loc[length][1] {
-		return true
+	return defaultAreaRules.isPolygon(e)
+}
+
+// closed
+//
+// English:
+//
+// # Returns true when the way is a closed ring, with at least four points and the last equal to the first
+//
+// Português:
+//
+// Devolve true quando o way é um anel fechado, com pelo menos quatro pontos e o último igual ao primeiro
+func (e *Way) closed() bool {
+	var length = len(e.Loc) - 1
+	if length < 3 {
+		return false
 	}
 
-	return false
+	return e.Loc[0][0] == e.Loc[length][0] && e.Loc[0][1] == e.Loc[length][1]
 }
 
 func (e *Way) MakeGeoJSonFeature() (geoJSonStr string) {
@@ -98,6 +139,21 @@ func (e *Way) MakeGeoJSonFeature() (geoJSonStr string) {
 	return e.GeoJSonFeature
 }
 
+// Name
+//
+// English:
+//
+// Returns the name in the first of the languages found, eg. Name("pt-BR", "pt", "en"), looking in International and
+// in the keys "name:xx" of Tag, and the tag "name" when none of them is found.
+//
+// Português:
+//
+// Devolve o nome no primeiro dos idiomas encontrado, ex. Name("pt-BR", "pt", "en"), procurando em International e nas
+// chaves "name:xx" de Tag, e a tag "name" quando nenhum deles é encontrado.
+func (e *Way) Name(lang ...string) (name string) {
+	return tagName(e.Tag, e.International, lang...)
+}
+
 func (e *Way) MakePolygonSurroundingsACW(meters float64) (polygon NewPolygon, err error) {
 	if len(e.Loc) < 3 {
 		err = errors.New("the way must have a minimum of three points")
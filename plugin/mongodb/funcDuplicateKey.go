@@ -0,0 +1,31 @@
+package mongodb
+
+import (
+	"errors"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// onlyDuplicateKeys
+//
+// English:
+//
+// Returns true when all the errors of an unordered InsertMany() are duplicate keys, documents already inserted by an
+// import interrupted after its last checkpoint.
+//
+// Português:
+//
+// Devolve true quando todos os erros de um InsertMany() não ordenado são chaves duplicadas, documentos já inseridos por
+// uma importação interrompida depois do seu último checkpoint.
+func onlyDuplicateKeys(err error) bool {
+	var bulk mongo.BulkWriteException
+	if !errors.As(err, &bulk) || bulk.WriteConcernError != nil || len(bulk.WriteErrors) == 0 {
+		return false
+	}
+
+	for _, writeError := range bulk.WriteErrors {
+		if writeError.Code != 11000 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,253 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"goosm/goosm"
+	"time"
+)
+
+type DbErrorLog struct { //nolint:typecheck
+	timeout    time.Duration
+	Client     *mongo.Client
+	Collection *mongo.Collection
+}
+
+// SetTimeout
+//
+// English:
+//
+// Determines timeout for all functions
+//
+//	Input:
+//	  timeout: maximum time for operation
+//
+// Português:
+//
+// Determina o timeout para todas as funções
+//
+//	Entrada:
+//	  timeout: tempo máximo para a operação
+func (e *DbErrorLog) SetTimeout(timeout time.Duration) {
+	e.timeout = timeout
+}
+
+// Connect
+//
+// English:
+//
+// Connect to the database
+//
+//	Input:
+//	  connection: database connection string. eg. "mongodb://127.0.0.1:27016/"
+//	  args: maintained by interface compatibility
+//
+// Português:
+//
+// Conecta ao banco de dados
+//
+//	Entrada:
+//	  connection: string de conexão ao banco de dados. Ex: "mongodb://127.0.0.1:27016/"
+//	  args: mantido por compatibilidade da interface
+func (e *DbErrorLog) Connect(connection string, _ ...interface{}) (err error) {
+	e.Client, err = mongo.NewClient(options.Client().ApplyURI(connection))
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbErrorLog.Connect().NewClient().error: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	err = e.Client.Connect(ctx)
+	cancel()
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbErrorLog.Connect().Connect().error: %v", err)
+		return
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), e.timeout)
+	err = e.Client.Ping(ctx, readpref.Primary())
+	cancel()
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbErrorLog.Connect().Ping().error: %v", err)
+		return
+	}
+	return
+}
+
+// Close
+//
+// English:
+//
+// # Close the connection to the database
+//
+// Português:
+//
+// Fecha a conexão com o banco de dados
+func (e *DbErrorLog) Close() (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	err = e.Client.Disconnect(ctx)
+	cancel()
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbErrorLog.Close().Disconnect().error: %v", err)
+		return
+	}
+	return
+}
+
+// New
+//
+// English:
+//
+// Prepare the database for use
+//
+//	Input:
+//	  connection: database connection string. Eg: "mongodb://127.0.0.1:27016/"
+//	  database: database name. Eg. "osm"
+//	  collection: collection name within the database. Eg. "errorLog", goosm.DB_OSM_ERROR_LOG_COLLECTIONS
+//
+//	Output:
+//	  referenceInitialized: database error log object ready to use
+//	  err: golang error object
+//
+// Português:
+//
+// Prepara o banco de dados para uso
+//
+//	Entrada:
+//	  connection: string de conexão ao banco de dados. Ex: "mongodb://127.0.0.1:27016/"
+//	  database: nome do banco de dados. Ex: "osm"
+//	  collection: nome da coleção dentro do banco de dados. Ex: "errorLog", goosm.DB_OSM_ERROR_LOG_COLLECTIONS
+//
+//	Saída:
+//	  referenceInitialized: objeto do banco de dados pronto para uso
+//	  err: objeto golang error
+func (e *DbErrorLog) New(connection, database, collection string, timeout time.Duration) (referenceInitialized interface{}, err error) { //nolint:typecheck
+	e.SetTimeout(timeout)
+
+	if err = e.Connect(connection); err != nil {
+		return
+	}
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbErrorLog.New().Connect().error: %v", err)
+		return
+	}
+
+	if err = e.createTable(database, collection); err != nil {
+		return
+	}
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbErrorLog.New().createTable().error: %v", err)
+		return
+	}
+
+	return e, err
+}
+
+// ElementError
+//
+// English:
+//
+// Inserts the report of an element not imported, implements goosm.ErrorSink
+//
+//	Input:
+//	  report: element, ID, reason code and raw data
+//
+// Português:
+//
+// Insere o relatório de um elemento não importado, implementa goosm.ErrorSink
+//
+//	Entrada:
+//	  report: elemento, ID, código do motivo e dados brutos
+func (e *DbErrorLog) ElementError(report goosm.ElementError) (err error) { //nolint:typecheck
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	_, err = e.Collection.InsertOne(ctx, report)
+	cancel()
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbErrorLog.ElementError().InsertOne().error: %v", err)
+		return
+	}
+	return
+}
+
+// createTable
+//
+// English:
+//
+// Create the collection and indexes
+//
+//	Input:
+//	  database: database name. Eg. "osm"
+//	  collection: collection name within the database. Eg. "errorLog"
+//
+// Português:
+//
+// Cria a coleção e os índices
+//
+//	Entrada:
+//	  database: nome do banco de dados. Ex: "osm"
+//	  collection: nome da coleção dentro do banco de dados. Ex: "errorLog"
+func (e *DbErrorLog) createTable(database, collection string) (err error) {
+	e.Collection = e.Client.Database(database).Collection(collection)
+
+	indexes := e.Collection.Indexes()
+
+	var cursor *mongo.Cursor
+	cursor, err = indexes.List(context.Background())
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbErrorLog.createTable().List().error: %v", err)
+		return
+	}
+
+	results := make([]bson.M, 0)
+	err = cursor.All(context.Background(), &results)
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbErrorLog.createTable().All().error: %v", err)
+		return
+	}
+
+	pass := false
+	for _, result := range results {
+		if result["name"] == "__element__" {
+			pass = true
+			break
+		}
+	}
+
+	if !pass {
+		name := "__element__"
+		_, err = indexes.CreateOne(
+			context.Background(),
+			mongo.IndexModel{
+				Keys: bson.D{{Key: "element", Value: 1}, {Key: "id", Value: 1}},
+				Options: &options.IndexOptions{
+					Name: &name,
+				},
+			},
+		)
+		if err != nil {
+			err = fmt.Errorf("mongodb.DbErrorLog.createTable().CreateOne().error: %v", err)
+			return
+		}
+
+		name = "__reason__"
+		_, err = indexes.CreateOne(
+			context.Background(),
+			mongo.IndexModel{
+				Keys: bson.M{"reason": 1},
+				Options: &options.IndexOptions{
+					Name: &name,
+				},
+			},
+		)
+		if err != nil {
+			err = fmt.Errorf("mongodb.DbErrorLog.createTable().CreateOne().error: %v", err)
+			return
+		}
+	}
+
+	return
+}
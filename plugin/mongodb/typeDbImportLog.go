@@ -0,0 +1,339 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"goosm/goosm"
+	"time"
+)
+
+type DbImportLog struct { //nolint:typecheck
+	timeout           time.Duration
+	Client            *mongo.Client
+	Collection        *mongo.Collection
+	CollectionSources *mongo.Collection
+}
+
+// SetTimeout
+//
+// English:
+//
+// Determines timeout for all functions
+//
+//	Input:
+//	  timeout: maximum time for operation
+//
+// Português:
+//
+// Determina o timeout para todas as funções
+//
+//	Entrada:
+//	  timeout: tempo máximo para a operação
+func (e *DbImportLog) SetTimeout(timeout time.Duration) {
+	e.timeout = timeout
+}
+
+// Connect
+//
+// English:
+//
+// Connect to the database
+//
+//	Input:
+//	  connection: database connection string. eg. "mongodb://127.0.0.1:27016/"
+//	  args: maintained by interface compatibility
+//
+// Português:
+//
+// Conecta ao banco de dados
+//
+//	Entrada:
+//	  connection: string de conexão ao banco de dados. Ex: "mongodb://127.0.0.1:27016/"
+//	  args: mantido por compatibilidade da interface
+func (e *DbImportLog) Connect(connection string, _ ...interface{}) (err error) {
+	e.Client, err = mongo.NewClient(options.Client().ApplyURI(connection))
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbImportLog.Connect().NewClient().error: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	err = e.Client.Connect(ctx)
+	cancel()
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbImportLog.Connect().Connect().error: %v", err)
+		return
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), e.timeout)
+	err = e.Client.Ping(ctx, readpref.Primary())
+	cancel()
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbImportLog.Connect().Ping().error: %v", err)
+		return
+	}
+	return
+}
+
+// Close
+//
+// English:
+//
+// # Close the connection to the database
+//
+// Português:
+//
+// Fecha a conexão com o banco de dados
+func (e *DbImportLog) Close() (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	err = e.Client.Disconnect(ctx)
+	cancel()
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbImportLog.Close().Disconnect().error: %v", err)
+		return
+	}
+	return
+}
+
+// New
+//
+// English:
+//
+// Prepare the database for use
+//
+//	Input:
+//	  connection: database connection string. Eg: "mongodb://127.0.0.1:27016/"
+//	  database: database name. Eg. "osm"
+//	  collection: collection of the import runs. Eg. goosm.DB_OSM_IMPORT_LOG_COLLECTIONS
+//	  collectionSources: collection of the source files. Eg. goosm.DB_OSM_SOURCE_FILES_LOG_COLLECTIONS
+//
+//	Output:
+//	  referenceInitialized: database import log object ready to use
+//	  err: golang error object
+//
+// Português:
+//
+// Prepara o banco de dados para uso
+//
+//	Entrada:
+//	  connection: string de conexão ao banco de dados. Ex: "mongodb://127.0.0.1:27016/"
+//	  database: nome do banco de dados. Ex: "osm"
+//	  collection: coleção das execuções de importação. Ex: goosm.DB_OSM_IMPORT_LOG_COLLECTIONS
+//	  collectionSources: coleção dos arquivos de origem. Ex: goosm.DB_OSM_SOURCE_FILES_LOG_COLLECTIONS
+//
+//	Saída:
+//	  referenceInitialized: objeto do banco de dados pronto para uso
+//	  err: objeto golang error
+func (e *DbImportLog) New(connection, database, collection, collectionSources string, timeout time.Duration) (referenceInitialized interface{}, err error) { //nolint:typecheck
+	e.SetTimeout(timeout)
+
+	if err = e.Connect(connection); err != nil {
+		return
+	}
+
+	if err = e.createTable(database, collection, collectionSources); err != nil {
+		return
+	}
+
+	return e, err
+}
+
+// SetImportRun
+//
+// English:
+//
+// Inserts the run or replaces the run with the same Id, implements goosm.InterfaceDbImportLog
+//
+//	Input:
+//	  run: reference to object goosm.ImportRun
+//
+// Português:
+//
+// Insere a execução ou substitui a execução com o mesmo Id, implementa goosm.InterfaceDbImportLog
+//
+//	Entrada:
+//	  run: referência ao objeto goosm.ImportRun
+func (e *DbImportLog) SetImportRun(run *goosm.ImportRun) (err error) { //nolint:typecheck
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	_, err = e.Collection.ReplaceOne(ctx, bson.M{"_id": run.Id}, run, options.Replace().SetUpsert(true))
+	cancel()
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbImportLog.SetImportRun().ReplaceOne().error: %v", err)
+		return
+	}
+	return
+}
+
+// GetImportRuns
+//
+// English:
+//
+// # Returns all the runs, the newest first
+//
+// Português:
+//
+// Devolve todas as execuções, a mais nova primeiro
+func (e *DbImportLog) GetImportRuns() (runs []goosm.ImportRun, err error) { //nolint:typecheck
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+
+	var cursor *mongo.Cursor
+	cursor, err = e.Collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"start": -1}))
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbImportLog.GetImportRuns().Find().error: %v", err)
+		return
+	}
+
+	runs = make([]goosm.ImportRun, 0)
+	err = cursor.All(ctx, &runs)
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbImportLog.GetImportRuns().All().error: %v", err)
+		return
+	}
+	return
+}
+
+// SetSourceFile
+//
+// English:
+//
+// Inserts the source file or replaces the source file with the same SHA-256, implements goosm.InterfaceDbImportLog
+//
+//	Input:
+//	  file: reference to object goosm.SourceFile
+//
+// Português:
+//
+// Insere o arquivo de origem ou substitui o arquivo de origem com o mesmo SHA-256, implementa
+// goosm.InterfaceDbImportLog
+//
+//	Entrada:
+//	  file: referência ao objeto goosm.SourceFile
+func (e *DbImportLog) SetSourceFile(file *goosm.SourceFile) (err error) { //nolint:typecheck
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	_, err = e.CollectionSources.ReplaceOne(ctx, bson.M{"_id": file.SHA256}, file, options.Replace().SetUpsert(true))
+	cancel()
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbImportLog.SetSourceFile().ReplaceOne().error: %v", err)
+		return
+	}
+	return
+}
+
+// GetSourceFile
+//
+// English:
+//
+// Returns the source file by its SHA-256
+//
+//	Output:
+//	  found: false when the file is not in the collection
+//
+// Português:
+//
+// Devolve o arquivo de origem pelo seu SHA-256
+//
+//	Saída:
+//	  found: false quando o arquivo não está na coleção
+func (e *DbImportLog) GetSourceFile(sha256 string) (file goosm.SourceFile, found bool, err error) { //nolint:typecheck
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	err = e.CollectionSources.FindOne(ctx, bson.M{"_id": sha256}).Decode(&file)
+	cancel()
+	if err == mongo.ErrNoDocuments {
+		err = nil
+		return
+	}
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbImportLog.GetSourceFile().FindOne().error: %v", err)
+		return
+	}
+
+	found = true
+	return
+}
+
+// createTable
+//
+// English:
+//
+// Create the collections and indexes
+//
+//	Input:
+//	  database: database name. Eg. "osm"
+//	  collection: collection of the import runs. Eg. "importLog"
+//	  collectionSources: collection of the source files. Eg. "sourceFile"
+//
+// Português:
+//
+// Cria as coleções e os índices
+//
+//	Entrada:
+//	  database: nome do banco de dados. Ex: "osm"
+//	  collection: coleção das execuções de importação. Ex: "importLog"
+//	  collectionSources: coleção dos arquivos de origem. Ex: "sourceFile"
+func (e *DbImportLog) createTable(database, collection, collectionSources string) (err error) {
+	e.Collection = e.Client.Database(database).Collection(collection)
+	e.CollectionSources = e.Client.Database(database).Collection(collectionSources)
+
+	indexes := e.Collection.Indexes()
+
+	var cursor *mongo.Cursor
+	cursor, err = indexes.List(context.Background())
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbImportLog.createTable().List().error: %v", err)
+		return
+	}
+
+	results := make([]bson.M, 0)
+	err = cursor.All(context.Background(), &results)
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbImportLog.createTable().All().error: %v", err)
+		return
+	}
+
+	pass := false
+	for _, result := range results {
+		if result["name"] == "__sha256__" {
+			pass = true
+			break
+		}
+	}
+
+	if !pass {
+		name := "__sha256__"
+		_, err = indexes.CreateOne(
+			context.Background(),
+			mongo.IndexModel{
+				Keys: bson.M{"sha256": 1},
+				Options: &options.IndexOptions{
+					Name: &name,
+				},
+			},
+		)
+		if err != nil {
+			err = fmt.Errorf("mongodb.DbImportLog.createTable().CreateOne().error: %v", err)
+			return
+		}
+
+		name = "__start__"
+		_, err = indexes.CreateOne(
+			context.Background(),
+			mongo.IndexModel{
+				Keys: bson.M{"start": -1},
+				Options: &options.IndexOptions{
+					Name: &name,
+				},
+			},
+		)
+		if err != nil {
+			err = fmt.Errorf("mongodb.DbImportLog.createTable().CreateOne().error: %v", err)
+			return
+		}
+	}
+
+	return
+}
@@ -214,12 +214,18 @@ func (e *DbNode) GetById(id int64) (node goosm.Node, err error) { //nolint:typec
 //	Input:
 //	  list: reference to slice with []goosm.Node objects
 //
+//	Note:
+//	  * IDs already in the collection are ignored, so an interrupted import can be resumed.
+//
 // Português:
 //
 // Insere um bloco de nodes no banco de dados
 //
 //	Entrada:
 //	  list: referência ao slice com os objetos []goosm.Node
+//
+//	Nota:
+//	  * IDs já presentes na coleção são ignorados, assim uma importação interrompida pode ser retomada.
 func (e *DbNode) SetMany(list *[]goosm.Node) (err error) { //nolint:typecheck
 	nodeDb := Node{}
 	var listDb = make([]interface{}, len(*list))
@@ -229,8 +235,13 @@ func (e *DbNode) SetMany(list *[]goosm.Node) (err error) { //nolint:typecheck
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
-	_, err = e.Collection.InsertMany(ctx, listDb)
+	// English: unordered, so a document already inserted by an interrupted import does not stop the others
+	// Português: não ordenado, assim um documento já inserido por uma importação interrompida não para os outros
+	_, err = e.Collection.InsertMany(ctx, listDb, options.InsertMany().SetOrdered(false))
 	cancel()
+	if err != nil && onlyDuplicateKeys(err) {
+		err = nil
+	}
 	if err != nil {
 		err = fmt.Errorf("mongodb.DbNode.SetMany().InsertMany().error: %v", err)
 		return
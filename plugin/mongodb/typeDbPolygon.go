@@ -0,0 +1,360 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"goosm/goosm"
+	"time"
+)
+
+type DbPolygon struct {
+	timeout    time.Duration
+	Client     *mongo.Client
+	Collection *mongo.Collection
+}
+
+// SetTimeout
+//
+// English:
+//
+// Determines timeout for all functions
+//
+//	Input:
+//	  timeout: maximum time for operation
+//
+// Português:
+//
+// Determina o timeout para todas as funções
+//
+//	Entrada:
+//	  timeout: tempo máximo para a operação
+func (e *DbPolygon) SetTimeout(timeout time.Duration) {
+	e.timeout = timeout
+}
+
+// Connect
+//
+// English:
+//
+// Connect to the database
+//
+//	Input:
+//	  connection: database connection string. eg. "mongodb://127.0.0.1:27016/"
+//	  args: maintained by interface compatibility
+//
+// Português:
+//
+// Conecta ao banco de dados
+//
+//	Entrada:
+//	  connection: string de conexão ao banco de dados. Ex: "mongodb://127.0.0.1:27016/"
+//	  args: mantido por compatibilidade da interface
+func (e *DbPolygon) Connect(connection string, _ ...interface{}) (err error) {
+	e.Client, err = mongo.NewClient(options.Client().ApplyURI(connection))
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbPolygon.Connect().NewClient().error: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	err = e.Client.Connect(ctx)
+	cancel()
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbPolygon.Connect().Connect().error: %v", err)
+		return
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), e.timeout)
+	err = e.Client.Ping(ctx, readpref.Primary())
+	cancel()
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbPolygon.Connect().Ping().error: %v", err)
+		return
+	}
+	return
+}
+
+// Close
+//
+// English:
+//
+// # Close the connection to the database
+//
+// Português:
+//
+// Fecha a conexão com o banco de dados
+func (e *DbPolygon) Close() (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	err = e.Client.Disconnect(ctx)
+	cancel()
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbPolygon.Close().Disconnect().error: %v", err)
+		return
+	}
+	return
+}
+
+// New
+//
+// English:
+//
+// Prepare the database for use
+//
+//	Input:
+//	  connection: database connection string. Eg: "mongodb://127.0.0.1:27016/"
+//	  database: database name. Eg. "osm"
+//	  collection: collection name within the database. Eg. "polygon"
+//
+//	Output:
+//	  referenceInitialized: database polygon object ready to use
+//	  err: golang error object
+//
+// Português:
+//
+// Prepara o banco de dados para uso
+//
+//	Entrada:
+//	  connection: string de conexão ao banco de dados. Ex: "mongodb://127.0.0.1:27016/"
+//	  database: nome do banco de dados. Ex: "osm"
+//	  collection: nome da coleção dentro do banco de dados. Ex: "polygon"
+//
+//	Saída:
+//	  referenceInitialized: objeto do banco de dados pronto para uso
+//	  err: objeto golang error
+func (e *DbPolygon) New(connection, database, collection string, timeout time.Duration) (referenceInitialized interface{}, err error) {
+	e.SetTimeout(timeout)
+
+	if err = e.Connect(connection); err != nil {
+		return
+	}
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbPolygon.New().Connect().error: %v", err)
+		return
+	}
+
+	if err = e.createTable(database, collection); err != nil {
+		return
+	}
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbPolygon.New().createTable().error: %v", err)
+		return
+	}
+
+	return e, err
+}
+
+// SetOne
+//
+// English:
+//
+// Insert a single polygon, assembled from a relation, into the database
+//
+//	Input:
+//	  polygon: reference to object goosm.PolygonList
+//
+// Português:
+//
+// Insere um único polígono, montado a partir de uma relation, no banco de dados
+//
+//	Entrada:
+//	  polygon: referencia ao objeto goosm.PolygonList.
+func (e *DbPolygon) SetOne(polygon *goosm.PolygonList) (err error) {
+	var polygonDb = Polygon{}
+	polygonDb.ToDbPolygon(polygon)
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	_, err = e.Collection.InsertOne(ctx, polygonDb)
+	cancel()
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbPolygon.SetOne().InsertOne().error: %v", err)
+		return
+	}
+	return
+}
+
+// GetById
+//
+// English:
+//
+// Returns a polygon according to the relation ID
+//
+//	Input:
+//	  id: ID in the Create Street Maps project pattern
+//
+// Português:
+//
+// Retorna um polígono de acordo com o ID da relation
+//
+//	Entrada:
+//	  id: ID no padrão do projeto Create Street Maps.
+func (e *DbPolygon) GetById(id int64) (polygon goosm.PolygonList, err error) {
+	var polygonDb Polygon
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	err = e.Collection.FindOne(ctx, bson.M{"_id": id}).Decode(&polygonDb)
+	cancel()
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbPolygon.GetById().FindOne().error: %v", err)
+		return
+	}
+
+	polygon = polygonDb.ToOsmPolygonList()
+	return
+}
+
+// SetMany
+//
+// English:
+//
+// Insert a block of polygons into the database
+//
+//	Input:
+//	  list: reference to slice with []goosm.PolygonList objects
+//
+//	Note:
+//	  * IDs already in the collection are ignored, so an interrupted import can be resumed.
+//
+// Português:
+//
+// Insere um bloco de polígonos no banco de dados
+//
+//	Entrada:
+//	  list: referência ao slice com os objetos []goosm.PolygonList
+//
+//	Nota:
+//	  * IDs já presentes na coleção são ignorados, assim uma importação interrompida pode ser retomada.
+func (e *DbPolygon) SetMany(list *[]goosm.PolygonList) (err error) {
+	polygonDb := Polygon{}
+	var listDb = make([]interface{}, len(*list))
+	for key, polygon := range *list {
+		polygonDb.ToDbPolygon(&polygon)
+		listDb[key] = polygonDb
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	// English: unordered, so a document already inserted by an interrupted import does not stop the others
+	// Português: não ordenado, assim um documento já inserido por uma importação interrompida não para os outros
+	_, err = e.Collection.InsertMany(ctx, listDb, options.InsertMany().SetOrdered(false))
+	cancel()
+	if err != nil && onlyDuplicateKeys(err) {
+		err = nil
+	}
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbPolygon.SetMany().InsertMany().error: %v", err)
+		return
+	}
+	return
+}
+
+// createTable
+//
+// English:
+//
+// Create the collection and indexes
+//
+//	Input:
+//	  database: database name. Eg. "osm"
+//	  collection: collection name within the database. Eg. "polygon"
+//
+// Português:
+//
+// Cria a coleção e os índices
+//
+//	Entrada:
+//	  database: nome do banco de dados. Ex: "osm"
+//	  collection: nome da coleção dentro do banco de dados. Ex: "polygon"
+func (e *DbPolygon) createTable(database, collection string) (err error) {
+	e.Collection = e.Client.Database(database).Collection(collection)
+
+	indexes := e.Collection.Indexes()
+
+	var cursor *mongo.Cursor
+	cursor, err = indexes.List(context.Background())
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbPolygon.createTable().List().error: %v", err)
+		return
+	}
+
+	results := make([]bson.M, 0)
+	err = cursor.All(context.Background(), &results)
+	if err != nil {
+		err = fmt.Errorf("mongodb.DbPolygon.createTable().All().error: %v", err)
+		return
+	}
+
+	pass := false
+	for _, result := range results {
+		if result["name"] == "__loc__" {
+			pass = true
+			break
+		}
+	}
+
+	if !pass {
+		name := "__loc__"
+		_, err = indexes.CreateOne(
+			context.Background(),
+			mongo.IndexModel{
+				Keys: bson.M{"loc": "2dsphere"},
+				Options: &options.IndexOptions{
+					Name: &name,
+				},
+			},
+		)
+		if err != nil {
+			err = fmt.Errorf("mongodb.DbPolygon.createTable().CreateOne(loc).error: %v", err)
+			return
+		}
+
+		name = "__tags__"
+		_, err = indexes.CreateOne(
+			context.Background(),
+			mongo.IndexModel{
+				Keys: bson.M{"tag": 1},
+				Options: &options.IndexOptions{
+					Name: &name,
+				},
+			},
+		)
+		if err != nil {
+			err = fmt.Errorf("mongodb.DbPolygon.createTable().CreateOne(tag).error: %v", err)
+			return
+		}
+
+		name = "__idWay__"
+		_, err = indexes.CreateOne(
+			context.Background(),
+			mongo.IndexModel{
+				Keys: bson.M{"idWay": 1},
+				Options: &options.IndexOptions{
+					Name: &name,
+				},
+			},
+		)
+		if err != nil {
+			err = fmt.Errorf("mongodb.DbPolygon.createTable().CreateOne(idWay).error: %v", err)
+			return
+		}
+	}
+
+	return
+}
+
+//
+//
+//
+//
+//
+//
+//
+//
+//
+//
+//
+//
+//
+//
+//
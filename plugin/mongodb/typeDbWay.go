@@ -214,12 +214,18 @@ func (e *DbWay) GetById(id int64) (way goosm.Way, err error) {
 //	Input:
 //	  list: reference to slice with []goosm.Way objects
 //
+//	Note:
+//	  * IDs already in the collection are ignored, so an interrupted import can be resumed.
+//
 // Português:
 //
 // Insere um bloco de ways no banco de dados
 //
 //	Entrada:
 //	  list: referência ao slice com os objetos []goosm.Way
+//
+//	Nota:
+//	  * IDs já presentes na coleção são ignorados, assim uma importação interrompida pode ser retomada.
 func (e *DbWay) SetMany(list *[]goosm.Way) (err error) {
 	wayDb := Way{}
 	var listDb = make([]interface{}, len(*list))
@@ -229,8 +235,13 @@ func (e *DbWay) SetMany(list *[]goosm.Way) (err error) {
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
-	_, err = e.Collection.InsertMany(ctx, listDb)
+	// English: unordered, so a document already inserted by an interrupted import does not stop the others
+	// Português: não ordenado, assim um documento já inserido por uma importação interrompida não para os outros
+	_, err = e.Collection.InsertMany(ctx, listDb, options.InsertMany().SetOrdered(false))
 	cancel()
+	if err != nil && onlyDuplicateKeys(err) {
+		err = nil
+	}
 	if err != nil {
 		err = fmt.Errorf("mongodb.DbWay.SetMany().InsertMany().error: %v", err)
 		return
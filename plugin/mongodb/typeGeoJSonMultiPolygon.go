@@ -0,0 +1,6 @@
+package mongodb //nolint:typecheck
+
+type GeoJSonMultiPolygon struct {
+	Type        string           `bson:"type"`
+	Coordinates [][][][2]float64 `bson:"coordinates"`
+}
@@ -13,22 +13,32 @@ type Node struct {
 	// Tags do Create Street Maps
 	Tag map[string]string `bson:"tag,omitempty"`
 
+	// Nomes por idioma, das chaves "name:xx", apenas quando PbfProcess.SetTagPolicy() foi chamado
+	International map[string]string `bson:"international,omitempty"`
+
 	GeoJSonFeature string `bson:"geoJSonFeature,omitempty"`
+
+	// Metadados da última edição, apenas quando PbfProcess.SetMetadata(true) foi chamado
+	Metadata *goosm.Metadata `bson:"metadata,omitempty"`
 }
 
 func (e Node) ToOsmNode() (node goosm.Node) {
 	node.Id = e.Id
 	node.Tag = e.Tag
+	node.International = e.International
 	node.Loc = e.Loc.Coordinates
 	node.GeoJSonFeature = e.GeoJSonFeature
+	node.Metadata = e.Metadata
 	return
 }
 
 func (e *Node) ToDbNode(node *goosm.Node) (dbNode Node) {
 	e.Id = node.Id
 	e.Tag = node.Tag
+	e.International = node.International
 	e.Loc.Type = "Point"
 	e.Loc.Coordinates = node.Loc
 	e.GeoJSonFeature = node.GeoJSonFeature
+	e.Metadata = node.Metadata
 	return
 }
@@ -0,0 +1,86 @@
+package mongodb
+
+import "goosm/goosm"
+
+type Polygon struct {
+	Id  int64             `bson:"_id"`
+	Tag map[string]string `bson:"tag,omitempty"`
+
+	// English: rings of the relation, where the first ring of each polygon is the outer ring and the others are holes
+	// Português: anéis da relation, onde o primeiro anel de cada polígono é o anel externo e os demais são buracos
+	Loc            GeoJSonMultiPolygon `bson:"loc"`
+	IdWay          []int64             `bson:"idWay,omitempty"`
+	GeoJSonFeature string              `bson:"geoJSonFeature,omitempty"`
+}
+
+func (e Polygon) ToOsmPolygonList() (polygonList goosm.PolygonList) {
+	polygonList.Id = e.Id
+	polygonList.Tag = e.Tag
+	polygonList.IdWay = e.IdWay
+	polygonList.GeoJSonFeature = e.GeoJSonFeature
+
+	for _, rings := range e.Loc.Coordinates {
+		for ringKey, ring := range rings {
+			var polygon = goosm.Polygon{}
+			polygon.Role = "outer"
+			if ringKey != 0 {
+				polygon.Role = "inner"
+			}
+
+			for _, loc := range ring {
+				polygon.AddLngLatDegrees(loc[0], loc[1])
+			}
+
+			_ = polygon.Init()
+			polygonList.AddPolygon(&polygon)
+		}
+	}
+
+	return
+}
+
+func (e *Polygon) ToDbPolygon(polygonList *goosm.PolygonList) (dbPolygon Polygon) {
+	e.Id = polygonList.Id
+	e.Tag = polygonList.Tag
+	e.IdWay = polygonList.IdWay
+	e.GeoJSonFeature = polygonList.GeoJSonFeature
+	e.Loc.Type = "MultiPolygon"
+	e.Loc.Coordinates = make([][][][2]float64, 0)
+
+	// English: each outer ring starts a new polygon and receives the inner rings contained in it
+	// Português: cada anel externo inicia um novo polígono e recebe os anéis internos contidos nele
+	var outerList = make([]goosm.Polygon, 0)
+	for _, polygon := range polygonList.List {
+		if polygon.Role == "inner" {
+			continue
+		}
+
+		outerList = append(outerList, polygon)
+		e.Loc.Coordinates = append(e.Loc.Coordinates, [][][2]float64{e.ring(&polygon)})
+	}
+
+	for _, polygon := range polygonList.List {
+		if polygon.Role != "inner" || len(polygon.PointsList) == 0 {
+			continue
+		}
+
+		for outerKey := range outerList {
+			inside, _ := outerList[outerKey].PointInPolygon(polygon.PointsList[0])
+			if inside {
+				e.Loc.Coordinates[outerKey] = append(e.Loc.Coordinates[outerKey], e.ring(&polygon))
+				break
+			}
+		}
+	}
+
+	return *e
+}
+
+func (e *Polygon) ring(polygon *goosm.Polygon) (ring [][2]float64) {
+	ring = make([][2]float64, len(polygon.PointsList))
+	for k, point := range polygon.PointsList {
+		ring[k] = point.Loc
+	}
+
+	return
+}
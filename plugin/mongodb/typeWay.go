@@ -6,12 +6,15 @@ type Way struct {
 	Id             int64             `bson:"_id"`
 	IsPolygon      bool              `bson:"isPolygon"`
 	Tag            map[string]string `bson:"tag,omitempty"`
+	International  map[string]string `bson:"international,omitempty"`
 	Loc            GeoJSonLineString `bson:"loc"`
+	Pieces         [][][2]float64    `bson:"pieces,omitempty"`
 	LocFirst       [2]float64        `bson:"locFirst"`
 	LocLast        [2]float64        `bson:"locLast"`
 	IdList         []int64           `bson:"idList,omitempty"`
 	DistanceTotal  float64           `bson:"distanceTotal"`
 	GeoJSonFeature string            `bson:"geoJSonFeature,omitempty"`
+	Metadata       *goosm.Metadata   `bson:"metadata,omitempty"`
 }
 
 func (e Way) ToOsmWay() (way goosm.Way) {
@@ -22,11 +25,14 @@ func (e Way) ToOsmWay() (way goosm.Way) {
 	way.Id = e.Id
 	way.IsPolygon = e.IsPolygon
 	way.Tag = e.Tag
+	way.International = e.International
 	way.Loc = e.Loc.Coordinates
+	way.Pieces = e.Pieces
 	way.LocFirst = e.LocFirst
 	way.LocLast = e.LocLast
 	way.DistanceTotal = e.DistanceTotal
 	way.GeoJSonFeature = e.GeoJSonFeature
+	way.Metadata = e.Metadata
 	return
 }
 
@@ -51,11 +57,14 @@ func (e *Way) ToDbWay(way *goosm.Way) (dbWay Way) {
 	e.Id = way.Id
 	e.IsPolygon = way.IsPolygon
 	e.Tag = way.Tag
+	e.International = way.International
 	e.Loc.Type = "LineString"
 	e.Loc.Coordinates = way.Loc
+	e.Pieces = way.Pieces
 	e.LocFirst = way.LocFirst
 	e.LocLast = way.LocLast
 	e.DistanceTotal = way.DistanceTotal
 	e.GeoJSonFeature = way.GeoJSonFeature
+	e.Metadata = way.Metadata
 	return *e
 }